@@ -12,11 +12,13 @@ import (
 
 // AdminHandler は管理者機能のAPIハンドラー
 type AdminHandler struct {
-	userRepo        repositories.UserRepository
-	systemRepo      repositories.SystemSettingsRepository
-	activityService *services.ActivityLogService
-	backupService   *services.BackupService
-	metricsService  *services.SystemMetricsService
+	userRepo         repositories.UserRepository
+	systemRepo       repositories.SystemSettingsRepository
+	activityService  *services.ActivityLogService
+	backupService    *services.BackupService
+	metricsService   *services.SystemMetricsService
+	twoFactorService *services.TwoFactorService
+	loginThrottle    *services.LoginThrottleService
 }
 
 // NewAdminHandler は新しいAdminHandlerを作成します
@@ -26,13 +28,17 @@ func NewAdminHandler(
 	activityService *services.ActivityLogService,
 	backupService *services.BackupService,
 	metricsService *services.SystemMetricsService,
+	twoFactorService *services.TwoFactorService,
+	loginThrottle *services.LoginThrottleService,
 ) *AdminHandler {
 	return &AdminHandler{
-		userRepo:        userRepo,
-		systemRepo:      systemRepo,
-		activityService: activityService,
-		backupService:   backupService,
-		metricsService:  metricsService,
+		userRepo:         userRepo,
+		systemRepo:       systemRepo,
+		activityService:  activityService,
+		backupService:    backupService,
+		metricsService:   metricsService,
+		twoFactorService: twoFactorService,
+		loginThrottle:    loginThrottle,
 	}
 }
 
@@ -78,7 +84,7 @@ func (h *AdminHandler) GetUsers(c *gin.Context) {
 
 // UpdateUser はユーザー情報を更新します
 // @Summary ユーザー情報更新
-// @Description 管理者がユーザー情報を更新します
+// @Description 管理者がユーザー情報を更新します。reset_two_factor を true にすると、認証アプリを紛失したユーザーの二要素認証を解除します
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -106,9 +112,10 @@ func (h *AdminHandler) UpdateUser(c *gin.Context) {
 	}
 
 	var updateData struct {
-		FullName string `json:"full_name"`
-		IsAdmin  *bool  `json:"is_admin"`
-		IsActive *bool  `json:"is_active"`
+		FullName       string `json:"full_name"`
+		IsAdmin        *bool  `json:"is_admin"`
+		IsActive       *bool  `json:"is_active"`
+		ResetTwoFactor bool   `json:"reset_two_factor"`
 	}
 
 	if err := c.ShouldBindJSON(&updateData); err != nil {
@@ -136,6 +143,16 @@ func (h *AdminHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
+	// 二要素認証の解除
+	twoFactorReset := false
+	if updateData.ResetTwoFactor {
+		twoFactorReset, err = h.twoFactorService.Reset(c.Request.Context(), userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset two-factor authentication"})
+			return
+		}
+	}
+
 	// アクティビティログに記録
 	currentUserID := c.GetInt64("user_id")
 	currentUsername := c.GetString("username")
@@ -152,10 +169,79 @@ func (h *AdminHandler) UpdateUser(c *gin.Context) {
 			"updated_fields": updateData,
 		},
 	)
+	if twoFactorReset {
+		h.activityService.LogActivity(
+			c.Request.Context(),
+			currentUserID,
+			currentUsername,
+			models.ActionUserTwoFactorDisabled,
+			models.ResourceUser,
+			userID,
+			c.ClientIP(),
+			c.GetHeader("User-Agent"),
+			map[string]interface{}{
+				"reset_by_admin": true,
+			},
+		)
+	}
 
 	c.JSON(http.StatusOK, user)
 }
 
+// UnlockUser はログインの失敗によるアカウントのロックを解除します
+// @Summary アカウントのロック解除
+// @Description 管理者がログインの失敗が続いてロックされたアカウントのロックを解除し、失敗回数を消去します
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "ユーザーID"
+// @Success 200 {object} map[string]interface{} "解除成功メッセージ"
+// @Failure 400 {object} map[string]string "リクエストエラー"
+// @Failure 401 {object} map[string]string "認証エラー"
+// @Failure 403 {object} map[string]string "権限エラー"
+// @Failure 404 {object} map[string]string "ユーザーが見つからない"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/users/{id}/unlock [post]
+// @Security BearerAuth
+func (h *AdminHandler) UnlockUser(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if _, err := h.userRepo.GetByID(c.Request.Context(), userID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	wasLocked, err := h.loginThrottle.Unlock(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlock user"})
+		return
+	}
+
+	// アクティビティログに記録
+	h.activityService.LogActivity(
+		c.Request.Context(),
+		c.GetInt64("user_id"),
+		c.GetString("username"),
+		models.ActionUserUnlocked,
+		models.ResourceUser,
+		userID,
+		c.ClientIP(),
+		c.GetHeader("User-Agent"),
+		map[string]interface{}{
+			"was_locked": wasLocked,
+		},
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "User unlocked successfully",
+		"was_locked": wasLocked,
+	})
+}
+
 // GetSystemSettings はシステム設定を取得します
 // @Summary システム設定取得
 // @Description 管理者がシステム設定を取得します
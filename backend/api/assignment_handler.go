@@ -1,38 +1,53 @@
 package api
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/shimauma0312/module-tickethub/backend/models"
 	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
 )
 
 // AssignmentHandler はアサイン機能のハンドラーを管理する構造体
 type AssignmentHandler struct {
-	issueRepo repositories.IssueRepository
-	userRepo  repositories.UserRepository
+	issueRepo         repositories.IssueRepository
+	userRepo          repositories.UserRepository
+	eventBus          *services.EventBus
+	permissionService *services.RepositoryPermissionService
 }
 
 // NewAssignmentHandler は新しいAssignmentHandlerを作成します
 func NewAssignmentHandler(
 	issueRepo repositories.IssueRepository,
 	userRepo repositories.UserRepository,
+	eventBus *services.EventBus,
+	permissionService *services.RepositoryPermissionService,
 ) *AssignmentHandler {
 	return &AssignmentHandler{
-		issueRepo: issueRepo,
-		userRepo:  userRepo,
+		issueRepo:         issueRepo,
+		userRepo:          userRepo,
+		eventBus:          eventBus,
+		permissionService: permissionService,
 	}
 }
 
-// @Summary Issueの担当者設定
-// @Description 指定されたIssueの担当者を設定します
+// AssignmentRequest はアサインリクエストのデータ構造
+type AssignmentRequest struct {
+	AssigneeIDs []int64 `json:"assignee_ids"` // 追加・削除する担当者
+	AssigneeID  int64   `json:"assignee_id"`  // 旧クライアント用（assignee_ids がない場合に担当者をこのユーザーに置き換える）
+}
+
+// @Summary Issueの担当者追加
+// @Description 指定されたIssueに担当者を追加します（assignee_id のみ指定した場合は担当者を置き換えます）
 // @Tags assignments
 // @Accept json
 // @Produce json
 // @Param id path int true "Issue ID"
-// @Param assignment body map[string]int64 true "担当者情報"
+// @Param assignment body AssignmentRequest true "担当者情報"
 // @Success 200 {object} map[string]interface{}
 // @Router /api/v1/issues/{id}/assign [put]
 func (h *AssignmentHandler) AssignIssue(c *gin.Context) {
@@ -46,7 +61,7 @@ func (h *AssignmentHandler) AssignIssue(c *gin.Context) {
 	// データベースから取得
 	issue, err := h.issueRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Issue not found")
 		return
 	}
 
@@ -56,52 +71,69 @@ func (h *AssignmentHandler) AssignIssue(c *gin.Context) {
 		return
 	}
 
-	// リクエストの解析
-	var req struct {
-		AssigneeID int64 `json:"assignee_id"`
+	// triage以上のロールの確認
+	if !authorizeRepository(c, h.permissionService, issue.RepositoryID, models.RepositoryRoleTriage, "Issue not found") {
+		return
 	}
+
+	// リクエストの解析
+	var req AssignmentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// 担当者IDの検証（0は担当者なしを意味する）
-	if req.AssigneeID > 0 {
-		user, err := h.userRepo.GetByID(c.Request.Context(), req.AssigneeID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// 担当者IDの検証（assignee_id の0は担当者なしを意味する）
+	if req.AssigneeIDs == nil {
+		if req.AssigneeID > 0 && !h.checkAssignee(c, req.AssigneeID) {
 			return
 		}
-		if user == nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Assignee user not found"})
-			return
+	} else {
+		for _, userID := range req.AssigneeIDs {
+			if !h.checkAssignee(c, userID) {
+				return
+			}
 		}
 	}
 
 	// 担当者の更新
-	issue.AssigneeID = req.AssigneeID
+	before := issue.Clone()
+	if req.AssigneeIDs == nil {
+		issue.SetAssignees([]int64{req.AssigneeID})
+	} else {
+		for _, userID := range req.AssigneeIDs {
+			issue.AddAssignee(userID)
+		}
+	}
 	issue.UpdatedAt = models.CurrentTime()
 
-	// データベースに保存
-	err = h.issueRepo.Update(c.Request.Context(), issue)
+	// データベースに保存（変更履歴も記録）
+	err = h.issueRepo.UpdateWithEvents(c.Request.Context(), issue, models.DiffIssueEvents(before, issue, getUserIDFromContext(c)))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	// 担当者が追加された場合はイベントを発行
+	if added := models.AddedAssignees(before, issue); len(added) > 0 {
+		h.eventBus.Publish(c.Request.Context(), services.NewIssueAssignedEvent(getUserIDFromContext(c), issue, added))
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message":     "Issue assigned successfully",
-		"issue_id":    issue.ID,
-		"assignee_id": issue.AssigneeID,
+		"message":      "Issue assigned successfully",
+		"issue_id":     issue.ID,
+		"assignee_id":  issue.AssigneeID,
+		"assignee_ids": issue.Assignees(),
 	})
 }
 
 // @Summary Issueの担当者削除
-// @Description 指定されたIssueの担当者を削除します
+// @Description 指定されたIssueから担当者を削除します（assignee_ids を指定しない場合はすべての担当者を削除します）
 // @Tags assignments
 // @Accept json
 // @Produce json
 // @Param id path int true "Issue ID"
+// @Param assignment body AssignmentRequest false "削除する担当者"
 // @Success 200 {object} map[string]interface{}
 // @Router /api/v1/issues/{id}/unassign [put]
 func (h *AssignmentHandler) UnassignIssue(c *gin.Context) {
@@ -115,7 +147,7 @@ func (h *AssignmentHandler) UnassignIssue(c *gin.Context) {
 	// データベースから取得
 	issue, err := h.issueRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Issue not found")
 		return
 	}
 
@@ -125,19 +157,66 @@ func (h *AssignmentHandler) UnassignIssue(c *gin.Context) {
 		return
 	}
 
+	// triage以上のロールの確認
+	if !authorizeRepository(c, h.permissionService, issue.RepositoryID, models.RepositoryRoleTriage, "Issue not found") {
+		return
+	}
+
+	// リクエストの解析（本文は省略可能）
+	var req AssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// 担当者の削除
-	issue.AssigneeID = 0
+	before := issue.Clone()
+	if req.AssigneeIDs == nil {
+		issue.SetAssignees(nil)
+	} else {
+		for _, userID := range req.AssigneeIDs {
+			issue.RemoveAssignee(userID)
+		}
+	}
 	issue.UpdatedAt = models.CurrentTime()
 
-	// データベースに保存
-	err = h.issueRepo.Update(c.Request.Context(), issue)
+	// データベースに保存（変更履歴も記録）
+	err = h.issueRepo.UpdateWithEvents(c.Request.Context(), issue, models.DiffIssueEvents(before, issue, getUserIDFromContext(c)))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message":  "Issue unassigned successfully",
-		"issue_id": issue.ID,
+		"message":      "Issue unassigned successfully",
+		"issue_id":     issue.ID,
+		"assignee_id":  issue.AssigneeID,
+		"assignee_ids": issue.Assignees(),
 	})
 }
+
+// checkAssignee は担当者に指定されたユーザーが存在するかを確認します
+func (h *AssignmentHandler) checkAssignee(c *gin.Context, userID int64) bool {
+	if userID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignee ID"})
+		return false
+	}
+	user, err := h.userRepo.GetByID(c.Request.Context(), userID)
+	if err != nil || user == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Assignee user not found"})
+		return false
+	}
+	return true
+}
+
+// applyAssigneeRequest はリクエストで指定された担当者をIssueに設定します
+// assignee_ids がない旧クライアントの場合は assignee_id が変わったときのみ担当者を置き換えます
+func applyAssigneeRequest(issue *models.Issue, assigneeIDs []int64, assigneeID int64) {
+	if assigneeIDs != nil {
+		issue.SetAssignees(assigneeIDs)
+		return
+	}
+	if assigneeID != issue.AssigneeID {
+		issue.SetAssignees([]int64{assigneeID})
+	}
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"errors"
+	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -11,13 +13,18 @@ import (
 
 // AuthHandler は認証関連のハンドラー
 type AuthHandler struct {
-	authService *services.AuthService
+	authService              *services.AuthService
+	activityService          *services.ActivityLogService
+	emailVerificationService *services.EmailVerificationService
 }
 
 // NewAuthHandler は新しいAuthHandlerを作成します
-func NewAuthHandler(authService *services.AuthService) *AuthHandler {
+// emailVerificationService がnilの場合は登録時に確認メールを送信しません
+func NewAuthHandler(authService *services.AuthService, activityService *services.ActivityLogService, emailVerificationService *services.EmailVerificationService) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:              authService,
+		activityService:          activityService,
+		emailVerificationService: emailVerificationService,
 	}
 }
 
@@ -83,13 +90,26 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	// メールアドレスの確認が必須の場合は確認メールを送信
+	// 送信に失敗してもユーザーは作成済みのため、登録は成功とし /auth/verify-email/resend で再送信できるようにする
+	verificationRequired := false
+	if h.emailVerificationService != nil {
+		verificationRequired, err = h.emailVerificationService.StartVerification(c.Request.Context(), user)
+		if err != nil {
+			log.Printf("Failed to send verification email to user %d: %v", user.ID, err)
+			verificationRequired = true
+		}
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "User registered successfully",
 		"user": gin.H{
-			"id":       user.ID,
-			"username": user.Username,
-			"email":    user.Email,
+			"id":             user.ID,
+			"username":       user.Username,
+			"email":          user.Email,
+			"email_verified": user.IsEmailVerified(),
 		},
+		"email_verification_required": verificationRequired,
 	})
 }
 
@@ -113,10 +133,28 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	)
 
 	if err != nil {
-		if err == services.ErrInvalidCredentials {
+		// 失敗が続いている場合は試行を制限
+		var throttled *services.LoginThrottledError
+		if errors.As(err, &throttled) {
+			respondLoginThrottled(c, throttled)
+			return
+		}
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			// アクティビティログに記録
+			logLoginFailure(c, h.activityService, user, req.UsernameOrEmail, err, "invalid_credentials")
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 			return
 		}
+		// 二要素認証が有効な場合は /auth/login/2fa で使用する一時トークンを返す
+		if err == services.ErrTwoFactorRequired {
+			pendingToken, expiresIn, err := h.authService.IssueMFAPendingToken(user)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to login"})
+				return
+			}
+			respondTwoFactorRequired(c, pendingToken, expiresIn)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to login"})
 		return
 	}
@@ -135,9 +173,10 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Login successful",
 		"user": gin.H{
-			"id":       user.ID,
-			"username": user.Username,
-			"email":    user.Email,
+			"id":             user.ID,
+			"username":       user.Username,
+			"email":          user.Email,
+			"email_verified": user.IsEmailVerified(),
 		},
 		"token": TokenResponse{
 			AccessToken:  accessToken,
@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/shimauma0312/module-tickethub/backend/models"
 	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
 )
 
 // CommentHandler はComment関連のハンドラーを管理する構造体
@@ -14,8 +15,12 @@ type CommentHandler struct {
 	commentRepo    repositories.CommentRepository
 	issueRepo      repositories.IssueRepository
 	discussionRepo repositories.DiscussionRepository
-	reactionRepo   repositories.ReactionRepository
 	userRepo       repositories.UserRepository
+	eventBus       *services.EventBus
+
+	reactionService   *services.ReactionService
+	revisionService   *services.RevisionService
+	permissionService *services.RepositoryPermissionService
 }
 
 // NewCommentHandler は新しいCommentHandlerを作成します
@@ -23,15 +28,21 @@ func NewCommentHandler(
 	commentRepo repositories.CommentRepository,
 	issueRepo repositories.IssueRepository,
 	discussionRepo repositories.DiscussionRepository,
-	reactionRepo repositories.ReactionRepository,
 	userRepo repositories.UserRepository,
+	eventBus *services.EventBus,
+	reactionService *services.ReactionService,
+	revisionService *services.RevisionService,
+	permissionService *services.RepositoryPermissionService,
 ) *CommentHandler {
 	return &CommentHandler{
-		commentRepo:    commentRepo,
-		issueRepo:      issueRepo,
-		discussionRepo: discussionRepo,
-		reactionRepo:   reactionRepo,
-		userRepo:       userRepo,
+		commentRepo:       commentRepo,
+		issueRepo:         issueRepo,
+		discussionRepo:    discussionRepo,
+		userRepo:          userRepo,
+		eventBus:          eventBus,
+		reactionService:   reactionService,
+		revisionService:   revisionService,
+		permissionService: permissionService,
 	}
 }
 
@@ -74,27 +85,9 @@ func (h *CommentHandler) ListComments(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
 
-	// ターゲットの存在確認
-	if targetType == "issue" {
-		issue, err := h.issueRepo.GetByID(c.Request.Context(), targetID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		if issue == nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
-			return
-		}
-	} else {
-		discussion, err := h.discussionRepo.GetByID(c.Request.Context(), targetID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		if discussion == nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Discussion not found"})
-			return
-		}
+	// ターゲットの存在と閲覧権限の確認
+	if !h.authorizeTarget(c, targetType, targetID) {
+		return
 	}
 
 	// コメント一覧の取得
@@ -104,6 +97,12 @@ func (h *CommentHandler) ListComments(c *gin.Context) {
 		return
 	}
 
+	// リアクションの集計
+	if err := h.reactionService.AttachToComments(c.Request.Context(), comments, getUserIDFromContext(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	// コメントと返信をグループ化（返信はTreeNodeに変換）
 	commentTree := buildCommentTree(comments)
 
@@ -174,7 +173,7 @@ func (h *CommentHandler) GetComment(c *gin.Context) {
 	// データベースから取得
 	comment, err := h.commentRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Comment not found")
 		return
 	}
 
@@ -184,6 +183,17 @@ func (h *CommentHandler) GetComment(c *gin.Context) {
 		return
 	}
 
+	// 閲覧できないリポジトリの場合
+	if !h.authorizeComment(c, comment, models.RepositoryRoleRead, "Comment not found") {
+		return
+	}
+
+	// リアクションの集計
+	if err := h.reactionService.AttachToComments(c.Request.Context(), []*models.Comment{comment}, getUserIDFromContext(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, comment)
 }
 
@@ -218,27 +228,9 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 		return
 	}
 
-	// ターゲットの存在確認
-	if targetType == "issue" {
-		issue, err := h.issueRepo.GetByID(c.Request.Context(), targetID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		if issue == nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
-			return
-		}
-	} else {
-		discussion, err := h.discussionRepo.GetByID(c.Request.Context(), targetID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		if discussion == nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Discussion not found"})
-			return
-		}
+	// ターゲットの存在と閲覧権限の確認
+	if !h.authorizeTarget(c, targetType, targetID) {
+		return
 	}
 
 	// リクエストの解析
@@ -258,6 +250,9 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 		return
 	}
 
+	// イベントの発行
+	h.eventBus.Publish(c.Request.Context(), services.NewCommentEvent(services.EventCommentCreated, userID.(int64), targetType, targetID, comment))
+
 	c.JSON(http.StatusCreated, comment)
 }
 
@@ -302,7 +297,7 @@ func (h *CommentHandler) CreateReplyComment(c *gin.Context) {
 	// 親コメントの存在確認
 	parentComment, err := h.commentRepo.GetByID(c.Request.Context(), req.ParentCommentID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Parent comment not found")
 		return
 	}
 	if parentComment == nil {
@@ -316,6 +311,11 @@ func (h *CommentHandler) CreateReplyComment(c *gin.Context) {
 		return
 	}
 
+	// ターゲットの閲覧権限の確認
+	if !h.authorizeTarget(c, targetType, targetID) {
+		return
+	}
+
 	// 返信コメントの作成
 	comment := models.NewReply(req.Body, userID.(int64), targetID, req.ParentCommentID, targetType)
 
@@ -326,6 +326,9 @@ func (h *CommentHandler) CreateReplyComment(c *gin.Context) {
 		return
 	}
 
+	// イベントの発行
+	h.eventBus.Publish(c.Request.Context(), services.NewCommentEvent(services.EventCommentCreated, userID.(int64), targetType, targetID, comment))
+
 	c.JSON(http.StatusCreated, comment)
 }
 
@@ -356,7 +359,7 @@ func (h *CommentHandler) UpdateComment(c *gin.Context) {
 	// データベースから取得
 	comment, err := h.commentRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Comment not found")
 		return
 	}
 
@@ -366,10 +369,8 @@ func (h *CommentHandler) UpdateComment(c *gin.Context) {
 		return
 	}
 
-	// 作成者のみ編集可能（または管理者）
-	isAdmin, _ := c.Get("is_admin")
-	if comment.CreatorID != userID.(int64) && !(isAdmin != nil && isAdmin.(bool)) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to update this comment"})
+	// 作成者またはmaintain以上のロールを持つユーザーのみ編集可能
+	if !h.authorizeComment(c, comment, models.RepositoryRoleMaintain, "Comment not found") {
 		return
 	}
 
@@ -381,6 +382,7 @@ func (h *CommentHandler) UpdateComment(c *gin.Context) {
 	}
 
 	// コメントの更新
+	previousBody := comment.Body
 	comment.Edit(req.Body)
 
 	// データベースに保存
@@ -390,6 +392,21 @@ func (h *CommentHandler) UpdateComment(c *gin.Context) {
 		return
 	}
 
+	// 本文の編集履歴の記録
+	if err := h.revisionService.Record(c.Request.Context(), models.RevisionTargetComment, comment.ID, previousBody, comment.CreatorID, comment.CreatedAt, comment.Body, userID.(int64)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// イベントの発行（返信の場合は親コメントのターゲットタイプを使用）
+	targetType := comment.Type
+	if comment.IsReply() {
+		if parentComment, err := h.commentRepo.GetByID(c.Request.Context(), comment.ParentCommentID); err == nil {
+			targetType = parentComment.Type
+		}
+	}
+	h.eventBus.Publish(c.Request.Context(), services.NewCommentEvent(services.EventCommentUpdated, userID.(int64), targetType, comment.TargetID, comment))
+
 	c.JSON(http.StatusOK, comment)
 }
 
@@ -403,7 +420,7 @@ func (h *CommentHandler) UpdateComment(c *gin.Context) {
 // @Router /api/v1/comments/{id} [delete]
 func (h *CommentHandler) DeleteComment(c *gin.Context) {
 	// ユーザーIDの取得
-	userID, exists := c.Get("user_id")
+	_, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
@@ -419,7 +436,7 @@ func (h *CommentHandler) DeleteComment(c *gin.Context) {
 	// データベースから取得
 	comment, err := h.commentRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Comment not found")
 		return
 	}
 
@@ -429,10 +446,32 @@ func (h *CommentHandler) DeleteComment(c *gin.Context) {
 		return
 	}
 
-	// 作成者のみ削除可能（または管理者）
-	isAdmin, _ := c.Get("is_admin")
-	if comment.CreatorID != userID.(int64) && !(isAdmin != nil && isAdmin.(bool)) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to delete this comment"})
+	// 作成者またはmaintain以上のロールを持つユーザーのみ削除可能
+	if !h.authorizeComment(c, comment, models.RepositoryRoleMaintain, "Comment not found") {
+		return
+	}
+
+	// 回答として選ばれている場合は選択を解除
+	if comment.Type == "discussion" {
+		discussion, err := h.discussionRepo.GetByID(c.Request.Context(), comment.TargetID)
+		if err == nil && discussion != nil && discussion.AnswerCommentID == comment.ID {
+			discussion.UnmarkAnswer()
+			if err := h.discussionRepo.Update(c.Request.Context(), discussion); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+	}
+
+	// リアクションの削除
+	if err := h.reactionService.RemoveAll(c.Request.Context(), models.ReactionTargetComment, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 編集履歴の削除
+	if err := h.revisionService.RemoveAll(c.Request.Context(), models.RevisionTargetComment, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -467,7 +506,7 @@ func (h *CommentHandler) ListReplies(c *gin.Context) {
 	// 親コメントの存在確認
 	parentComment, err := h.commentRepo.GetByID(c.Request.Context(), commentID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Parent comment not found")
 		return
 	}
 	if parentComment == nil {
@@ -475,6 +514,11 @@ func (h *CommentHandler) ListReplies(c *gin.Context) {
 		return
 	}
 
+	// 閲覧できないリポジトリの場合
+	if !h.authorizeComment(c, parentComment, models.RepositoryRoleRead, "Parent comment not found") {
+		return
+	}
+
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 
@@ -485,6 +529,12 @@ func (h *CommentHandler) ListReplies(c *gin.Context) {
 		return
 	}
 
+	// リアクションの集計
+	if err := h.reactionService.AttachToComments(c.Request.Context(), replies, getUserIDFromContext(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"replies": replies,
 		"total":   total,
@@ -492,3 +542,41 @@ func (h *CommentHandler) ListReplies(c *gin.Context) {
 		"limit":   limit,
 	})
 }
+
+// authorizeTarget はコメントのターゲットとなるIssue・Discussionの存在とリポジトリの閲覧権限を確認します
+func (h *CommentHandler) authorizeTarget(c *gin.Context, targetType string, targetID int64) bool {
+	if targetType == "issue" {
+		issue, err := h.issueRepo.GetByID(c.Request.Context(), targetID)
+		if err != nil {
+			respondFetchError(c, err, "Issue not found")
+			return false
+		}
+		if issue == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+			return false
+		}
+		return authorizeRepository(c, h.permissionService, issue.RepositoryID, models.RepositoryRoleRead, "Issue not found")
+	}
+
+	discussion, err := h.discussionRepo.GetByID(c.Request.Context(), targetID)
+	if err != nil {
+		respondFetchError(c, err, "Discussion not found")
+		return false
+	}
+	if discussion == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Discussion not found"})
+		return false
+	}
+	return authorizeRepository(c, h.permissionService, discussion.RepositoryID, models.RepositoryRoleRead, "Discussion not found")
+}
+
+// authorizeComment はコメントの作成者には閲覧権限のみを、それ以外のユーザーには
+// コメントが属するリポジトリで required 以上のロールを要求します
+func (h *CommentHandler) authorizeComment(c *gin.Context, comment *models.Comment, required models.RepositoryRole, notFound string) bool {
+	repositoryID, err := commentRepositoryID(c.Request.Context(), comment, h.commentRepo, h.issueRepo, h.discussionRepo)
+	if err != nil {
+		respondRepositoryPermissionError(c, err, notFound)
+		return false
+	}
+	return authorizeAuthorOrRepository(c, h.permissionService, repositoryID, comment.CreatorID, required, notFound)
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+)
+
+// ConvertToDiscussionRequest はIssueからDiscussionへの変換リクエストのデータ構造
+type ConvertToDiscussionRequest struct {
+	Category string `json:"category"` // 変換後のカテゴリ（省略時はgeneral）
+}
+
+// redirectConverted は変換済みのIssue・Discussionの場合に変換後のリソースへのリダイレクトを返します
+// レスポンスを返した場合はtrueを返します
+func redirectConverted(
+	c *gin.Context,
+	conversionService *services.ConversionService,
+	permissionService *services.RepositoryPermissionService,
+	sourceType string,
+	sourceID int64,
+) bool {
+	redirect, err := conversionService.Resolve(c.Request.Context(), sourceType, sourceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return true
+	}
+	if redirect == nil {
+		return false
+	}
+
+	respondRedirect(c, permissionService, redirect, fmt.Sprintf("/api/v1/%ss/%d", redirect.TargetType, redirect.TargetID))
+	return true
+}
+
+// redirectConvertedNumber はリポジトリ内の番号で指定された変換済みのIssue・Discussionの場合に
+// 変換後のリソースの番号のURLへのリダイレクトを返します。レスポンスを返した場合はtrueを返します
+func redirectConvertedNumber(
+	c *gin.Context,
+	conversionService *services.ConversionService,
+	permissionService *services.RepositoryPermissionService,
+	sourceType string,
+	number int64,
+) bool {
+	repo := getRepositoryScope(c)
+	redirect, err := conversionService.ResolveNumber(c.Request.Context(), repo.ID, sourceType, number)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return true
+	}
+	if redirect == nil {
+		return false
+	}
+
+	respondRedirect(c, permissionService, redirect, fmt.Sprintf("/api/v1/repos/%s/%ss/%d", repo.Name, redirect.TargetType, redirect.Number))
+	return true
+}
+
+// respondRedirect は閲覧権限を確認して変換後のリソースへの301レスポンスを返します
+func respondRedirect(c *gin.Context, permissionService *services.RepositoryPermissionService, redirect *models.Redirect, location string) {
+	notFound := "Issue not found"
+	if redirect.SourceType == models.RedirectTypeDiscussion {
+		notFound = "Discussion not found"
+	}
+	if !authorizeRepository(c, permissionService, redirect.RepositoryID, models.RepositoryRoleRead, notFound) {
+		return
+	}
+
+	c.Header("Location", location)
+	c.JSON(http.StatusMovedPermanently, gin.H{
+		"error":    fmt.Sprintf("This %s has moved to %s", redirect.SourceType, location),
+		"location": location,
+		"redirect": redirect,
+	})
+}
+
+// respondConversionError は変換のエラーに応じたレスポンスを返します
+func respondConversionError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidDiscussionCategory) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
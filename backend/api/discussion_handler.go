@@ -7,25 +7,48 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/shimauma0312/module-tickethub/backend/models"
 	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
 )
 
 // DiscussionHandler はDiscussion関連のハンドラーを管理する構造体
 type DiscussionHandler struct {
 	discussionRepo repositories.DiscussionRepository
+	commentRepo    repositories.CommentRepository
 	labelRepo      repositories.LabelRepository
 	userRepo       repositories.UserRepository
+	templateRepo   repositories.TemplateRepository
+	eventBus       *services.EventBus
+
+	reactionService   *services.ReactionService
+	revisionService   *services.RevisionService
+	conversionService *services.ConversionService
+	permissionService *services.RepositoryPermissionService
 }
 
 // NewDiscussionHandler は新しいDiscussionHandlerを作成します
 func NewDiscussionHandler(
 	discussionRepo repositories.DiscussionRepository,
+	commentRepo repositories.CommentRepository,
 	labelRepo repositories.LabelRepository,
 	userRepo repositories.UserRepository,
+	templateRepo repositories.TemplateRepository,
+	eventBus *services.EventBus,
+	reactionService *services.ReactionService,
+	revisionService *services.RevisionService,
+	conversionService *services.ConversionService,
+	permissionService *services.RepositoryPermissionService,
 ) *DiscussionHandler {
 	return &DiscussionHandler{
-		discussionRepo: discussionRepo,
-		labelRepo:      labelRepo,
-		userRepo:       userRepo,
+		discussionRepo:    discussionRepo,
+		commentRepo:       commentRepo,
+		labelRepo:         labelRepo,
+		userRepo:          userRepo,
+		templateRepo:      templateRepo,
+		eventBus:          eventBus,
+		reactionService:   reactionService,
+		revisionService:   revisionService,
+		conversionService: conversionService,
+		permissionService: permissionService,
 	}
 }
 
@@ -36,6 +59,10 @@ type DiscussionRequest struct {
 	Category string   `json:"category" binding:"required"`
 	Labels   []string `json:"labels"`
 	IsDraft  bool     `json:"is_draft"`
+
+	// 作成時のみ使用（テンプレートのフォームの送信内容は項目IDをキーに指定）
+	TemplateID int64                  `json:"template_id"`
+	Fields     map[string]interface{} `json:"fields"`
 }
 
 // @Summary Discussion一覧の取得
@@ -47,7 +74,9 @@ type DiscussionRequest struct {
 // @Param limit query int false "1ページあたりの件数" default(10)
 // @Param status query string false "ステータス (open/closed/answered)" default("open")
 // @Param category query string false "カテゴリ (general/question/announcement/idea)"
+// @Param unanswered query bool false "回答が選ばれていない質問のみ"
 // @Param label query string false "ラベル名"
+// @Param repository query string false "リポジトリ名"
 // @Success 200 {object} map[string]interface{}
 // @Router /api/v1/discussions [get]
 func (h *DiscussionHandler) ListDiscussions(c *gin.Context) {
@@ -67,8 +96,21 @@ func (h *DiscussionHandler) ListDiscussions(c *gin.Context) {
 		filter["category"] = category
 	}
 
-	// ラベルが指定されている場合は別途処理が必要
-	// （実際の実装ではリポジトリレイヤーでラベルフィルタリングを行う）
+	// リポジトリの絞り込み（指定がない場合は閲覧できるリポジトリに限定）
+	if !scopeRepositoryFilter(c, h.permissionService, filter) {
+		return
+	}
+
+	// 未回答の質問のみの場合
+	if unanswered, _ := strconv.ParseBool(c.Query("unanswered")); unanswered {
+		filter["category"] = "question"
+		filter["answer_comment_id"] = 0
+	}
+
+	// ラベルが指定されている場合
+	if label := c.Query("label"); label != "" {
+		filter["label"] = label
+	}
 
 	// データベースから取得
 	discussions, total, err := h.discussionRepo.List(c.Request.Context(), filter, page, limit)
@@ -77,6 +119,12 @@ func (h *DiscussionHandler) ListDiscussions(c *gin.Context) {
 		return
 	}
 
+	// リアクションの集計
+	if err := h.reactionService.AttachToDiscussions(c.Request.Context(), discussions, getUserIDFromContext(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"discussions": discussions,
 		"total":       total,
@@ -92,6 +140,7 @@ func (h *DiscussionHandler) ListDiscussions(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Discussion ID"
 // @Success 200 {object} models.Discussion
+// @Success 301 {object} map[string]interface{}
 // @Router /api/v1/discussions/{id} [get]
 func (h *DiscussionHandler) GetDiscussion(c *gin.Context) {
 	// IDの取得
@@ -103,8 +152,13 @@ func (h *DiscussionHandler) GetDiscussion(c *gin.Context) {
 
 	// データベースから取得
 	discussion, err := h.discussionRepo.GetByID(c.Request.Context(), id)
+
+	// Issueに変換済みの場合は変換後のIssueへリダイレクト
+	if (err != nil || discussion == nil) && redirectConverted(c, h.conversionService, h.permissionService, models.RedirectTypeDiscussion, id) {
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Discussion not found")
 		return
 	}
 
@@ -114,17 +168,81 @@ func (h *DiscussionHandler) GetDiscussion(c *gin.Context) {
 		return
 	}
 
+	// 閲覧できないリポジトリの場合
+	if !authorizeRepository(c, h.permissionService, discussion.RepositoryID, models.RepositoryRoleRead, "Discussion not found") {
+		return
+	}
+
+	h.respondDiscussion(c, discussion)
+}
+
+// @Summary リポジトリ内の番号によるDiscussionの取得
+// @Description 指定されたリポジトリ内の番号のDiscussionを取得します
+// @Tags discussions
+// @Accept json
+// @Produce json
+// @Param name path string true "リポジトリ名"
+// @Param number path int true "Discussion番号"
+// @Success 200 {object} models.Discussion
+// @Success 301 {object} map[string]interface{}
+// @Router /api/v1/repos/{name}/discussions/{number} [get]
+func (h *DiscussionHandler) GetDiscussionByNumber(c *gin.Context) {
+	// 番号の取得
+	number, err := strconv.ParseInt(c.Param("number"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid discussion number format"})
+		return
+	}
+
+	// データベースから取得
+	discussion, err := h.discussionRepo.GetByNumber(c.Request.Context(), getRepositoryScope(c).ID, number)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 見つからない場合（Issueに変換済みの場合は同じ番号のIssueへリダイレクト）
+	if discussion == nil {
+		if !redirectConvertedNumber(c, h.conversionService, h.permissionService, models.RedirectTypeDiscussion, number) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Discussion not found"})
+		}
+		return
+	}
+
+	h.respondDiscussion(c, discussion)
+}
+
+// respondDiscussion は回答者とリアクションの集計を設定してDiscussionを返します
+func (h *DiscussionHandler) respondDiscussion(c *gin.Context, discussion *models.Discussion) {
+	// 回答者の取得
+	if discussion.AnswerCommentID > 0 {
+		answeredBy, err := h.getAnswerer(c, discussion.AnswerCommentID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		discussion.AnsweredBy = answeredBy
+	}
+
+	// リアクションの集計
+	if err := h.reactionService.AttachToDiscussions(c.Request.Context(), []*models.Discussion{discussion}, getUserIDFromContext(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, discussion)
 }
 
 // @Summary Discussionの作成
-// @Description 新しいDiscussionを作成します
+// @Description 新しいDiscussionを作成します（リポジトリの指定がない場合は既定のリポジトリに作成し、リポジトリ内の番号を採番します）
 // @Tags discussions
 // @Accept json
 // @Produce json
+// @Param repository query string false "リポジトリ名"
 // @Param discussion body DiscussionRequest true "Discussion情報"
 // @Success 201 {object} models.Discussion
 // @Router /api/v1/discussions [post]
+// @Router /api/v1/repos/{name}/discussions [post]
 func (h *DiscussionHandler) CreateDiscussion(c *gin.Context) {
 	// ユーザーIDの取得
 	userID, exists := c.Get("user_id")
@@ -140,14 +258,39 @@ func (h *DiscussionHandler) CreateDiscussion(c *gin.Context) {
 		return
 	}
 
+	// テンプレートとフォームの送信内容の確認（カテゴリごとのテンプレートのみ使用可能）
+	template, ok := applyTemplate(c, h.templateRepo, h.labelRepo, getRepositoryScope(c).ID, models.TemplateTypeDiscussion, req.TemplateID, req.Fields)
+	if !ok {
+		return
+	}
+	if template != nil && template.Category != req.Category {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Template is for the '" + template.Category + "' category"})
+		return
+	}
+
 	// Discussionの作成
 	discussion := models.NewDiscussion(req.Title, req.Body, req.Category, userID.(int64))
+	discussion.RepositoryID = getRepositoryScope(c).ID
 	discussion.IsDraft = req.IsDraft
 
+	// テンプレートの適用（タイトルの接頭辞・本文・既定のラベル）
+	if template != nil {
+		discussion.Title = template.ApplyTitle(discussion.Title)
+		discussion.Body = template.RenderBody(discussion.Body, req.Fields)
+		for _, label := range template.DefaultLabels {
+			discussion.AddLabel(label)
+		}
+	}
+
 	// ラベルの設定
 	for _, label := range req.Labels {
 		discussion.AddLabel(label)
 	}
+	labels, ok := checkLabels(c, h.labelRepo, discussion.RepositoryID, nil, discussion.Labels, models.LabelTypeDiscussion)
+	if !ok {
+		return
+	}
+	discussion.Labels = labels
 
 	// データベースに保存
 	err := h.discussionRepo.Create(c.Request.Context(), discussion)
@@ -156,6 +299,9 @@ func (h *DiscussionHandler) CreateDiscussion(c *gin.Context) {
 		return
 	}
 
+	// イベントの発行
+	h.eventBus.Publish(c.Request.Context(), services.NewDiscussionEvent(services.EventDiscussionCreated, userID.(int64), discussion))
+
 	c.JSON(http.StatusCreated, discussion)
 }
 
@@ -186,7 +332,7 @@ func (h *DiscussionHandler) UpdateDiscussion(c *gin.Context) {
 	// データベースから取得
 	discussion, err := h.discussionRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Discussion not found")
 		return
 	}
 
@@ -196,10 +342,8 @@ func (h *DiscussionHandler) UpdateDiscussion(c *gin.Context) {
 		return
 	}
 
-	// 作成者のみ編集可能（または管理者）
-	isAdmin, _ := c.Get("is_admin")
-	if discussion.CreatorID != userID.(int64) && !(isAdmin != nil && isAdmin.(bool)) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to update this discussion"})
+	// 作成者またはmaintain以上のロールを持つユーザーのみ編集可能
+	if !authorizeAuthorOrRepository(c, h.permissionService, discussion.RepositoryID, discussion.CreatorID, models.RepositoryRoleMaintain, "Discussion not found") {
 		return
 	}
 
@@ -211,6 +355,7 @@ func (h *DiscussionHandler) UpdateDiscussion(c *gin.Context) {
 	}
 
 	// Discussionの更新
+	previousBody := discussion.Body
 	discussion.Title = req.Title
 	discussion.Body = req.Body
 	discussion.Category = req.Category
@@ -218,10 +363,16 @@ func (h *DiscussionHandler) UpdateDiscussion(c *gin.Context) {
 	discussion.UpdatedAt = models.CurrentTime()
 
 	// ラベルの更新
+	previousLabels := discussion.Labels
 	discussion.Labels = []string{} // 既存のラベルをクリア
 	for _, label := range req.Labels {
 		discussion.AddLabel(label)
 	}
+	labels, ok := checkLabels(c, h.labelRepo, discussion.RepositoryID, previousLabels, discussion.Labels, models.LabelTypeDiscussion)
+	if !ok {
+		return
+	}
+	discussion.Labels = labels
 
 	// データベースに保存
 	err = h.discussionRepo.Update(c.Request.Context(), discussion)
@@ -230,6 +381,15 @@ func (h *DiscussionHandler) UpdateDiscussion(c *gin.Context) {
 		return
 	}
 
+	// 本文の編集履歴の記録
+	if err := h.revisionService.Record(c.Request.Context(), models.RevisionTargetDiscussion, discussion.ID, previousBody, discussion.CreatorID, discussion.CreatedAt, discussion.Body, userID.(int64)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// イベントの発行
+	h.eventBus.Publish(c.Request.Context(), services.NewDiscussionEvent(services.EventDiscussionUpdated, userID.(int64), discussion))
+
 	c.JSON(http.StatusOK, discussion)
 }
 
@@ -243,7 +403,7 @@ func (h *DiscussionHandler) UpdateDiscussion(c *gin.Context) {
 // @Router /api/v1/discussions/{id} [delete]
 func (h *DiscussionHandler) DeleteDiscussion(c *gin.Context) {
 	// ユーザーIDの取得
-	userID, exists := c.Get("user_id")
+	_, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
@@ -259,7 +419,7 @@ func (h *DiscussionHandler) DeleteDiscussion(c *gin.Context) {
 	// データベースから取得
 	discussion, err := h.discussionRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Discussion not found")
 		return
 	}
 
@@ -269,10 +429,20 @@ func (h *DiscussionHandler) DeleteDiscussion(c *gin.Context) {
 		return
 	}
 
-	// 作成者のみ削除可能（または管理者）
-	isAdmin, _ := c.Get("is_admin")
-	if discussion.CreatorID != userID.(int64) && !(isAdmin != nil && isAdmin.(bool)) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to delete this discussion"})
+	// 作成者またはmaintain以上のロールを持つユーザーのみ削除可能
+	if !authorizeAuthorOrRepository(c, h.permissionService, discussion.RepositoryID, discussion.CreatorID, models.RepositoryRoleMaintain, "Discussion not found") {
+		return
+	}
+
+	// リアクションの削除
+	if err := h.reactionService.RemoveAll(c.Request.Context(), models.ReactionTargetDiscussion, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 編集履歴の削除
+	if err := h.revisionService.RemoveAll(c.Request.Context(), models.RevisionTargetDiscussion, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -286,6 +456,51 @@ func (h *DiscussionHandler) DeleteDiscussion(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Discussion deleted successfully"})
 }
 
+// @Summary DiscussionのIssueへの変換
+// @Description 指定されたIDのDiscussionを同じ番号のIssueに変換します。本文・ラベル・コメント（返信を含む）・リアクション・編集履歴を引き継ぎ、変換前のIDは変換後のIssueにリダイレクトされます
+// @Tags discussions
+// @Accept json
+// @Produce json
+// @Param id path int true "Discussion ID"
+// @Success 201 {object} services.ConversionResult
+// @Router /api/v1/discussions/{id}/convert-to-issue [post]
+func (h *DiscussionHandler) ConvertToIssue(c *gin.Context) {
+	// ユーザーIDの取得
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// IDの取得
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid discussion ID format"})
+		return
+	}
+
+	// データベースから取得
+	discussion, err := h.discussionRepo.GetByID(c.Request.Context(), id)
+	if err != nil || discussion == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Discussion not found"})
+		return
+	}
+
+	// 作成者またはtriage以上のロールを持つユーザーのみ変換可能
+	if !authorizeAuthorOrRepository(c, h.permissionService, discussion.RepositoryID, discussion.CreatorID, models.RepositoryRoleTriage, "Discussion not found") {
+		return
+	}
+
+	// Issueへの変換
+	result, err := h.conversionService.DiscussionToIssue(c.Request.Context(), discussion, userID.(int64))
+	if err != nil {
+		respondConversionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
 // @Summary Discussionのステータス変更
 // @Description 指定されたIDのDiscussionのステータスを変更します
 // @Tags discussions
@@ -306,7 +521,7 @@ func (h *DiscussionHandler) UpdateDiscussionStatus(c *gin.Context) {
 	// データベースから取得
 	discussion, err := h.discussionRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Discussion not found")
 		return
 	}
 
@@ -316,6 +531,11 @@ func (h *DiscussionHandler) UpdateDiscussionStatus(c *gin.Context) {
 		return
 	}
 
+	// 作成者またはtriage以上のロールを持つユーザーのみステータスを変更可能
+	if !authorizeAuthorOrRepository(c, h.permissionService, discussion.RepositoryID, discussion.CreatorID, models.RepositoryRoleTriage, "Discussion not found") {
+		return
+	}
+
 	// リクエストの解析
 	var req struct {
 		Status string `json:"status" binding:"required"`
@@ -359,7 +579,7 @@ func (h *DiscussionHandler) UpdateDiscussionStatus(c *gin.Context) {
 // @Router /api/v1/discussions/{id}/draft [patch]
 func (h *DiscussionHandler) UpdateDiscussionDraftStatus(c *gin.Context) {
 	// ユーザーIDの取得
-	userID, exists := c.Get("user_id")
+	_, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
@@ -375,7 +595,7 @@ func (h *DiscussionHandler) UpdateDiscussionDraftStatus(c *gin.Context) {
 	// データベースから取得
 	discussion, err := h.discussionRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Discussion not found")
 		return
 	}
 
@@ -385,10 +605,8 @@ func (h *DiscussionHandler) UpdateDiscussionDraftStatus(c *gin.Context) {
 		return
 	}
 
-	// 作成者のみ編集可能（または管理者）
-	isAdmin, _ := c.Get("is_admin")
-	if discussion.CreatorID != userID.(int64) && !(isAdmin != nil && isAdmin.(bool)) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to update this discussion"})
+	// 作成者またはmaintain以上のロールを持つユーザーのみ編集可能
+	if !authorizeAuthorOrRepository(c, h.permissionService, discussion.RepositoryID, discussion.CreatorID, models.RepositoryRoleMaintain, "Discussion not found") {
 		return
 	}
 
@@ -422,6 +640,7 @@ func (h *DiscussionHandler) UpdateDiscussionDraftStatus(c *gin.Context) {
 // @Param q query string true "検索クエリ"
 // @Param page query int false "ページ番号" default(1)
 // @Param limit query int false "1ページあたりの件数" default(10)
+// @Param repository query string false "リポジトリ名"
 // @Success 200 {object} map[string]interface{}
 // @Router /api/v1/discussions/search [get]
 func (h *DiscussionHandler) SearchDiscussions(c *gin.Context) {
@@ -435,13 +654,25 @@ func (h *DiscussionHandler) SearchDiscussions(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
+	// リポジトリの絞り込み（指定がない場合は閲覧できるリポジトリに限定）
+	filter := map[string]interface{}{}
+	if !scopeRepositoryFilter(c, h.permissionService, filter) {
+		return
+	}
+
 	// データベースから検索
-	discussions, total, err := h.discussionRepo.Search(c.Request.Context(), query, page, limit)
+	discussions, total, err := h.discussionRepo.Search(c.Request.Context(), query, filter, page, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	// リアクションの集計
+	if err := h.reactionService.AttachToDiscussions(c.Request.Context(), discussions, getUserIDFromContext(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"discussions": discussions,
 		"total":       total,
@@ -450,3 +681,164 @@ func (h *DiscussionHandler) SearchDiscussions(c *gin.Context) {
 		"query":       query,
 	})
 }
+
+// AnswerRequest は回答コメントの選択リクエストのデータ構造
+type AnswerRequest struct {
+	CommentID int64 `json:"comment_id" binding:"required"`
+}
+
+// @Summary 回答コメントの選択
+// @Description 質問カテゴリのDiscussionで、トップレベルのコメントを回答として選び回答済みにします（作成者または管理者のみ）
+// @Tags discussions
+// @Accept json
+// @Produce json
+// @Param id path int true "Discussion ID"
+// @Param answer body AnswerRequest true "回答コメント情報"
+// @Success 200 {object} models.Discussion
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/discussions/{id}/answer [put]
+func (h *DiscussionHandler) MarkAnswer(c *gin.Context) {
+	discussion, ok := h.getAnswerableDiscussion(c)
+	if !ok {
+		return
+	}
+
+	// リクエストの解析
+	var req AnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// コメントの存在確認
+	comment, err := h.commentRepo.GetByID(c.Request.Context(), req.CommentID)
+	if isRecordNotFound(err) || (err == nil && comment == nil) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Discussionのトップレベルのコメントのみ回答にできる
+	if comment.IsReply() || comment.Type != "discussion" || comment.TargetID != discussion.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only a top-level comment on this discussion can be marked as the answer"})
+		return
+	}
+
+	// 回答の選択
+	discussion.MarkAnswer(comment.ID)
+
+	// データベースに保存
+	if err := h.discussionRepo.Update(c.Request.Context(), discussion); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 回答者の取得
+	answeredBy, err := h.getAnswerer(c, comment.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	discussion.AnsweredBy = answeredBy
+
+	c.JSON(http.StatusOK, discussion)
+}
+
+// @Summary 回答コメントの選択解除
+// @Description 回答として選ばれたコメントの選択を解除し、Discussionをオープンに戻します（作成者または管理者のみ）
+// @Tags discussions
+// @Accept json
+// @Produce json
+// @Param id path int true "Discussion ID"
+// @Success 200 {object} models.Discussion
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/discussions/{id}/answer [delete]
+func (h *DiscussionHandler) UnmarkAnswer(c *gin.Context) {
+	discussion, ok := h.getAnswerableDiscussion(c)
+	if !ok {
+		return
+	}
+
+	if discussion.AnswerCommentID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Discussion has no answer"})
+		return
+	}
+
+	// 回答の選択解除
+	discussion.UnmarkAnswer()
+
+	// データベースに保存
+	if err := h.discussionRepo.Update(c.Request.Context(), discussion); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, discussion)
+}
+
+// getAnswerableDiscussion はログインユーザーが回答を選択できる質問カテゴリのDiscussionを取得します
+func (h *DiscussionHandler) getAnswerableDiscussion(c *gin.Context) (*models.Discussion, bool) {
+	userID := getUserIDFromContext(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, false
+	}
+
+	// IDの取得
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid discussion ID format"})
+		return nil, false
+	}
+
+	// データベースから取得
+	discussion, err := h.discussionRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondFetchError(c, err, "Discussion not found")
+		return nil, false
+	}
+
+	// 見つからない場合
+	if discussion == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Discussion not found"})
+		return nil, false
+	}
+
+	// 作成者またはtriage以上のロールを持つユーザーのみ選択可能
+	if !authorizeAuthorOrRepository(c, h.permissionService, discussion.RepositoryID, discussion.CreatorID, models.RepositoryRoleTriage, "Discussion not found") {
+		return nil, false
+	}
+
+	if !discussion.IsQuestion() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only discussions in the 'question' category can have an answer"})
+		return nil, false
+	}
+
+	return discussion, true
+}
+
+// getAnswerer は回答コメントの投稿者を取得します（回答コメントが削除されている場合はnil）
+func (h *DiscussionHandler) getAnswerer(c *gin.Context, commentID int64) (*models.DiscussionAnswerer, error) {
+	comment, err := h.commentRepo.GetByID(c.Request.Context(), commentID)
+	if isRecordNotFound(err) || (err == nil && comment == nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := h.userRepo.GetByID(c.Request.Context(), comment.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DiscussionAnswerer{
+		UserID:    user.ID,
+		Username:  user.Username,
+		AvatarURL: user.AvatarURL,
+	}, nil
+}
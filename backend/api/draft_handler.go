@@ -7,22 +7,29 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/shimauma0312/module-tickethub/backend/models"
 	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
 )
 
 // DraftHandler はドラフト機能のハンドラーを管理する構造体
 type DraftHandler struct {
-	issueRepo      repositories.IssueRepository
-	discussionRepo repositories.DiscussionRepository
+	issueRepo         repositories.IssueRepository
+	discussionRepo    repositories.DiscussionRepository
+	labelRepo         repositories.LabelRepository
+	permissionService *services.RepositoryPermissionService
 }
 
 // NewDraftHandler は新しいDraftHandlerを作成します
 func NewDraftHandler(
 	issueRepo repositories.IssueRepository,
 	discussionRepo repositories.DiscussionRepository,
+	labelRepo repositories.LabelRepository,
+	permissionService *services.RepositoryPermissionService,
 ) *DraftHandler {
 	return &DraftHandler{
-		issueRepo:      issueRepo,
-		discussionRepo: discussionRepo,
+		issueRepo:         issueRepo,
+		discussionRepo:    discussionRepo,
+		labelRepo:         labelRepo,
+		permissionService: permissionService,
 	}
 }
 
@@ -31,7 +38,8 @@ type IssueDraftRequest struct {
 	Title       string   `json:"title"`
 	Body        string   `json:"body"`
 	Labels      []string `json:"labels"`
-	AssigneeID  int64    `json:"assignee_id"`
+	AssigneeID  int64    `json:"assignee_id"` // 旧クライアント用（assignee_ids がない場合に使用）
+	AssigneeIDs []int64  `json:"assignee_ids"`
 	MilestoneID int64    `json:"milestone_id"`
 }
 
@@ -84,7 +92,7 @@ func (h *DraftHandler) SaveIssueDraft(c *gin.Context) {
 		// データベースから取得
 		issue, err = h.issueRepo.GetByID(c.Request.Context(), id)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondFetchError(c, err, "Issue not found")
 			return
 		}
 
@@ -94,21 +102,20 @@ func (h *DraftHandler) SaveIssueDraft(c *gin.Context) {
 			return
 		}
 
-		// 作成者のみ編集可能（または管理者）
-		isAdmin, _ := c.Get("is_admin")
-		if issue.CreatorID != userID.(int64) && !(isAdmin != nil && isAdmin.(bool)) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to update this issue"})
+		// 作成者またはmaintain以上のロールを持つユーザーのみ編集可能
+		if !authorizeAuthorOrRepository(c, h.permissionService, issue.RepositoryID, issue.CreatorID, models.RepositoryRoleMaintain, "Issue not found") {
 			return
 		}
 
 		// Issueの更新
+		before := issue.Clone()
 		if req.Title != "" {
 			issue.Title = req.Title
 		}
 		if req.Body != "" {
 			issue.Body = req.Body
 		}
-		issue.AssigneeID = req.AssigneeID
+		applyAssigneeRequest(issue, req.AssigneeIDs, req.AssigneeID)
 		issue.MilestoneID = req.MilestoneID
 		issue.IsDraft = true
 		issue.UpdatedAt = models.CurrentTime()
@@ -119,10 +126,15 @@ func (h *DraftHandler) SaveIssueDraft(c *gin.Context) {
 			for _, label := range req.Labels {
 				issue.AddLabel(label)
 			}
+			labels, ok := checkLabels(c, h.labelRepo, issue.RepositoryID, before.Labels, issue.Labels, models.LabelTypeIssue)
+			if !ok {
+				return
+			}
+			issue.Labels = labels
 		}
 
-		// データベースに保存
-		err = h.issueRepo.Update(c.Request.Context(), issue)
+		// データベースに保存（変更履歴も記録）
+		err = h.issueRepo.UpdateWithEvents(c.Request.Context(), issue, models.DiffIssueEvents(before, issue, userID.(int64)))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -135,14 +147,20 @@ func (h *DraftHandler) SaveIssueDraft(c *gin.Context) {
 		}
 
 		issue = models.NewIssue(title, req.Body, userID.(int64))
+		issue.RepositoryID = getRepositoryScope(c).ID
 		issue.IsDraft = true
-		issue.AssigneeID = req.AssigneeID
+		applyAssigneeRequest(issue, req.AssigneeIDs, req.AssigneeID)
 		issue.MilestoneID = req.MilestoneID
 
 		// ラベルの設定
 		for _, label := range req.Labels {
 			issue.AddLabel(label)
 		}
+		labels, ok := checkLabels(c, h.labelRepo, issue.RepositoryID, nil, issue.Labels, models.LabelTypeIssue)
+		if !ok {
+			return
+		}
+		issue.Labels = labels
 
 		// データベースに保存
 		err = h.issueRepo.Create(c.Request.Context(), issue)
@@ -196,7 +214,7 @@ func (h *DraftHandler) SaveDiscussionDraft(c *gin.Context) {
 		// データベースから取得
 		discussion, err = h.discussionRepo.GetByID(c.Request.Context(), id)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondFetchError(c, err, "Discussion not found")
 			return
 		}
 
@@ -206,10 +224,8 @@ func (h *DraftHandler) SaveDiscussionDraft(c *gin.Context) {
 			return
 		}
 
-		// 作成者のみ編集可能（または管理者）
-		isAdmin, _ := c.Get("is_admin")
-		if discussion.CreatorID != userID.(int64) && !(isAdmin != nil && isAdmin.(bool)) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to update this discussion"})
+		// 作成者またはmaintain以上のロールを持つユーザーのみ編集可能
+		if !authorizeAuthorOrRepository(c, h.permissionService, discussion.RepositoryID, discussion.CreatorID, models.RepositoryRoleMaintain, "Discussion not found") {
 			return
 		}
 
@@ -228,10 +244,16 @@ func (h *DraftHandler) SaveDiscussionDraft(c *gin.Context) {
 
 		// ラベルの更新（指定されている場合のみ）
 		if req.Labels != nil {
+			previousLabels := discussion.Labels
 			discussion.Labels = []string{} // 既存のラベルをクリア
 			for _, label := range req.Labels {
 				discussion.AddLabel(label)
 			}
+			labels, ok := checkLabels(c, h.labelRepo, discussion.RepositoryID, previousLabels, discussion.Labels, models.LabelTypeDiscussion)
+			if !ok {
+				return
+			}
+			discussion.Labels = labels
 		}
 
 		// データベースに保存
@@ -253,12 +275,18 @@ func (h *DraftHandler) SaveDiscussionDraft(c *gin.Context) {
 		}
 
 		discussion = models.NewDiscussion(title, req.Body, category, userID.(int64))
+		discussion.RepositoryID = getRepositoryScope(c).ID
 		discussion.IsDraft = true
 
 		// ラベルの設定
 		for _, label := range req.Labels {
 			discussion.AddLabel(label)
 		}
+		labels, ok := checkLabels(c, h.labelRepo, discussion.RepositoryID, nil, discussion.Labels, models.LabelTypeDiscussion)
+		if !ok {
+			return
+		}
+		discussion.Labels = labels
 
 		// データベースに保存
 		err = h.discussionRepo.Create(c.Request.Context(), discussion)
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+)
+
+// EmailVerificationHandler はメールアドレスの確認関連のハンドラーを管理する構造体
+type EmailVerificationHandler struct {
+	emailVerificationService *services.EmailVerificationService
+	activityService          *services.ActivityLogService
+}
+
+// NewEmailVerificationHandler は新しいEmailVerificationHandlerを作成します
+func NewEmailVerificationHandler(emailVerificationService *services.EmailVerificationService, activityService *services.ActivityLogService) *EmailVerificationHandler {
+	return &EmailVerificationHandler{
+		emailVerificationService: emailVerificationService,
+		activityService:          activityService,
+	}
+}
+
+// VerifyEmailRequest はメールアドレスの確認リクエストのデータ構造
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// VerifyEmail はメールアドレスの確認ハンドラー
+// @Summary メールアドレスの確認
+// @Description 確認メールに記載したトークンでメールアドレスを確認済みにします
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body VerifyEmailRequest true "確認トークン"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /auth/verify-email [post]
+func (h *EmailVerificationHandler) VerifyEmail(c *gin.Context) {
+	// リクエストの解析
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.emailVerificationService.Verify(c.Request.Context(), req.Token)
+	if err != nil {
+		if errors.Is(err, services.ErrEmailVerificationTokenInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired verification token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify email address"})
+		return
+	}
+
+	// アクティビティログに記録
+	h.activityService.LogActivity(
+		c.Request.Context(),
+		user.ID,
+		user.Username,
+		models.ActionUserEmailVerified,
+		models.ResourceUser,
+		user.ID,
+		c.ClientIP(),
+		c.GetHeader("User-Agent"),
+		map[string]interface{}{"email": user.Email},
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Email address verified successfully",
+		"email_verified": true,
+	})
+}
+
+// ResendVerificationEmail は確認メールの再送信ハンドラー
+// @Summary 確認メールの再送信
+// @Description ログイン中のユーザーに確認メールを再送信します。以前に送信したリンクは無効になります
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]interface{}
+// @Router /auth/verify-email/resend [post]
+func (h *EmailVerificationHandler) ResendVerificationEmail(c *gin.Context) {
+	err := h.emailVerificationService.Resend(c.Request.Context(), getUserIDFromContext(c))
+	if err != nil {
+		var rateLimited *services.EmailVerificationRateLimitError
+		switch {
+		case errors.As(err, &rateLimited):
+			retryAfter := int(math.Ceil(rateLimited.RetryAfter.Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":       "Too many verification emails requested",
+				"retry_after": retryAfter,
+			})
+		case errors.Is(err, services.ErrEmailAlreadyVerified):
+			c.JSON(http.StatusConflict, gin.H{"error": "Email address is already verified"})
+		case errors.Is(err, services.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send verification email"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Verification email sent"})
+}
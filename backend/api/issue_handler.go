@@ -1,12 +1,15 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
+	"regexp"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/shimauma0312/module-tickethub/backend/models"
 	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
 )
 
 // IssueHandler はIssue関連のハンドラーを管理する構造体
@@ -15,6 +18,16 @@ type IssueHandler struct {
 	labelRepo     repositories.LabelRepository
 	milestoneRepo repositories.MilestoneRepository
 	userRepo      repositories.UserRepository
+	commentRepo   repositories.CommentRepository
+	eventRepo     repositories.IssueEventRepository
+	templateRepo  repositories.TemplateRepository
+	eventBus      *services.EventBus
+
+	reactionService   *services.ReactionService
+	revisionService   *services.RevisionService
+	linkService       *services.IssueLinkService
+	conversionService *services.ConversionService
+	permissionService *services.RepositoryPermissionService
 }
 
 // NewIssueHandler は新しいIssueHandlerを作成します
@@ -23,12 +36,30 @@ func NewIssueHandler(
 	labelRepo repositories.LabelRepository,
 	milestoneRepo repositories.MilestoneRepository,
 	userRepo repositories.UserRepository,
+	commentRepo repositories.CommentRepository,
+	eventRepo repositories.IssueEventRepository,
+	templateRepo repositories.TemplateRepository,
+	eventBus *services.EventBus,
+	reactionService *services.ReactionService,
+	revisionService *services.RevisionService,
+	linkService *services.IssueLinkService,
+	conversionService *services.ConversionService,
+	permissionService *services.RepositoryPermissionService,
 ) *IssueHandler {
 	return &IssueHandler{
-		issueRepo:     issueRepo,
-		labelRepo:     labelRepo,
-		milestoneRepo: milestoneRepo,
-		userRepo:      userRepo,
+		issueRepo:         issueRepo,
+		labelRepo:         labelRepo,
+		milestoneRepo:     milestoneRepo,
+		userRepo:          userRepo,
+		commentRepo:       commentRepo,
+		eventRepo:         eventRepo,
+		templateRepo:      templateRepo,
+		eventBus:          eventBus,
+		reactionService:   reactionService,
+		revisionService:   revisionService,
+		linkService:       linkService,
+		conversionService: conversionService,
+		permissionService: permissionService,
 	}
 }
 
@@ -37,9 +68,14 @@ type IssueRequest struct {
 	Title       string   `json:"title" binding:"required"`
 	Body        string   `json:"body"`
 	Labels      []string `json:"labels"`
-	AssigneeID  int64    `json:"assignee_id"`
+	AssigneeID  int64    `json:"assignee_id"` // 旧クライアント用（assignee_ids がない場合に使用）
+	AssigneeIDs []int64  `json:"assignee_ids"`
 	MilestoneID int64    `json:"milestone_id"`
 	IsDraft     bool     `json:"is_draft"`
+
+	// 作成時のみ使用（テンプレートのフォームの送信内容は項目IDをキーに指定）
+	TemplateID int64                  `json:"template_id"`
+	Fields     map[string]interface{} `json:"fields"`
 }
 
 // @Summary Issue一覧の取得
@@ -51,8 +87,9 @@ type IssueRequest struct {
 // @Param limit query int false "1ページあたりの件数" default(10)
 // @Param status query string false "ステータス (open/closed)" default("open")
 // @Param label query string false "ラベル名"
-// @Param assignee query int false "担当者ID"
+// @Param assignee query int false "担当者ID（いずれかの担当者に一致）"
 // @Param milestone query int false "マイルストーンID"
+// @Param repository query string false "リポジトリ名"
 // @Success 200 {object} map[string]interface{}
 // @Router /api/v1/issues [get]
 func (h *IssueHandler) ListIssues(c *gin.Context) {
@@ -60,15 +97,17 @@ func (h *IssueHandler) ListIssues(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	status := c.DefaultQuery("status", "open")
-	
-	// projectIDStr := c.Query("project_id")
-	// var projectID *int64
 
 	// フィルタの作成
 	filter := map[string]interface{}{
 		"status": status,
 	}
 
+	// リポジトリの絞り込み（指定がない場合は閲覧できるリポジトリに限定）
+	if !scopeRepositoryFilter(c, h.permissionService, filter) {
+		return
+	}
+
 	// 担当者IDが指定されている場合
 	assigneeIDStr := c.Query("assignee")
 	if assigneeIDStr != "" {
@@ -87,15 +126,6 @@ func (h *IssueHandler) ListIssues(c *gin.Context) {
 		}
 	}
 
-	// プロジェクトIDが指定されている場合
-	// if projectIDStr != "" {
-	// 	projectIDParsed, err := strconv.ParseInt(projectIDStr, 10, 64)
-	// 	if err == nil && projectIDParsed > 0 {
-	// 		projectID = &projectIDParsed
-	// 		filter["project_id"] = projectIDParsed
-	// 	}
-	// }
-
 	// ラベルが指定されている場合は別途処理が必要
 	// （実際の実装ではリポジトリレイヤーでラベルフィルタリングを行う）
 
@@ -106,6 +136,18 @@ func (h *IssueHandler) ListIssues(c *gin.Context) {
 		return
 	}
 
+	// リアクションの集計
+	if err := h.reactionService.AttachToIssues(c.Request.Context(), issues, getUserIDFromContext(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 子のIssueの進捗の集計
+	if err := h.linkService.AttachSubIssueProgress(c.Request.Context(), issues); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"issues": issues,
 		"total":  total,
@@ -121,6 +163,7 @@ func (h *IssueHandler) ListIssues(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Issue ID"
 // @Success 200 {object} models.Issue
+// @Success 301 {object} map[string]interface{}
 // @Router /api/v1/issues/{id} [get]
 func (h *IssueHandler) GetIssue(c *gin.Context) {
 	// IDの取得
@@ -132,28 +175,156 @@ func (h *IssueHandler) GetIssue(c *gin.Context) {
 
 	// データベースから取得
 	issue, err := h.issueRepo.GetByID(c.Request.Context(), id)
+
+	// Discussionに変換済みの場合は変換後のDiscussionへリダイレクト
+	if (err != nil || issue == nil) && redirectConverted(c, h.conversionService, h.permissionService, models.RedirectTypeIssue, id) {
+		return
+	}
+	if err != nil {
+		respondFetchError(c, err, "Issue not found")
+		return
+	}
+
+	// 見つからない場合
+	if issue == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		return
+	}
+
+	// 閲覧できないリポジトリの場合
+	if !authorizeRepository(c, h.permissionService, issue.RepositoryID, models.RepositoryRoleRead, "Issue not found") {
+		return
+	}
+
+	h.respondIssue(c, issue)
+}
+
+// @Summary リポジトリ内の番号によるIssueの取得
+// @Description 指定されたリポジトリ内の番号のIssueを取得します
+// @Tags issues
+// @Accept json
+// @Produce json
+// @Param name path string true "リポジトリ名"
+// @Param number path int true "Issue番号"
+// @Success 200 {object} models.Issue
+// @Success 301 {object} map[string]interface{}
+// @Router /api/v1/repos/{name}/issues/{number} [get]
+func (h *IssueHandler) GetIssueByNumber(c *gin.Context) {
+	// 番号の取得
+	number, err := strconv.ParseInt(c.Param("number"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid issue number format"})
+		return
+	}
+
+	// データベースから取得
+	issue, err := h.issueRepo.GetByNumber(c.Request.Context(), getRepositoryScope(c).ID, number)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	// 見つからない場合（Discussionに変換済みの場合は同じ番号のDiscussionへリダイレクト）
+	if issue == nil {
+		if !redirectConvertedNumber(c, h.conversionService, h.permissionService, models.RedirectTypeIssue, number) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		}
+		return
+	}
+
+	h.respondIssue(c, issue)
+}
+
+// @Summary Issueのタイムラインの取得
+// @Description 指定されたIDのIssueの変更履歴（クローズ・ラベル・担当者・マイルストーンの変更など）とコメントを時系列順に取得します
+// @Tags issues
+// @Accept json
+// @Produce json
+// @Param id path int true "Issue ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/issues/{id}/timeline [get]
+func (h *IssueHandler) GetIssueTimeline(c *gin.Context) {
+	// IDの取得
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid issue ID format"})
+		return
+	}
+
+	// データベースから取得
+	issue, err := h.issueRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondFetchError(c, err, "Issue not found")
+		return
+	}
+
 	// 見つからない場合
 	if issue == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
 		return
 	}
 
+	// 閲覧できないリポジトリの場合
+	if !authorizeRepository(c, h.permissionService, issue.RepositoryID, models.RepositoryRoleRead, "Issue not found") {
+		return
+	}
+
+	// 変更履歴とコメントの取得
+	events, err := h.eventRepo.ListByIssue(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	comments, err := h.commentRepo.ListAllByTarget(c.Request.Context(), id, "issue")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// リアクションの集計
+	if err := h.reactionService.AttachToComments(c.Request.Context(), comments, getUserIDFromContext(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	timeline := models.BuildTimeline(events, comments)
+	c.JSON(http.StatusOK, gin.H{
+		"issue_id": id,
+		"timeline": timeline,
+		"total":    len(timeline),
+	})
+}
+
+// respondIssue はリアクションの集計と関連グラフを設定してIssueを返します
+func (h *IssueHandler) respondIssue(c *gin.Context, issue *models.Issue) {
+	// リアクションの集計
+	if err := h.reactionService.AttachToIssues(c.Request.Context(), []*models.Issue{issue}, getUserIDFromContext(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 関連グラフの取得
+	links, err := h.linkService.Graph(c.Request.Context(), issue)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	issue.Links = links
+	issue.SubIssueProgress = links.SubIssueProgress()
+
 	c.JSON(http.StatusOK, issue)
 }
 
 // @Summary Issueの作成
-// @Description 新しいIssueを作成します
+// @Description 新しいIssueを作成します（リポジトリの指定がない場合は既定のリポジトリに作成し、リポジトリ内の番号を採番します）
 // @Tags issues
 // @Accept json
 // @Produce json
+// @Param repository query string false "リポジトリ名"
 // @Param issue body IssueRequest true "Issue情報"
 // @Success 201 {object} models.Issue
 // @Router /api/v1/issues [post]
+// @Router /api/v1/repos/{name}/issues [post]
 func (h *IssueHandler) CreateIssue(c *gin.Context) {
 	// ユーザーIDの取得
 	userID, exists := c.Get("user_id")
@@ -169,16 +340,45 @@ func (h *IssueHandler) CreateIssue(c *gin.Context) {
 		return
 	}
 
+	// テンプレートとフォームの送信内容の確認
+	template, ok := applyTemplate(c, h.templateRepo, h.labelRepo, getRepositoryScope(c).ID, models.TemplateTypeIssue, req.TemplateID, req.Fields)
+	if !ok {
+		return
+	}
+
 	// Issueの作成
 	issue := models.NewIssue(req.Title, req.Body, userID.(int64))
+	issue.RepositoryID = getRepositoryScope(c).ID
 	issue.IsDraft = req.IsDraft
-	issue.AssigneeID = req.AssigneeID
+	applyAssigneeRequest(issue, req.AssigneeIDs, req.AssigneeID)
 	issue.MilestoneID = req.MilestoneID
 
+	// テンプレートの適用（タイトルの接頭辞・本文・既定のラベルと担当者）
+	if template != nil {
+		issue.Title = template.ApplyTitle(issue.Title)
+		issue.Body = template.RenderBody(issue.Body, req.Fields)
+		for _, label := range template.DefaultLabels {
+			issue.AddLabel(label)
+		}
+		if len(issue.Assignees()) == 0 && template.DefaultAssigneeID > 0 {
+			issue.SetAssignees([]int64{template.DefaultAssigneeID})
+		}
+	}
+
+	// マイルストーンの確認
+	if !h.checkMilestone(c, issue) {
+		return
+	}
+
 	// ラベルの設定
 	for _, label := range req.Labels {
 		issue.AddLabel(label)
 	}
+	labels, ok := checkLabels(c, h.labelRepo, issue.RepositoryID, nil, issue.Labels, models.LabelTypeIssue)
+	if !ok {
+		return
+	}
+	issue.Labels = labels
 
 	// データベースに保存
 	err := h.issueRepo.Create(c.Request.Context(), issue)
@@ -187,6 +387,9 @@ func (h *IssueHandler) CreateIssue(c *gin.Context) {
 		return
 	}
 
+	// イベントの発行
+	h.eventBus.Publish(c.Request.Context(), services.NewIssueEvent(services.EventIssueCreated, userID.(int64), issue))
+
 	c.JSON(http.StatusCreated, issue)
 }
 
@@ -217,7 +420,7 @@ func (h *IssueHandler) UpdateIssue(c *gin.Context) {
 	// データベースから取得
 	issue, err := h.issueRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Issue not found")
 		return
 	}
 
@@ -227,10 +430,8 @@ func (h *IssueHandler) UpdateIssue(c *gin.Context) {
 		return
 	}
 
-	// 作成者のみ編集可能（または管理者）
-	isAdmin, _ := c.Get("is_admin")
-	if issue.CreatorID != userID.(int64) && !(isAdmin != nil && isAdmin.(bool)) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to update this issue"})
+	// 作成者またはmaintain以上のロールを持つユーザーのみ編集可能
+	if !authorizeAuthorOrRepository(c, h.permissionService, issue.RepositoryID, issue.CreatorID, models.RepositoryRoleMaintain, "Issue not found") {
 		return
 	}
 
@@ -242,26 +443,49 @@ func (h *IssueHandler) UpdateIssue(c *gin.Context) {
 	}
 
 	// Issueの更新
+	before := issue.Clone()
 	issue.Title = req.Title
 	issue.Body = req.Body
 	issue.IsDraft = req.IsDraft
-	issue.AssigneeID = req.AssigneeID
+	applyAssigneeRequest(issue, req.AssigneeIDs, req.AssigneeID)
 	issue.MilestoneID = req.MilestoneID
 	issue.UpdatedAt = models.CurrentTime()
 
+	// マイルストーンの確認
+	if !h.checkMilestone(c, issue) {
+		return
+	}
+
 	// ラベルの更新
 	issue.Labels = []string{} // 既存のラベルをクリア
 	for _, label := range req.Labels {
 		issue.AddLabel(label)
 	}
+	labels, ok := checkLabels(c, h.labelRepo, issue.RepositoryID, before.Labels, issue.Labels, models.LabelTypeIssue)
+	if !ok {
+		return
+	}
+	issue.Labels = labels
 
-	// データベースに保存
-	err = h.issueRepo.Update(c.Request.Context(), issue)
+	// データベースに保存（変更履歴も記録）
+	err = h.issueRepo.UpdateWithEvents(c.Request.Context(), issue, models.DiffIssueEvents(before, issue, userID.(int64)))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	// 本文の編集履歴の記録
+	if err := h.revisionService.Record(c.Request.Context(), models.RevisionTargetIssue, issue.ID, before.Body, issue.CreatorID, issue.CreatedAt, issue.Body, userID.(int64)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// イベントの発行
+	h.eventBus.Publish(c.Request.Context(), services.NewIssueEvent(services.EventIssueUpdated, userID.(int64), issue))
+	if added := models.AddedAssignees(before, issue); len(added) > 0 {
+		h.eventBus.Publish(c.Request.Context(), services.NewIssueAssignedEvent(userID.(int64), issue, added))
+	}
+
 	c.JSON(http.StatusOK, issue)
 }
 
@@ -275,7 +499,7 @@ func (h *IssueHandler) UpdateIssue(c *gin.Context) {
 // @Router /api/v1/issues/{id} [delete]
 func (h *IssueHandler) DeleteIssue(c *gin.Context) {
 	// ユーザーIDの取得
-	userID, exists := c.Get("user_id")
+	_, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
@@ -291,7 +515,7 @@ func (h *IssueHandler) DeleteIssue(c *gin.Context) {
 	// データベースから取得
 	issue, err := h.issueRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Issue not found")
 		return
 	}
 
@@ -301,10 +525,26 @@ func (h *IssueHandler) DeleteIssue(c *gin.Context) {
 		return
 	}
 
-	// 作成者のみ削除可能（または管理者）
-	isAdmin, _ := c.Get("is_admin")
-	if issue.CreatorID != userID.(int64) && !(isAdmin != nil && isAdmin.(bool)) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to delete this issue"})
+	// 作成者またはmaintain以上のロールを持つユーザーのみ削除可能
+	if !authorizeAuthorOrRepository(c, h.permissionService, issue.RepositoryID, issue.CreatorID, models.RepositoryRoleMaintain, "Issue not found") {
+		return
+	}
+
+	// リアクションの削除
+	if err := h.reactionService.RemoveAll(c.Request.Context(), models.ReactionTargetIssue, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 編集履歴の削除
+	if err := h.revisionService.RemoveAll(c.Request.Context(), models.RevisionTargetIssue, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 他のIssueとの関連の削除
+	if err := h.linkService.RemoveAll(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -318,14 +558,74 @@ func (h *IssueHandler) DeleteIssue(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Issue deleted successfully"})
 }
 
+// @Summary IssueのDiscussionへの変換
+// @Description 指定されたIDのIssueを同じ番号のDiscussionに変換します。本文・ラベル・コメント（返信を含む）・リアクション・編集履歴を引き継ぎ、変換前のIDは変換後のDiscussionにリダイレクトされます
+// @Tags issues
+// @Accept json
+// @Produce json
+// @Param id path int true "Issue ID"
+// @Param request body ConvertToDiscussionRequest false "変換後のカテゴリ"
+// @Success 201 {object} services.ConversionResult
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/issues/{id}/convert-to-discussion [post]
+func (h *IssueHandler) ConvertToDiscussion(c *gin.Context) {
+	// ユーザーIDの取得
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// IDの取得
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid issue ID format"})
+		return
+	}
+
+	// リクエストの解析（本文は省略可能）
+	var req ConvertToDiscussionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Category == "" {
+		req.Category = "general"
+	}
+
+	// データベースから取得
+	issue, err := h.issueRepo.GetByID(c.Request.Context(), id)
+	if err != nil || issue == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		return
+	}
+
+	// 作成者またはtriage以上のロールを持つユーザーのみ変換可能
+	if !authorizeAuthorOrRepository(c, h.permissionService, issue.RepositoryID, issue.CreatorID, models.RepositoryRoleTriage, "Issue not found") {
+		return
+	}
+
+	// Discussionへの変換
+	result, err := h.conversionService.IssueToDiscussion(c.Request.Context(), issue, req.Category, userID.(int64))
+	if err != nil {
+		respondConversionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
 // @Summary Issueのステータス変更
-// @Description 指定されたIDのIssueのステータスを変更します
+// @Description 指定されたIDのIssueのステータスを変更します（オープンなブロッカーが残っている場合は force を指定しない限りクローズできません）
 // @Tags issues
 // @Accept json
 // @Produce json
 // @Param id path int true "Issue ID"
-// @Param status body map[string]string true "ステータス情報"
+// @Param status body map[string]interface{} true "ステータス情報"
 // @Success 200 {object} models.Issue
+// @Failure 409 {object} map[string]interface{}
 // @Router /api/v1/issues/{id}/status [patch]
 func (h *IssueHandler) UpdateIssueStatus(c *gin.Context) {
 	// IDの取得
@@ -338,7 +638,7 @@ func (h *IssueHandler) UpdateIssueStatus(c *gin.Context) {
 	// データベースから取得
 	issue, err := h.issueRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Issue not found")
 		return
 	}
 
@@ -348,9 +648,15 @@ func (h *IssueHandler) UpdateIssueStatus(c *gin.Context) {
 		return
 	}
 
+	// 作成者またはtriage以上のロールを持つユーザーのみステータスを変更可能
+	if !authorizeAuthorOrRepository(c, h.permissionService, issue.RepositoryID, issue.CreatorID, models.RepositoryRoleTriage, "Issue not found") {
+		return
+	}
+
 	// リクエストの解析
 	var req struct {
 		Status string `json:"status" binding:"required"`
+		Force  bool   `json:"force"` // オープンなブロッカーが残っていてもクローズする
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -358,26 +664,182 @@ func (h *IssueHandler) UpdateIssueStatus(c *gin.Context) {
 	}
 
 	// ステータスの更新
+	before := issue.Clone()
 	switch req.Status {
 	case "open":
 		issue.Reopen()
 	case "closed":
+		// オープンなブロッカーの確認
+		if issue.Status != "closed" && !req.Force {
+			blockers, err := h.linkService.OpenBlockers(c.Request.Context(), issue)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			if len(blockers) > 0 {
+				c.JSON(http.StatusConflict, gin.H{
+					"error":         "Issue is blocked by open issues. Set force to close it anyway",
+					"open_blockers": blockers,
+				})
+				return
+			}
+		}
 		issue.Close()
 	default:
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Must be 'open' or 'closed'"})
 		return
 	}
 
-	// データベースに保存
-	err = h.issueRepo.Update(c.Request.Context(), issue)
+	// データベースに保存（変更履歴も記録）
+	err = h.issueRepo.UpdateWithEvents(c.Request.Context(), issue, models.DiffIssueEvents(before, issue, getUserIDFromContext(c)))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	// ステータスが変更された場合はイベントを発行
+	if issue.Status != before.Status {
+		h.eventBus.Publish(c.Request.Context(), services.NewIssueEvent(services.EventIssueStatusChanged, getUserIDFromContext(c), issue))
+	}
+
 	c.JSON(http.StatusOK, issue)
 }
 
+// @Summary Issueのチェックリストの取得
+// @Description 指定されたIDのIssueの本文のチェックリスト項目と完了数を取得します
+// @Tags issues
+// @Accept json
+// @Produce json
+// @Param id path int true "Issue ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/issues/{id}/tasks [get]
+func (h *IssueHandler) ListIssueTasks(c *gin.Context) {
+	// IDの取得
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid issue ID format"})
+		return
+	}
+
+	// データベースから取得
+	issue, err := h.issueRepo.GetByID(c.Request.Context(), id)
+	if err != nil || issue == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		return
+	}
+
+	// 閲覧できないリポジトリの場合
+	if !authorizeRepository(c, h.permissionService, issue.RepositoryID, models.RepositoryRoleRead, "Issue not found") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"issue_id": issue.ID,
+		"tasks":    models.ParseTaskList(issue.Body),
+		"progress": issue.TaskProgress,
+	})
+}
+
+// @Summary チェックリスト項目の子Issueへの変換
+// @Description 本文の指定された番号（0始まり）のチェックリスト項目から子のIssueを作成し、項目をそのIssueへの参照（#番号）に置き換えます
+// @Tags issues
+// @Accept json
+// @Produce json
+// @Param id path int true "親のIssue ID"
+// @Param index path int true "チェックリスト項目の番号"
+// @Success 201 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/issues/{id}/tasks/{index}/convert [post]
+func (h *IssueHandler) ConvertTaskToIssue(c *gin.Context) {
+	// ユーザーIDの取得
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// IDの取得
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid issue ID format"})
+		return
+	}
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task index format"})
+		return
+	}
+
+	// データベースから取得
+	parent, err := h.issueRepo.GetByID(c.Request.Context(), id)
+	if err != nil || parent == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		return
+	}
+
+	// 本文を書き換えるため、作成者またはmaintain以上のロールを持つユーザーのみ変換可能
+	if !authorizeAuthorOrRepository(c, h.permissionService, parent.RepositoryID, parent.CreatorID, models.RepositoryRoleMaintain, "Issue not found") {
+		return
+	}
+
+	// チェックリスト項目の取得
+	tasks := models.ParseTaskList(parent.Body)
+	if index < 0 || index >= len(tasks) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task list item not found"})
+		return
+	}
+	task := tasks[index]
+	if issueReferencePattern.MatchString(task.Text) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Task list item already references an issue"})
+		return
+	}
+
+	// 子のIssueの作成（チェック済みの項目はクローズ済みとして作成）
+	child := models.NewIssue(task.Text, "", userID.(int64))
+	child.RepositoryID = parent.RepositoryID
+	if task.Checked {
+		child.Close()
+	}
+	if err := h.issueRepo.Create(c.Request.Context(), child); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.eventBus.Publish(c.Request.Context(), services.NewIssueEvent(services.EventIssueCreated, userID.(int64), child))
+
+	// 親子関係の追加
+	link, err := h.linkService.Create(c.Request.Context(), parent, child.ID, models.IssueLinkParentOf, userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 親の本文の項目を子のIssueへの参照に置き換え
+	before := parent.Clone()
+	parent.Body, err = models.ReplaceTaskListItemText(parent.Body, index, fmt.Sprintf("#%d", child.Number))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.issueRepo.UpdateWithEvents(c.Request.Context(), parent, models.DiffIssueEvents(before, parent, userID.(int64))); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 本文の編集履歴の記録
+	if err := h.revisionService.Record(c.Request.Context(), models.RevisionTargetIssue, parent.ID, before.Body, parent.CreatorID, parent.CreatedAt, parent.Body, userID.(int64)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.eventBus.Publish(c.Request.Context(), services.NewIssueEvent(services.EventIssueUpdated, userID.(int64), parent))
+
+	c.JSON(http.StatusCreated, gin.H{
+		"issue":  child,
+		"parent": parent,
+		"link":   link,
+	})
+}
+
 // @Summary Issueのドラフト状態変更
 // @Description 指定されたIDのIssueのドラフト状態を変更します
 // @Tags issues
@@ -389,7 +851,7 @@ func (h *IssueHandler) UpdateIssueStatus(c *gin.Context) {
 // @Router /api/v1/issues/{id}/draft [patch]
 func (h *IssueHandler) UpdateIssueDraftStatus(c *gin.Context) {
 	// ユーザーIDの取得
-	userID, exists := c.Get("user_id")
+	_, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
@@ -405,7 +867,7 @@ func (h *IssueHandler) UpdateIssueDraftStatus(c *gin.Context) {
 	// データベースから取得
 	issue, err := h.issueRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Issue not found")
 		return
 	}
 
@@ -415,10 +877,8 @@ func (h *IssueHandler) UpdateIssueDraftStatus(c *gin.Context) {
 		return
 	}
 
-	// 作成者のみ編集可能（または管理者）
-	isAdmin, _ := c.Get("is_admin")
-	if issue.CreatorID != userID.(int64) && !(isAdmin != nil && isAdmin.(bool)) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to update this issue"})
+	// 作成者またはmaintain以上のロールを持つユーザーのみ編集可能
+	if !authorizeAuthorOrRepository(c, h.permissionService, issue.RepositoryID, issue.CreatorID, models.RepositoryRoleMaintain, "Issue not found") {
 		return
 	}
 
@@ -432,11 +892,12 @@ func (h *IssueHandler) UpdateIssueDraftStatus(c *gin.Context) {
 	}
 
 	// ドラフト状態の更新
+	before := issue.Clone()
 	issue.IsDraft = req.IsDraft
 	issue.UpdatedAt = models.CurrentTime()
 
-	// データベースに保存
-	err = h.issueRepo.Update(c.Request.Context(), issue)
+	// データベースに保存（変更履歴も記録）
+	err = h.issueRepo.UpdateWithEvents(c.Request.Context(), issue, models.DiffIssueEvents(before, issue, getUserIDFromContext(c)))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -453,6 +914,7 @@ func (h *IssueHandler) UpdateIssueDraftStatus(c *gin.Context) {
 // @Param q query string true "検索クエリ"
 // @Param page query int false "ページ番号" default(1)
 // @Param limit query int false "1ページあたりの件数" default(10)
+// @Param repository query string false "リポジトリ名"
 // @Success 200 {object} map[string]interface{}
 // @Router /api/v1/issues/search [get]
 func (h *IssueHandler) SearchIssues(c *gin.Context) {
@@ -466,13 +928,31 @@ func (h *IssueHandler) SearchIssues(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
+	// リポジトリの絞り込み（指定がない場合は閲覧できるリポジトリに限定）
+	filter := map[string]interface{}{}
+	if !scopeRepositoryFilter(c, h.permissionService, filter) {
+		return
+	}
+
 	// データベースから検索
-	issues, total, err := h.issueRepo.Search(c.Request.Context(), query, page, limit)
+	issues, total, err := h.issueRepo.Search(c.Request.Context(), query, filter, page, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	// リアクションの集計
+	if err := h.reactionService.AttachToIssues(c.Request.Context(), issues, getUserIDFromContext(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 子のIssueの進捗の集計
+	if err := h.linkService.AttachSubIssueProgress(c.Request.Context(), issues); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"issues": issues,
 		"total":  total,
@@ -481,3 +961,20 @@ func (h *IssueHandler) SearchIssues(c *gin.Context) {
 		"query":  query,
 	})
 }
+
+// issueReferencePattern はIssueへの参照（#番号）のみからなるチェックリスト項目に一致する正規表現
+var issueReferencePattern = regexp.MustCompile(`^#\d+$`)
+
+// checkMilestone はIssueのマイルストーンが同じリポジトリに属しているかを確認し、不正な場合はエラーレスポンスを返します
+func (h *IssueHandler) checkMilestone(c *gin.Context, issue *models.Issue) bool {
+	if issue.MilestoneID == 0 {
+		return true
+	}
+
+	milestone, err := h.milestoneRepo.GetByID(c.Request.Context(), issue.MilestoneID)
+	if err != nil || milestone.RepositoryID != issue.RepositoryID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Milestone not found in this repository"})
+		return false
+	}
+	return true
+}
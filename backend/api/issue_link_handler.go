@@ -0,0 +1,169 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+)
+
+// IssueLinkHandler はIssue間の関連のハンドラーを管理する構造体
+type IssueLinkHandler struct {
+	issueRepo         repositories.IssueRepository
+	linkService       *services.IssueLinkService
+	permissionService *services.RepositoryPermissionService
+}
+
+// NewIssueLinkHandler は新しいIssueLinkHandlerを作成します
+func NewIssueLinkHandler(
+	issueRepo repositories.IssueRepository,
+	linkService *services.IssueLinkService,
+	permissionService *services.RepositoryPermissionService,
+) *IssueLinkHandler {
+	return &IssueLinkHandler{
+		issueRepo:         issueRepo,
+		linkService:       linkService,
+		permissionService: permissionService,
+	}
+}
+
+// IssueLinkRequest は関連の追加リクエストの構造体
+type IssueLinkRequest struct {
+	IssueID int64                `json:"issue_id" binding:"required"` // 関連先のIssue ID
+	Type    models.IssueLinkType `json:"type" binding:"required"`     // このIssueから見た関連の種類
+}
+
+// RegisterRoutes はIssue間の関連のルートを登録します
+func (h *IssueLinkHandler) RegisterRoutes(router *gin.RouterGroup, authRouter *gin.RouterGroup) {
+	router.GET("/issues/:id/links", h.ListLinks)
+	authRouter.POST("/issues/:id/links", h.CreateLink)
+	authRouter.DELETE("/issues/:id/links/:link_id", h.DeleteLink)
+}
+
+// @Summary Issueの関連グラフの取得
+// @Description 指定されたIDのIssueと直接関連するIssueからなる関連グラフを取得します
+// @Tags issue-links
+// @Accept json
+// @Produce json
+// @Param id path int true "Issue ID"
+// @Success 200 {object} models.IssueLinkGraph
+// @Router /api/v1/issues/{id}/links [get]
+func (h *IssueLinkHandler) ListLinks(c *gin.Context) {
+	issue, ok := h.authorizeIssue(c, models.RepositoryRoleRead)
+	if !ok {
+		return
+	}
+
+	graph, err := h.linkService.Graph(c.Request.Context(), issue)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, graph)
+}
+
+// @Summary Issueの関連の追加
+// @Description 同じリポジトリ内のIssueとの関連（blocks/blocked_by/duplicates/duplicated_by/relates_to/parent_of/child_of）を追加します
+// @Tags issue-links
+// @Accept json
+// @Produce json
+// @Param id path int true "Issue ID"
+// @Param link body IssueLinkRequest true "関連情報"
+// @Success 201 {object} models.IssueLink
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/issues/{id}/links [post]
+func (h *IssueLinkHandler) CreateLink(c *gin.Context) {
+	// 作成者またはtriage以上のロールを持つユーザーのみ関連を変更可能
+	issue, ok := h.authorizeIssue(c, models.RepositoryRoleTriage)
+	if !ok {
+		return
+	}
+
+	// リクエストの解析
+	var req IssueLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// データベースに保存
+	link, err := h.linkService.Create(c.Request.Context(), issue, req.IssueID, req.Type, getUserIDFromContext(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidIssueLinkType), errors.Is(err, models.ErrIssueLinkToSelf), errors.Is(err, services.ErrIssueLinkTargetNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrIssueLinkExists), errors.Is(err, services.ErrIssueLinkCycle), errors.Is(err, services.ErrIssueLinkParentExists):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// @Summary Issueの関連の削除
+// @Description 指定されたIDのIssueに含まれる関連を削除します
+// @Tags issue-links
+// @Accept json
+// @Produce json
+// @Param id path int true "Issue ID"
+// @Param link_id path int true "関連ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/issues/{id}/links/{link_id} [delete]
+func (h *IssueLinkHandler) DeleteLink(c *gin.Context) {
+	// 作成者またはtriage以上のロールを持つユーザーのみ関連を変更可能
+	issue, ok := h.authorizeIssue(c, models.RepositoryRoleTriage)
+	if !ok {
+		return
+	}
+
+	// 関連IDの取得
+	linkID, err := strconv.ParseInt(c.Param("link_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid link ID format"})
+		return
+	}
+
+	// データベースから削除
+	if err := h.linkService.Delete(c.Request.Context(), issue.ID, linkID); err != nil {
+		if errors.Is(err, services.ErrIssueLinkNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Issue link not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Issue link deleted successfully"})
+}
+
+// authorizeIssue はIssueの存在と権限を確認し、Issueを返します
+// 閲覧以外は作成者であれば指定されたロールがなくても許可します
+func (h *IssueLinkHandler) authorizeIssue(c *gin.Context, role models.RepositoryRole) (*models.Issue, bool) {
+	// IDの取得
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid issue ID format"})
+		return nil, false
+	}
+
+	// データベースから取得
+	issue, err := h.issueRepo.GetByID(c.Request.Context(), id)
+	if err != nil || issue == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		return nil, false
+	}
+
+	if role == models.RepositoryRoleRead {
+		return issue, authorizeRepository(c, h.permissionService, issue.RepositoryID, role, "Issue not found")
+	}
+	return issue, authorizeAuthorOrRepository(c, h.permissionService, issue.RepositoryID, issue.CreatorID, role, "Issue not found")
+}
@@ -1,23 +1,27 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/shimauma0312/module-tickethub/backend/models"
 	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
 )
 
 // LabelHandler はLabel関連のハンドラーを管理する構造体
 type LabelHandler struct {
-	labelRepo repositories.LabelRepository
+	labelRepo         repositories.LabelRepository
+	permissionService *services.RepositoryPermissionService
 }
 
 // NewLabelHandler は新しいLabelHandlerを作成します
-func NewLabelHandler(labelRepo repositories.LabelRepository) *LabelHandler {
+func NewLabelHandler(labelRepo repositories.LabelRepository, permissionService *services.RepositoryPermissionService) *LabelHandler {
 	return &LabelHandler{
-		labelRepo: labelRepo,
+		labelRepo:         labelRepo,
+		permissionService: permissionService,
 	}
 }
 
@@ -27,6 +31,7 @@ type LabelRequest struct {
 	Description string `json:"description"`
 	Color       string `json:"color" binding:"required"`
 	Type        string `json:"type" binding:"required"` // issue/discussion/both
+	Exclusive   bool   `json:"exclusive"`               // 同じスコープ（priority/high の priority）の排他的なラベルを1つだけ付与する
 }
 
 // @Summary ラベル一覧の取得
@@ -37,19 +42,29 @@ type LabelRequest struct {
 // @Param page query int false "ページ番号" default(1)
 // @Param limit query int false "1ページあたりの件数" default(50)
 // @Param type query string false "ラベルタイプ (issue/discussion/both)"
+// @Param scope query string false "スコープ"
+// @Param repository query string false "リポジトリ名"
 // @Success 200 {object} map[string]interface{}
 // @Router /api/v1/labels [get]
+// @Router /api/v1/repos/{name}/labels [get]
 func (h *LabelHandler) ListLabels(c *gin.Context) {
 	// クエリパラメータの取得
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
 	labelType := c.Query("type")
+	scope := c.Query("scope")
 
 	// フィルタの作成
 	filter := map[string]interface{}{}
 	if labelType != "" {
 		filter["type"] = labelType
 	}
+	if scope != "" {
+		filter["scope"] = scope
+	}
+	if !scopeRepositoryFilter(c, h.permissionService, filter) {
+		return
+	}
 
 	// データベースから取得
 	labels, total, err := h.labelRepo.List(c.Request.Context(), filter, page, limit)
@@ -66,6 +81,40 @@ func (h *LabelHandler) ListLabels(c *gin.Context) {
 	})
 }
 
+// @Summary スコープごとのラベル一覧の取得
+// @Description スコープ（priority/high の priority）を持つラベルをスコープごとにまとめて取得します
+// @Tags labels
+// @Accept json
+// @Produce json
+// @Param type query string false "ラベルタイプ (issue/discussion/both)"
+// @Param repository query string false "リポジトリ名"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/labels/scopes [get]
+// @Router /api/v1/repos/{name}/labels/scopes [get]
+func (h *LabelHandler) ListLabelScopes(c *gin.Context) {
+	// フィルタの作成
+	filter := map[string]interface{}{}
+	if labelType := c.Query("type"); labelType != "" {
+		filter["type"] = labelType
+	}
+	if !scopeRepositoryFilter(c, h.permissionService, filter) {
+		return
+	}
+
+	// データベースから取得
+	labels, err := h.labelRepo.ListScoped(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	scopes := models.GroupLabelsByScope(labels)
+	c.JSON(http.StatusOK, gin.H{
+		"scopes": scopes,
+		"total":  len(scopes),
+	})
+}
+
 // @Summary ラベルの取得
 // @Description 指定されたIDのラベルを取得します
 // @Tags labels
@@ -85,7 +134,7 @@ func (h *LabelHandler) GetLabel(c *gin.Context) {
 	// データベースから取得
 	label, err := h.labelRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Label not found")
 		return
 	}
 
@@ -95,22 +144,28 @@ func (h *LabelHandler) GetLabel(c *gin.Context) {
 		return
 	}
 
+	// 閲覧できないリポジトリの場合
+	if !authorizeRepository(c, h.permissionService, label.RepositoryID, models.RepositoryRoleRead, "Label not found") {
+		return
+	}
+
 	c.JSON(http.StatusOK, label)
 }
 
 // @Summary ラベルの作成
-// @Description 新しいラベルを作成します
+// @Description 新しいラベルを作成します（ラベル名はリポジトリごとに一意です）
 // @Tags labels
 // @Accept json
 // @Produce json
+// @Param repository query string false "リポジトリ名"
 // @Param label body LabelRequest true "ラベル情報"
 // @Success 201 {object} models.Label
 // @Router /api/v1/labels [post]
+// @Router /api/v1/repos/{name}/labels [post]
 func (h *LabelHandler) CreateLabel(c *gin.Context) {
-	// 管理者権限の確認
-	isAdmin, exists := c.Get("is_admin")
-	if !exists || !isAdmin.(bool) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Admin privileges required"})
+	// write以上のロールの確認
+	repo := getRepositoryScope(c)
+	if !authorizeRepository(c, h.permissionService, repo.ID, models.RepositoryRoleWrite, "Repository not found") {
 		return
 	}
 
@@ -121,9 +176,9 @@ func (h *LabelHandler) CreateLabel(c *gin.Context) {
 		return
 	}
 
-	// 同名ラベルの存在確認
-	existingLabel, err := h.labelRepo.GetByName(c.Request.Context(), req.Name, req.Type)
-	if err != nil && err.Error() != "label not found" {
+	// 同名ラベルの存在確認（リポジトリ内）
+	existingLabel, err := h.labelRepo.GetByName(c.Request.Context(), repo.ID, req.Name, req.Type)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -134,6 +189,8 @@ func (h *LabelHandler) CreateLabel(c *gin.Context) {
 
 	// ラベルの作成
 	label := models.NewLabel(req.Name, req.Description, req.Color, req.Type)
+	label.RepositoryID = repo.ID
+	label.Exclusive = req.Exclusive
 
 	// 検証
 	if !label.IsValid() {
@@ -152,7 +209,7 @@ func (h *LabelHandler) CreateLabel(c *gin.Context) {
 }
 
 // @Summary ラベルの更新
-// @Description 指定されたIDのラベルを更新します
+// @Description 指定されたIDのラベルを更新します（名前の変更は付与済みのIssue・Discussionにも反映されます）
 // @Tags labels
 // @Accept json
 // @Produce json
@@ -161,13 +218,6 @@ func (h *LabelHandler) CreateLabel(c *gin.Context) {
 // @Success 200 {object} models.Label
 // @Router /api/v1/labels/{id} [put]
 func (h *LabelHandler) UpdateLabel(c *gin.Context) {
-	// 管理者権限の確認
-	isAdmin, exists := c.Get("is_admin")
-	if !exists || !isAdmin.(bool) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Admin privileges required"})
-		return
-	}
-
 	// IDの取得
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -178,7 +228,7 @@ func (h *LabelHandler) UpdateLabel(c *gin.Context) {
 	// データベースから取得
 	label, err := h.labelRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Label not found")
 		return
 	}
 
@@ -188,6 +238,11 @@ func (h *LabelHandler) UpdateLabel(c *gin.Context) {
 		return
 	}
 
+	// write以上のロールの確認
+	if !authorizeRepository(c, h.permissionService, label.RepositoryID, models.RepositoryRoleWrite, "Label not found") {
+		return
+	}
+
 	// リクエストの解析
 	var req LabelRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -195,10 +250,10 @@ func (h *LabelHandler) UpdateLabel(c *gin.Context) {
 		return
 	}
 
-	// 同名ラベルの存在確認（更新対象以外）
+	// 同名ラベルの存在確認（リポジトリ内の更新対象以外）
 	if req.Name != label.Name {
-		existingLabel, err := h.labelRepo.GetByName(c.Request.Context(), req.Name, req.Type)
-		if err != nil && err.Error() != "label not found" {
+		existingLabel, err := h.labelRepo.GetByName(c.Request.Context(), label.RepositoryID, req.Name, req.Type)
+		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
@@ -209,7 +264,7 @@ func (h *LabelHandler) UpdateLabel(c *gin.Context) {
 	}
 
 	// ラベルの更新
-	label.Update(req.Name, req.Description, req.Color, req.Type)
+	label.Update(req.Name, req.Description, req.Color, req.Type, req.Exclusive)
 
 	// 検証
 	if !label.IsValid() {
@@ -217,6 +272,17 @@ func (h *LabelHandler) UpdateLabel(c *gin.Context) {
 		return
 	}
 
+	// 付与済みの対象に付与できなくなるタイプの変更は不可
+	issues, discussions, err := h.labelRepo.CountUsage(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if (issues > 0 && !label.AppliesTo(models.LabelTypeIssue)) || (discussions > 0 && !label.AppliesTo(models.LabelTypeDiscussion)) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Label type conflicts with issues or discussions it is applied to"})
+		return
+	}
+
 	// データベースに保存
 	err = h.labelRepo.Update(c.Request.Context(), label)
 	if err != nil {
@@ -228,7 +294,7 @@ func (h *LabelHandler) UpdateLabel(c *gin.Context) {
 }
 
 // @Summary ラベルの削除
-// @Description 指定されたIDのラベルを削除します
+// @Description 指定されたIDのラベルを削除し、付与済みのIssue・Discussionからも取り除きます
 // @Tags labels
 // @Accept json
 // @Produce json
@@ -236,13 +302,6 @@ func (h *LabelHandler) UpdateLabel(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Router /api/v1/labels/{id} [delete]
 func (h *LabelHandler) DeleteLabel(c *gin.Context) {
-	// 管理者権限の確認
-	isAdmin, exists := c.Get("is_admin")
-	if !exists || !isAdmin.(bool) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Admin privileges required"})
-		return
-	}
-
 	// IDの取得
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -253,7 +312,7 @@ func (h *LabelHandler) DeleteLabel(c *gin.Context) {
 	// データベースから取得
 	label, err := h.labelRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Label not found")
 		return
 	}
 
@@ -263,8 +322,13 @@ func (h *LabelHandler) DeleteLabel(c *gin.Context) {
 		return
 	}
 
-	// データベースから削除
-	err = h.labelRepo.Delete(c.Request.Context(), id)
+	// write以上のロールの確認
+	if !authorizeRepository(c, h.permissionService, label.RepositoryID, models.RepositoryRoleWrite, "Label not found") {
+		return
+	}
+
+	// データベースから削除（取り除いたIssueの変更履歴も記録）
+	err = h.labelRepo.DeleteWithEvents(c.Request.Context(), id, getUserIDFromContext(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -272,3 +336,48 @@ func (h *LabelHandler) DeleteLabel(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Label deleted successfully"})
 }
+
+// checkLabels はラベルがリポジトリに存在し、対象（issue/discussion）に付与できるかを確認し、不正な場合はエラーレスポンスを返します
+// 排他的なスコープのラベルが追加された場合は、同じスコープの以前から付与されていたラベルを外したラベルの一覧を返します
+func checkLabels(c *gin.Context, labelRepo repositories.LabelRepository, repositoryID int64, previous, names []string, targetType string) ([]string, bool) {
+	if len(names) == 0 {
+		return names, true
+	}
+
+	labels, err := labelRepo.ListByNames(c.Request.Context(), repositoryID, names)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	// 名前ごとに付与するラベルを決定（同じ名前の場合は対象の種類と一致するラベルを優先）
+	applicable := make(map[string]*models.Label, len(names))
+	for _, name := range names {
+		found := false
+		for _, label := range labels {
+			if label.Name != name {
+				continue
+			}
+			found = true
+			if existing, ok := applicable[name]; label.AppliesTo(targetType) && (!ok || (existing.Type != targetType && label.Type == targetType)) {
+				applicable[name] = label
+			}
+		}
+		if !found {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Label %q not found in this repository", name)})
+			return nil, false
+		}
+		if _, ok := applicable[name]; !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Label %q cannot be applied to %ss", name, targetType)})
+			return nil, false
+		}
+	}
+
+	// 排他的なスコープのラベルの解決
+	resolved, err := models.ResolveExclusiveLabels(previous, names, applicable)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return resolved, true
+}
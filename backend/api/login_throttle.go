@@ -0,0 +1,70 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+)
+
+// respondLoginThrottled はログインの試行を制限している場合に 429 Too Many Requests と Retry-After ヘッダーを返します
+func respondLoginThrottled(c *gin.Context, err *services.LoginThrottledError) {
+	retryAfter := int(math.Ceil(err.RetryAfter.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	locked := errors.Is(err, services.ErrAccountLocked)
+	message := "Too many failed login attempts"
+	if locked {
+		message = "Account is temporarily locked due to too many failed login attempts"
+	}
+
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       message,
+		"locked":      locked,
+		"retry_after": retryAfter,
+	})
+}
+
+// logLoginFailure はログインの失敗をアクティビティログに記録し、この失敗でアカウントをロックした場合はロックも記録します
+// 存在しないアカウントの場合 user はnilで、入力されたユーザー名またはメールアドレスを記録します
+func logLoginFailure(c *gin.Context, activityService *services.ActivityLogService, user *models.User, usernameOrEmail string, err error, reason string) {
+	var userID int64
+	username := usernameOrEmail
+	if user != nil {
+		userID = user.ID
+		username = user.Username
+	}
+
+	activityService.LogActivity(
+		c.Request.Context(),
+		userID,
+		username,
+		models.ActionUserLoginFailed,
+		models.ResourceUser,
+		userID,
+		c.ClientIP(),
+		c.GetHeader("User-Agent"),
+		map[string]interface{}{
+			"reason": reason,
+		},
+	)
+	if errors.Is(err, services.ErrAccountLocked) {
+		activityService.LogActivity(
+			c.Request.Context(),
+			userID,
+			username,
+			models.ActionUserLocked,
+			models.ResourceUser,
+			userID,
+			c.ClientIP(),
+			c.GetHeader("User-Agent"),
+			nil,
+		)
+	}
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+)
+
+// MentionHandler はメンション関連のハンドラーを管理する構造体
+type MentionHandler struct {
+	mentionService *services.MentionService
+}
+
+// NewMentionHandler は新しいMentionHandlerを作成します
+func NewMentionHandler(mentionService *services.MentionService) *MentionHandler {
+	return &MentionHandler{
+		mentionService: mentionService,
+	}
+}
+
+// @Summary 自分宛てのメンション一覧の取得
+// @Description ログインユーザーがメンションされたIssue・Discussion・コメントの一覧を新しい順に取得します
+// @Tags mentions
+// @Accept json
+// @Produce json
+// @Param page query int false "ページ番号" default(1)
+// @Param limit query int false "1ページあたりの件数" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/users/me/mentions [get]
+func (h *MentionHandler) ListMyMentions(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// クエリパラメータの取得
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// データベースから取得
+	mentions, total, err := h.mentionService.ListMentions(c.Request.Context(), userID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mentions": mentions,
+		"total":    total,
+		"page":     page,
+		"limit":    limit,
+	})
+}
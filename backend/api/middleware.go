@@ -1,14 +1,18 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
 	"github.com/shimauma0312/module-tickethub/backend/services"
 )
 
 // AuthMiddleware は認証ミドルウェア
-func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
+// JWTのアクセストークンとパーソナルアクセストークンのどちらでも認証できます
+func AuthMiddleware(authService *services.AuthService, tokenService *services.PersonalAccessTokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// トークンの取得
 		token := extractToken(c)
@@ -19,8 +23,7 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 		}
 
 		// トークンの検証
-		user, claims, err := authService.ValidateToken(c.Request.Context(), token)
-		if err != nil {
+		if err := authenticate(c, authService, tokenService, token); err != nil {
 			var statusCode int
 			var message string
 
@@ -37,6 +40,9 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 			case services.ErrUserNotFound:
 				statusCode = http.StatusUnauthorized
 				message = "User not found"
+			case services.ErrUserDisabled:
+				statusCode = http.StatusUnauthorized
+				message = "User account is disabled"
 			default:
 				statusCode = http.StatusInternalServerError
 				message = "Failed to validate token"
@@ -47,23 +53,217 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 			return
 		}
 
-		// アクセストークンのみ許可
-		if claims.TokenType != "access" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token type"})
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware は任意認証ミドルウェア
+// 有効なアクセストークンがある場合のみユーザー情報をコンテキストに設定し、ない場合は未認証として続行する
+func OptionalAuthMiddleware(authService *services.AuthService, tokenService *services.PersonalAccessTokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := extractToken(c)
+		if token != "" {
+			_ = authenticate(c, authService, tokenService, token)
+		}
+
+		c.Next()
+	}
+}
+
+// authenticate はトークンを検証し、ユーザー情報をコンテキストに設定します
+// パーソナルアクセストークンの場合は付与されたスコープも設定します
+func authenticate(c *gin.Context, authService *services.AuthService, tokenService *services.PersonalAccessTokenService, token string) error {
+	if models.IsPersonalAccessToken(token) {
+		user, pat, err := tokenService.Authenticate(c.Request.Context(), token, c.ClientIP())
+		if err != nil {
+			return err
+		}
+		setAuthenticatedUser(c, user)
+		c.Set("token_id", pat.ID)
+		c.Set("token_scopes", pat.Scopes)
+		return nil
+	}
+
+	user, claims, err := authService.ValidateToken(c.Request.Context(), token)
+	if err != nil {
+		return err
+	}
+
+	// アクセストークンのみ許可
+	if claims.TokenType != string(models.AccessToken) {
+		return services.ErrTokenInvalid
+	}
+
+	setAuthenticatedUser(c, user)
+	return nil
+}
+
+// setAuthenticatedUser はユーザー情報をコンテキストに設定します
+func setAuthenticatedUser(c *gin.Context, user *models.User) {
+	c.Set("user_id", user.ID)
+	c.Set("username", user.Username)
+	c.Set("is_admin", user.IsAdmin)
+	c.Set("email_verified", user.IsEmailVerified())
+}
+
+// getTokenScopes はパーソナルアクセストークンのスコープを取得する
+// パーソナルアクセストークンで認証していない場合はfalseを返す
+func getTokenScopes(c *gin.Context) ([]string, bool) {
+	scopes, exists := c.Get("token_scopes")
+	if !exists {
+		return nil, false
+	}
+	return scopes.([]string), true
+}
+
+// RequireScope はパーソナルアクセストークンのスコープを確認するミドルウェア
+// GET・HEADは readScope、それ以外のメソッドは writeScope が必要です
+// JWTで認証したリクエストと未認証のリクエストはスコープで制限しません
+func RequireScope(readScope, writeScope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope := writeScope
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			scope = readScope
+		}
+
+		if !authorizeScope(c, scope) {
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// SessionOnlyMiddleware はパーソナルアクセストークンでの利用を禁止するミドルウェア
+// トークンの管理やパスワードの変更など、ログインしたセッションでのみ許可する操作に使用する
+func SessionOnlyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := getTokenScopes(c); ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": "This endpoint cannot be used with a personal access token"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// authorizeScope はパーソナルアクセストークンに指定したスコープが付与されているかを確認します
+// 付与されていない場合は403を返し、falseを返します
+func authorizeScope(c *gin.Context, scope string) bool {
+	scopes, ok := getTokenScopes(c)
+	if !ok || models.ScopesAllow(scopes, scope) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{
+		"error":          fmt.Sprintf("Token does not have the required scope: %s", scope),
+		"required_scope": scope,
+	})
+	return false
+}
+
+// RepositoryScopeMiddleware はリポジトリの絞り込みミドルウェア
+// パスの :name またはクエリの repository で指定されたリポジトリをコンテキストに設定する
+// 指定がない場合はリポジトリで絞り込まずに続行する
+// ログインユーザーが閲覧できないリポジトリは存在しないものとして404を返す
+func RepositoryScopeMiddleware(repoRepo repositories.RepositoryRepository, permissionService *services.RepositoryPermissionService) gin.HandlerFunc {
+	return repositoryScope(repoRepo, permissionService, false)
+}
+
+// DefaultRepositoryScopeMiddleware は作成先リポジトリの解決ミドルウェア
+// リポジトリの指定がない場合は既定のリポジトリをコンテキストに設定する
+func DefaultRepositoryScopeMiddleware(repoRepo repositories.RepositoryRepository, permissionService *services.RepositoryPermissionService) gin.HandlerFunc {
+	return repositoryScope(repoRepo, permissionService, true)
+}
+
+// repositoryScope はリポジトリを解決してコンテキストに設定するミドルウェアを作成する
+func repositoryScope(repoRepo repositories.RepositoryRepository, permissionService *services.RepositoryPermissionService, useDefault bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		if name == "" {
+			name = c.Query("repository")
+		}
+
+		var repo *models.Repository
+		var err error
+		switch {
+		case name != "":
+			repo, err = repoRepo.GetByName(c.Request.Context(), name)
+		case useDefault:
+			repo, err = repoRepo.GetDefault(c.Request.Context())
+		default:
+			c.Next()
+			return
+		}
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+		if repo == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Repository not found"})
+			c.Abort()
+			return
+		}
+
+		// 閲覧権限の確認
+		if err := permissionService.AuthorizeRepository(c.Request.Context(), repo, getUserIDFromContext(c), models.RepositoryRoleRead); err != nil {
+			respondRepositoryPermissionError(c, err, "Repository not found")
 			c.Abort()
 			return
 		}
 
-		// ユーザー情報をコンテキストに設定
-		c.Set("user_id", user.ID)
-		c.Set("username", user.Username)
-		c.Set("is_admin", user.IsAdmin)
+		c.Set("repository", repo)
+		c.Next()
+	}
+}
+
+// getRepositoryScope はミドルウェアで解決されたリポジトリを取得する（指定がない場合はnil）
+func getRepositoryScope(c *gin.Context) *models.Repository {
+	repo, exists := c.Get("repository")
+	if !exists {
+		return nil
+	}
+	return repo.(*models.Repository)
+}
+
+// RequireVerifiedEmailMiddleware はメールアドレスを確認していないユーザーの書き込みを禁止するミドルウェア
+// システム設定の RequireEmailVerify が有効な場合のみ、GET・HEAD・OPTIONS以外のメソッドを403で拒否します（管理者は除く）
+func RequireVerifiedEmailMiddleware(emailVerificationService *services.EmailVerificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if c.GetBool("email_verified") || c.GetBool("is_admin") {
+			c.Next()
+			return
+		}
+
+		required, err := emailVerificationService.IsRequired(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get system settings"})
+			c.Abort()
+			return
+		}
+		if required {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":                       "Email address is not verified",
+				"email_verification_required": true,
+			})
+			c.Abort()
+			return
+		}
 
 		c.Next()
 	}
 }
 
 // AdminMiddleware は管理者権限ミドルウェア
+// パーソナルアクセストークンの場合は admin スコープも必要です
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		isAdmin, exists := c.Get("is_admin")
@@ -73,6 +273,11 @@ func AdminMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		if !authorizeScope(c, models.ScopeAdmin) {
+			c.Abort()
+			return
+		}
+
 		c.Next()
 	}
 }
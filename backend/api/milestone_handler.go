@@ -8,17 +8,29 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/shimauma0312/module-tickethub/backend/models"
 	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
 )
 
 // MilestoneHandler はMilestone関連のハンドラーを管理する構造体
 type MilestoneHandler struct {
-	milestoneRepo repositories.MilestoneRepository
+	milestoneRepo     repositories.MilestoneRepository
+	issueRepo         repositories.IssueRepository
+	issueEventRepo    repositories.IssueEventRepository
+	permissionService *services.RepositoryPermissionService
 }
 
 // NewMilestoneHandler は新しいMilestoneHandlerを作成します
-func NewMilestoneHandler(milestoneRepo repositories.MilestoneRepository) *MilestoneHandler {
+func NewMilestoneHandler(
+	milestoneRepo repositories.MilestoneRepository,
+	issueRepo repositories.IssueRepository,
+	issueEventRepo repositories.IssueEventRepository,
+	permissionService *services.RepositoryPermissionService,
+) *MilestoneHandler {
 	return &MilestoneHandler{
-		milestoneRepo: milestoneRepo,
+		milestoneRepo:     milestoneRepo,
+		issueRepo:         issueRepo,
+		issueEventRepo:    issueEventRepo,
+		permissionService: permissionService,
 	}
 }
 
@@ -30,15 +42,17 @@ type MilestoneRequest struct {
 }
 
 // @Summary マイルストーン一覧の取得
-// @Description マイルストーンの一覧を取得します
+// @Description マイルストーンの一覧を期日の近い順（期日のないものは最後）に、Issueの進捗とともに取得します
 // @Tags milestones
 // @Accept json
 // @Produce json
 // @Param page query int false "ページ番号" default(1)
 // @Param limit query int false "1ページあたりの件数" default(10)
 // @Param status query string false "ステータス (open/closed)" default("open")
+// @Param repository query string false "リポジトリ名"
 // @Success 200 {object} map[string]interface{}
 // @Router /api/v1/milestones [get]
+// @Router /api/v1/repos/{name}/milestones [get]
 func (h *MilestoneHandler) ListMilestones(c *gin.Context) {
 	// クエリパラメータの取得
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
@@ -49,6 +63,9 @@ func (h *MilestoneHandler) ListMilestones(c *gin.Context) {
 	filter := map[string]interface{}{
 		"status": status,
 	}
+	if !scopeRepositoryFilter(c, h.permissionService, filter) {
+		return
+	}
 
 	// データベースから取得
 	milestones, total, err := h.milestoneRepo.List(c.Request.Context(), filter, page, limit)
@@ -57,6 +74,11 @@ func (h *MilestoneHandler) ListMilestones(c *gin.Context) {
 		return
 	}
 
+	// 進捗の集計
+	if !h.attachProgress(c, milestones...) {
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"milestones": milestones,
 		"total":      total,
@@ -66,7 +88,7 @@ func (h *MilestoneHandler) ListMilestones(c *gin.Context) {
 }
 
 // @Summary マイルストーンの取得
-// @Description 指定されたIDのマイルストーンを取得します
+// @Description 指定されたIDのマイルストーンをIssueの進捗とともに取得します
 // @Tags milestones
 // @Accept json
 // @Produce json
@@ -84,7 +106,7 @@ func (h *MilestoneHandler) GetMilestone(c *gin.Context) {
 	// データベースから取得
 	milestone, err := h.milestoneRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Milestone not found")
 		return
 	}
 
@@ -94,17 +116,88 @@ func (h *MilestoneHandler) GetMilestone(c *gin.Context) {
 		return
 	}
 
+	// 閲覧できないリポジトリの場合
+	if !authorizeRepository(c, h.permissionService, milestone.RepositoryID, models.RepositoryRoleRead, "Milestone not found") {
+		return
+	}
+
+	// 進捗の集計
+	if !h.attachProgress(c, milestone) {
+		return
+	}
+
 	c.JSON(http.StatusOK, milestone)
 }
 
+// @Summary マイルストーンのバーンダウンの取得
+// @Description マイルストーンの作成日から期日（期日がない場合は今日）までの日ごとのオープンなIssue数を、Issueのクローズ・再オープンの履歴から再構成して取得します
+// @Tags milestones
+// @Accept json
+// @Produce json
+// @Param id path int true "マイルストーンID"
+// @Success 200 {object} models.MilestoneBurndown
+// @Router /api/v1/milestones/{id}/burndown [get]
+func (h *MilestoneHandler) GetBurndown(c *gin.Context) {
+	// IDの取得
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid milestone ID format"})
+		return
+	}
+
+	// データベースから取得
+	milestone, err := h.milestoneRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondFetchError(c, err, "Milestone not found")
+		return
+	}
+
+	// 見つからない場合
+	if milestone == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Milestone not found"})
+		return
+	}
+
+	// 閲覧できないリポジトリの場合
+	if !authorizeRepository(c, h.permissionService, milestone.RepositoryID, models.RepositoryRoleRead, "Milestone not found") {
+		return
+	}
+
+	// マイルストーンに含まれる（含まれていた）Issueと変更履歴の取得
+	issues, err := h.issueRepo.ListByMilestoneHistory(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	issueIDs := make([]int64, len(issues))
+	for i, issue := range issues {
+		issueIDs[i] = issue.ID
+	}
+	events, err := h.issueEventRepo.ListByIssues(c.Request.Context(), issueIDs, []models.IssueEventType{
+		models.IssueEventClosed,
+		models.IssueEventReopened,
+		models.IssueEventMilestoned,
+		models.IssueEventDemilestoned,
+		models.IssueEventConvertedFromDraft,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BuildMilestoneBurndown(milestone, issues, events, time.Now()))
+}
+
 // @Summary マイルストーンの作成
 // @Description 新しいマイルストーンを作成します
 // @Tags milestones
 // @Accept json
 // @Produce json
+// @Param repository query string false "リポジトリ名"
 // @Param milestone body MilestoneRequest true "マイルストーン情報"
 // @Success 201 {object} models.Milestone
 // @Router /api/v1/milestones [post]
+// @Router /api/v1/repos/{name}/milestones [post]
 func (h *MilestoneHandler) CreateMilestone(c *gin.Context) {
 	// ユーザーIDの取得
 	userID, exists := c.Get("user_id")
@@ -113,6 +206,12 @@ func (h *MilestoneHandler) CreateMilestone(c *gin.Context) {
 		return
 	}
 
+	// write以上のロールの確認
+	repo := getRepositoryScope(c)
+	if !authorizeRepository(c, h.permissionService, repo.ID, models.RepositoryRoleWrite, "Repository not found") {
+		return
+	}
+
 	// リクエストの解析
 	var req MilestoneRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -133,6 +232,7 @@ func (h *MilestoneHandler) CreateMilestone(c *gin.Context) {
 
 	// マイルストーンの作成
 	milestone := models.NewMilestone(req.Title, req.Description, dueDate, userID.(int64))
+	milestone.RepositoryID = repo.ID
 
 	// 検証
 	if !milestone.IsValid() {
@@ -146,6 +246,7 @@ func (h *MilestoneHandler) CreateMilestone(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	milestone.SetProgress(0, 0, time.Now())
 
 	c.JSON(http.StatusCreated, milestone)
 }
@@ -161,7 +262,7 @@ func (h *MilestoneHandler) CreateMilestone(c *gin.Context) {
 // @Router /api/v1/milestones/{id} [put]
 func (h *MilestoneHandler) UpdateMilestone(c *gin.Context) {
 	// ユーザーIDの取得
-	userID, exists := c.Get("user_id")
+	_, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
@@ -177,7 +278,7 @@ func (h *MilestoneHandler) UpdateMilestone(c *gin.Context) {
 	// データベースから取得
 	milestone, err := h.milestoneRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Milestone not found")
 		return
 	}
 
@@ -187,10 +288,8 @@ func (h *MilestoneHandler) UpdateMilestone(c *gin.Context) {
 		return
 	}
 
-	// 作成者のみ編集可能（または管理者）
-	isAdmin, _ := c.Get("is_admin")
-	if milestone.CreatorID != userID.(int64) && !(isAdmin != nil && isAdmin.(bool)) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to update this milestone"})
+	// write以上のロールを持つユーザーのみ編集可能
+	if !authorizeRepository(c, h.permissionService, milestone.RepositoryID, models.RepositoryRoleWrite, "Milestone not found") {
 		return
 	}
 
@@ -228,6 +327,11 @@ func (h *MilestoneHandler) UpdateMilestone(c *gin.Context) {
 		return
 	}
 
+	// 進捗の集計
+	if !h.attachProgress(c, milestone) {
+		return
+	}
+
 	c.JSON(http.StatusOK, milestone)
 }
 
@@ -241,7 +345,7 @@ func (h *MilestoneHandler) UpdateMilestone(c *gin.Context) {
 // @Router /api/v1/milestones/{id} [delete]
 func (h *MilestoneHandler) DeleteMilestone(c *gin.Context) {
 	// ユーザーIDの取得
-	userID, exists := c.Get("user_id")
+	_, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
@@ -257,7 +361,7 @@ func (h *MilestoneHandler) DeleteMilestone(c *gin.Context) {
 	// データベースから取得
 	milestone, err := h.milestoneRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Milestone not found")
 		return
 	}
 
@@ -267,10 +371,8 @@ func (h *MilestoneHandler) DeleteMilestone(c *gin.Context) {
 		return
 	}
 
-	// 作成者のみ削除可能（または管理者）
-	isAdmin, _ := c.Get("is_admin")
-	if milestone.CreatorID != userID.(int64) && !(isAdmin != nil && isAdmin.(bool)) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to delete this milestone"})
+	// write以上のロールを持つユーザーのみ削除可能
+	if !authorizeRepository(c, h.permissionService, milestone.RepositoryID, models.RepositoryRoleWrite, "Milestone not found") {
 		return
 	}
 
@@ -295,7 +397,7 @@ func (h *MilestoneHandler) DeleteMilestone(c *gin.Context) {
 // @Router /api/v1/milestones/{id}/status [patch]
 func (h *MilestoneHandler) UpdateMilestoneStatus(c *gin.Context) {
 	// ユーザーIDの取得
-	userID, exists := c.Get("user_id")
+	_, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
@@ -311,7 +413,7 @@ func (h *MilestoneHandler) UpdateMilestoneStatus(c *gin.Context) {
 	// データベースから取得
 	milestone, err := h.milestoneRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFetchError(c, err, "Milestone not found")
 		return
 	}
 
@@ -321,10 +423,8 @@ func (h *MilestoneHandler) UpdateMilestoneStatus(c *gin.Context) {
 		return
 	}
 
-	// 作成者のみ編集可能（または管理者）
-	isAdmin, _ := c.Get("is_admin")
-	if milestone.CreatorID != userID.(int64) && !(isAdmin != nil && isAdmin.(bool)) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to update this milestone"})
+	// write以上のロールを持つユーザーのみ編集可能
+	if !authorizeRepository(c, h.permissionService, milestone.RepositoryID, models.RepositoryRoleWrite, "Milestone not found") {
 		return
 	}
 
@@ -355,5 +455,30 @@ func (h *MilestoneHandler) UpdateMilestoneStatus(c *gin.Context) {
 		return
 	}
 
+	// 進捗の集計
+	if !h.attachProgress(c, milestone) {
+		return
+	}
+
 	c.JSON(http.StatusOK, milestone)
 }
+
+// attachProgress はマイルストーンにIssueの進捗と期日超過を設定し、失敗した場合はエラーレスポンスを返します
+func (h *MilestoneHandler) attachProgress(c *gin.Context, milestones ...*models.Milestone) bool {
+	ids := make([]int64, len(milestones))
+	for i, milestone := range milestones {
+		ids[i] = milestone.ID
+	}
+
+	counts, err := h.issueRepo.CountByMilestones(c.Request.Context(), ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+
+	now := time.Now()
+	for _, milestone := range milestones {
+		milestone.SetProgress(counts[milestone.ID]["open"], counts[milestone.ID]["closed"], now)
+	}
+	return true
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+)
+
+const (
+	// oidcStateCookie はログインを開始したブラウザに state を紐付けるCookieの名前
+	oidcStateCookie = "oidc_state"
+	// oidcStateCookieMaxAge はログインの状態の有効期限と同じ10分
+	oidcStateCookieMaxAge = 10 * 60
+)
+
+// OIDCHandler はOpenID Connectによるシングルサインオンのハンドラーを管理する構造体
+type OIDCHandler struct {
+	oidcService *services.OIDCService
+}
+
+// NewOIDCHandler は新しいOIDCHandlerを作成します
+func NewOIDCHandler(oidcService *services.OIDCService) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService: oidcService,
+	}
+}
+
+// RegisterRoutes はシングルサインオンのルートを登録します
+func (h *OIDCHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/oidc/providers", h.ListProviders)
+	router.GET("/oidc/:provider/login", h.Login)
+	router.GET("/oidc/:provider/callback", h.Callback)
+}
+
+// ListProviders は設定されているIDプロバイダーの一覧を取得するハンドラー
+// @Summary シングルサインオンのIDプロバイダー一覧取得
+// @Description ログインに使用できるIDプロバイダーの名前の一覧を取得します
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /auth/oidc/providers [get]
+func (h *OIDCHandler) ListProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": h.oidcService.Providers()})
+}
+
+// Login はIDプロバイダーの認可エンドポイントにリダイレクトするハンドラー
+// @Summary シングルサインオンの開始
+// @Description PKCEのcode_challengeとnonceを付けてIDプロバイダーの認可エンドポイントにリダイレクトします
+// @Tags auth
+// @Param provider path string true "IDプロバイダー名"
+// @Success 302
+// @Failure 404 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /auth/oidc/{provider}/login [get]
+func (h *OIDCHandler) Login(c *gin.Context) {
+	authURL, state, err := h.oidcService.BeginLogin(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		respondOIDCError(c, err)
+		return
+	}
+
+	// 他人が開始したログインのコールバックを完了させられないよう、state をこのブラウザのCookieに紐付ける
+	// IDプロバイダーからのトップレベルのリダイレクトで送信されるようSameSite=Laxにする
+	setOIDCStateCookie(c, state, oidcStateCookieMaxAge)
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback はIDプロバイダーからのリダイレクトを受け取り、ログインを完了するハンドラー
+// @Summary シングルサインオンのコールバック
+// @Description 認可コードをIDトークンに交換して検証し、ユーザーの作成または紐付けを行ってトークンを発行します
+// @Tags auth
+// @Produce json
+// @Param provider path string true "IDプロバイダー名"
+// @Param code query string true "認可コード"
+// @Param state query string true "ログイン開始時に発行したstate"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /auth/oidc/{provider}/callback [get]
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	// IDプロバイダーでの認可の拒否やエラー
+	if providerErr := c.Query("error"); providerErr != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":             "Identity provider returned an error: " + providerErr,
+			"error_description": c.Query("error_description"),
+		})
+		return
+	}
+
+	code := c.Query("code")
+	state := c.Query("state")
+	if code == "" || state == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code and state are required"})
+		return
+	}
+
+	// ログインを開始したブラウザからのコールバックか確認
+	cookieState, err := c.Cookie(oidcStateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookieState), []byte(state)) != 1 {
+		respondOIDCError(c, services.ErrOIDCInvalidState)
+		return
+	}
+	setOIDCStateCookie(c, "", -1)
+
+	user, accessToken, refreshToken, err := h.oidcService.CompleteLogin(
+		c.Request.Context(),
+		c.Param("provider"),
+		state,
+		code,
+		c.GetHeader("User-Agent"),
+		c.ClientIP(),
+	)
+	if err != nil {
+		// 二要素認証が有効な場合はパスワードでのログインと同じく一時トークンを返す
+		if errors.Is(err, services.ErrTwoFactorRequired) {
+			pendingToken, expiresIn, err := h.oidcService.IssueMFAPendingToken(user)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to login"})
+				return
+			}
+			respondTwoFactorRequired(c, pendingToken, expiresIn)
+			return
+		}
+		respondOIDCError(c, err)
+		return
+	}
+
+	// パスワードでのログインと同じくJWTトークンをCookieにも設定
+	c.SetCookie(
+		"access_token",
+		accessToken,
+		int(30*time.Minute.Seconds()), // 30分
+		"/",
+		"",
+		false, // 本番環境ではtrueに
+		true,  // HttpOnly
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Login successful",
+		"user": gin.H{
+			"id":             user.ID,
+			"username":       user.Username,
+			"email":          user.Email,
+			"email_verified": user.IsEmailVerified(),
+		},
+		"token": TokenResponse{
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+			ExpiresIn:    int(30 * time.Minute.Seconds()),
+			TokenType:    "Bearer",
+		},
+	})
+}
+
+// setOIDCStateCookie は state のCookieを設定します（maxAge が負の場合は削除します）
+func setOIDCStateCookie(c *gin.Context, state string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(
+		oidcStateCookie,
+		state,
+		maxAge,
+		"/",
+		"",
+		false, // 本番環境ではtrueに
+		true,  // HttpOnly
+	)
+}
+
+// respondOIDCError はシングルサインオンのエラーをHTTPステータスに変換して返します
+func respondOIDCError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrOIDCProviderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Identity provider not found"})
+	case errors.Is(err, services.ErrOIDCInvalidState):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrOIDCInvalidIDToken), errors.Is(err, services.ErrOIDCEmailRequired):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUserDisabled):
+		c.JSON(http.StatusForbidden, gin.H{"error": "User account is disabled"})
+	case errors.Is(err, services.ErrOIDCEmailNotVerified):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrOIDCProviderUnavailable):
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to login with identity provider"})
+	}
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+)
+
+// PersonalAccessTokenHandler はパーソナルアクセストークン関連のハンドラーを管理する構造体
+type PersonalAccessTokenHandler struct {
+	tokenService *services.PersonalAccessTokenService
+}
+
+// NewPersonalAccessTokenHandler は新しいPersonalAccessTokenHandlerを作成します
+func NewPersonalAccessTokenHandler(tokenService *services.PersonalAccessTokenService) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{
+		tokenService: tokenService,
+	}
+}
+
+// PersonalAccessTokenRequest はパーソナルアクセストークンの作成リクエストのデータ構造
+type PersonalAccessTokenRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required"` // 例: issues:read, issues:write, admin
+	ExpiresAt *time.Time `json:"expires_at"`                // 省略時は30日後、最長365日後まで
+}
+
+// PersonalAccessTokenResponse はパーソナルアクセストークンの作成レスポンスのデータ構造
+// 平文のトークンは作成時のみ返します
+type PersonalAccessTokenResponse struct {
+	*models.PersonalAccessToken
+	Token string `json:"token"`
+}
+
+// RegisterRoutes はパーソナルアクセストークンのルートを登録します
+func (h *PersonalAccessTokenHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/users/me/tokens", h.ListTokens)
+	router.POST("/users/me/tokens", h.CreateToken)
+	router.DELETE("/users/me/tokens/:id", h.DeleteToken)
+}
+
+// @Summary パーソナルアクセストークン一覧の取得
+// @Description ログインユーザーのパーソナルアクセストークンの一覧を最終利用日時・IPアドレスとともに取得します
+// @Tags tokens
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/users/me/tokens [get]
+func (h *PersonalAccessTokenHandler) ListTokens(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// データベースから取得
+	tokens, err := h.tokenService.List(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tokens": tokens,
+		"total":  len(tokens),
+	})
+}
+
+// @Summary パーソナルアクセストークンの作成
+// @Description 名前・スコープ・有効期限を指定してパーソナルアクセストークンを発行します。トークン文字列はこのレスポンスでのみ返します
+// @Tags tokens
+// @Accept json
+// @Produce json
+// @Param token body PersonalAccessTokenRequest true "パーソナルアクセストークン情報"
+// @Success 201 {object} PersonalAccessTokenResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/users/me/tokens [post]
+func (h *PersonalAccessTokenHandler) CreateToken(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// リクエストの解析
+	var req PersonalAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var expiresAt time.Time
+	if req.ExpiresAt != nil {
+		expiresAt = *req.ExpiresAt
+	}
+
+	// データベースに保存
+	token, plaintext, err := h.tokenService.Create(c.Request.Context(), userID, req.Name, req.Scopes, expiresAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidPersonalAccessToken):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrPersonalAccessTokenNameTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, PersonalAccessTokenResponse{PersonalAccessToken: token, Token: plaintext})
+}
+
+// @Summary パーソナルアクセストークンの削除
+// @Description 指定されたIDのパーソナルアクセストークンを削除し、以降の認証で使用できないようにします
+// @Tags tokens
+// @Accept json
+// @Produce json
+// @Param id path int true "トークンID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/users/me/tokens/{id} [delete]
+func (h *PersonalAccessTokenHandler) DeleteToken(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// IDの取得
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token ID"})
+		return
+	}
+
+	// データベースから削除
+	if err := h.tokenService.Delete(c.Request.Context(), userID, id); err != nil {
+		if errors.Is(err, services.ErrPersonalAccessTokenNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Token not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Token deleted successfully"})
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+)
+
+// ReactionHandler はReaction関連のハンドラーを管理する構造体
+type ReactionHandler struct {
+	issueRepo         repositories.IssueRepository
+	discussionRepo    repositories.DiscussionRepository
+	commentRepo       repositories.CommentRepository
+	reactionService   *services.ReactionService
+	permissionService *services.RepositoryPermissionService
+}
+
+// NewReactionHandler は新しいReactionHandlerを作成します
+func NewReactionHandler(
+	issueRepo repositories.IssueRepository,
+	discussionRepo repositories.DiscussionRepository,
+	commentRepo repositories.CommentRepository,
+	reactionService *services.ReactionService,
+	permissionService *services.RepositoryPermissionService,
+) *ReactionHandler {
+	return &ReactionHandler{
+		issueRepo:         issueRepo,
+		discussionRepo:    discussionRepo,
+		commentRepo:       commentRepo,
+		reactionService:   reactionService,
+		permissionService: permissionService,
+	}
+}
+
+// ReactionRequest はリアクションの付け外しリクエストのデータ構造
+type ReactionRequest struct {
+	Emoji string `json:"emoji" binding:"required"` // +1/-1/laugh/hooray/confused/heart/rocket/eyes
+}
+
+// RegisterRoutes はリアクションのルートを登録します
+func (h *ReactionHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/issues/:id/reactions", h.ToggleIssueReaction)
+	router.POST("/discussions/:id/reactions", h.ToggleDiscussionReaction)
+	router.POST("/comments/:id/reactions", h.ToggleCommentReaction)
+}
+
+// @Summary Issueへのリアクションの付け外し
+// @Description 指定した絵文字のリアクションが未登録なら追加し、登録済みなら削除します
+// @Tags reactions
+// @Accept json
+// @Produce json
+// @Param id path int true "Issue ID"
+// @Param reaction body ReactionRequest true "リアクション情報"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/issues/{id}/reactions [post]
+func (h *ReactionHandler) ToggleIssueReaction(c *gin.Context) {
+	// IDの取得
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid issue ID format"})
+		return
+	}
+
+	// ターゲットの存在確認
+	issue, err := h.issueRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondFetchError(c, err, "Issue not found")
+		return
+	}
+	if issue == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		return
+	}
+	if !authorizeRepository(c, h.permissionService, issue.RepositoryID, models.RepositoryRoleRead, "Issue not found") {
+		return
+	}
+
+	h.toggle(c, models.ReactionTargetIssue, id)
+}
+
+// @Summary Discussionへのリアクションの付け外し
+// @Description 指定した絵文字のリアクションが未登録なら追加し、登録済みなら削除します
+// @Tags reactions
+// @Accept json
+// @Produce json
+// @Param id path int true "Discussion ID"
+// @Param reaction body ReactionRequest true "リアクション情報"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/discussions/{id}/reactions [post]
+func (h *ReactionHandler) ToggleDiscussionReaction(c *gin.Context) {
+	// IDの取得
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid discussion ID format"})
+		return
+	}
+
+	// ターゲットの存在確認
+	discussion, err := h.discussionRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondFetchError(c, err, "Discussion not found")
+		return
+	}
+	if discussion == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Discussion not found"})
+		return
+	}
+	if !authorizeRepository(c, h.permissionService, discussion.RepositoryID, models.RepositoryRoleRead, "Discussion not found") {
+		return
+	}
+
+	h.toggle(c, models.ReactionTargetDiscussion, id)
+}
+
+// @Summary コメントへのリアクションの付け外し
+// @Description 指定した絵文字のリアクションが未登録なら追加し、登録済みなら削除します
+// @Tags reactions
+// @Accept json
+// @Produce json
+// @Param id path int true "コメントID"
+// @Param reaction body ReactionRequest true "リアクション情報"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/comments/{id}/reactions [post]
+func (h *ReactionHandler) ToggleCommentReaction(c *gin.Context) {
+	// IDの取得
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID format"})
+		return
+	}
+
+	// ターゲットの存在確認
+	comment, err := h.commentRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondFetchError(c, err, "Comment not found")
+		return
+	}
+	if comment == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+	repositoryID, err := commentRepositoryID(c.Request.Context(), comment, h.commentRepo, h.issueRepo, h.discussionRepo)
+	if err != nil {
+		respondRepositoryPermissionError(c, err, "Comment not found")
+		return
+	}
+	if !authorizeRepository(c, h.permissionService, repositoryID, models.RepositoryRoleRead, "Comment not found") {
+		return
+	}
+
+	h.toggle(c, models.ReactionTargetComment, id)
+}
+
+// toggle はリクエストの絵文字でリアクションを付け外しし、最新の集計を返します
+func (h *ReactionHandler) toggle(c *gin.Context, targetType string, targetID int64) {
+	userID := getUserIDFromContext(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// リクエストの解析
+	var req ReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// リアクションの付け外し
+	reacted, summaries, err := h.reactionService.Toggle(c.Request.Context(), targetType, targetID, userID, req.Emoji)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidReactionEmoji) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "allowed_emojis": models.ReactionEmojis})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if summaries == nil {
+		summaries = []models.ReactionSummary{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"target_type": targetType,
+		"target_id":   targetID,
+		"emoji":       req.Emoji,
+		"reacted":     reacted,
+		"reactions":   summaries,
+	})
+}
@@ -12,21 +12,35 @@ import (
 
 // RepositoryHandler はリポジトリ管理のAPIハンドラー
 type RepositoryHandler struct {
-	repoRepo        repositories.RepositoryRepository
-	activityService *services.ActivityLogService
+	repoRepo          repositories.RepositoryRepository
+	memberRepo        repositories.RepositoryMemberRepository
+	userRepo          repositories.UserRepository
+	activityService   *services.ActivityLogService
+	permissionService *services.RepositoryPermissionService
 }
 
 // NewRepositoryHandler は新しいRepositoryHandlerを作成します
 func NewRepositoryHandler(
 	repoRepo repositories.RepositoryRepository,
+	memberRepo repositories.RepositoryMemberRepository,
+	userRepo repositories.UserRepository,
 	activityService *services.ActivityLogService,
+	permissionService *services.RepositoryPermissionService,
 ) *RepositoryHandler {
 	return &RepositoryHandler{
-		repoRepo:        repoRepo,
-		activityService: activityService,
+		repoRepo:          repoRepo,
+		memberRepo:        memberRepo,
+		userRepo:          userRepo,
+		activityService:   activityService,
+		permissionService: permissionService,
 	}
 }
 
+// RepositoryMemberRequest はメンバーの追加・ロール変更リクエストのデータ構造
+type RepositoryMemberRequest struct {
+	Role models.RepositoryRole `json:"role" binding:"required"` // read/triage/write/maintain/admin
+}
+
 // GetRepositories はリポジトリ一覧を取得します
 // @Summary リポジトリ一覧取得
 // @Description 管理者がシステム内のリポジトリ一覧を取得します
@@ -300,6 +314,12 @@ func (h *RepositoryHandler) DeleteRepository(c *gin.Context) {
 		return
 	}
 
+	// メンバーの削除
+	if err := h.memberRepo.DeleteByRepository(c.Request.Context(), repoID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete repository members"})
+		return
+	}
+
 	// リポジトリを削除
 	if err := h.repoRepo.Delete(c.Request.Context(), repoID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete repository"})
@@ -325,3 +345,162 @@ func (h *RepositoryHandler) DeleteRepository(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Repository deleted successfully"})
 }
+
+// ListVisibleRepositories はログインユーザーが閲覧できるリポジトリ一覧を取得します
+// @Summary 閲覧できるリポジトリ一覧取得
+// @Description 公開リポジトリ、ログインしている場合は組織内公開リポジトリ、所有またはメンバーとして参加しているリポジトリの一覧を取得します
+// @Tags repositories
+// @Accept json
+// @Produce json
+// @Param page query int false "ページ番号" default(1)
+// @Param limit query int false "1ページあたりの件数" default(20)
+// @Success 200 {object} map[string]interface{} "リポジトリ一覧"
+// @Router /api/v1/repos [get]
+func (h *RepositoryHandler) ListVisibleRepositories(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	// 閲覧できるリポジトリに限定（管理者の場合は制限なし）
+	filter := make(map[string]interface{})
+	repositoryIDs, err := h.permissionService.VisibleRepositoryIDs(c.Request.Context(), getUserIDFromContext(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get repositories"})
+		return
+	}
+	if repositoryIDs != nil {
+		filter["ids"] = repositoryIDs
+	}
+
+	repositories, total, err := h.repoRepo.List(c.Request.Context(), filter, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get repositories"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"repositories": repositories,
+		"total":        total,
+		"page":         page,
+		"limit":        limit,
+	})
+}
+
+// GetRepositoryByName は名前を指定してリポジトリを取得します
+// @Summary リポジトリ取得
+// @Description 名前を指定してリポジトリを取得します（閲覧できないリポジトリは404）
+// @Tags repositories
+// @Accept json
+// @Produce json
+// @Param name path string true "リポジトリ名"
+// @Success 200 {object} models.Repository "リポジトリ詳細"
+// @Failure 404 {object} map[string]string "リポジトリが見つからない"
+// @Router /api/v1/repos/{name} [get]
+func (h *RepositoryHandler) GetRepositoryByName(c *gin.Context) {
+	c.JSON(http.StatusOK, getRepositoryScope(c))
+}
+
+// ListMembers はリポジトリのメンバー一覧を取得します
+// @Summary リポジトリのメンバー一覧取得
+// @Description リポジトリのメンバーとロールの一覧を取得します
+// @Tags repositories
+// @Accept json
+// @Produce json
+// @Param name path string true "リポジトリ名"
+// @Success 200 {object} map[string]interface{} "メンバー一覧"
+// @Router /api/v1/repos/{name}/members [get]
+func (h *RepositoryHandler) ListMembers(c *gin.Context) {
+	repo := getRepositoryScope(c)
+
+	members, err := h.memberRepo.ListByRepository(c.Request.Context(), repo.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get repository members"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"members": members,
+		"total":   len(members),
+	})
+}
+
+// SaveMember はリポジトリにメンバーを追加し、既に参加している場合はロールを変更します
+// @Summary リポジトリのメンバー追加・ロール変更
+// @Description リポジトリにメンバーを追加またはロールを変更します（adminロールが必要）
+// @Tags repositories
+// @Accept json
+// @Produce json
+// @Param name path string true "リポジトリ名"
+// @Param user_id path int true "ユーザーID"
+// @Param member body RepositoryMemberRequest true "ロール"
+// @Success 200 {object} models.RepositoryMember "メンバー"
+// @Failure 400 {object} map[string]string "リクエストエラー"
+// @Failure 403 {object} map[string]string "権限エラー"
+// @Router /api/v1/repos/{name}/members/{user_id} [put]
+func (h *RepositoryHandler) SaveMember(c *gin.Context) {
+	repo := getRepositoryScope(c)
+	if !authorizeRepository(c, h.permissionService, repo.ID, models.RepositoryRoleAdmin, "Repository not found") {
+		return
+	}
+
+	userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req RepositoryMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	// ユーザーの存在確認
+	if _, err := h.userRepo.GetByID(c.Request.Context(), userID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	member := models.NewRepositoryMember(repo.ID, userID, req.Role)
+	if !member.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role. Must be one of read, triage, write, maintain or admin"})
+		return
+	}
+
+	if err := h.memberRepo.Save(c.Request.Context(), member); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save repository member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember はリポジトリからメンバーを外します
+// @Summary リポジトリのメンバー削除
+// @Description リポジトリからメンバーを外します（adminロールが必要）
+// @Tags repositories
+// @Accept json
+// @Produce json
+// @Param name path string true "リポジトリ名"
+// @Param user_id path int true "ユーザーID"
+// @Success 200 {object} map[string]string "削除成功メッセージ"
+// @Failure 403 {object} map[string]string "権限エラー"
+// @Router /api/v1/repos/{name}/members/{user_id} [delete]
+func (h *RepositoryHandler) RemoveMember(c *gin.Context) {
+	repo := getRepositoryScope(c)
+	if !authorizeRepository(c, h.permissionService, repo.ID, models.RepositoryRoleAdmin, "Repository not found") {
+		return
+	}
+
+	userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.memberRepo.Delete(c.Request.Context(), repo.ID, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove repository member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Repository member removed successfully"})
+}
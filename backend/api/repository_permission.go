@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+	"gorm.io/gorm"
+)
+
+// authorizeRepository はログインユーザーがリポジトリで required 以上のロールを持つか確認し、
+// 持たない場合はエラーレスポンスを返します
+// 閲覧できないリポジトリの内容は存在を隠すため、notFound のメッセージで404を返します
+func authorizeRepository(
+	c *gin.Context,
+	permissionService *services.RepositoryPermissionService,
+	repositoryID int64,
+	required models.RepositoryRole,
+	notFound string,
+) bool {
+	_, err := permissionService.Authorize(c.Request.Context(), repositoryID, getUserIDFromContext(c), required)
+	if err != nil {
+		respondRepositoryPermissionError(c, err, notFound)
+		return false
+	}
+	return true
+}
+
+// authorizeAuthorOrRepository は作成者本人には閲覧権限のみを、それ以外のユーザーには required 以上のロールを要求します
+func authorizeAuthorOrRepository(
+	c *gin.Context,
+	permissionService *services.RepositoryPermissionService,
+	repositoryID, authorID int64,
+	required models.RepositoryRole,
+	notFound string,
+) bool {
+	if authorID != 0 && authorID == getUserIDFromContext(c) {
+		required = models.RepositoryRoleRead
+	}
+	return authorizeRepository(c, permissionService, repositoryID, required, notFound)
+}
+
+// respondRepositoryPermissionError はリポジトリの権限確認のエラーをレスポンスに変換します
+func respondRepositoryPermissionError(c *gin.Context, err error, notFound string) {
+	switch {
+	case errors.Is(err, services.ErrRepositoryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+	case errors.Is(err, services.ErrRepositoryPermissionDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to perform this action in this repository"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// respondFetchError はIssue・Discussion・コメントなどの取得のエラーをレスポンスに変換します
+// 存在しないIDは閲覧できないリポジトリの内容と同じ notFound のメッセージで404を返し、IDの存在を推測できないようにします
+func respondFetchError(c *gin.Context, err error, notFound string) {
+	if isRecordNotFound(err) {
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// isRecordNotFound は取得対象が存在しなかったことを表すエラーかどうかを判定します
+func isRecordNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
+
+// scopeRepositoryFilter は一覧取得のフィルタにリポジトリの絞り込みを設定します
+// リポジトリが指定されていない場合は、ログインユーザーが閲覧できるリポジトリに限定します
+func scopeRepositoryFilter(c *gin.Context, permissionService *services.RepositoryPermissionService, filter map[string]interface{}) bool {
+	if repo := getRepositoryScope(c); repo != nil {
+		filter["repository_id"] = repo.ID
+		return true
+	}
+
+	repositoryIDs, err := permissionService.VisibleRepositoryIDs(c.Request.Context(), getUserIDFromContext(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if repositoryIDs != nil {
+		filter["repository_id"] = repositoryIDs
+	}
+	return true
+}
+
+// commentRepositoryID はコメント（返信を含む）が属するIssue・Discussionのリポジトリを取得します
+// 親のIssue・Discussionが見つからない場合は services.ErrRepositoryNotFound を返します
+func commentRepositoryID(
+	ctx context.Context,
+	comment *models.Comment,
+	commentRepo repositories.CommentRepository,
+	issueRepo repositories.IssueRepository,
+	discussionRepo repositories.DiscussionRepository,
+) (int64, error) {
+	// 返信の場合は親コメントのターゲットタイプを使用
+	root := comment
+	for root.IsReply() {
+		parent, err := commentRepo.GetByID(ctx, root.ParentCommentID)
+		if err != nil || parent == nil || parent.ID == root.ID {
+			return 0, services.ErrRepositoryNotFound
+		}
+		root = parent
+	}
+
+	switch root.Type {
+	case "issue":
+		issue, err := issueRepo.GetByID(ctx, root.TargetID)
+		if err != nil || issue == nil {
+			return 0, services.ErrRepositoryNotFound
+		}
+		return issue.RepositoryID, nil
+	case "discussion":
+		discussion, err := discussionRepo.GetByID(ctx, root.TargetID)
+		if err != nil || discussion == nil {
+			return 0, services.ErrRepositoryNotFound
+		}
+		return discussion.RepositoryID, nil
+	}
+	return 0, services.ErrRepositoryNotFound
+}
@@ -0,0 +1,262 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+)
+
+// RevisionHandler は本文の編集履歴のハンドラーを管理する構造体
+type RevisionHandler struct {
+	issueRepo         repositories.IssueRepository
+	discussionRepo    repositories.DiscussionRepository
+	commentRepo       repositories.CommentRepository
+	revisionService   *services.RevisionService
+	permissionService *services.RepositoryPermissionService
+}
+
+// NewRevisionHandler は新しいRevisionHandlerを作成します
+func NewRevisionHandler(
+	issueRepo repositories.IssueRepository,
+	discussionRepo repositories.DiscussionRepository,
+	commentRepo repositories.CommentRepository,
+	revisionService *services.RevisionService,
+	permissionService *services.RepositoryPermissionService,
+) *RevisionHandler {
+	return &RevisionHandler{
+		issueRepo:         issueRepo,
+		discussionRepo:    discussionRepo,
+		commentRepo:       commentRepo,
+		revisionService:   revisionService,
+		permissionService: permissionService,
+	}
+}
+
+// RegisterRoutes は編集履歴のルートを登録します
+func (h *RevisionHandler) RegisterRoutes(router *gin.RouterGroup, adminRouter *gin.RouterGroup) {
+	router.GET("/issues/:id/revisions", h.ListIssueRevisions)
+	router.GET("/issues/:id/revisions/diff", h.DiffIssueRevisions)
+	router.GET("/discussions/:id/revisions", h.ListDiscussionRevisions)
+	router.GET("/discussions/:id/revisions/diff", h.DiffDiscussionRevisions)
+	router.GET("/comments/:id/revisions", h.ListCommentRevisions)
+	router.GET("/comments/:id/revisions/diff", h.DiffCommentRevisions)
+	adminRouter.POST("/revisions/:id/redact", h.RedactRevision)
+}
+
+// @Summary Issueの編集履歴の取得
+// @Description 指定されたIDのIssueの本文の版を古い順に取得します
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param id path int true "Issue ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/issues/{id}/revisions [get]
+func (h *RevisionHandler) ListIssueRevisions(c *gin.Context) {
+	h.list(c, models.RevisionTargetIssue)
+}
+
+// @Summary Issueの版の差分の取得
+// @Description 指定されたIDのIssueの2つの版の本文の差分をunified形式で取得します
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param id path int true "Issue ID"
+// @Param from query int true "比較元の版ID"
+// @Param to query int true "比較先の版ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/issues/{id}/revisions/diff [get]
+func (h *RevisionHandler) DiffIssueRevisions(c *gin.Context) {
+	h.diff(c, models.RevisionTargetIssue)
+}
+
+// @Summary Discussionの編集履歴の取得
+// @Description 指定されたIDのDiscussionの本文の版を古い順に取得します
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param id path int true "Discussion ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/discussions/{id}/revisions [get]
+func (h *RevisionHandler) ListDiscussionRevisions(c *gin.Context) {
+	h.list(c, models.RevisionTargetDiscussion)
+}
+
+// @Summary Discussionの版の差分の取得
+// @Description 指定されたIDのDiscussionの2つの版の本文の差分をunified形式で取得します
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param id path int true "Discussion ID"
+// @Param from query int true "比較元の版ID"
+// @Param to query int true "比較先の版ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/discussions/{id}/revisions/diff [get]
+func (h *RevisionHandler) DiffDiscussionRevisions(c *gin.Context) {
+	h.diff(c, models.RevisionTargetDiscussion)
+}
+
+// @Summary コメントの編集履歴の取得
+// @Description 指定されたIDのコメントの本文の版を古い順に取得します
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param id path int true "コメントID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/comments/{id}/revisions [get]
+func (h *RevisionHandler) ListCommentRevisions(c *gin.Context) {
+	h.list(c, models.RevisionTargetComment)
+}
+
+// @Summary コメントの版の差分の取得
+// @Description 指定されたIDのコメントの2つの版の本文の差分をunified形式で取得します
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param id path int true "コメントID"
+// @Param from query int true "比較元の版ID"
+// @Param to query int true "比較先の版ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/comments/{id}/revisions/diff [get]
+func (h *RevisionHandler) DiffCommentRevisions(c *gin.Context) {
+	h.diff(c, models.RevisionTargetComment)
+}
+
+// @Summary 版の削除（管理者用）
+// @Description 指定された版の本文を削除します（最新の版は現在の本文のため削除できません）
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param id path int true "版ID"
+// @Success 200 {object} models.BodyRevision
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/revisions/{id}/redact [post]
+func (h *RevisionHandler) RedactRevision(c *gin.Context) {
+	// IDの取得
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision ID format"})
+		return
+	}
+
+	// 版の削除
+	revision, err := h.revisionService.Redact(c.Request.Context(), id, getUserIDFromContext(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRevisionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		case errors.Is(err, services.ErrRevisionIsCurrent):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+// list は対象の版の一覧を返します
+func (h *RevisionHandler) list(c *gin.Context, targetType string) {
+	targetID, ok := h.authorizeTarget(c, targetType)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.revisionService.List(c.Request.Context(), targetType, targetID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"target_type": targetType,
+		"target_id":   targetID,
+		"revisions":   revisions,
+		"total":       len(revisions),
+	})
+}
+
+// diff は対象の2つの版の差分を返します
+func (h *RevisionHandler) diff(c *gin.Context, targetType string) {
+	targetID, ok := h.authorizeTarget(c, targetType)
+	if !ok {
+		return
+	}
+
+	// 比較する版の取得
+	fromID, err := strconv.ParseInt(c.Query("from"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from revision ID"})
+		return
+	}
+	toID, err := strconv.ParseInt(c.Query("to"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to revision ID"})
+		return
+	}
+
+	diff, err := h.revisionService.Diff(c.Request.Context(), targetType, targetID, fromID, toID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRevisionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		case errors.Is(err, services.ErrRevisionRedacted):
+			c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from": fromID,
+		"to":   toID,
+		"diff": diff,
+	})
+}
+
+// authorizeTarget は対象の存在とリポジトリの閲覧権限を確認し、対象のIDを返します
+func (h *RevisionHandler) authorizeTarget(c *gin.Context, targetType string) (int64, bool) {
+	// IDの取得
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + targetType + " ID format"})
+		return 0, false
+	}
+
+	ctx := c.Request.Context()
+	switch targetType {
+	case models.RevisionTargetIssue:
+		issue, err := h.issueRepo.GetByID(ctx, id)
+		if err != nil || issue == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+			return 0, false
+		}
+		return id, authorizeRepository(c, h.permissionService, issue.RepositoryID, models.RepositoryRoleRead, "Issue not found")
+	case models.RevisionTargetDiscussion:
+		discussion, err := h.discussionRepo.GetByID(ctx, id)
+		if err != nil || discussion == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Discussion not found"})
+			return 0, false
+		}
+		return id, authorizeRepository(c, h.permissionService, discussion.RepositoryID, models.RepositoryRoleRead, "Discussion not found")
+	default:
+		comment, err := h.commentRepo.GetByID(ctx, id)
+		if err != nil || comment == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+			return 0, false
+		}
+		repositoryID, err := commentRepositoryID(ctx, comment, h.commentRepo, h.issueRepo, h.discussionRepo)
+		if err != nil {
+			respondRepositoryPermissionError(c, err, "Comment not found")
+			return 0, false
+		}
+		return id, authorizeRepository(c, h.permissionService, repositoryID, models.RepositoryRoleRead, "Comment not found")
+	}
+}
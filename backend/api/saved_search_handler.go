@@ -0,0 +1,299 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+)
+
+// SavedSearchHandler は保存済み検索関連のハンドラーを管理する構造体
+type SavedSearchHandler struct {
+	savedSearchRepo    repositories.SavedSearchRepository
+	savedSearchService *services.SavedSearchService
+}
+
+// NewSavedSearchHandler は新しいSavedSearchHandlerを作成します
+func NewSavedSearchHandler(
+	savedSearchRepo repositories.SavedSearchRepository,
+	savedSearchService *services.SavedSearchService,
+) *SavedSearchHandler {
+	return &SavedSearchHandler{
+		savedSearchRepo:    savedSearchRepo,
+		savedSearchService: savedSearchService,
+	}
+}
+
+// SavedSearchRequest は保存済み検索の作成・更新リクエストのデータ構造
+type SavedSearchRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Query    string `json:"query"`
+	Sort     string `json:"sort"` // 例: updated-desc, created-asc, relevance
+	IsShared bool   `json:"is_shared"`
+}
+
+// RegisterRoutes は保存済み検索のルートを登録します
+func (h *SavedSearchHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/saved-searches", h.ListSavedSearches)
+	router.POST("/saved-searches", h.CreateSavedSearch)
+	router.GET("/saved-searches/:id", h.GetSavedSearch)
+	router.PUT("/saved-searches/:id", h.UpdateSavedSearch)
+	router.DELETE("/saved-searches/:id", h.DeleteSavedSearch)
+	router.GET("/saved-searches/:id/results", h.GetSavedSearchResults)
+}
+
+// @Summary 保存済み検索一覧の取得
+// @Description 自分が作成した、または共有された保存済み検索の一覧を前回の閲覧以降の新着件数とともに取得します
+// @Tags saved-searches
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/saved-searches [get]
+func (h *SavedSearchHandler) ListSavedSearches(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// データベースから取得
+	summaries, err := h.savedSearchService.ListWithCounts(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"saved_searches": summaries,
+		"total":          len(summaries),
+	})
+}
+
+// @Summary 保存済み検索の取得
+// @Description 指定されたIDの保存済み検索を前回の閲覧以降の新着件数とともに取得します
+// @Tags saved-searches
+// @Accept json
+// @Produce json
+// @Param id path int true "保存済み検索ID"
+// @Success 200 {object} services.SavedSearchSummary
+// @Router /api/v1/saved-searches/{id} [get]
+func (h *SavedSearchHandler) GetSavedSearch(c *gin.Context) {
+	savedSearch, userID, ok := h.getVisibleSavedSearch(c)
+	if !ok {
+		return
+	}
+
+	count, err := h.savedSearchService.CountNew(c.Request.Context(), savedSearch, userID)
+	if err != nil {
+		respondSearchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, services.SavedSearchSummary{SavedSearch: savedSearch, NewCount: count})
+}
+
+// @Summary 保存済み検索の作成
+// @Description 検索クエリ文字列・並び順・共有設定を保存します
+// @Tags saved-searches
+// @Accept json
+// @Produce json
+// @Param saved_search body SavedSearchRequest true "保存済み検索情報"
+// @Success 201 {object} models.SavedSearch
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/saved-searches [post]
+func (h *SavedSearchHandler) CreateSavedSearch(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// リクエストの解析
+	var req SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	savedSearch := models.NewSavedSearch(userID, req.Name, req.Query, req.Sort, req.IsShared)
+
+	// 検証
+	if !h.validate(c, savedSearch) {
+		return
+	}
+
+	// データベースに保存
+	if err := h.savedSearchRepo.Create(c.Request.Context(), savedSearch); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, savedSearch)
+}
+
+// @Summary 保存済み検索の更新
+// @Description 指定されたIDの保存済み検索を更新します（作成者のみ）
+// @Tags saved-searches
+// @Accept json
+// @Produce json
+// @Param id path int true "保存済み検索ID"
+// @Param saved_search body SavedSearchRequest true "保存済み検索情報"
+// @Success 200 {object} models.SavedSearch
+// @Router /api/v1/saved-searches/{id} [put]
+func (h *SavedSearchHandler) UpdateSavedSearch(c *gin.Context) {
+	savedSearch, ok := h.getOwnSavedSearch(c)
+	if !ok {
+		return
+	}
+
+	// リクエストの解析
+	var req SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	savedSearch.Update(req.Name, req.Query, req.Sort, req.IsShared)
+
+	// 検証
+	if !h.validate(c, savedSearch) {
+		return
+	}
+
+	// データベースに保存
+	if err := h.savedSearchRepo.Update(c.Request.Context(), savedSearch); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, savedSearch)
+}
+
+// @Summary 保存済み検索の削除
+// @Description 指定されたIDの保存済み検索を削除します（作成者のみ）
+// @Tags saved-searches
+// @Accept json
+// @Produce json
+// @Param id path int true "保存済み検索ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/saved-searches/{id} [delete]
+func (h *SavedSearchHandler) DeleteSavedSearch(c *gin.Context) {
+	savedSearch, ok := h.getOwnSavedSearch(c)
+	if !ok {
+		return
+	}
+
+	// データベースから削除
+	if err := h.savedSearchRepo.Delete(c.Request.Context(), savedSearch.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Saved search deleted successfully"})
+}
+
+// @Summary 保存済み検索の実行
+// @Description 保存済み検索を実行して結果を返し、前回の閲覧日時を更新します
+// @Tags saved-searches
+// @Accept json
+// @Produce json
+// @Param id path int true "保存済み検索ID"
+// @Param limit query int false "結果の上限数" default(20)
+// @Param offset query int false "結果のオフセット" default(0)
+// @Success 200 {object} models.SearchResults
+// @Router /api/v1/saved-searches/{id}/results [get]
+func (h *SavedSearchHandler) GetSavedSearchResults(c *gin.Context) {
+	savedSearch, userID, ok := h.getVisibleSavedSearch(c)
+	if !ok {
+		return
+	}
+
+	// クエリパラメータの取得
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	// 検索の実行
+	results, err := h.savedSearchService.Execute(c.Request.Context(), savedSearch, userID, limit, offset)
+	if err != nil {
+		respondSearchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// validate は保存済み検索の内容を検証し、不正な場合はエラーレスポンスを返します
+func (h *SavedSearchHandler) validate(c *gin.Context, savedSearch *models.SavedSearch) bool {
+	if !savedSearch.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid saved search data"})
+		return false
+	}
+
+	if err := h.savedSearchService.Validate(savedSearch); err != nil {
+		var syntaxErr *models.SearchSyntaxError
+		if errors.As(err, &syntaxErr) {
+			respondSearchError(c, err)
+			return false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+// getVisibleSavedSearch はログインユーザーが閲覧できる保存済み検索を取得します
+// 他人の共有されていない保存済み検索は存在を隠すため404を返します
+func (h *SavedSearchHandler) getVisibleSavedSearch(c *gin.Context) (*models.SavedSearch, int64, bool) {
+	userID := getUserIDFromContext(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, 0, false
+	}
+
+	// IDの取得
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid saved search ID format"})
+		return nil, 0, false
+	}
+
+	// データベースから取得
+	savedSearch, err := h.savedSearchRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, 0, false
+	}
+
+	// 見つからない場合
+	if savedSearch == nil || !savedSearch.IsVisibleTo(userID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Saved search not found"})
+		return nil, 0, false
+	}
+
+	return savedSearch, userID, true
+}
+
+// getOwnSavedSearch はログインユーザーが作成した保存済み検索を取得します
+func (h *SavedSearchHandler) getOwnSavedSearch(c *gin.Context) (*models.SavedSearch, bool) {
+	savedSearch, userID, ok := h.getVisibleSavedSearch(c)
+	if !ok {
+		return nil, false
+	}
+
+	if savedSearch.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the owner can modify this saved search"})
+		return nil, false
+	}
+
+	return savedSearch, true
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -12,51 +13,60 @@ import (
 
 // SearchHandler は検索APIハンドラ
 type SearchHandler struct {
-	searchService services.SearchService
+	searchService     services.SearchService
+	permissionService *services.RepositoryPermissionService
 }
 
 // NewSearchHandler は SearchHandler の新しいインスタンスを作成する
-func NewSearchHandler(searchService services.SearchService) *SearchHandler {
+func NewSearchHandler(searchService services.SearchService, permissionService *services.RepositoryPermissionService) *SearchHandler {
 	return &SearchHandler{
-		searchService: searchService,
+		searchService:     searchService,
+		permissionService: permissionService,
 	}
 }
 
 // RegisterRoutes はAPIルートを登録する
-func (h *SearchHandler) RegisterRoutes(router *gin.Engine) {
-	search := router.Group("/api/search")
-	{
-		search.GET("", h.Search)
-		search.POST("/rebuild-index", h.RebuildIndex)
-	}
+// インデックス再構築は管理者権限が必要なため、adminRouterに登録する
+func (h *SearchHandler) RegisterRoutes(router *gin.RouterGroup, adminRouter *gin.RouterGroup) {
+	router.GET("/search", h.Search)
+	adminRouter.POST("/search/rebuild-index", h.RebuildIndex)
 }
 
 // Search はコンテンツを検索するハンドラ
 // @Summary コンテンツを検索する
-// @Description 指定したクエリに基づいてイシューやコメントを検索する
+// @Description 指定したクエリに基づいてイシュー・ディスカッション・コメント・マイルストーン・ラベルを検索し、種類ごとのファセットを返す
 // @Tags 検索
 // @Accept json
 // @Produce json
-// @Param query query string false "検索クエリ"
+// @Param query query string false "検索クエリ (例: crash -label:wontfix \"exact phrase\" created:>2026-01-01 assignee:@me sort:updated-asc)"
 // @Param limit query int false "結果の上限数" default(20)
 // @Param offset query int false "結果のオフセット" default(0)
 // @Param labels query string false "ラベルフィルタ (カンマ区切り)"
 // @Param status query string false "ステータスフィルタ (open/closed/all)" default(all)
 // @Param assignee_id query int false "担当者IDフィルタ"
 // @Param creator_id query int false "作成者IDフィルタ"
+// @Param category query string false "カテゴリフィルタ (Discussionのみ)"
+// @Param repository query string false "リポジトリ名フィルタ"
+// @Param types query string false "結果種類フィルタ (カンマ区切り: issue/comment/discussion/discussion_comment/milestone/label)"
 // @Success 200 {object} models.SearchResults "検索結果"
-// @Failure 400 {object} ErrorResponse "不正なリクエスト"
+// @Failure 400 {object} ErrorResponse "不正なリクエスト（構文エラーの場合はpositionを含む）"
 // @Failure 500 {object} ErrorResponse "サーバエラー"
-// @Router /api/search [get]
+// @Router /api/v1/search [get]
 func (h *SearchHandler) Search(c *gin.Context) {
 	// クエリパラメータの取得
-	queryString := c.Query("query")
+	query, err := h.searchService.ParseQuery(c.Query("query"))
+	if err != nil {
+		respondSearchError(c, err)
+		return
+	}
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 	labelsStr := c.Query("labels")
 	status := c.DefaultQuery("status", "all")
 	assigneeID, _ := strconv.ParseInt(c.Query("assignee_id"), 10, 64)
 	creatorID, _ := strconv.ParseInt(c.Query("creator_id"), 10, 64)
+	category := c.Query("category")
+	repository := c.Query("repository")
 
 	// ラベルの解析
 	var labels []string
@@ -64,24 +74,40 @@ func (h *SearchHandler) Search(c *gin.Context) {
 		labels = splitAndTrim(labelsStr, ",")
 	}
 
-	// 検索クエリオブジェクトの作成
-	query := models.SearchQuery{
-		Query:      queryString,
-		Labels:     labels,
-		Status:     status,
-		AssigneeID: assigneeID,
-		CreatorID:  creatorID,
-		Limit:      limit,
-		Offset:     offset,
+	// 結果種類の解析
+	var types []models.SearchResultType
+	for _, t := range splitAndTrim(c.Query("types"), ",") {
+		resultType := models.SearchResultType(t)
+		if !resultType.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid search result type: " + t})
+			return
+		}
+		types = append(types, resultType)
+	}
+
+	// 検索クエリオブジェクトの作成（クエリ文字列の条件とパラメータのフィルタはANDで結合される）
+	query.Labels = labels
+	query.Status = status
+	query.AssigneeID = assigneeID
+	query.CreatorID = creatorID
+	query.Category = category
+	query.Repository = repository
+	query.Types = types
+	query.Limit = limit
+	query.Offset = offset
+	query.ViewerID = getUserIDFromContext(c)
+
+	// 閲覧できるリポジトリに限定
+	query.RepositoryIDs, err = h.permissionService.VisibleRepositoryIDs(c.Request.Context(), query.ViewerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
 	// 検索サービスの呼び出し
 	results, err := h.searchService.Search(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "検索に失敗しました",
-			"details": err.Error(),
-		})
+		respondSearchError(c, err)
 		return
 	}
 
@@ -95,17 +121,11 @@ func (h *SearchHandler) Search(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} SuccessResponse "インデックス再構築成功"
+// @Failure 403 {object} ErrorResponse "権限なし"
 // @Failure 500 {object} ErrorResponse "サーバエラー"
-// @Router /api/search/rebuild-index [post]
+// @Router /api/v1/search/rebuild-index [post]
 func (h *SearchHandler) RebuildIndex(c *gin.Context) {
-	// 管理者権限のチェック (実際の実装ではミドルウェアで行うことが多い)
-	// if !isAdmin(c) {
-	// 	c.JSON(http.StatusForbidden, ErrorResponse{
-	// 		Error: "権限がありません",
-	// 	})
-	// 	return
-	// }
-
+	// 管理者権限のチェックはAdminMiddlewareで行う
 	err := h.searchService.RebuildIndex(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -120,6 +140,24 @@ func (h *SearchHandler) RebuildIndex(c *gin.Context) {
 	})
 }
 
+// respondSearchError は検索エラーをレスポンスとして返す
+// 構文エラーの場合はエラー位置を含めて400を返す
+func respondSearchError(c *gin.Context, err error) {
+	var syntaxErr *models.SearchSyntaxError
+	if errors.As(err, &syntaxErr) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":    syntaxErr.Message,
+			"position": syntaxErr.Pos,
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "検索に失敗しました",
+		"details": err.Error(),
+	})
+}
+
 // splitAndTrim は文字列をデリミタで分割し、各要素をトリムする
 func splitAndTrim(s, delimiter string) []string {
 	if s == "" {
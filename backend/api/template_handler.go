@@ -0,0 +1,357 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+)
+
+// TemplateHandler はIssue・Discussionのテンプレートのハンドラーを管理する構造体
+type TemplateHandler struct {
+	templateRepo      repositories.TemplateRepository
+	labelRepo         repositories.LabelRepository
+	userRepo          repositories.UserRepository
+	permissionService *services.RepositoryPermissionService
+}
+
+// NewTemplateHandler は新しいTemplateHandlerを作成します
+func NewTemplateHandler(
+	templateRepo repositories.TemplateRepository,
+	labelRepo repositories.LabelRepository,
+	userRepo repositories.UserRepository,
+	permissionService *services.RepositoryPermissionService,
+) *TemplateHandler {
+	return &TemplateHandler{
+		templateRepo:      templateRepo,
+		labelRepo:         labelRepo,
+		userRepo:          userRepo,
+		permissionService: permissionService,
+	}
+}
+
+// TemplateRequest はテンプレート作成・更新リクエストのデータ構造
+type TemplateRequest struct {
+	Name              string                 `json:"name" binding:"required"`
+	Type              string                 `json:"type" binding:"required"` // issue/discussion
+	Description       string                 `json:"description"`
+	Category          string                 `json:"category"` // Discussionテンプレートの対象カテゴリ
+	TitlePrefix       string                 `json:"title_prefix"`
+	Body              string                 `json:"body"`
+	DefaultLabels     []string               `json:"default_labels"`
+	DefaultAssigneeID int64                  `json:"default_assignee_id"`
+	Fields            []models.TemplateField `json:"fields"`
+}
+
+// @Summary テンプレート一覧の取得
+// @Description Issue・Discussionのテンプレートの一覧を名前順に取得します
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param type query string false "種類 (issue/discussion)"
+// @Param category query string false "Discussionのカテゴリ"
+// @Param repository query string false "リポジトリ名"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/templates [get]
+// @Router /api/v1/repos/{name}/templates [get]
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	// フィルタの作成
+	filter := map[string]interface{}{}
+	if templateType := c.Query("type"); templateType != "" {
+		filter["type"] = templateType
+	}
+	if category := c.Query("category"); category != "" {
+		filter["category"] = category
+	}
+	if !scopeRepositoryFilter(c, h.permissionService, filter) {
+		return
+	}
+
+	// データベースから取得
+	templates, err := h.templateRepo.List(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if templates == nil {
+		templates = []*models.Template{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"templates": templates,
+		"total":     len(templates),
+	})
+}
+
+// @Summary テンプレートの取得
+// @Description 指定されたIDのテンプレートを取得します
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path int true "テンプレートID"
+// @Success 200 {object} models.Template
+// @Router /api/v1/templates/{id} [get]
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	template, ok := h.getTemplate(c, models.RepositoryRoleRead)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// @Summary テンプレートの作成
+// @Description 新しいテンプレートを作成します（リポジトリのadminロールが必要です）
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param repository query string false "リポジトリ名"
+// @Param template body TemplateRequest true "テンプレート情報"
+// @Success 201 {object} models.Template
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/templates [post]
+// @Router /api/v1/repos/{name}/templates [post]
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	// adminロールの確認
+	repo := getRepositoryScope(c)
+	if !authorizeRepository(c, h.permissionService, repo.ID, models.RepositoryRoleAdmin, "Repository not found") {
+		return
+	}
+
+	// リクエストの解析
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// テンプレートの作成と検証
+	template := models.NewTemplate(repo.ID, req.Type, req.Name, getUserIDFromContext(c))
+	if !h.applyRequest(c, template, &req) {
+		return
+	}
+
+	// データベースに保存
+	if err := h.templateRepo.Create(c.Request.Context(), template); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// @Summary テンプレートの更新
+// @Description 指定されたIDのテンプレートを更新します（リポジトリのadminロールが必要です）
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path int true "テンプレートID"
+// @Param template body TemplateRequest true "テンプレート情報"
+// @Success 200 {object} models.Template
+// @Router /api/v1/templates/{id} [put]
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	template, ok := h.getTemplate(c, models.RepositoryRoleAdmin)
+	if !ok {
+		return
+	}
+
+	// リクエストの解析
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// テンプレートの更新と検証
+	template.Type = req.Type
+	template.Name = req.Name
+	template.UpdatedAt = models.CurrentTime()
+	if !h.applyRequest(c, template, &req) {
+		return
+	}
+
+	// データベースに保存
+	if err := h.templateRepo.Update(c.Request.Context(), template); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// @Summary テンプレートの削除
+// @Description 指定されたIDのテンプレートを削除します（作成済みのIssue・Discussionには影響しません）
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path int true "テンプレートID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/templates/{id} [delete]
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	template, ok := h.getTemplate(c, models.RepositoryRoleAdmin)
+	if !ok {
+		return
+	}
+
+	// データベースから削除
+	if err := h.templateRepo.Delete(c.Request.Context(), template.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted successfully"})
+}
+
+// getTemplate はパスのIDのテンプレートを取得し、リポジトリの権限を確認します
+func (h *TemplateHandler) getTemplate(c *gin.Context, role models.RepositoryRole) (*models.Template, bool) {
+	// IDの取得
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID format"})
+		return nil, false
+	}
+
+	// データベースから取得
+	template, err := h.templateRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	// 見つからない場合
+	if template == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return nil, false
+	}
+
+	return template, authorizeRepository(c, h.permissionService, template.RepositoryID, role, "Template not found")
+}
+
+// applyRequest はリクエストの内容をテンプレートに設定して検証し、不正な場合はエラーレスポンスを返します
+func (h *TemplateHandler) applyRequest(c *gin.Context, template *models.Template, req *TemplateRequest) bool {
+	template.Description = req.Description
+	template.Category = req.Category
+	template.TitlePrefix = req.TitlePrefix
+	template.Body = req.Body
+	template.DefaultAssigneeID = req.DefaultAssigneeID
+	template.Fields = req.Fields
+	if template.Fields == nil {
+		template.Fields = []models.TemplateField{}
+	}
+
+	// 設定とフォームの項目の検証
+	if err := template.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+
+	// 同名テンプレートの存在確認（リポジトリ内・種類ごと）
+	existing, err := h.templateRepo.GetByName(c.Request.Context(), template.RepositoryID, template.Type, template.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if existing != nil && existing.ID != template.ID {
+		c.JSON(http.StatusConflict, gin.H{"error": "Template with this name already exists"})
+		return false
+	}
+
+	// 既定のラベルの確認
+	labels, ok := checkLabels(c, h.labelRepo, template.RepositoryID, nil, uniqueStrings(req.DefaultLabels), template.Type)
+	if !ok {
+		return false
+	}
+	template.DefaultLabels = labels
+	if template.DefaultLabels == nil {
+		template.DefaultLabels = []string{}
+	}
+
+	// 既定の担当者の確認
+	if template.DefaultAssigneeID > 0 {
+		if user, err := h.userRepo.GetByID(c.Request.Context(), template.DefaultAssigneeID); err != nil || user == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Default assignee not found"})
+			return false
+		}
+	}
+	return true
+}
+
+// applyTemplate は作成リクエストで指定されたテンプレートを取得してフォームの送信内容を検証し、不正な場合はエラーレスポンスを返します
+// テンプレートが指定されていない場合はnilを返します。既に存在しない既定のラベルはテンプレートから取り除きます
+func applyTemplate(
+	c *gin.Context,
+	templateRepo repositories.TemplateRepository,
+	labelRepo repositories.LabelRepository,
+	repositoryID int64,
+	templateType string,
+	templateID int64,
+	fields map[string]interface{},
+) (*models.Template, bool) {
+	if templateID == 0 {
+		if len(fields) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "fields can only be submitted with a template_id"})
+			return nil, false
+		}
+		return nil, true
+	}
+
+	// テンプレートの取得（別のリポジトリ・種類のテンプレートは指定できない）
+	template, err := templateRepo.GetByID(c.Request.Context(), templateID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if template == nil || template.RepositoryID != repositoryID || template.Type != templateType {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Template not found in this repository"})
+		return nil, false
+	}
+
+	// フォームの送信内容の検証
+	if errs := template.ValidateSubmission(fields); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Submission does not match the template",
+			"fields": errs,
+		})
+		return nil, false
+	}
+
+	// 既定のラベルのうち存在するものだけを残す
+	if len(template.DefaultLabels) > 0 {
+		labels, err := labelRepo.ListByNames(c.Request.Context(), repositoryID, template.DefaultLabels)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		existing := make(map[string]bool, len(labels))
+		for _, label := range labels {
+			if label.AppliesTo(templateType) {
+				existing[label.Name] = true
+			}
+		}
+		defaults := []string{}
+		for _, name := range template.DefaultLabels {
+			if existing[name] {
+				defaults = append(defaults, name)
+			}
+		}
+		template.DefaultLabels = defaults
+	}
+	return template, true
+}
+
+// uniqueStrings は重複を取り除いた文字列の一覧を返します（順序は維持）
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,294 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+)
+
+// TwoFactorHandler は二要素認証関連のハンドラーを管理する構造体
+type TwoFactorHandler struct {
+	twoFactorService *services.TwoFactorService
+	activityService  *services.ActivityLogService
+}
+
+// NewTwoFactorHandler は新しいTwoFactorHandlerを作成します
+func NewTwoFactorHandler(twoFactorService *services.TwoFactorService, activityService *services.ActivityLogService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+		activityService:  activityService,
+	}
+}
+
+// TwoFactorCodeRequest は二要素認証のコードを送信するリクエストのデータ構造
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"` // TOTPのコード（6桁）またはリカバリーコード
+}
+
+// TwoFactorLoginRequest はログインの2段階目のリクエストのデータ構造
+type TwoFactorLoginRequest struct {
+	MFAPendingToken string `json:"mfa_pending_token" binding:"required"`
+	Code            string `json:"code" binding:"required"` // TOTPのコード（6桁）またはリカバリーコード
+}
+
+// respondTwoFactorRequired はトークンの代わりに /auth/login/2fa で使用する一時トークンを返します
+// パスワードとシングルサインオンのどちらのログインでも同じ形式で返します
+func respondTwoFactorRequired(c *gin.Context, pendingToken string, expiresIn int) {
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "Two-factor authentication required",
+		"mfa_required":      true,
+		"mfa_pending_token": pendingToken,
+		"expires_in":        expiresIn,
+	})
+}
+
+// RegisterRoutes は二要素認証の設定のルートを登録します
+func (h *TwoFactorHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/2fa", h.GetStatus)
+	router.POST("/2fa/setup", h.Setup)
+	router.POST("/2fa/enable", h.Enable)
+	router.POST("/2fa/disable", h.Disable)
+	router.POST("/2fa/recovery-codes", h.RegenerateRecoveryCodes)
+}
+
+// GetStatus は二要素認証の状態を取得するハンドラー
+// @Summary 二要素認証の状態取得
+// @Description 二要素認証が有効かどうかと未使用のリカバリーコードの数を取得します
+// @Tags auth
+// @Produce json
+// @Success 200 {object} services.TwoFactorStatus
+// @Router /auth/2fa [get]
+func (h *TwoFactorHandler) GetStatus(c *gin.Context) {
+	// データベースから取得
+	status, err := h.twoFactorService.Status(c.Request.Context(), getUserIDFromContext(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get two-factor status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// Setup は二要素認証の登録を開始するハンドラー
+// @Summary 二要素認証の登録開始
+// @Description TOTPの共有鍵と認証アプリに登録するための otpauth:// 形式のURIを発行します。コードで確認するまでは有効になりません
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]string
+// @Router /auth/2fa/setup [post]
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	secret, uri, err := h.twoFactorService.Setup(c.Request.Context(), getUserIDFromContext(c), c.GetString("username"))
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"secret":      secret,
+		"otpauth_uri": uri,
+	})
+}
+
+// Enable は認証アプリのコードで確認して二要素認証を有効にするハンドラー
+// @Summary 二要素認証の有効化
+// @Description 認証アプリのコードで登録を確認して二要素認証を有効にし、リカバリーコードを返します。リカバリーコードはこのレスポンスでのみ返します
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body TwoFactorCodeRequest true "認証アプリのコード"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /auth/2fa/enable [post]
+func (h *TwoFactorHandler) Enable(c *gin.Context) {
+	// リクエストの解析
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	recoveryCodes, err := h.twoFactorService.Enable(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	// アクティビティログに記録
+	h.activityService.LogActivity(
+		c.Request.Context(),
+		userID,
+		c.GetString("username"),
+		models.ActionUserTwoFactorEnabled,
+		models.ResourceUser,
+		userID,
+		c.ClientIP(),
+		c.GetHeader("User-Agent"),
+		nil,
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Two-factor authentication enabled",
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+// Disable は二要素認証を無効にするハンドラー
+// @Summary 二要素認証の無効化
+// @Description 認証アプリのコードまたはリカバリーコードで確認して二要素認証を無効にします
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body TwoFactorCodeRequest true "認証アプリのコードまたはリカバリーコード"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	// リクエストの解析
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	if err := h.twoFactorService.Disable(c.Request.Context(), userID, req.Code); err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	// アクティビティログに記録
+	h.activityService.LogActivity(
+		c.Request.Context(),
+		userID,
+		c.GetString("username"),
+		models.ActionUserTwoFactorDisabled,
+		models.ResourceUser,
+		userID,
+		c.ClientIP(),
+		c.GetHeader("User-Agent"),
+		nil,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes はリカバリーコードを作り直すハンドラー
+// @Summary リカバリーコードの再発行
+// @Description 認証アプリのコードで確認してリカバリーコードを作り直します。以前のリカバリーコードは使用できなくなります
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body TwoFactorCodeRequest true "認証アプリのコード"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /auth/2fa/recovery-codes [post]
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	// リクエストの解析
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recoveryCodes, err := h.twoFactorService.RegenerateRecoveryCodes(c.Request.Context(), getUserIDFromContext(c), req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": recoveryCodes})
+}
+
+// CompleteLogin はログインの2段階目として二要素認証のコードを確認するハンドラー
+// @Summary 二要素認証によるログインの完了
+// @Description ログインで返された mfa_pending_token と認証アプリのコードまたはリカバリーコードを確認し、トークンを発行します
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body TwoFactorLoginRequest true "一時トークンとコード"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]interface{}
+// @Router /auth/login/2fa [post]
+func (h *TwoFactorHandler) CompleteLogin(c *gin.Context) {
+	// リクエストの解析
+	var req TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, accessToken, refreshToken, err := h.twoFactorService.CompleteLogin(
+		c.Request.Context(),
+		req.MFAPendingToken,
+		req.Code,
+		c.GetHeader("User-Agent"),
+		c.ClientIP(),
+	)
+	if err != nil {
+		var throttled *services.LoginThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			respondLoginThrottled(c, throttled)
+		case errors.Is(err, services.ErrTokenInvalid), errors.Is(err, services.ErrUserNotFound):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired mfa_pending_token"})
+		case errors.Is(err, services.ErrUserDisabled):
+			c.JSON(http.StatusForbidden, gin.H{"error": "User account is disabled"})
+		case errors.Is(err, services.ErrInvalidTwoFactorCode):
+			// アクティビティログに記録
+			logLoginFailure(c, h.activityService, user, "", err, "invalid_two_factor_code")
+			c.JSON(http.StatusUnauthorized, gin.H{"error": services.ErrInvalidTwoFactorCode.Error()})
+		case errors.Is(err, services.ErrTwoFactorNotEnabled):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": services.ErrInvalidTwoFactorCode.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to login"})
+		}
+		return
+	}
+
+	// パスワードでのログインと同じくJWTトークンをCookieにも設定
+	c.SetCookie(
+		"access_token",
+		accessToken,
+		int(30*time.Minute.Seconds()), // 30分
+		"/",
+		"",
+		false, // 本番環境ではtrueに
+		true,  // HttpOnly
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Login successful",
+		"user": gin.H{
+			"id":             user.ID,
+			"username":       user.Username,
+			"email":          user.Email,
+			"email_verified": user.IsEmailVerified(),
+		},
+		"token": TokenResponse{
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+			ExpiresIn:    int(30 * time.Minute.Seconds()),
+			TokenType:    "Bearer",
+		},
+	})
+}
+
+// respondTwoFactorError は二要素認証の設定のエラーをHTTPステータスに変換して返します
+func respondTwoFactorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTwoFactorCode):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTwoFactorAlreadyEnabled), errors.Is(err, services.ErrTwoFactorNotEnabled), errors.Is(err, services.ErrTwoFactorSetupRequired):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update two-factor authentication"})
+	}
+}
@@ -29,6 +29,9 @@ package docs
 // @tag.name comments
 // @tag.description コメント関連のエンドポイント
 
+// @tag.name reactions
+// @tag.description リアクション関連のエンドポイント
+
 // @tag.name labels
 // @tag.description ラベル関連のエンドポイント
 
@@ -43,3 +46,9 @@ package docs
 
 // @tag.name drafts
 // @tag.description ドラフト保存関連のエンドポイント
+
+// @tag.name mentions
+// @tag.description メンション関連のエンドポイント
+
+// @tag.name saved-searches
+// @tag.description 保存済み検索関連のエンドポイント
@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -13,6 +16,7 @@ import (
 	"github.com/shimauma0312/module-tickethub/backend/api"
 	"github.com/shimauma0312/module-tickethub/backend/config"
 	_ "github.com/shimauma0312/module-tickethub/backend/docs" // Swaggerドキュメント用
+	"github.com/shimauma0312/module-tickethub/backend/models"
 	"github.com/shimauma0312/module-tickethub/backend/services"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -68,6 +72,22 @@ func main() {
 		log.Fatalf("Failed to create password reset repository: %v", err)
 	}
 
+	twoFactorRepo, err := repoFactory.NewTwoFactorRepository()
+	if err != nil {
+		log.Fatalf("Failed to create two factor repository: %v", err)
+	}
+
+	// ログインの試行の制限（しきい値はシステム設定から読み込む）
+	systemSettingsRepo, err := repoFactory.NewSystemSettingsRepository()
+	if err != nil {
+		log.Fatalf("Failed to create system settings repository: %v", err)
+	}
+	loginThrottleRepo, err := repoFactory.NewLoginThrottleRepository()
+	if err != nil {
+		log.Fatalf("Failed to create login throttle repository: %v", err)
+	}
+	loginThrottleService := services.NewLoginThrottleService(loginThrottleRepo, systemSettingsRepo)
+
 	// JWT設定
 	jwtSecret := os.Getenv("JWT_SECRET")
 	if jwtSecret == "" {
@@ -79,9 +99,76 @@ func main() {
 		userRepo,
 		tokenRepo,
 		passwordResetRepo,
+		twoFactorRepo,
+		loginThrottleService,
 		jwtSecret,
 	)
 
+	// 二要素認証のサービスの作成
+	twoFactorService := services.NewTwoFactorService(twoFactorRepo, userRepo, authService)
+
+	// メールアドレスの確認のサービスの作成
+	// SMTP_HOST が設定されていない場合はメールを送信せずにログに出力する
+	var mailSender services.MailSender = services.NewLogMailSender()
+	if smtpHost := os.Getenv("SMTP_HOST"); smtpHost != "" {
+		smtpPort, _ := strconv.Atoi(os.Getenv("SMTP_PORT"))
+		mailSender = services.NewSMTPMailSender(
+			smtpHost,
+			smtpPort,
+			os.Getenv("SMTP_USER"),
+			os.Getenv("SMTP_PASSWORD"),
+			os.Getenv("SMTP_FROM"),
+		)
+	}
+	emailVerificationRepo, err := repoFactory.NewEmailVerificationRepository()
+	if err != nil {
+		log.Fatalf("Failed to create email verification repository: %v", err)
+	}
+	emailVerificationService := services.NewEmailVerificationService(emailVerificationRepo, userRepo, systemSettingsRepo, mailSender)
+
+	// アクティビティログのサービスの作成（認証ルートと管理者機能で使用）
+	activityLogRepo, err := repoFactory.NewActivityLogRepository()
+	if err != nil {
+		log.Fatalf("Failed to create activity log repository: %v", err)
+	}
+	activityLogService := services.NewActivityLogService(activityLogRepo)
+
+	// パーソナルアクセストークンのサービスの作成
+	personalAccessTokenRepo, err := repoFactory.NewPersonalAccessTokenRepository()
+	if err != nil {
+		log.Fatalf("Failed to create personal access token repository: %v", err)
+	}
+	tokenService := services.NewPersonalAccessTokenService(personalAccessTokenRepo, userRepo)
+
+	// シングルサインオン（OpenID Connect）のサービスの作成
+	// OIDC_ISSUER_URL が設定されている場合のみIDプロバイダーを登録する
+	userIdentityRepo, err := repoFactory.NewUserIdentityRepository()
+	if err != nil {
+		log.Fatalf("Failed to create user identity repository: %v", err)
+	}
+	oidcLoginStateRepo, err := repoFactory.NewOIDCLoginStateRepository()
+	if err != nil {
+		log.Fatalf("Failed to create OIDC login state repository: %v", err)
+	}
+	var oidcProviders []*services.OIDCProvider
+	if issuerURL := os.Getenv("OIDC_ISSUER_URL"); issuerURL != "" {
+		providerName := os.Getenv("OIDC_PROVIDER_NAME")
+		if providerName == "" {
+			providerName = "oidc"
+		}
+		oidcProviders = append(oidcProviders, services.NewOIDCProvider(services.OIDCProviderConfig{
+			Name:         providerName,
+			IssuerURL:    issuerURL,
+			ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+			ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+			RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+			Scopes:       splitEnvList(os.Getenv("OIDC_SCOPES")),
+			GroupsClaim:  os.Getenv("OIDC_GROUPS_CLAIM"),
+			AdminGroups:  splitEnvList(os.Getenv("OIDC_ADMIN_GROUPS")),
+		}, nil))
+	}
+	oidcService := services.NewOIDCService(oidcLoginStateRepo, userIdentityRepo, userRepo, authService, oidcProviders...)
+
 	// Ginの設定
 	r := gin.Default()
 
@@ -124,23 +211,34 @@ func main() {
 		authGroup := apiGroup.Group("/auth")
 		{
 			// 認証ハンドラーの作成
-			authHandler := api.NewAuthHandler(authService)
+			authHandler := api.NewAuthHandler(authService, activityLogService, emailVerificationService)
+			twoFactorHandler := api.NewTwoFactorHandler(twoFactorService, activityLogService)
+			emailVerificationHandler := api.NewEmailVerificationHandler(emailVerificationService, activityLogService)
 
 			// 認証ルートの設定
 			authGroup.POST("/register", authHandler.Register)
 			authGroup.POST("/login", authHandler.Login)
+			authGroup.POST("/login/2fa", twoFactorHandler.CompleteLogin)
 			authGroup.POST("/refresh-token", authHandler.RefreshToken)
 			authGroup.POST("/password-reset", authHandler.InitiatePasswordReset)
 			authGroup.POST("/password-reset/validate", authHandler.ValidatePasswordResetToken)
 			authGroup.POST("/password-reset/complete", authHandler.CompletePasswordReset)
+			authGroup.POST("/verify-email", emailVerificationHandler.VerifyEmail)
+
+			// シングルサインオンのルートの設定
+			oidcHandler := api.NewOIDCHandler(oidcService)
+			oidcHandler.RegisterRoutes(authGroup)
 
 			// 認証が必要なルート
+			// パスワードの変更などはパーソナルアクセストークンでは利用できない
 			authRequiredGroup := authGroup.Group("/")
-			authRequiredGroup.Use(api.AuthMiddleware(authService))
+			authRequiredGroup.Use(api.AuthMiddleware(authService, tokenService), api.SessionOnlyMiddleware())
 			{
 				authRequiredGroup.POST("/logout", authHandler.Logout)
 				authRequiredGroup.POST("/logout-all", authHandler.LogoutAll)
 				authRequiredGroup.POST("/change-password", authHandler.ChangePassword)
+				twoFactorHandler.RegisterRoutes(authRequiredGroup)
+				authRequiredGroup.POST("/verify-email/resend", emailVerificationHandler.ResendVerificationEmail)
 			}
 		}
 
@@ -177,22 +275,42 @@ func main() {
 				log.Fatalf("Failed to create reaction repository: %v", err)
 			}
 
-			userRepo, err := repoFactory.NewUserRepository()
+			issueEventRepo, err := repoFactory.NewIssueEventRepository()
 			if err != nil {
-				log.Fatalf("Failed to create user repository: %v", err)
+				log.Fatalf("Failed to create issue event repository: %v", err)
 			}
 
-			// 管理者機能用リポジトリの作成
-			systemSettingsRepo, err := repoFactory.NewSystemSettingsRepository()
+			revisionRepo, err := repoFactory.NewBodyRevisionRepository()
 			if err != nil {
-				log.Fatalf("Failed to create system settings repository: %v", err)
+				log.Fatalf("Failed to create body revision repository: %v", err)
 			}
 
-			activityLogRepo, err := repoFactory.NewActivityLogRepository()
+			issueLinkRepo, err := repoFactory.NewIssueLinkRepository()
 			if err != nil {
-				log.Fatalf("Failed to create activity log repository: %v", err)
+				log.Fatalf("Failed to create issue link repository: %v", err)
+			}
+
+			templateRepo, err := repoFactory.NewTemplateRepository()
+			if err != nil {
+				log.Fatalf("Failed to create template repository: %v", err)
+			}
+
+			redirectRepo, err := repoFactory.NewRedirectRepository()
+			if err != nil {
+				log.Fatalf("Failed to create redirect repository: %v", err)
+			}
+
+			conversionRepo, err := repoFactory.NewConversionRepository()
+			if err != nil {
+				log.Fatalf("Failed to create conversion repository: %v", err)
+			}
+
+			userRepo, err := repoFactory.NewUserRepository()
+			if err != nil {
+				log.Fatalf("Failed to create user repository: %v", err)
 			}
 
+			// 管理者機能用リポジトリの作成
 			backupRepo, err := repoFactory.NewBackupRepository()
 			if err != nil {
 				log.Fatalf("Failed to create backup repository: %v", err)
@@ -204,103 +322,259 @@ func main() {
 				log.Fatalf("Failed to create search service: %v", err)
 			}
 
+			// 起動時に検索インデックスの整合性を確認
+			if err := searchService.VerifyIndex(context.Background()); err != nil {
+				log.Printf("Warning: failed to verify search index: %v", err)
+			}
+
+			// 書き込み時に検索インデックスを更新するリポジトリに差し替え
+			issueRepo = services.NewIndexingIssueRepository(issueRepo, searchService)
+			commentRepo = services.NewIndexingCommentRepository(commentRepo, searchService)
+			discussionRepo = services.NewIndexingDiscussionRepository(discussionRepo, searchService)
+			milestoneRepo = services.NewIndexingMilestoneRepository(milestoneRepo, searchService)
+			labelRepo = services.NewIndexingLabelRepository(labelRepo, searchService)
+			conversionRepo = services.NewIndexingConversionRepository(conversionRepo, commentRepo, searchService)
+
+			// 通知サービスの作成
+			smtpPort, _ := strconv.Atoi(os.Getenv("SMTP_PORT"))
+			notificationService, err := services.NewNotificationService(
+				repoFactory,
+				os.Getenv("VAPID_PRIVATE_KEY"),
+				os.Getenv("VAPID_PUBLIC_KEY"),
+				os.Getenv("SMTP_HOST"),
+				smtpPort,
+				os.Getenv("SMTP_USER"),
+				os.Getenv("SMTP_PASSWORD"),
+				os.Getenv("SMTP_FROM"),
+				os.Getenv("APP_BASE_URL"),
+			)
+			if err != nil {
+				log.Fatalf("Failed to create notification service: %v", err)
+			}
+
+			mentionRepo, err := repoFactory.NewMentionRepository()
+			if err != nil {
+				log.Fatalf("Failed to create mention repository: %v", err)
+			}
+			mentionService := services.NewMentionService(mentionRepo, userRepo, notificationService)
+
+			// ドメインイベントの配信設定
+			eventBus := services.NewEventBus()
+			services.NewNotificationDispatcher(notificationService, issueRepo, discussionRepo, commentRepo).Register(eventBus)
+			mentionService.Register(eventBus)
+
+			// リポジトリの権限判定サービスの作成
+			repoRepo, err := repoFactory.NewRepositoryRepository()
+			if err != nil {
+				log.Fatalf("Failed to create repository repository: %v", err)
+			}
+			repoMemberRepo, err := repoFactory.NewRepositoryMemberRepository()
+			if err != nil {
+				log.Fatalf("Failed to create repository member repository: %v", err)
+			}
+			permissionService := services.NewRepositoryPermissionService(repoRepo, repoMemberRepo, userRepo)
+
 			// 各種ハンドラーの作成
-			issueHandler := api.NewIssueHandler(issueRepo, labelRepo, milestoneRepo, userRepo)
-			discussionHandler := api.NewDiscussionHandler(discussionRepo, labelRepo, userRepo)
-			commentHandler := api.NewCommentHandler(commentRepo, issueRepo, discussionRepo, reactionRepo, userRepo)
-			labelHandler := api.NewLabelHandler(labelRepo)
-			milestoneHandler := api.NewMilestoneHandler(milestoneRepo)
-			assignmentHandler := api.NewAssignmentHandler(issueRepo, userRepo)
+			reactionService := services.NewReactionService(reactionRepo)
+			revisionService := services.NewRevisionService(revisionRepo)
+			issueLinkService := services.NewIssueLinkService(issueLinkRepo, issueRepo)
+			conversionService := services.NewConversionService(conversionRepo, labelRepo, redirectRepo)
+			issueHandler := api.NewIssueHandler(issueRepo, labelRepo, milestoneRepo, userRepo, commentRepo, issueEventRepo, templateRepo, eventBus, reactionService, revisionService, issueLinkService, conversionService, permissionService)
+			discussionHandler := api.NewDiscussionHandler(discussionRepo, commentRepo, labelRepo, userRepo, templateRepo, eventBus, reactionService, revisionService, conversionService, permissionService)
+			commentHandler := api.NewCommentHandler(commentRepo, issueRepo, discussionRepo, userRepo, eventBus, reactionService, revisionService, permissionService)
+			reactionHandler := api.NewReactionHandler(issueRepo, discussionRepo, commentRepo, reactionService, permissionService)
+			revisionHandler := api.NewRevisionHandler(issueRepo, discussionRepo, commentRepo, revisionService, permissionService)
+			issueLinkHandler := api.NewIssueLinkHandler(issueRepo, issueLinkService, permissionService)
+			labelHandler := api.NewLabelHandler(labelRepo, permissionService)
+			templateHandler := api.NewTemplateHandler(templateRepo, labelRepo, userRepo, permissionService)
+			milestoneHandler := api.NewMilestoneHandler(milestoneRepo, issueRepo, issueEventRepo, permissionService)
+			assignmentHandler := api.NewAssignmentHandler(issueRepo, userRepo, eventBus, permissionService)
+			notificationHandler := api.NewNotificationHandler(notificationService)
+			mentionHandler := api.NewMentionHandler(mentionService)
 			markdownHandler := api.NewMarkdownHandler()
-			draftHandler := api.NewDraftHandler(issueRepo, discussionRepo)
-			searchHandler := api.NewSearchHandler(searchService)
+			draftHandler := api.NewDraftHandler(issueRepo, discussionRepo, labelRepo, permissionService)
+			searchHandler := api.NewSearchHandler(searchService, permissionService)
+			personalAccessTokenHandler := api.NewPersonalAccessTokenHandler(tokenService)
+
+			savedSearchRepo, err := repoFactory.NewSavedSearchRepository()
+			if err != nil {
+				log.Fatalf("Failed to create saved search repository: %v", err)
+			}
+			savedSearchHandler := api.NewSavedSearchHandler(savedSearchRepo, services.NewSavedSearchService(savedSearchRepo, searchService, permissionService))
 
 			// 管理者機能用サービスとハンドラーの作成
-			activityLogService := services.NewActivityLogService(activityLogRepo)
 			backupService := services.NewBackupService(backupRepo, "backups", string(dbConfig.Type), dbConfig.DSN())
 			systemMetricsService := services.NewSystemMetricsService(userRepo, issueRepo, discussionRepo, commentRepo, backupRepo)
-			adminHandler := api.NewAdminHandler(userRepo, systemSettingsRepo, activityLogService, backupService, systemMetricsService)
+			adminHandler := api.NewAdminHandler(userRepo, systemSettingsRepo, activityLogService, backupService, systemMetricsService, twoFactorService, loginThrottleService)
 
 			// リポジトリ管理のハンドラー作成
-			repoRepo, err := repoFactory.NewRepositoryRepository()
-			if err != nil {
-				log.Fatalf("Failed to create repository repository: %v", err)
-			}
-			repositoryHandler := api.NewRepositoryHandler(repoRepo, activityLogService)
+			repositoryHandler := api.NewRepositoryHandler(repoRepo, repoMemberRepo, userRepo, activityLogService, permissionService)
 
 			// 認証が必要なルートグループ
+			// メールアドレスの確認が必須の場合、未確認のユーザーは書き込みができない
 			authGroup := v1.Group("/")
-			authGroup.Use(api.AuthMiddleware(authService))
+			authGroup.Use(api.AuthMiddleware(authService, tokenService), api.RequireVerifiedEmailMiddleware(emailVerificationService))
 
 			// 管理者権限が必要なルートグループ
 			adminGroup := authGroup.Group("/")
 			adminGroup.Use(api.AdminMiddleware())
 
+			// 未認証でも利用できるが、認証済みの場合はログインユーザーを識別するルートグループ
+			optionalAuthGroup := v1.Group("/")
+			optionalAuthGroup.Use(api.OptionalAuthMiddleware(authService, tokenService))
+
+			// パーソナルアクセストークンのスコープ
+			// GET・HEADは :read、それ以外は :write のスコープが必要（JWTで認証したリクエストはスコープで制限しない）
+			issueTokenScope := api.RequireScope(models.ScopeIssuesRead, models.ScopeIssuesWrite)
+			discussionTokenScope := api.RequireScope(models.ScopeDiscussionsRead, models.ScopeDiscussionsWrite)
+			commentTokenScope := api.RequireScope(models.ScopeCommentsRead, models.ScopeCommentsWrite)
+			repoTokenScope := api.RequireScope(models.ScopeReposRead, models.ScopeReposWrite)
+			userTokenScope := api.RequireScope(models.ScopeUserRead, models.ScopeUserWrite)
+
+			// リポジトリのスコープ
+			// 従来のルートは ?repository= で絞り込み、作成時は未指定ならデフォルトリポジトリに所属させる
+			repoScope := api.RepositoryScopeMiddleware(repoRepo, permissionService)
+			defaultRepoScope := api.DefaultRepositoryScopeMiddleware(repoRepo, permissionService)
+
 			// Issue関連のエンドポイント
-			v1.GET("/issues", issueHandler.ListIssues)
-			v1.GET("/issues/:id", issueHandler.GetIssue)
-			v1.GET("/issues/search", issueHandler.SearchIssues)
-			authGroup.POST("/issues", issueHandler.CreateIssue)
-			authGroup.PUT("/issues/:id", issueHandler.UpdateIssue)
-			authGroup.DELETE("/issues/:id", issueHandler.DeleteIssue)
-			authGroup.PATCH("/issues/:id/status", issueHandler.UpdateIssueStatus)
-			authGroup.PATCH("/issues/:id/draft", issueHandler.UpdateIssueDraftStatus)
+			// 一覧・詳細はログインユーザーが付けたリアクションを判定するため任意認証
+			optionalAuthGroup.GET("/issues", issueTokenScope, repoScope, issueHandler.ListIssues)
+			optionalAuthGroup.GET("/issues/:id", issueTokenScope, issueHandler.GetIssue)
+			optionalAuthGroup.GET("/issues/:id/timeline", issueTokenScope, issueHandler.GetIssueTimeline)
+			optionalAuthGroup.GET("/issues/search", issueTokenScope, repoScope, issueHandler.SearchIssues)
+			authGroup.POST("/issues", issueTokenScope, defaultRepoScope, issueHandler.CreateIssue)
+			authGroup.PUT("/issues/:id", issueTokenScope, issueHandler.UpdateIssue)
+			authGroup.DELETE("/issues/:id", issueTokenScope, issueHandler.DeleteIssue)
+			authGroup.PATCH("/issues/:id/status", issueTokenScope, issueHandler.UpdateIssueStatus)
+			authGroup.PATCH("/issues/:id/draft", issueTokenScope, issueHandler.UpdateIssueDraftStatus)
+			optionalAuthGroup.GET("/issues/:id/tasks", issueTokenScope, issueHandler.ListIssueTasks)
+			authGroup.POST("/issues/:id/tasks/:index/convert", issueTokenScope, issueHandler.ConvertTaskToIssue)
+			authGroup.POST("/issues/:id/convert-to-discussion", issueTokenScope, discussionTokenScope, issueHandler.ConvertToDiscussion)
 
 			// Discussion関連のエンドポイント
-			v1.GET("/discussions", discussionHandler.ListDiscussions)
-			v1.GET("/discussions/:id", discussionHandler.GetDiscussion)
-			v1.GET("/discussions/search", discussionHandler.SearchDiscussions)
-			authGroup.POST("/discussions", discussionHandler.CreateDiscussion)
-			authGroup.PUT("/discussions/:id", discussionHandler.UpdateDiscussion)
-			authGroup.DELETE("/discussions/:id", discussionHandler.DeleteDiscussion)
-			authGroup.PATCH("/discussions/:id/status", discussionHandler.UpdateDiscussionStatus)
-			authGroup.PATCH("/discussions/:id/draft", discussionHandler.UpdateDiscussionDraftStatus)
+			optionalAuthGroup.GET("/discussions", discussionTokenScope, repoScope, discussionHandler.ListDiscussions)
+			optionalAuthGroup.GET("/discussions/:id", discussionTokenScope, discussionHandler.GetDiscussion)
+			optionalAuthGroup.GET("/discussions/search", discussionTokenScope, repoScope, discussionHandler.SearchDiscussions)
+			authGroup.POST("/discussions", discussionTokenScope, defaultRepoScope, discussionHandler.CreateDiscussion)
+			authGroup.PUT("/discussions/:id", discussionTokenScope, discussionHandler.UpdateDiscussion)
+			authGroup.DELETE("/discussions/:id", discussionTokenScope, discussionHandler.DeleteDiscussion)
+			authGroup.PATCH("/discussions/:id/status", discussionTokenScope, discussionHandler.UpdateDiscussionStatus)
+			authGroup.PATCH("/discussions/:id/draft", discussionTokenScope, discussionHandler.UpdateDiscussionDraftStatus)
+			authGroup.PUT("/discussions/:id/answer", discussionTokenScope, discussionHandler.MarkAnswer)
+			authGroup.DELETE("/discussions/:id/answer", discussionTokenScope, discussionHandler.UnmarkAnswer)
+			authGroup.POST("/discussions/:id/convert-to-issue", discussionTokenScope, issueTokenScope, discussionHandler.ConvertToIssue)
 
 			// コメント関連のエンドポイント
-			v1.GET("/comments/:id", commentHandler.GetComment)
-			v1.GET("/:target_type/:target_id/comments", commentHandler.ListComments)
-			v1.GET("/comments/:comment_id/replies", commentHandler.ListReplies)
-			authGroup.POST("/:target_type/:target_id/comments", commentHandler.CreateComment)
-			authGroup.POST("/:target_type/:target_id/comments/reply", commentHandler.CreateReplyComment)
-			authGroup.PUT("/comments/:id", commentHandler.UpdateComment)
-			authGroup.DELETE("/comments/:id", commentHandler.DeleteComment)
+			optionalAuthGroup.GET("/comments/:id", commentTokenScope, commentHandler.GetComment)
+			optionalAuthGroup.GET("/:target_type/:target_id/comments", commentTokenScope, commentHandler.ListComments)
+			optionalAuthGroup.GET("/comments/:comment_id/replies", commentTokenScope, commentHandler.ListReplies)
+			authGroup.POST("/:target_type/:target_id/comments", commentTokenScope, commentHandler.CreateComment)
+			authGroup.POST("/:target_type/:target_id/comments/reply", commentTokenScope, commentHandler.CreateReplyComment)
+			authGroup.PUT("/comments/:id", commentTokenScope, commentHandler.UpdateComment)
+			authGroup.DELETE("/comments/:id", commentTokenScope, commentHandler.DeleteComment)
+
+			// リアクション関連のエンドポイント
+			reactionHandler.RegisterRoutes(authGroup.Group("/", commentTokenScope))
+
+			// 編集履歴関連のエンドポイント
+			// 閲覧は対象のリポジトリの閲覧権限が必要、版の削除は管理者のみ
+			revisionHandler.RegisterRoutes(optionalAuthGroup.Group("/", commentTokenScope), adminGroup)
+
+			// Issue間の関連のエンドポイント
+			// 閲覧はリポジトリの閲覧権限、変更は作成者またはtriage以上のロールが必要
+			issueLinkHandler.RegisterRoutes(optionalAuthGroup.Group("/", issueTokenScope), authGroup.Group("/", issueTokenScope))
 
 			// ラベル関連のエンドポイント
-			v1.GET("/labels", labelHandler.ListLabels)
-			v1.GET("/labels/:id", labelHandler.GetLabel)
-			adminGroup.POST("/labels", labelHandler.CreateLabel)
-			adminGroup.PUT("/labels/:id", labelHandler.UpdateLabel)
-			adminGroup.DELETE("/labels/:id", labelHandler.DeleteLabel)
+			// 一覧・詳細は非公開リポジトリのメンバーを識別するため任意認証、変更はwrite以上のロールが必要
+			optionalAuthGroup.GET("/labels", repoTokenScope, repoScope, labelHandler.ListLabels)
+			optionalAuthGroup.GET("/labels/scopes", repoTokenScope, repoScope, labelHandler.ListLabelScopes)
+			optionalAuthGroup.GET("/labels/:id", repoTokenScope, labelHandler.GetLabel)
+			authGroup.POST("/labels", repoTokenScope, defaultRepoScope, labelHandler.CreateLabel)
+			authGroup.PUT("/labels/:id", repoTokenScope, labelHandler.UpdateLabel)
+			authGroup.DELETE("/labels/:id", repoTokenScope, labelHandler.DeleteLabel)
+
+			// テンプレート関連のエンドポイント
+			// 閲覧はリポジトリの閲覧権限、作成・更新・削除はadminロールが必要
+			optionalAuthGroup.GET("/templates", repoTokenScope, repoScope, templateHandler.ListTemplates)
+			optionalAuthGroup.GET("/templates/:id", repoTokenScope, templateHandler.GetTemplate)
+			authGroup.POST("/templates", repoTokenScope, defaultRepoScope, templateHandler.CreateTemplate)
+			authGroup.PUT("/templates/:id", repoTokenScope, templateHandler.UpdateTemplate)
+			authGroup.DELETE("/templates/:id", repoTokenScope, templateHandler.DeleteTemplate)
 
 			// マイルストーン関連のエンドポイント
-			v1.GET("/milestones", milestoneHandler.ListMilestones)
-			v1.GET("/milestones/:id", milestoneHandler.GetMilestone)
-			authGroup.POST("/milestones", milestoneHandler.CreateMilestone)
-			authGroup.PUT("/milestones/:id", milestoneHandler.UpdateMilestone)
-			authGroup.DELETE("/milestones/:id", milestoneHandler.DeleteMilestone)
-			authGroup.PATCH("/milestones/:id/status", milestoneHandler.UpdateMilestoneStatus)
+			optionalAuthGroup.GET("/milestones", repoTokenScope, repoScope, milestoneHandler.ListMilestones)
+			optionalAuthGroup.GET("/milestones/:id", repoTokenScope, milestoneHandler.GetMilestone)
+			optionalAuthGroup.GET("/milestones/:id/burndown", repoTokenScope, milestoneHandler.GetBurndown)
+			authGroup.POST("/milestones", repoTokenScope, defaultRepoScope, milestoneHandler.CreateMilestone)
+			authGroup.PUT("/milestones/:id", repoTokenScope, milestoneHandler.UpdateMilestone)
+			authGroup.DELETE("/milestones/:id", repoTokenScope, milestoneHandler.DeleteMilestone)
+			authGroup.PATCH("/milestones/:id/status", repoTokenScope, milestoneHandler.UpdateMilestoneStatus)
+
+			// リポジトリ単位のエンドポイント（Issue・Discussionはリポジトリ内の番号で参照）
+			// 閲覧できないリポジトリは存在しないものとして404を返す
+			optionalAuthGroup.GET("/repos", repoTokenScope, repositoryHandler.ListVisibleRepositories)
+			repoPublicGroup := optionalAuthGroup.Group("/repos/:name", repoScope)
+			{
+				repoPublicGroup.GET("", repoTokenScope, repositoryHandler.GetRepositoryByName)
+				repoPublicGroup.GET("/members", repoTokenScope, repositoryHandler.ListMembers)
+				repoPublicGroup.GET("/issues", issueTokenScope, issueHandler.ListIssues)
+				repoPublicGroup.GET("/issues/search", issueTokenScope, issueHandler.SearchIssues)
+				repoPublicGroup.GET("/issues/:number", issueTokenScope, issueHandler.GetIssueByNumber)
+				repoPublicGroup.GET("/discussions", discussionTokenScope, discussionHandler.ListDiscussions)
+				repoPublicGroup.GET("/discussions/search", discussionTokenScope, discussionHandler.SearchDiscussions)
+				repoPublicGroup.GET("/discussions/:number", discussionTokenScope, discussionHandler.GetDiscussionByNumber)
+				repoPublicGroup.GET("/labels", repoTokenScope, labelHandler.ListLabels)
+				repoPublicGroup.GET("/labels/scopes", repoTokenScope, labelHandler.ListLabelScopes)
+				repoPublicGroup.GET("/milestones", repoTokenScope, milestoneHandler.ListMilestones)
+				repoPublicGroup.GET("/templates", repoTokenScope, templateHandler.ListTemplates)
+			}
+			repoAuthGroup := authGroup.Group("/repos/:name", repoScope)
+			{
+				repoAuthGroup.POST("/issues", issueTokenScope, issueHandler.CreateIssue)
+				repoAuthGroup.POST("/discussions", discussionTokenScope, discussionHandler.CreateDiscussion)
+				repoAuthGroup.POST("/milestones", repoTokenScope, milestoneHandler.CreateMilestone)
+				repoAuthGroup.POST("/labels", repoTokenScope, labelHandler.CreateLabel)
+				repoAuthGroup.POST("/templates", repoTokenScope, templateHandler.CreateTemplate)
+				repoAuthGroup.PUT("/members/:user_id", repoTokenScope, repositoryHandler.SaveMember)
+				repoAuthGroup.DELETE("/members/:user_id", repoTokenScope, repositoryHandler.RemoveMember)
+			}
 
 			// アサイン関連のエンドポイント
-			authGroup.PUT("/issues/:id/assign", assignmentHandler.AssignIssue)
-			authGroup.PUT("/issues/:id/unassign", assignmentHandler.UnassignIssue)
+			authGroup.PUT("/issues/:id/assign", issueTokenScope, assignmentHandler.AssignIssue)
+			authGroup.PUT("/issues/:id/unassign", issueTokenScope, assignmentHandler.UnassignIssue)
 
 			// Markdown関連のエンドポイント
 			v1.POST("/markdown", markdownHandler.RenderMarkdown)
 			v1.POST("/markdown/raw", markdownHandler.RenderRawMarkdown)
 
 			// ドラフト関連のエンドポイント
-			authGroup.GET("/drafts", draftHandler.ListDrafts)
-			authGroup.POST("/drafts/issues", draftHandler.SaveIssueDraft)
-			authGroup.PUT("/drafts/issues/:id", draftHandler.SaveIssueDraft)
-			authGroup.POST("/drafts/discussions", draftHandler.SaveDiscussionDraft)
-			authGroup.PUT("/drafts/discussions/:id", draftHandler.SaveDiscussionDraft)
+			authGroup.GET("/drafts", userTokenScope, draftHandler.ListDrafts)
+			authGroup.POST("/drafts/issues", issueTokenScope, defaultRepoScope, draftHandler.SaveIssueDraft)
+			authGroup.PUT("/drafts/issues/:id", issueTokenScope, draftHandler.SaveIssueDraft)
+			authGroup.POST("/drafts/discussions", discussionTokenScope, defaultRepoScope, draftHandler.SaveDiscussionDraft)
+			authGroup.PUT("/drafts/discussions/:id", discussionTokenScope, draftHandler.SaveDiscussionDraft)
+
+			// 通知関連のエンドポイント
+			notificationHandler.RegisterRoutes(authGroup.Group("/", userTokenScope))
+
+			// メンション関連のエンドポイント
+			authGroup.GET("/users/me/mentions", userTokenScope, mentionHandler.ListMyMentions)
 
 			// 検索関連のエンドポイント
-			searchHandler.RegisterRoutes(r)
+			// 検索は未認証でも利用できるが、認証済みの場合は assignee:@me などを解決する
+			searchHandler.RegisterRoutes(optionalAuthGroup.Group("/", repoTokenScope), adminGroup)
+
+			// 保存済み検索関連のエンドポイント
+			savedSearchHandler.RegisterRoutes(authGroup.Group("/", userTokenScope))
+
+			// パーソナルアクセストークン関連のエンドポイント
+			// トークンの管理はパーソナルアクセストークンでは利用できない
+			personalAccessTokenHandler.RegisterRoutes(authGroup.Group("/", api.SessionOnlyMiddleware()))
 
 			// 管理者専用のエンドポイント
 			adminGroup.GET("/users", adminHandler.GetUsers)
 			adminGroup.PUT("/users/:id", adminHandler.UpdateUser)
+			adminGroup.POST("/users/:id/unlock", adminHandler.UnlockUser)
 
 			adminGroup.GET("/settings", adminHandler.GetSystemSettings)
 			adminGroup.PUT("/settings", adminHandler.UpdateSystemSettings)
@@ -328,3 +602,14 @@ func main() {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+// splitEnvList はカンマ区切りの環境変数の値を空白を除いた配列に変換します
+func splitEnvList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
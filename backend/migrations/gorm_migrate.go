@@ -12,10 +12,67 @@ import (
 func GormMigrate(db *gorm.DB) error {
 	log.Println("Running GORM database migrations...")
 
+	// ラベルのマイグレーション（Issue・Discussionのラベルが参照するため先に実行）
+	if err := models.AutoMigrateLabel(db); err != nil {
+		return fmt.Errorf("failed to migrate label table: %w", err)
+	}
+	if err := PrepareLegacyIssueLabels(db); err != nil {
+		return err
+	}
+
 	// Issue関連のマイグレーション
 	if err := models.AutoMigrateIssue(db); err != nil {
 		return fmt.Errorf("failed to migrate issue tables: %w", err)
 	}
+	if err := models.AutoMigrateIssueEvent(db); err != nil {
+		return fmt.Errorf("failed to migrate issue event table: %w", err)
+	}
+	if err := models.AutoMigrateIssueLink(db); err != nil {
+		return fmt.Errorf("failed to migrate issue link table: %w", err)
+	}
+
+	// Discussion・マイルストーンのマイグレーション
+	if err := models.AutoMigrateDiscussion(db); err != nil {
+		return fmt.Errorf("failed to migrate discussion table: %w", err)
+	}
+	if err := models.AutoMigrateMilestone(db); err != nil {
+		return fmt.Errorf("failed to migrate milestone table: %w", err)
+	}
+
+	// テンプレートのマイグレーション
+	if err := models.AutoMigrateTemplate(db); err != nil {
+		return fmt.Errorf("failed to migrate template table: %w", err)
+	}
+
+	// Issue・Discussionの変換のリダイレクトのマイグレーション
+	if err := models.AutoMigrateRedirect(db); err != nil {
+		return fmt.Errorf("failed to migrate redirect table: %w", err)
+	}
+
+	// パーソナルアクセストークンのマイグレーション
+	if err := models.AutoMigratePersonalAccessToken(db); err != nil {
+		return fmt.Errorf("failed to migrate personal access token table: %w", err)
+	}
+
+	// シングルサインオンのマイグレーション
+	if err := models.AutoMigrateOIDC(db); err != nil {
+		return fmt.Errorf("failed to migrate oidc tables: %w", err)
+	}
+
+	// 二要素認証のマイグレーション
+	if err := models.AutoMigrateTwoFactor(db); err != nil {
+		return fmt.Errorf("failed to migrate two factor tables: %w", err)
+	}
+
+	// ログインの試行の制限のマイグレーション
+	if err := models.AutoMigrateLoginThrottle(db); err != nil {
+		return fmt.Errorf("failed to migrate login throttle table: %w", err)
+	}
+
+	// メールアドレスの確認のマイグレーション
+	if err := models.AutoMigrateEmailVerification(db); err != nil {
+		return fmt.Errorf("failed to migrate email verification table: %w", err)
+	}
 
 	// システム設定のマイグレーション
 	if err := models.AutoMigrateSystemSettings(db); err != nil {
@@ -27,10 +84,63 @@ func GormMigrate(db *gorm.DB) error {
 		return fmt.Errorf("failed to migrate activity log tables: %w", err)
 	}
 
+	// メンションのマイグレーション
+	if err := models.AutoMigrateMention(db); err != nil {
+		return fmt.Errorf("failed to migrate mention table: %w", err)
+	}
+
+	// 編集履歴のマイグレーション
+	if err := models.AutoMigrateBodyRevision(db); err != nil {
+		return fmt.Errorf("failed to migrate body revision table: %w", err)
+	}
+
+	// リアクションのマイグレーション
+	if err := models.AutoMigrateReaction(db); err != nil {
+		return fmt.Errorf("failed to migrate reaction table: %w", err)
+	}
+
+	// 保存済み検索のマイグレーション
+	if err := models.AutoMigrateSavedSearch(db); err != nil {
+		return fmt.Errorf("failed to migrate saved search tables: %w", err)
+	}
+
 	// リポジトリのマイグレーション
 	if err := models.AutoMigrateRepository(db); err != nil {
 		return fmt.Errorf("failed to migrate repository table: %w", err)
 	}
+	if err := models.AutoMigrateRepositoryMember(db); err != nil {
+		return fmt.Errorf("failed to migrate repository member table: %w", err)
+	}
+
+	// 既存データのリポジトリへの割り当てと採番
+	if err := MigrateRepositoryScope(db); err != nil {
+		return fmt.Errorf("failed to migrate repository scope: %w", err)
+	}
+
+	// 文字列で保持されていたラベルのラベルIDへの移行
+	if err := MigrateLabelReferences(db); err != nil {
+		return err
+	}
+
+	// 既存のラベルへのスコープの設定
+	if err := MigrateLabelScopes(db); err != nil {
+		return err
+	}
+
+	// 既存の担当者の複数担当者テーブルへの移行
+	if err := MigrateIssueAssignees(db); err != nil {
+		return err
+	}
+
+	// 既存のIssueのチェックリストの集計
+	if err := MigrateIssueTaskProgress(db); err != nil {
+		return err
+	}
+
+	// 既存のユーザーへのメールアドレスの確認日時の設定
+	if err := MigrateUserEmailVerified(db); err != nil {
+		return err
+	}
 
 	log.Println("GORM database migration completed successfully")
 	return nil
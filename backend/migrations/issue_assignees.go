@@ -0,0 +1,21 @@
+package migrations
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// MigrateIssueAssignees は issues.assignee_id に設定されている既存の担当者を issue_assignees に移行します
+// 担当者が未登録のIssueのみを対象にするため、何度実行しても結果が変わりません
+func MigrateIssueAssignees(db *gorm.DB) error {
+	err := db.Exec(`
+		INSERT INTO issue_assignees (issue_id, user_id, position)
+		SELECT i.id, i.assignee_id, 0 FROM issues i
+		WHERE i.assignee_id IS NOT NULL AND i.assignee_id <> 0
+		AND NOT EXISTS (SELECT 1 FROM issue_assignees ia WHERE ia.issue_id = i.id)`).Error
+	if err != nil {
+		return fmt.Errorf("failed to migrate issue assignees: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"gorm.io/gorm"
+)
+
+// MigrateIssueTaskProgress は既存のIssueに本文から集計したチェックリストの完了数を設定します
+// 未集計でチェックボックスを含みうる本文のみを対象にするため、何度実行しても結果が変わりません
+func MigrateIssueTaskProgress(db *gorm.DB) error {
+	var issues []struct {
+		ID   int64
+		Body string
+	}
+	if err := db.Model(&models.IssueGorm{}).Unscoped().
+		Select("id, body").
+		Where("task_total = 0 AND body LIKE ?", "%]%").
+		Scan(&issues).Error; err != nil {
+		return fmt.Errorf("failed to list issues for task progress migration: %w", err)
+	}
+
+	for _, issue := range issues {
+		progress := models.ComputeTaskProgress(issue.Body)
+		if progress.Total == 0 {
+			continue
+		}
+		if err := db.Model(&models.IssueGorm{}).Unscoped().Where("id = ?", issue.ID).Updates(map[string]interface{}{
+			"task_checked": progress.Completed,
+			"task_total":   progress.Total,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to migrate task progress of issue %d: %w", issue.ID, err)
+		}
+	}
+	return nil
+}
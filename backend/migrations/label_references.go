@@ -0,0 +1,138 @@
+package migrations
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// legacyIssueLabelsTable はラベル名を文字列で保持していた旧issue_labelsテーブルの退避先
+const legacyIssueLabelsTable = "issue_labels_legacy"
+
+// PrepareLegacyIssueLabels はラベル名を文字列で保持する旧issue_labelsテーブルを退避し、
+// ラベルIDを参照する新しいissue_labelsテーブルを作成できるようにします
+func PrepareLegacyIssueLabels(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable("issue_labels") || !migrator.HasColumn("issue_labels", "label") {
+		return nil
+	}
+	if err := migrator.RenameTable("issue_labels", legacyIssueLabelsTable); err != nil {
+		return fmt.Errorf("failed to rename legacy issue labels table: %w", err)
+	}
+	return nil
+}
+
+// legacyIssueLabel は旧issue_labelsテーブルの行
+type legacyIssueLabel struct {
+	IssueID      int64
+	Label        string
+	RepositoryID int64
+}
+
+// legacyDiscussionLabels は旧discussions.labelsカラム（JSON）の行
+type legacyDiscussionLabels struct {
+	ID           int64
+	RepositoryID int64
+	Labels       string
+}
+
+// MigrateLabelReferences は文字列で保持されていたIssue・Discussionのラベルを、
+// リポジトリ内のラベルIDを参照する issue_labels・discussion_labels に移行します
+// 対応するラベルが存在しない場合はラベルを作成し、種類が合わない場合は種類を both に変更します
+// 移行後は旧テーブルを削除し旧カラムを空にするため、何度実行しても結果が変わりません
+func MigrateLabelReferences(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		migrator := tx.Migrator()
+
+		// Issueのラベルの移行
+		if migrator.HasTable(legacyIssueLabelsTable) {
+			var rows []legacyIssueLabel
+			if err := tx.Table(legacyIssueLabelsTable + " il").
+				Select("il.issue_id, il.label, i.repository_id").
+				Joins("JOIN issues i ON i.id = il.issue_id").
+				Scan(&rows).Error; err != nil {
+				return fmt.Errorf("failed to read legacy issue labels: %w", err)
+			}
+
+			for _, row := range rows {
+				labelID, err := ensureLabel(tx, row.RepositoryID, row.Label, models.LabelTypeIssue)
+				if err != nil {
+					return err
+				}
+				issueLabel := models.IssueLabel{IssueID: row.IssueID, LabelID: labelID}
+				if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&issueLabel).Error; err != nil {
+					return fmt.Errorf("failed to migrate issue label: %w", err)
+				}
+			}
+
+			if err := migrator.DropTable(legacyIssueLabelsTable); err != nil {
+				return fmt.Errorf("failed to drop legacy issue labels table: %w", err)
+			}
+		}
+
+		// Discussionのラベルの移行
+		if migrator.HasColumn(&models.Discussion{}, "labels") {
+			var rows []legacyDiscussionLabels
+			if err := tx.Table("discussions").
+				Select("id, repository_id, labels").
+				Where("labels IS NOT NULL AND labels <> ''").
+				Scan(&rows).Error; err != nil {
+				return fmt.Errorf("failed to read legacy discussion labels: %w", err)
+			}
+
+			for _, row := range rows {
+				var names []string
+				if err := json.Unmarshal([]byte(row.Labels), &names); err != nil {
+					return fmt.Errorf("failed to parse labels of discussion %d: %w", row.ID, err)
+				}
+				for _, name := range names {
+					labelID, err := ensureLabel(tx, row.RepositoryID, name, models.LabelTypeDiscussion)
+					if err != nil {
+						return err
+					}
+					discussionLabel := models.DiscussionLabel{DiscussionID: row.ID, LabelID: labelID}
+					if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&discussionLabel).Error; err != nil {
+						return fmt.Errorf("failed to migrate discussion label: %w", err)
+					}
+				}
+			}
+
+			if err := tx.Table("discussions").Where("labels IS NOT NULL").Update("labels", nil).Error; err != nil {
+				return fmt.Errorf("failed to clear legacy discussion labels: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ensureLabel はリポジトリ内で対象（issue/discussion）に付与できるラベルのIDを返します
+// 同じ名前のラベルが別の種類のみの場合は種類を both に変更し、存在しない場合は作成します
+func ensureLabel(tx *gorm.DB, repositoryID int64, name, targetType string) (int64, error) {
+	var labels []models.Label
+	if err := tx.Where("repository_id = ? AND name = ?", repositoryID, name).Order("id ASC").Find(&labels).Error; err != nil {
+		return 0, fmt.Errorf("failed to find label %q: %w", name, err)
+	}
+
+	for _, label := range labels {
+		if label.AppliesTo(targetType) {
+			return label.ID, nil
+		}
+	}
+	if len(labels) > 0 {
+		label := labels[0]
+		if err := tx.Model(&label).Update("type", models.LabelTypeBoth).Error; err != nil {
+			return 0, fmt.Errorf("failed to update type of label %q: %w", name, err)
+		}
+		return label.ID, nil
+	}
+
+	label := models.NewLabel(name, "", models.DefaultLabelColor, targetType)
+	label.RepositoryID = repositoryID
+	if err := tx.Create(label).Error; err != nil {
+		return 0, fmt.Errorf("failed to create label %q: %w", name, err)
+	}
+	return label.ID, nil
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"gorm.io/gorm"
+)
+
+// MigrateLabelScopes は既存のラベルにラベル名から求めたスコープを設定します
+// スコープが未設定で区切り文字を含むラベルのみを対象にするため、何度実行しても結果が変わりません
+func MigrateLabelScopes(db *gorm.DB) error {
+	var labels []models.Label
+	if err := db.Where("scope = '' AND name LIKE ?", "%"+models.LabelScopeSeparator+"%").Find(&labels).Error; err != nil {
+		return fmt.Errorf("failed to list labels for scope migration: %w", err)
+	}
+
+	for _, label := range labels {
+		scope := models.LabelScope(label.Name)
+		if scope == "" {
+			continue
+		}
+		if err := db.Model(&models.Label{}).Where("id = ?", label.ID).Update("scope", scope).Error; err != nil {
+			return fmt.Errorf("failed to migrate scope of label %d: %w", label.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package migrations
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"gorm.io/gorm"
+)
+
+// repositoryScopedTables はリポジトリに所属するテーブル
+var repositoryScopedTables = []string{"issues", "discussions", "labels", "milestones"}
+
+// numberedTables はリポジトリ内で番号を採番するテーブル（IssueとDiscussionで番号を共有します）
+var numberedTables = []string{"issues", "discussions"}
+
+// MigrateRepositoryScope はリポジトリ未所属の既存データをデフォルトリポジトリに割り当て、
+// 番号が未採番のIssue・Discussionに作成順で番号を振ります
+// 何度実行しても結果が変わらないように実装されています
+func MigrateRepositoryScope(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// デフォルトリポジトリの確保
+		defaultRepo := models.NewRepository(models.DefaultRepositoryName, "Default repository", models.PublicRepo, 0)
+		if err := tx.Where("name = ?", models.DefaultRepositoryName).FirstOrCreate(defaultRepo).Error; err != nil {
+			return fmt.Errorf("failed to ensure default repository: %w", err)
+		}
+
+		// リポジトリ未所属のデータをデフォルトリポジトリに割り当て
+		for _, table := range repositoryScopedTables {
+			if !tx.Migrator().HasTable(table) {
+				continue
+			}
+			if err := tx.Table(table).
+				Where("repository_id = 0 OR repository_id IS NULL").
+				Update("repository_id", defaultRepo.ID).Error; err != nil {
+				return fmt.Errorf("failed to assign %s to default repository: %w", table, err)
+			}
+		}
+
+		if err := numberUnnumberedRows(tx); err != nil {
+			return err
+		}
+
+		// リポジトリ内の番号の一意制約
+		for _, table := range numberedTables {
+			if !tx.Migrator().HasTable(table) {
+				continue
+			}
+			sql := fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_repository_number ON %s (repository_id, number)", table, table)
+			if err := tx.Exec(sql).Error; err != nil {
+				return fmt.Errorf("failed to create number index on %s: %w", table, err)
+			}
+		}
+		return nil
+	})
+}
+
+// unnumberedRow は採番対象の行
+type unnumberedRow struct {
+	table        string
+	ID           int64
+	RepositoryID int64
+	CreatedAt    time.Time
+}
+
+// numberUnnumberedRows は番号が未採番のIssue・Discussionにリポジトリごとに作成順で番号を振ります
+func numberUnnumberedRows(tx *gorm.DB) error {
+	var rows []unnumberedRow
+	next := make(map[int64]int64)
+
+	for _, table := range numberedTables {
+		if !tx.Migrator().HasTable(table) {
+			continue
+		}
+
+		var tableRows []unnumberedRow
+		if err := tx.Table(table).Select("id, repository_id, created_at").
+			Where("number = 0 OR number IS NULL").
+			Scan(&tableRows).Error; err != nil {
+			return fmt.Errorf("failed to find unnumbered %s: %w", table, err)
+		}
+		for i := range tableRows {
+			tableRows[i].table = table
+		}
+		rows = append(rows, tableRows...)
+
+		// 採番済みの最大番号
+		var maxNumbers []struct {
+			RepositoryID int64
+			MaxNumber    int64
+		}
+		if err := tx.Table(table).Select("repository_id, MAX(number) AS max_number").
+			Group("repository_id").
+			Scan(&maxNumbers).Error; err != nil {
+			return fmt.Errorf("failed to find max number of %s: %w", table, err)
+		}
+		for _, m := range maxNumbers {
+			if m.MaxNumber > next[m.RepositoryID] {
+				next[m.RepositoryID] = m.MaxNumber
+			}
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	// リポジトリに記録された最終番号
+	var repos []models.Repository
+	if err := tx.Select("id, last_number").Find(&repos).Error; err != nil {
+		return fmt.Errorf("failed to load repositories: %w", err)
+	}
+	for _, repo := range repos {
+		if repo.LastNumber > next[repo.ID] {
+			next[repo.ID] = repo.LastNumber
+		}
+	}
+
+	// IssueとDiscussionをまたいで作成順に採番
+	sortUnnumberedRows(rows)
+	for _, row := range rows {
+		next[row.RepositoryID]++
+		if err := tx.Table(row.table).Where("id = ?", row.ID).
+			Update("number", next[row.RepositoryID]).Error; err != nil {
+			return fmt.Errorf("failed to number %s %d: %w", row.table, row.ID, err)
+		}
+	}
+
+	for repositoryID, lastNumber := range next {
+		if err := tx.Model(&models.Repository{}).Where("id = ?", repositoryID).
+			Update("last_number", lastNumber).Error; err != nil {
+			return fmt.Errorf("failed to update last number of repository %d: %w", repositoryID, err)
+		}
+	}
+	return nil
+}
+
+// sortUnnumberedRows は採番対象の行を作成日時順（同時刻はテーブル・ID順）に並べ替えます
+func sortUnnumberedRows(rows []unnumberedRow) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		if !rows[i].CreatedAt.Equal(rows[j].CreatedAt) {
+			return rows[i].CreatedAt.Before(rows[j].CreatedAt)
+		}
+		if rows[i].table != rows[j].table {
+			return rows[i].table > rows[j].table // issues を先に
+		}
+		return rows[i].ID < rows[j].ID
+	})
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"gorm.io/gorm"
+)
+
+// MigrateUserEmailVerified はusersテーブルにメールアドレスの確認日時のカラムを追加します
+// カラムの追加前に登録されたユーザーは確認済みとして扱い、作成日時を確認日時に設定します
+// カラムが存在する場合は何もしないため、何度実行しても結果が変わりません
+func MigrateUserEmailVerified(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&models.User{}) || migrator.HasColumn(&models.User{}, "EmailVerifiedAt") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().AddColumn(&models.User{}, "EmailVerifiedAt"); err != nil {
+			return fmt.Errorf("failed to add email_verified_at column to users: %w", err)
+		}
+		if err := tx.Model(&models.User{}).Where("email_verified_at IS NULL").
+			Update("email_verified_at", gorm.Expr("created_at")).Error; err != nil {
+			return fmt.Errorf("failed to mark existing users as email verified: %w", err)
+		}
+		return nil
+	})
+}
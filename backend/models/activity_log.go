@@ -25,14 +25,20 @@ type LogAction string
 
 const (
 	// ユーザー関連
-	ActionUserCreated         LogAction = "user.created"
-	ActionUserUpdated         LogAction = "user.updated"
-	ActionUserDeleted         LogAction = "user.deleted"
-	ActionUserActivated       LogAction = "user.activated"
-	ActionUserDeactivated     LogAction = "user.deactivated"
-	ActionUserLogin           LogAction = "user.login"
-	ActionUserLogout          LogAction = "user.logout"
-	ActionUserPasswordChanged LogAction = "user.password_changed"
+	ActionUserCreated           LogAction = "user.created"
+	ActionUserUpdated           LogAction = "user.updated"
+	ActionUserDeleted           LogAction = "user.deleted"
+	ActionUserActivated         LogAction = "user.activated"
+	ActionUserDeactivated       LogAction = "user.deactivated"
+	ActionUserLogin             LogAction = "user.login"
+	ActionUserLoginFailed       LogAction = "user.login_failed"
+	ActionUserLocked            LogAction = "user.locked"
+	ActionUserUnlocked          LogAction = "user.unlocked"
+	ActionUserLogout            LogAction = "user.logout"
+	ActionUserPasswordChanged   LogAction = "user.password_changed"
+	ActionUserTwoFactorEnabled  LogAction = "user.2fa_enabled"
+	ActionUserTwoFactorDisabled LogAction = "user.2fa_disabled"
+	ActionUserEmailVerified     LogAction = "user.email_verified"
 
 	// Issue関連
 	ActionIssueCreated    LogAction = "issue.created"
@@ -12,6 +12,8 @@ const (
 	AccessToken TokenType = "access"
 	// RefreshToken はリフレッシュトークンを表す
 	RefreshToken TokenType = "refresh"
+	// MFAPendingToken はパスワードの認証後に二要素認証の入力を待つ間の一時トークンを表す
+	MFAPendingToken TokenType = "mfa_pending"
 )
 
 // AuthToken は認証トークン情報を表す構造体
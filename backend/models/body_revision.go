@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 編集履歴の対象の種類
+const (
+	RevisionTargetIssue      = "issue"
+	RevisionTargetDiscussion = "discussion"
+	RevisionTargetComment    = "comment"
+)
+
+// BodyRevision はIssue・Discussion・コメントの本文の版を表す構造体
+// 最初の編集時に編集前の本文（作成者・作成日時）を最初の版として記録し、以降は編集ごとに版を追加します
+type BodyRevision struct {
+	ID             int64      `json:"id"`
+	TargetType     string     `gorm:"not null;index:idx_body_revision_target" json:"target_type"` // issue/discussion/comment
+	TargetID       int64      `gorm:"not null;index:idx_body_revision_target" json:"target_id"`
+	Body           string     `json:"body"`
+	EditorID       int64      `gorm:"not null" json:"editor_id"`
+	EditorUsername string     `gorm:"->;-:migration" json:"editor_username,omitempty"` // 一覧取得時にusersテーブルから取得
+	IsRedacted     bool       `gorm:"not null;default:false" json:"is_redacted"`
+	RedactedBy     int64      `json:"redacted_by,omitempty"`
+	RedactedAt     *time.Time `json:"redacted_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// NewBodyRevision は新しいBodyRevisionインスタンスを作成する
+func NewBodyRevision(targetType string, targetID int64, body string, editorID int64, createdAt time.Time) *BodyRevision {
+	return &BodyRevision{
+		TargetType: targetType,
+		TargetID:   targetID,
+		Body:       body,
+		EditorID:   editorID,
+		CreatedAt:  createdAt,
+	}
+}
+
+// IsValidRevisionTargetType は編集履歴の対象の種類が有効かどうかを検証する
+func IsValidRevisionTargetType(targetType string) bool {
+	return targetType == RevisionTargetIssue || targetType == RevisionTargetDiscussion || targetType == RevisionTargetComment
+}
+
+// Redact は版の本文を削除し、削除した管理者と日時を記録する
+func (r *BodyRevision) Redact(adminID int64) {
+	now := time.Now()
+	r.Body = ""
+	r.IsRedacted = true
+	r.RedactedBy = adminID
+	r.RedactedAt = &now
+}
+
+// AutoMigrateBodyRevision はBodyRevisionテーブルを作成・更新します
+func AutoMigrateBodyRevision(db *gorm.DB) error {
+	return db.AutoMigrate(&BodyRevision{})
+}
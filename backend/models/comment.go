@@ -15,6 +15,8 @@ type Comment struct {
 	TargetID        int64     `json:"target_id"`
 	ParentCommentID int64     `json:"parent_comment_id,omitempty"`
 	IsEdited        bool      `json:"is_edited"`
+
+	Reactions []ReactionSummary `gorm:"-" json:"reactions,omitempty"` // 絵文字ごとのリアクションの集計
 }
 
 // NewComment は新しいCommentインスタンスを作成する
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// リダイレクトの変換前・変換後のリソースの種類
+const (
+	RedirectTypeIssue      = "issue"
+	RedirectTypeDiscussion = "discussion"
+)
+
+// Redirect はIssue・Discussionの変換前のIDから変換後のリソースへのリダイレクトを表す構造体
+// 変換後のリソースがさらに変換された場合は最新のリソースを指すように更新します
+type Redirect struct {
+	ID           int64     `json:"id"`
+	RepositoryID int64     `gorm:"not null;index:idx_redirect_number" json:"repository_id"`
+	Number       int64     `gorm:"not null;index:idx_redirect_number" json:"number"` // 変換前後で共通のリポジトリ内の番号
+	SourceType   string    `gorm:"not null;index:idx_redirect_source" json:"source_type"`
+	SourceID     int64     `gorm:"not null;index:idx_redirect_source" json:"source_id"`
+	TargetType   string    `gorm:"not null;index:idx_redirect_target" json:"target_type"`
+	TargetID     int64     `gorm:"not null;index:idx_redirect_target" json:"target_id"`
+	CreatorID    int64     `gorm:"not null" json:"creator_id"` // 変換したユーザーID
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewRedirect は新しいRedirectインスタンスを作成する
+func NewRedirect(repositoryID, number int64, sourceType string, sourceID int64, targetType string, targetID int64, creatorID int64) *Redirect {
+	return &Redirect{
+		RepositoryID: repositoryID,
+		Number:       number,
+		SourceType:   sourceType,
+		SourceID:     sourceID,
+		TargetType:   targetType,
+		TargetID:     targetID,
+		CreatorID:    creatorID,
+		CreatedAt:    time.Now(),
+	}
+}
+
+// ToDiscussion はIssueを同じ番号・本文・ラベルのDiscussionに変換する
+// 担当者・マイルストーンはDiscussionにないため引き継ぎません
+func (i *Issue) ToDiscussion(category string) *Discussion {
+	discussion := NewDiscussion(i.Title, i.Body, category, i.CreatorID)
+	discussion.RepositoryID = i.RepositoryID
+	discussion.Number = i.Number
+	discussion.Labels = append([]string{}, i.Labels...)
+	discussion.IsDraft = i.IsDraft
+	discussion.CreatedAt = i.CreatedAt
+	if i.Status == "closed" {
+		discussion.Close()
+	}
+	return discussion
+}
+
+// ToIssue はDiscussionを同じ番号・本文・ラベルのIssueに変換する
+// 回答済みのDiscussionはクローズ済みのIssueになり、回答の選択は引き継ぎません
+func (d *Discussion) ToIssue() *Issue {
+	issue := NewIssue(d.Title, d.Body, d.CreatorID)
+	issue.RepositoryID = d.RepositoryID
+	issue.Number = d.Number
+	issue.Labels = append([]string{}, d.Labels...)
+	issue.IsDraft = d.IsDraft
+	issue.CreatedAt = d.CreatedAt
+	if d.Status == "closed" || d.Status == "answered" {
+		issue.Close()
+	}
+	return issue
+}
+
+// AutoMigrateRedirect はRedirectテーブルを作成・更新します
+func AutoMigrateRedirect(db *gorm.DB) error {
+	return db.AutoMigrate(&Redirect{})
+}
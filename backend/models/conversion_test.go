@@ -0,0 +1,89 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssue_ToDiscussion(t *testing.T) {
+	createdAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		status     string
+		wantStatus string
+	}{
+		{name: "オープン", status: "open", wantStatus: "open"},
+		{name: "クローズ済み", status: "closed", wantStatus: "closed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := models.NewIssue("crash", "details", 3)
+			issue.ID = 10
+			issue.RepositoryID = 2
+			issue.Number = 7
+			issue.Status = tt.status
+			issue.Labels = []string{"bug"}
+			issue.AssigneeIDs = []int64{4}
+			issue.MilestoneID = 5
+			issue.IsDraft = true
+			issue.CreatedAt = createdAt
+
+			discussion := issue.ToDiscussion("question")
+			assert.Equal(t, int64(0), discussion.ID)
+			assert.Equal(t, int64(2), discussion.RepositoryID)
+			assert.Equal(t, int64(7), discussion.Number)
+			assert.Equal(t, "crash", discussion.Title)
+			assert.Equal(t, "details", discussion.Body)
+			assert.Equal(t, "question", discussion.Category)
+			assert.Equal(t, int64(3), discussion.CreatorID)
+			assert.Equal(t, []string{"bug"}, discussion.Labels)
+			assert.True(t, discussion.IsDraft)
+			assert.Equal(t, createdAt, discussion.CreatedAt)
+			assert.Equal(t, tt.wantStatus, discussion.Status)
+
+			// 変換元のラベルとは別のスライス
+			discussion.Labels[0] = "changed"
+			assert.Equal(t, []string{"bug"}, issue.Labels)
+		})
+	}
+}
+
+func TestDiscussion_ToIssue(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		wantStatus string
+	}{
+		{name: "オープン", status: "open", wantStatus: "open"},
+		{name: "クローズ済み", status: "closed", wantStatus: "closed"},
+		{name: "回答済みはクローズ", status: "answered", wantStatus: "closed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			discussion := models.NewDiscussion("how to", "question body", "question", 3)
+			discussion.ID = 11
+			discussion.RepositoryID = 2
+			discussion.Number = 8
+			discussion.Status = tt.status
+			discussion.Labels = []string{"help"}
+			discussion.AnswerCommentID = 20
+
+			issue := discussion.ToIssue()
+			assert.Equal(t, int64(0), issue.ID)
+			assert.Equal(t, int64(2), issue.RepositoryID)
+			assert.Equal(t, int64(8), issue.Number)
+			assert.Equal(t, "how to", issue.Title)
+			assert.Equal(t, "question body", issue.Body)
+			assert.Equal(t, int64(3), issue.CreatorID)
+			assert.Equal(t, []string{"help"}, issue.Labels)
+			assert.Empty(t, issue.AssigneeIDs)
+			assert.Equal(t, tt.wantStatus, issue.Status)
+		})
+	}
+}
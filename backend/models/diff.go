@@ -0,0 +1,258 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// diffContextLines は差分の前後に表示する変更のない行数
+const diffContextLines = 3
+
+// diffLine は差分の1行を表す構造体
+type diffLine struct {
+	op   byte // ' '/'-'/'+'
+	text string
+}
+
+// UnifiedDiff は2つのテキストの行単位の差分をunified形式で作成する（差分がない場合は空文字）
+func UnifiedDiff(oldText, newText, oldLabel, newLabel string) string {
+	lines := diffLines(splitLines(oldText), splitLines(newText))
+
+	var sb strings.Builder
+	for _, hunk := range diffHunks(lines) {
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldLabel, newLabel)
+		}
+		sb.WriteString(hunk)
+	}
+	return sb.String()
+}
+
+// splitLines はテキストを行に分割する（空のテキストは0行）
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines は行ごとの差分を作成する
+// 共通の先頭と末尾を除いてから、線形の空間で済むMyersのアルゴリズムで差分を求める
+// 変更のまとまりの中では削除した行を追加した行より先に並べる
+func diffLines(a, b []string) []diffLine {
+	lines := make([]diffLine, 0, len(a)+len(b))
+	lines = appendDiffLines(lines, a, b)
+
+	// 連続する変更の中で削除を追加より先に並べ替える
+	for k := 0; k < len(lines); {
+		if lines[k].op == ' ' {
+			k++
+			continue
+		}
+		end := k
+		for end < len(lines) && lines[end].op != ' ' {
+			end++
+		}
+		changes := lines[k:end]
+		sort.SliceStable(changes, func(i, j int) bool {
+			return changes[i].op == '-' && changes[j].op == '+'
+		})
+		k = end
+	}
+	return lines
+}
+
+// appendDiffLines は a と b の差分を lines に追加する
+func appendDiffLines(lines []diffLine, a, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	for _, text := range a[:prefix] {
+		lines = append(lines, diffLine{' ', text})
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	x, y, ok := 0, 0, false
+	if len(a) > 0 && len(b) > 0 {
+		x, y, ok = middleSnake(a, b)
+	}
+	if ok {
+		lines = appendDiffLines(lines, a[:x], b[:y])
+		lines = appendDiffLines(lines, a[x:], b[y:])
+	} else {
+		for _, text := range a {
+			lines = append(lines, diffLine{'-', text})
+		}
+		for _, text := range b {
+			lines = append(lines, diffLine{'+', text})
+		}
+	}
+	for _, text := range common {
+		lines = append(lines, diffLine{' ', text})
+	}
+	return lines
+}
+
+// middleSnake は最短の編集経路を前後から同時に探索し、経路が重なった位置で a と b を分割する位置を返す
+// 探索に使う配列は行数に比例する大きさで済む
+func middleSnake(a, b []string) (int, int, bool) {
+	n, m := len(a), len(b)
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	forward := make([]int, 2*maxD+2)  // forward[offset+k] は対角線 k 上で先頭から到達した最も遠い a の位置
+	backward := make([]int, 2*maxD+2) // backward[offset+k] は末尾から逆向きに到達した最も遠い位置
+	for i := range forward {
+		forward[i] = -1
+		backward[i] = -1
+	}
+	forward[offset+1] = 0
+	backward[offset+1] = 0
+
+	delta := n - m
+	front := delta%2 != 0 // 差が奇数の場合は前向きの探索で重なりを判定する
+	k1start, k1end, k2start, k2end := 0, 0, 0, 0
+	for d := 0; d < maxD; d++ {
+		for k1 := -d + k1start; k1 <= d-k1end; k1 += 2 {
+			i := offset + k1
+			var x1 int
+			if k1 == -d || (k1 != d && forward[i-1] < forward[i+1]) {
+				x1 = forward[i+1]
+			} else {
+				x1 = forward[i-1] + 1
+			}
+			y1 := x1 - k1
+			for x1 < n && y1 < m && a[x1] == b[y1] {
+				x1++
+				y1++
+			}
+			forward[i] = x1
+			switch {
+			case x1 > n:
+				k1end += 2
+			case y1 > m:
+				k1start += 2
+			case front:
+				j := offset + delta - k1
+				if j >= 0 && j < len(backward) && backward[j] != -1 && x1 >= n-backward[j] {
+					return x1, y1, true
+				}
+			}
+		}
+
+		for k2 := -d + k2start; k2 <= d-k2end; k2 += 2 {
+			j := offset + k2
+			var x2 int
+			if k2 == -d || (k2 != d && backward[j-1] < backward[j+1]) {
+				x2 = backward[j+1]
+			} else {
+				x2 = backward[j-1] + 1
+			}
+			y2 := x2 - k2
+			for x2 < n && y2 < m && a[n-x2-1] == b[m-y2-1] {
+				x2++
+				y2++
+			}
+			backward[j] = x2
+			switch {
+			case x2 > n:
+				k2end += 2
+			case y2 > m:
+				k2start += 2
+			case !front:
+				i := offset + delta - k2
+				if i >= 0 && i < len(forward) && forward[i] != -1 {
+					x1 := forward[i]
+					if y1 := offset + x1 - i; x1 >= n-x2 {
+						return x1, y1, true
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// diffHunks は変更箇所の前後に diffContextLines 行を含めたハンクを作成する
+func diffHunks(lines []diffLine) []string {
+	var hunks []string
+	oldLine, newLine := 0, 0 // lines[k] より前の行数
+
+	for k := 0; k < len(lines); {
+		if lines[k].op == ' ' {
+			oldLine++
+			newLine++
+			k++
+			continue
+		}
+
+		// ハンクの範囲を決定（変更の間の変更のない行が2*diffContextLines以下なら結合）
+		start := k - diffContextLines
+		if start < 0 {
+			start = 0
+		}
+		end := k
+		for end < len(lines) {
+			if lines[end].op != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(lines) && lines[next].op == ' ' {
+				next++
+			}
+			if next == len(lines) || next-end > 2*diffContextLines {
+				end += diffContextLines
+				if end > len(lines) {
+					end = len(lines)
+				}
+				break
+			}
+			end = next
+		}
+
+		// ハンクの開始位置（k より前の文脈の行を差し引く）
+		oldStart, newStart := oldLine-(k-start), newLine-(k-start)
+		oldCount, newCount := 0, 0
+		var body strings.Builder
+		for _, line := range lines[start:end] {
+			body.WriteByte(line.op)
+			body.WriteString(line.text)
+			body.WriteByte('\n')
+			if line.op != '+' {
+				oldCount++
+			}
+			if line.op != '-' {
+				newCount++
+			}
+		}
+		hunks = append(hunks, fmt.Sprintf("@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))+body.String())
+
+		// ハンクの終わりまで行数を進める
+		for ; k < end; k++ {
+			if lines[k].op != '+' {
+				oldLine++
+			}
+			if lines[k].op != '-' {
+				newLine++
+			}
+		}
+	}
+	return hunks
+}
+
+// hunkRange はハンクの範囲を「開始行,行数」の形式で返す（行数が0の場合の開始行は直前の行）
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -0,0 +1,165 @@
+package models_test
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+		want    string
+	}{
+		{
+			name:    "差分なし",
+			oldText: "a\nb\n",
+			newText: "a\nb",
+			want:    "",
+		},
+		{
+			name:    "1行の変更",
+			oldText: "a\nb\nc",
+			newText: "a\nB\nc",
+			want:    "--- v1\n+++ v2\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:    "空の本文への追加",
+			oldText: "",
+			newText: "a\nb",
+			want:    "--- v1\n+++ v2\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:    "すべて削除",
+			oldText: "a",
+			newText: "",
+			want:    "--- v1\n+++ v2\n@@ -1,1 +0,0 @@\n-a\n",
+		},
+		{
+			name:    "離れた変更は別のハンク",
+			oldText: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12",
+			newText: "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve",
+			want: "--- v1\n+++ v2\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n" +
+				"@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		},
+		{
+			name:    "近い変更は1つのハンク",
+			oldText: "1\n2\n3\n4\n5\n6\n7\n8",
+			newText: "one\n2\n3\n4\n5\n6\n7\neight",
+			want:    "--- v1\n+++ v2\n@@ -1,8 +1,8 @@\n-1\n+one\n 2\n 3\n 4\n 5\n 6\n 7\n-8\n+eight\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.UnifiedDiff(tt.oldText, tt.newText, "v1", "v2"))
+		})
+	}
+}
+
+func TestUnifiedDiff_Random(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randomText := func() []string {
+		lines := make([]string, rng.Intn(30))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.Intn(4)))
+		}
+		return lines
+	}
+
+	for n := 0; n < 500; n++ {
+		oldLines, newLines := randomText(), randomText()
+		oldText, newText := strings.Join(oldLines, "\n"), strings.Join(newLines, "\n")
+		diff := models.UnifiedDiff(oldText, newText, "v1", "v2")
+
+		// 差分を適用すると新しいテキストになり、変更した行数は最小になる
+		patched, deleted, added := applyUnifiedDiff(t, oldLines, diff)
+		require.Equal(t, newText, strings.Join(patched, "\n"), "old=%q new=%q", oldText, newText)
+		common := lcsLength(oldLines, newLines)
+		require.Equal(t, len(oldLines)-common, deleted, "old=%q new=%q", oldText, newText)
+		require.Equal(t, len(newLines)-common, added, "old=%q new=%q", oldText, newText)
+	}
+}
+
+func TestUnifiedDiff_LargeText(t *testing.T) {
+	// 行数の積に比例するメモリを使わずに大きな本文の差分を作成できる
+	oldLines := make([]string, 50000)
+	newLines := make([]string, 50000)
+	for i := range oldLines {
+		oldLines[i] = strconv.Itoa(i)
+		newLines[i] = strconv.Itoa(i)
+	}
+	newLines[100] = "changed"
+	newLines[40000] = "changed"
+
+	start := time.Now()
+	diff := models.UnifiedDiff(strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"), "v1", "v2")
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Contains(t, diff, "@@ -98,7 +98,7 @@\n 97\n 98\n 99\n-100\n+changed\n")
+	assert.Contains(t, diff, "-40000\n+changed\n")
+}
+
+// applyUnifiedDiff は unified形式の差分を lines に適用した結果と、削除・追加した行数を返す
+func applyUnifiedDiff(t *testing.T, lines []string, diff string) ([]string, int, int) {
+	t.Helper()
+	var result []string
+	deleted, added := 0, 0
+	next := 0 // lines のうち次に読む位置
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case line == "", strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+		case strings.HasPrefix(line, "@@"):
+			var oldStart, oldCount, newStart, newCount int
+			_, err := fmt.Sscanf(line, "@@ -%d,%d +%d,%d @@", &oldStart, &oldCount, &newStart, &newCount)
+			require.NoError(t, err, line)
+			if oldCount > 0 {
+				oldStart--
+			}
+			result = append(result, lines[next:oldStart]...)
+			next = oldStart
+		case line[0] == ' ':
+			require.Equal(t, lines[next], line[1:])
+			result = append(result, line[1:])
+			next++
+		case line[0] == '-':
+			require.Equal(t, lines[next], line[1:])
+			deleted++
+			next++
+		case line[0] == '+':
+			result = append(result, line[1:])
+			added++
+		}
+	}
+	return append(result, lines[next:]...), deleted, added
+}
+
+// lcsLength は a と b の最長共通部分列の長さを返す
+func lcsLength(a, b []string) int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	return lcs[0][0]
+}
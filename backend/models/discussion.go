@@ -2,21 +2,37 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Discussion はディスカッション情報を表す構造体
 type Discussion struct {
-	ID        int64     `json:"id"`
-	Title     string    `json:"title"`
-	Body      string    `json:"body"`
-	Status    string    `json:"status"`   // open/closed/answered
-	Category  string    `json:"category"` // general/question/announcement
-	Labels    []string  `json:"labels"`
-	CreatorID int64     `json:"creator_id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	IsDraft   bool      `json:"is_draft"`
-	ClosedAt  time.Time `json:"closed_at,omitempty"`
+	ID           int64     `json:"id"`
+	RepositoryID int64     `gorm:"not null;default:0;index" json:"repository_id"`
+	Number       int64     `gorm:"not null;default:0" json:"number"` // リポジトリ内の通し番号（Issueと共通）
+	Title        string    `json:"title"`
+	Body         string    `json:"body"`
+	Status       string    `json:"status"`          // open/closed/answered
+	Category     string    `json:"category"`        // general/question/announcement
+	Labels       []string  `gorm:"-" json:"labels"` // ラベル名（discussion_labelsテーブルで管理）
+	CreatorID    int64     `json:"creator_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	IsDraft      bool      `json:"is_draft"`
+	ClosedAt     time.Time `json:"closed_at,omitempty"`
+
+	AnswerCommentID int64               `gorm:"not null;default:0" json:"answer_comment_id,omitempty"` // 回答として選ばれたコメント（questionカテゴリのみ）
+	AnsweredBy      *DiscussionAnswerer `gorm:"-" json:"answered_by,omitempty"`                        // 回答コメントの投稿者
+
+	Reactions []ReactionSummary `gorm:"-" json:"reactions,omitempty"` // 絵文字ごとのリアクションの集計
+}
+
+// DiscussionAnswerer は回答として選ばれたコメントの投稿者を表す構造体
+type DiscussionAnswerer struct {
+	UserID    int64  `json:"user_id"`
+	Username  string `json:"username"`
+	AvatarURL string `json:"avatar_url,omitempty"`
 }
 
 // NewDiscussion は新しいDiscussionインスタンスを作成する
@@ -65,6 +81,27 @@ func (d *Discussion) MarkAsAnswered() {
 	d.UpdatedAt = time.Now()
 }
 
+// IsQuestion は質問カテゴリのディスカッションかどうかを判定する
+func (d *Discussion) IsQuestion() bool {
+	return d.Category == "question"
+}
+
+// MarkAnswer は指定したコメントを回答として選び、回答済み状態に設定する
+func (d *Discussion) MarkAnswer(commentID int64) {
+	d.AnswerCommentID = commentID
+	d.MarkAsAnswered()
+}
+
+// UnmarkAnswer は回答の選択を解除し、回答済みの場合はオープン状態に戻す
+func (d *Discussion) UnmarkAnswer() {
+	d.AnswerCommentID = 0
+	d.AnsweredBy = nil
+	if d.Status == "answered" {
+		d.Status = "open"
+	}
+	d.UpdatedAt = time.Now()
+}
+
 // Reopen はディスカッションを再オープン状態に設定する
 func (d *Discussion) Reopen() {
 	var zeroTime time.Time
@@ -100,3 +137,20 @@ func (d *Discussion) SetDraft(isDraft bool) {
 	d.IsDraft = isDraft
 	d.UpdatedAt = time.Now()
 }
+
+// DiscussionLabel はDiscussionに付与されたラベルを表すGORM構造体
+type DiscussionLabel struct {
+	DiscussionID int64  `gorm:"primaryKey;not null"`
+	LabelID      int64  `gorm:"primaryKey;not null;index"`
+	Label        *Label `gorm:"foreignKey:LabelID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName はテーブル名を指定
+func (DiscussionLabel) TableName() string {
+	return "discussion_labels"
+}
+
+// AutoMigrateDiscussion はDiscussionテーブルを作成・更新します
+func AutoMigrateDiscussion(db *gorm.DB) error {
+	return db.AutoMigrate(&Discussion{}, &DiscussionLabel{})
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EmailVerification はメールアドレスの確認トークンを表す構造体
+// トークンはハッシュ値のみを保存し、送信時のメールアドレスが変更された場合は無効とします
+type EmailVerification struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `gorm:"not null;index" json:"user_id"`
+	Email     string     `gorm:"not null" json:"email"` // 確認メールの送信先
+	TokenHash string     `gorm:"not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+}
+
+// NewEmailVerification は新しいEmailVerificationインスタンスを作成する
+func NewEmailVerification(userID int64, email, token string, expiresIn time.Duration) *EmailVerification {
+	now := time.Now()
+	return &EmailVerification{
+		UserID:    userID,
+		Email:     email,
+		TokenHash: HashEmailVerificationToken(token),
+		ExpiresAt: now.Add(expiresIn),
+		CreatedAt: now,
+	}
+}
+
+// HashEmailVerificationToken は確認トークンのSHA-256ハッシュ値を16進数で返す
+func HashEmailVerificationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsExpired はトークンが期限切れかどうかを判定する
+func (v *EmailVerification) IsExpired() bool {
+	return time.Now().After(v.ExpiresAt)
+}
+
+// MarkAsUsed はトークンを使用済みとしてマークする
+func (v *EmailVerification) MarkAsUsed() {
+	now := time.Now()
+	v.UsedAt = &now
+}
+
+// IsValid はトークンが user のメールアドレスの確認に使用できるかどうかを判定する
+func (v *EmailVerification) IsValid(user *User) bool {
+	return v.UsedAt == nil && !v.IsExpired() && v.UserID == user.ID && v.Email == user.Email
+}
+
+// AutoMigrateEmailVerification はEmailVerificationテーブルのマイグレーションを実行します
+func AutoMigrateEmailVerification(db *gorm.DB) error {
+	return db.AutoMigrate(&EmailVerification{})
+}
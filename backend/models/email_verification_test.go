@@ -0,0 +1,83 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashEmailVerificationToken(t *testing.T) {
+	hash := models.HashEmailVerificationToken("token")
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, models.HashEmailVerificationToken("token"))
+	assert.NotEqual(t, hash, models.HashEmailVerificationToken("Token"))
+
+	verification := models.NewEmailVerification(1, "alice@example.com", "token", time.Hour)
+	assert.Equal(t, hash, verification.TokenHash)
+	assert.Nil(t, verification.UsedAt)
+}
+
+func TestEmailVerification_IsValid(t *testing.T) {
+	user := &models.User{ID: 1, Email: "alice@example.com"}
+
+	tests := []struct {
+		name         string
+		verification func() *models.EmailVerification
+		want         bool
+	}{
+		{
+			name: "有効なトークン",
+			verification: func() *models.EmailVerification {
+				return models.NewEmailVerification(1, "alice@example.com", "token", time.Hour)
+			},
+			want: true,
+		},
+		{
+			name: "期限切れ",
+			verification: func() *models.EmailVerification {
+				return models.NewEmailVerification(1, "alice@example.com", "token", -time.Minute)
+			},
+			want: false,
+		},
+		{
+			name: "使用済み",
+			verification: func() *models.EmailVerification {
+				v := models.NewEmailVerification(1, "alice@example.com", "token", time.Hour)
+				v.MarkAsUsed()
+				return v
+			},
+			want: false,
+		},
+		{
+			name: "別のユーザー",
+			verification: func() *models.EmailVerification {
+				return models.NewEmailVerification(2, "alice@example.com", "token", time.Hour)
+			},
+			want: false,
+		},
+		{
+			name: "送信後にメールアドレスを変更",
+			verification: func() *models.EmailVerification {
+				return models.NewEmailVerification(1, "old@example.com", "token", time.Hour)
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.verification().IsValid(user))
+		})
+	}
+}
+
+func TestUser_MarkEmailVerified(t *testing.T) {
+	user := models.NewUser("alice", "alice@example.com", "hash", "Alice")
+	assert.False(t, user.IsEmailVerified())
+
+	user.MarkEmailVerified()
+	assert.True(t, user.IsEmailVerified())
+	assert.NotNil(t, user.EmailVerifiedAt)
+}
@@ -6,34 +6,57 @@ import (
 
 // Issue は課題チケットを表す構造体
 type Issue struct {
-	ID          int64     `json:"id"`
-	Title       string    `json:"title"`
-	Body        string    `json:"body"`
-	Status      string    `json:"status"`
-	Labels      []string  `json:"labels"`
-	AssigneeID  int64     `json:"assignee_id"`
-	CreatorID   int64     `json:"creator_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	IsDraft     bool      `json:"is_draft"`
-	MilestoneID int64     `json:"milestone_id,omitempty"`
+	ID           int64     `json:"id"`
+	RepositoryID int64     `json:"repository_id"`
+	Number       int64     `json:"number"` // リポジトリ内の通し番号（Discussionと共通）
+	Title        string    `json:"title"`
+	Body         string    `json:"body"`
+	Status       string    `json:"status"`
+	Labels       []string  `json:"labels"`
+	AssigneeID   int64     `json:"assignee_id"`  // 最初の担当者（旧クライアントとの互換用）
+	AssigneeIDs  []int64   `json:"assignee_ids"` // すべての担当者
+	CreatorID    int64     `json:"creator_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	IsDraft      bool      `json:"is_draft"`
+	MilestoneID  int64     `json:"milestone_id,omitempty"`
+
+	TaskProgress TaskProgress `json:"task_progress"` // 本文のチェックリストの完了数（保存時に本文から集計）
+
+	Reactions        []ReactionSummary `gorm:"-" json:"reactions,omitempty"`          // 絵文字ごとのリアクションの集計
+	Links            *IssueLinkGraph   `gorm:"-" json:"links,omitempty"`              // 他のIssueとの関連（詳細取得時のみ）
+	SubIssueProgress *TaskProgress     `gorm:"-" json:"sub_issue_progress,omitempty"` // 子のIssueのクローズ数（子がある場合のみ）
 }
 
 // NewIssue は新しいIssueインスタンスを作成する
 func NewIssue(title, body string, creatorID int64) *Issue {
 	now := time.Now()
 	return &Issue{
-		Title:     title,
-		Body:      body,
-		Status:    "open",
-		CreatorID: creatorID,
-		CreatedAt: now,
-		UpdatedAt: now,
-		IsDraft:   false,
-		Labels:    []string{},
+		Title:       title,
+		Body:        body,
+		Status:      "open",
+		CreatorID:   creatorID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		IsDraft:     false,
+		Labels:      []string{},
+		AssigneeIDs: []int64{},
 	}
 }
 
+// Clone はIssueのコピーを作成する（変更前の状態を保持するためラベルと担当者も複製する）
+func (i *Issue) Clone() *Issue {
+	clone := *i
+	clone.Labels = append([]string(nil), i.Labels...)
+	clone.AssigneeIDs = append([]int64(nil), i.AssigneeIDs...)
+	return &clone
+}
+
+// RefreshTaskProgress は本文からチェックリストの完了数を再集計する
+func (i *Issue) RefreshTaskProgress() {
+	i.TaskProgress = ComputeTaskProgress(i.Body)
+}
+
 // IsValid はIssueの検証を行う
 func (i *Issue) IsValid() bool {
 	return i.Title != "" && i.CreatorID > 0
@@ -72,3 +95,82 @@ func (i *Issue) RemoveLabel(label string) {
 		}
 	}
 }
+
+// Assignees はIssueの担当者の一覧を返す
+// 担当者の一覧が未設定で assignee_id のみ設定されている場合はその担当者を返す
+func (i *Issue) Assignees() []int64 {
+	if len(i.AssigneeIDs) > 0 {
+		return i.AssigneeIDs
+	}
+	if i.AssigneeID > 0 {
+		return []int64{i.AssigneeID}
+	}
+	return []int64{}
+}
+
+// HasAssignee は指定したユーザーがIssueの担当者かどうかを判定する
+func (i *Issue) HasAssignee(userID int64) bool {
+	for _, id := range i.Assignees() {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// SetAssignees はIssueの担当者を置き換える（重複と0は除外し、assignee_id は最初の担当者にする）
+func (i *Issue) SetAssignees(userIDs []int64) {
+	i.AssigneeIDs = []int64{}
+	i.AssigneeID = 0
+	for _, id := range userIDs {
+		i.AddAssignee(id)
+	}
+	i.UpdatedAt = time.Now()
+}
+
+// AddAssignee はIssueに担当者を追加する
+func (i *Issue) AddAssignee(userID int64) {
+	if userID <= 0 || i.HasAssignee(userID) {
+		return // 既に担当者のユーザーは追加しない
+	}
+	i.AssigneeIDs = append(i.Assignees(), userID)
+	i.AssigneeID = i.AssigneeIDs[0]
+	i.UpdatedAt = time.Now()
+}
+
+// RemoveAssignee はIssueから担当者を削除する
+func (i *Issue) RemoveAssignee(userID int64) {
+	assignees := i.Assignees()
+	for j, id := range assignees {
+		if id == userID {
+			i.AssigneeIDs = append(assignees[:j:j], assignees[j+1:]...)
+			i.AssigneeID = 0
+			if len(i.AssigneeIDs) > 0 {
+				i.AssigneeID = i.AssigneeIDs[0]
+			}
+			i.UpdatedAt = time.Now()
+			return
+		}
+	}
+}
+
+// AddedAssignees は変更前のIssueになく変更後のIssueに追加された担当者を返す
+func AddedAssignees(before, after *Issue) []int64 {
+	return subtractIDs(after.Assignees(), before.Assignees())
+}
+
+// subtractIDs は a に含まれ b に含まれないIDを返す
+func subtractIDs(a, b []int64) []int64 {
+	exists := make(map[int64]bool, len(b))
+	for _, id := range b {
+		exists[id] = true
+	}
+	var result []int64
+	for _, id := range a {
+		if !exists[id] {
+			result = append(result, id)
+			exists[id] = true
+		}
+	}
+	return result
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// IssueEventType はIssueの変更履歴の種類を表す型
+type IssueEventType string
+
+// Issueの変更履歴の種類
+const (
+	IssueEventClosed             IssueEventType = "closed"
+	IssueEventReopened           IssueEventType = "reopened"
+	IssueEventLabeled            IssueEventType = "labeled"
+	IssueEventUnlabeled          IssueEventType = "unlabeled"
+	IssueEventAssigned           IssueEventType = "assigned"
+	IssueEventUnassigned         IssueEventType = "unassigned"
+	IssueEventMilestoned         IssueEventType = "milestoned"
+	IssueEventDemilestoned       IssueEventType = "demilestoned"
+	IssueEventRenamed            IssueEventType = "renamed"
+	IssueEventConvertedFromDraft IssueEventType = "converted_from_draft"
+)
+
+// IssueEvent はIssueの変更履歴を表す構造体
+// 変更前後の値は種類ごとに、ステータス・ラベル名・ユーザーID・マイルストーンID・タイトルを文字列で保持します
+type IssueEvent struct {
+	ID            int64          `json:"id"`
+	IssueID       int64          `gorm:"not null;index" json:"issue_id"`
+	ActorID       int64          `gorm:"not null" json:"actor_id"`
+	Type          IssueEventType `gorm:"not null" json:"type"`
+	OldValue      string         `json:"old_value,omitempty"`
+	NewValue      string         `json:"new_value,omitempty"`
+	ActorUsername string         `gorm:"->;-:migration" json:"actor_username,omitempty"` // 一覧取得時にusersテーブルから取得
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+// NewIssueEvent は新しいIssueEventインスタンスを作成する
+func NewIssueEvent(issueID, actorID int64, eventType IssueEventType, oldValue, newValue string) *IssueEvent {
+	return &IssueEvent{
+		IssueID:   issueID,
+		ActorID:   actorID,
+		Type:      eventType,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		CreatedAt: time.Now(),
+	}
+}
+
+// DiffIssueEvents は変更前後のIssueを比較し、変更内容を表すIssueEventの一覧を作成する
+func DiffIssueEvents(before, after *Issue, actorID int64) []*IssueEvent {
+	var events []*IssueEvent
+	add := func(eventType IssueEventType, oldValue, newValue string) {
+		events = append(events, NewIssueEvent(after.ID, actorID, eventType, oldValue, newValue))
+	}
+
+	if before.Title != after.Title {
+		add(IssueEventRenamed, before.Title, after.Title)
+	}
+	if before.IsDraft && !after.IsDraft {
+		add(IssueEventConvertedFromDraft, "", "")
+	}
+	if before.Status != after.Status {
+		if after.Status == "closed" {
+			add(IssueEventClosed, before.Status, after.Status)
+		} else if before.Status == "closed" {
+			add(IssueEventReopened, before.Status, after.Status)
+		}
+	}
+
+	for _, label := range subtractLabels(after.Labels, before.Labels) {
+		add(IssueEventLabeled, "", label)
+	}
+	for _, label := range subtractLabels(before.Labels, after.Labels) {
+		add(IssueEventUnlabeled, label, "")
+	}
+
+	for _, userID := range subtractIDs(before.Assignees(), after.Assignees()) {
+		add(IssueEventUnassigned, formatEventID(userID), "")
+	}
+	for _, userID := range AddedAssignees(before, after) {
+		add(IssueEventAssigned, "", formatEventID(userID))
+	}
+
+	if before.MilestoneID != after.MilestoneID {
+		if after.MilestoneID == 0 {
+			add(IssueEventDemilestoned, formatEventID(before.MilestoneID), "")
+		} else {
+			add(IssueEventMilestoned, formatEventID(before.MilestoneID), formatEventID(after.MilestoneID))
+		}
+	}
+
+	return events
+}
+
+// subtractLabels は a に含まれ b に含まれないラベルを返す
+func subtractLabels(a, b []string) []string {
+	exists := make(map[string]bool, len(b))
+	for _, label := range b {
+		exists[label] = true
+	}
+	var result []string
+	for _, label := range a {
+		if !exists[label] {
+			result = append(result, label)
+			exists[label] = true
+		}
+	}
+	return result
+}
+
+// formatEventID はIDを変更履歴の値に変換する（0は空文字）
+func formatEventID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+// タイムラインの項目の種類
+const (
+	TimelineItemEvent   = "event"
+	TimelineItemComment = "comment"
+)
+
+// TimelineItem はIssueのタイムラインの項目（変更履歴またはコメント）を表す構造体
+type TimelineItem struct {
+	Type      string      `json:"type"` // event/comment
+	CreatedAt time.Time   `json:"created_at"`
+	Event     *IssueEvent `json:"event,omitempty"`
+	Comment   *Comment    `json:"comment,omitempty"`
+}
+
+// BuildTimeline は変更履歴とコメントを時系列順に並べたタイムラインを作成する
+// 同時刻の場合は変更履歴をコメントより先に並べる
+func BuildTimeline(events []*IssueEvent, comments []*Comment) []*TimelineItem {
+	items := make([]*TimelineItem, 0, len(events)+len(comments))
+	for _, event := range events {
+		items = append(items, &TimelineItem{Type: TimelineItemEvent, CreatedAt: event.CreatedAt, Event: event})
+	}
+	for _, comment := range comments {
+		items = append(items, &TimelineItem{Type: TimelineItemComment, CreatedAt: comment.CreatedAt, Comment: comment})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	})
+	return items
+}
+
+// AutoMigrateIssueEvent はIssueEventテーブルを作成・更新します
+func AutoMigrateIssueEvent(db *gorm.DB) error {
+	return db.AutoMigrate(&IssueEvent{})
+}
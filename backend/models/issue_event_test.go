@@ -0,0 +1,144 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffIssueEvents(t *testing.T) {
+	base := models.Issue{
+		ID:          1,
+		Title:       "タイトル",
+		Status:      "open",
+		Labels:      []string{"bug"},
+		AssigneeID:  2,
+		MilestoneID: 3,
+	}
+
+	type event struct {
+		Type     models.IssueEventType
+		OldValue string
+		NewValue string
+	}
+
+	tests := []struct {
+		name   string
+		change func(issue *models.Issue)
+		want   []event
+	}{
+		{
+			name:   "変更なし",
+			change: func(issue *models.Issue) {},
+			want:   nil,
+		},
+		{
+			name:   "クローズ",
+			change: func(issue *models.Issue) { issue.Close() },
+			want:   []event{{models.IssueEventClosed, "open", "closed"}},
+		},
+		{
+			name:   "タイトルの変更",
+			change: func(issue *models.Issue) { issue.Title = "新しいタイトル" },
+			want:   []event{{models.IssueEventRenamed, "タイトル", "新しいタイトル"}},
+		},
+		{
+			name: "ラベルの付け替え",
+			change: func(issue *models.Issue) {
+				issue.RemoveLabel("bug")
+				issue.AddLabel("feature")
+			},
+			want: []event{
+				{models.IssueEventLabeled, "", "feature"},
+				{models.IssueEventUnlabeled, "bug", ""},
+			},
+		},
+		{
+			name:   "担当者の変更",
+			change: func(issue *models.Issue) { issue.AssigneeID = 4 },
+			want: []event{
+				{models.IssueEventUnassigned, "2", ""},
+				{models.IssueEventAssigned, "", "4"},
+			},
+		},
+		{
+			name:   "担当者の削除",
+			change: func(issue *models.Issue) { issue.AssigneeID = 0 },
+			want:   []event{{models.IssueEventUnassigned, "2", ""}},
+		},
+		{
+			name: "複数の担当者の追加と削除",
+			change: func(issue *models.Issue) {
+				issue.AddAssignee(4)
+				issue.AddAssignee(5)
+				issue.RemoveAssignee(2)
+			},
+			want: []event{
+				{models.IssueEventUnassigned, "2", ""},
+				{models.IssueEventAssigned, "", "4"},
+				{models.IssueEventAssigned, "", "5"},
+			},
+		},
+		{
+			name:   "マイルストーンの変更",
+			change: func(issue *models.Issue) { issue.MilestoneID = 5 },
+			want:   []event{{models.IssueEventMilestoned, "3", "5"}},
+		},
+		{
+			name:   "マイルストーンの削除",
+			change: func(issue *models.Issue) { issue.MilestoneID = 0 },
+			want:   []event{{models.IssueEventDemilestoned, "3", ""}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := base.Clone()
+			after := base.Clone()
+			tt.change(after)
+
+			var got []event
+			for _, e := range models.DiffIssueEvents(before, after, 9) {
+				assert.Equal(t, int64(1), e.IssueID)
+				assert.Equal(t, int64(9), e.ActorID)
+				got = append(got, event{e.Type, e.OldValue, e.NewValue})
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDiffIssueEvents_ConvertedFromDraft(t *testing.T) {
+	before := &models.Issue{ID: 1, Title: "ドラフト", Status: "open", IsDraft: true}
+	after := before.Clone()
+	after.IsDraft = false
+
+	events := models.DiffIssueEvents(before, after, 1)
+
+	assert.Len(t, events, 1)
+	assert.Equal(t, models.IssueEventConvertedFromDraft, events[0].Type)
+}
+
+func TestBuildTimeline(t *testing.T) {
+	now := time.Now()
+	events := []*models.IssueEvent{
+		{ID: 1, Type: models.IssueEventLabeled, CreatedAt: now.Add(time.Minute)},
+		{ID: 2, Type: models.IssueEventClosed, CreatedAt: now.Add(3 * time.Minute)},
+	}
+	comments := []*models.Comment{
+		{ID: 10, CreatedAt: now},
+		{ID: 11, CreatedAt: now.Add(3 * time.Minute)},
+	}
+
+	timeline := models.BuildTimeline(events, comments)
+
+	assert.Len(t, timeline, 4)
+	assert.Equal(t, int64(10), timeline[0].Comment.ID)
+	assert.Equal(t, int64(1), timeline[1].Event.ID)
+	// 同時刻の場合は変更履歴が先
+	assert.Equal(t, models.TimelineItemEvent, timeline[2].Type)
+	assert.Equal(t, int64(2), timeline[2].Event.ID)
+	assert.Equal(t, int64(11), timeline[3].Comment.ID)
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"sort"
 	"time"
 
 	"gorm.io/gorm"
@@ -8,24 +9,37 @@ import (
 
 // IssueGorm はGORM用のIssue構造体
 type IssueGorm struct {
-	ID          int64          `gorm:"primaryKey;autoIncrement" json:"id"`
-	Title       string         `gorm:"not null" json:"title"`
-	Body        string         `gorm:"type:text;not null" json:"body"`
-	Status      string         `gorm:"not null;default:open" json:"status"`
-	AssigneeID  *int64         `gorm:"default:null" json:"assignee_id,omitempty"`
-	CreatorID   int64          `gorm:"not null" json:"creator_id"`
-	CreatedAt   time.Time      `gorm:"not null" json:"created_at"`
-	UpdatedAt   time.Time      `gorm:"not null" json:"updated_at"`
-	IsDraft     bool           `gorm:"not null;default:false" json:"is_draft"`
-	MilestoneID *int64         `gorm:"default:null" json:"milestone_id,omitempty"`
-	Labels      []IssueLabel   `gorm:"foreignKey:IssueID" json:"labels"`
-	DeletedAt   gorm.DeletedAt `gorm:"index"`
+	ID           int64           `gorm:"primaryKey;autoIncrement" json:"id"`
+	RepositoryID int64           `gorm:"not null;default:0;index" json:"repository_id"`
+	Number       int64           `gorm:"not null;default:0" json:"number"`
+	Title        string          `gorm:"not null" json:"title"`
+	Body         string          `gorm:"type:text;not null" json:"body"`
+	Status       string          `gorm:"not null;default:open" json:"status"`
+	AssigneeID   *int64          `gorm:"default:null" json:"assignee_id,omitempty"`
+	CreatorID    int64           `gorm:"not null" json:"creator_id"`
+	CreatedAt    time.Time       `gorm:"not null" json:"created_at"`
+	UpdatedAt    time.Time       `gorm:"not null" json:"updated_at"`
+	IsDraft      bool            `gorm:"not null;default:false" json:"is_draft"`
+	MilestoneID  *int64          `gorm:"default:null" json:"milestone_id,omitempty"`
+	TaskChecked  int             `gorm:"not null;default:0" json:"task_checked"` // 本文のチェック済みのチェックリスト項目数
+	TaskTotal    int             `gorm:"not null;default:0" json:"task_total"`   // 本文のチェックリスト項目数
+	Labels       []IssueLabel    `gorm:"foreignKey:IssueID" json:"labels"`
+	Assignees    []IssueAssignee `gorm:"foreignKey:IssueID" json:"assignees"`
+	DeletedAt    gorm.DeletedAt  `gorm:"index"`
 }
 
-// IssueLabel はIssueのラベルを表すGORM構造体
+// IssueLabel はIssueに付与されたラベルを表すGORM構造体
 type IssueLabel struct {
 	IssueID int64  `gorm:"primaryKey;not null"`
-	Label   string `gorm:"primaryKey;not null"`
+	LabelID int64  `gorm:"primaryKey;not null;index"`
+	Label   *Label `gorm:"foreignKey:LabelID;constraint:OnDelete:CASCADE"`
+}
+
+// IssueAssignee はIssueの担当者を表すGORM構造体
+type IssueAssignee struct {
+	IssueID  int64 `gorm:"primaryKey;not null"`
+	UserID   int64 `gorm:"primaryKey;not null;index"`
+	Position int   `gorm:"not null;default:0"` // 担当者の並び順（0が assignee_id の担当者）
 }
 
 // TableName はテーブル名を指定
@@ -38,68 +52,96 @@ func (IssueLabel) TableName() string {
 	return "issue_labels"
 }
 
+// TableName はテーブル名を指定
+func (IssueAssignee) TableName() string {
+	return "issue_assignees"
+}
+
 // ToModel はGORMモデルを通常のモデルに変換
 func (i *IssueGorm) ToModel() *Issue {
 	issue := &Issue{
-		ID:        i.ID,
-		Title:     i.Title,
-		Body:      i.Body,
-		Status:    i.Status,
-		CreatorID: i.CreatorID,
-		CreatedAt: i.CreatedAt,
-		UpdatedAt: i.UpdatedAt,
-		IsDraft:   i.IsDraft,
-		Labels:    make([]string, 0, len(i.Labels)),
+		ID:           i.ID,
+		RepositoryID: i.RepositoryID,
+		Number:       i.Number,
+		Title:        i.Title,
+		Body:         i.Body,
+		Status:       i.Status,
+		CreatorID:    i.CreatorID,
+		CreatedAt:    i.CreatedAt,
+		UpdatedAt:    i.UpdatedAt,
+		IsDraft:      i.IsDraft,
+		TaskProgress: TaskProgress{Completed: i.TaskChecked, Total: i.TaskTotal},
+		Labels:       make([]string, 0, len(i.Labels)),
+		AssigneeIDs:  make([]int64, 0, len(i.Assignees)),
 	}
 
 	if i.AssigneeID != nil {
 		issue.AssigneeID = *i.AssigneeID
 	}
 
+	assignees := append([]IssueAssignee(nil), i.Assignees...)
+	sort.SliceStable(assignees, func(a, b int) bool {
+		return assignees[a].Position < assignees[b].Position
+	})
+	for _, assignee := range assignees {
+		issue.AssigneeIDs = append(issue.AssigneeIDs, assignee.UserID)
+	}
+	if len(issue.AssigneeIDs) > 0 {
+		issue.AssigneeID = issue.AssigneeIDs[0]
+	} else if issue.AssigneeID > 0 {
+		issue.AssigneeIDs = append(issue.AssigneeIDs, issue.AssigneeID)
+	}
+
 	if i.MilestoneID != nil {
 		issue.MilestoneID = *i.MilestoneID
 	}
 
 	for _, label := range i.Labels {
-		issue.Labels = append(issue.Labels, label.Label)
+		if label.Label != nil {
+			issue.Labels = append(issue.Labels, label.Label.Name)
+		}
 	}
 
 	return issue
 }
 
-// FromModel は通常のモデルからGORMモデルを生成
+// FromModel は通常のモデルからGORMモデルを生成（ラベルはリポジトリ内のラベルIDに変換して別途保存する）
 func IssueFromModel(issue *Issue) *IssueGorm {
 	gormIssue := &IssueGorm{
-		ID:        issue.ID,
-		Title:     issue.Title,
-		Body:      issue.Body,
-		Status:    issue.Status,
-		CreatorID: issue.CreatorID,
-		CreatedAt: issue.CreatedAt,
-		UpdatedAt: issue.UpdatedAt,
-		IsDraft:   issue.IsDraft,
-		Labels:    make([]IssueLabel, 0, len(issue.Labels)),
+		ID:           issue.ID,
+		RepositoryID: issue.RepositoryID,
+		Number:       issue.Number,
+		Title:        issue.Title,
+		Body:         issue.Body,
+		Status:       issue.Status,
+		CreatorID:    issue.CreatorID,
+		CreatedAt:    issue.CreatedAt,
+		UpdatedAt:    issue.UpdatedAt,
+		IsDraft:      issue.IsDraft,
+		TaskChecked:  issue.TaskProgress.Completed,
+		TaskTotal:    issue.TaskProgress.Total,
 	}
 
-	if issue.AssigneeID > 0 {
-		gormIssue.AssigneeID = &issue.AssigneeID
+	assignees := issue.Assignees()
+	if len(assignees) > 0 {
+		gormIssue.AssigneeID = &assignees[0]
+	}
+	for position, userID := range assignees {
+		gormIssue.Assignees = append(gormIssue.Assignees, IssueAssignee{
+			IssueID:  issue.ID,
+			UserID:   userID,
+			Position: position,
+		})
 	}
 
 	if issue.MilestoneID > 0 {
 		gormIssue.MilestoneID = &issue.MilestoneID
 	}
 
-	for _, label := range issue.Labels {
-		gormIssue.Labels = append(gormIssue.Labels, IssueLabel{
-			IssueID: issue.ID,
-			Label:   label,
-		})
-	}
-
 	return gormIssue
 }
 
 // AutoMigrate はGORMのマイグレーションを実行
 func AutoMigrateIssue(db *gorm.DB) error {
-	return db.AutoMigrate(&IssueGorm{}, &IssueLabel{})
+	return db.AutoMigrate(&IssueGorm{}, &IssueLabel{}, &IssueAssignee{})
 }
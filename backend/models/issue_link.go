@@ -0,0 +1,287 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// IssueLinkType はIssue間の関連の種類を表す型
+type IssueLinkType string
+
+// 保存される関連の種類（source から target への向き）
+const (
+	IssueLinkBlocks     IssueLinkType = "blocks"     // source が target の完了を妨げている
+	IssueLinkDuplicates IssueLinkType = "duplicates" // source が target の重複
+	IssueLinkRelatesTo  IssueLinkType = "relates_to" // 向きのない関連
+	IssueLinkParentOf   IssueLinkType = "parent_of"  // source が target の親
+)
+
+// 逆向きの関連の種類（指定時に保存される種類へ変換され、取得時に片方のIssueから見た関連として使われる）
+const (
+	IssueLinkBlockedBy    IssueLinkType = "blocked_by"
+	IssueLinkDuplicatedBy IssueLinkType = "duplicated_by"
+	IssueLinkChildOf      IssueLinkType = "child_of"
+)
+
+var (
+	// ErrInvalidIssueLinkType は関連の種類が不正な場合のエラー
+	ErrInvalidIssueLinkType = errors.New("invalid link type. Must be one of blocks, blocked_by, duplicates, duplicated_by, relates_to, parent_of, child_of")
+	// ErrIssueLinkToSelf は自身への関連を追加しようとした場合のエラー
+	ErrIssueLinkToSelf = errors.New("an issue cannot be linked to itself")
+)
+
+// issueLinkInverses は関連の種類と逆向きの種類の対応
+var issueLinkInverses = map[IssueLinkType]IssueLinkType{
+	IssueLinkBlocks:       IssueLinkBlockedBy,
+	IssueLinkBlockedBy:    IssueLinkBlocks,
+	IssueLinkDuplicates:   IssueLinkDuplicatedBy,
+	IssueLinkDuplicatedBy: IssueLinkDuplicates,
+	IssueLinkRelatesTo:    IssueLinkRelatesTo,
+	IssueLinkParentOf:     IssueLinkChildOf,
+	IssueLinkChildOf:      IssueLinkParentOf,
+}
+
+// IsValid は関連の種類が有効かどうかを検証する
+func (t IssueLinkType) IsValid() bool {
+	_, ok := issueLinkInverses[t]
+	return ok
+}
+
+// IsStored は保存される向きの関連の種類かどうかを返す
+func (t IssueLinkType) IsStored() bool {
+	return t == IssueLinkBlocks || t == IssueLinkDuplicates || t == IssueLinkRelatesTo || t == IssueLinkParentOf
+}
+
+// Inverse は逆向きの関連の種類を返す
+func (t IssueLinkType) Inverse() IssueLinkType {
+	return issueLinkInverses[t]
+}
+
+// IsAcyclic は循環を許さない関連の種類かどうかを返す（向きのない relates_to 以外）
+func (t IssueLinkType) IsAcyclic() bool {
+	return t != IssueLinkRelatesTo
+}
+
+// IssueLink はIssue間の関連を表す構造体
+// 逆向きの種類で指定された関連は source と target を入れ替えて保存します
+type IssueLink struct {
+	ID            int64         `json:"id"`
+	SourceIssueID int64         `gorm:"not null;uniqueIndex:idx_issue_link_pair" json:"source_issue_id"`
+	TargetIssueID int64         `gorm:"not null;uniqueIndex:idx_issue_link_pair;index" json:"target_issue_id"`
+	Type          IssueLinkType `gorm:"not null;uniqueIndex:idx_issue_link_pair" json:"type"`
+	CreatorID     int64         `gorm:"not null" json:"creator_id"`
+	CreatedAt     time.Time     `json:"created_at"`
+
+	Relation IssueLinkType `gorm:"-" json:"relation,omitempty"` // グラフの中心のIssueから見た関連の種類
+}
+
+// NewIssueLink はIssueから見た関連の種類をもとに新しいIssueLinkインスタンスを作成する
+func NewIssueLink(issueID, otherIssueID int64, relation IssueLinkType, creatorID int64) (*IssueLink, error) {
+	if !relation.IsValid() {
+		return nil, ErrInvalidIssueLinkType
+	}
+	if issueID == otherIssueID {
+		return nil, ErrIssueLinkToSelf
+	}
+
+	link := &IssueLink{
+		SourceIssueID: issueID,
+		TargetIssueID: otherIssueID,
+		Type:          relation,
+		CreatorID:     creatorID,
+		CreatedAt:     time.Now(),
+	}
+	if !relation.IsStored() {
+		link.SourceIssueID, link.TargetIssueID = otherIssueID, issueID
+		link.Type = relation.Inverse()
+	}
+	return link, nil
+}
+
+// Involves は関連が指定されたIssueを含むかどうかを返す
+func (l *IssueLink) Involves(issueID int64) bool {
+	return l.SourceIssueID == issueID || l.TargetIssueID == issueID
+}
+
+// OtherIssueID は関連の指定されたIssueではない側のIssueのIDを返す
+func (l *IssueLink) OtherIssueID(issueID int64) int64 {
+	if l.SourceIssueID == issueID {
+		return l.TargetIssueID
+	}
+	return l.SourceIssueID
+}
+
+// RelationFrom は指定されたIssueから見た関連の種類を返す
+func (l *IssueLink) RelationFrom(issueID int64) IssueLinkType {
+	if l.SourceIssueID == issueID {
+		return l.Type
+	}
+	return l.Type.Inverse()
+}
+
+// Duplicates は同じ2つのIssueの間の同じ関連かどうかを返す（relates_to は向きを区別しない）
+func (l *IssueLink) Duplicates(other *IssueLink) bool {
+	if l.Type != other.Type {
+		return false
+	}
+	if l.SourceIssueID == other.SourceIssueID && l.TargetIssueID == other.TargetIssueID {
+		return true
+	}
+	return l.Type == IssueLinkRelatesTo && l.SourceIssueID == other.TargetIssueID && l.TargetIssueID == other.SourceIssueID
+}
+
+// IssueLinkCreatesCycle は既存の関連に新しい関連を追加すると同じ種類の関連が循環するかどうかを判定する
+// 新しい関連の target から同じ種類の関連を辿って source に到達できる場合に循環とみなします
+func IssueLinkCreatesCycle(links []*IssueLink, link *IssueLink) bool {
+	if !link.Type.IsAcyclic() {
+		return false
+	}
+
+	next := make(map[int64][]int64)
+	for _, l := range links {
+		if l.Type == link.Type {
+			next[l.SourceIssueID] = append(next[l.SourceIssueID], l.TargetIssueID)
+		}
+	}
+
+	visited := map[int64]bool{link.TargetIssueID: true}
+	queue := []int64{link.TargetIssueID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == link.SourceIssueID {
+			return true
+		}
+		for _, id := range next[current] {
+			if !visited[id] {
+				visited[id] = true
+				queue = append(queue, id)
+			}
+		}
+	}
+	return false
+}
+
+// IssueLinkNode は関連グラフに含まれるIssueの概要を表す構造体
+type IssueLinkNode struct {
+	ID           int64  `json:"id"`
+	RepositoryID int64  `json:"repository_id"`
+	Number       int64  `json:"number"`
+	Title        string `json:"title"`
+	Status       string `json:"status"`
+	IsDraft      bool   `json:"is_draft"`
+}
+
+// NewIssueLinkNode はIssueから関連グラフのノードを作成する
+func NewIssueLinkNode(issue *Issue) *IssueLinkNode {
+	return &IssueLinkNode{
+		ID:           issue.ID,
+		RepositoryID: issue.RepositoryID,
+		Number:       issue.Number,
+		Title:        issue.Title,
+		Status:       issue.Status,
+		IsDraft:      issue.IsDraft,
+	}
+}
+
+// IssueLinkGraph はIssueとその直接の関連先からなる関連グラフを表す構造体
+type IssueLinkGraph struct {
+	IssueID int64            `json:"issue_id"`
+	Nodes   []*IssueLinkNode `json:"nodes"` // 中心のIssueと関連先のIssue
+	Edges   []*IssueLink     `json:"edges"` // 保存される向きの関連（relation は中心のIssueから見た種類）
+}
+
+// BuildIssueLinkGraph は関連とIssueから関連グラフを作成する
+// 削除済みなどでIssueが見つからない関連は含めません
+func BuildIssueLinkGraph(issue *Issue, links []*IssueLink, issues []*Issue) *IssueLinkGraph {
+	graph := &IssueLinkGraph{
+		IssueID: issue.ID,
+		Nodes:   []*IssueLinkNode{NewIssueLinkNode(issue)},
+		Edges:   []*IssueLink{},
+	}
+
+	byID := make(map[int64]*Issue, len(issues))
+	for _, other := range issues {
+		byID[other.ID] = other
+	}
+
+	added := map[int64]bool{issue.ID: true}
+	for _, link := range links {
+		otherID := link.OtherIssueID(issue.ID)
+		other, ok := byID[otherID]
+		if !ok || !link.Involves(issue.ID) {
+			continue
+		}
+		link.Relation = link.RelationFrom(issue.ID)
+		graph.Edges = append(graph.Edges, link)
+		if !added[otherID] {
+			added[otherID] = true
+			graph.Nodes = append(graph.Nodes, NewIssueLinkNode(other))
+		}
+	}
+	return graph
+}
+
+// OpenBlockers は中心のIssueの完了を妨げているオープンなIssueを返す
+func (g *IssueLinkGraph) OpenBlockers() []*IssueLinkNode {
+	nodes := make(map[int64]*IssueLinkNode, len(g.Nodes))
+	for _, node := range g.Nodes {
+		nodes[node.ID] = node
+	}
+
+	blockers := []*IssueLinkNode{}
+	for _, edge := range g.Edges {
+		if edge.Type != IssueLinkBlocks || edge.TargetIssueID != g.IssueID {
+			continue
+		}
+		if node := nodes[edge.SourceIssueID]; node != nil && node.Status == "open" {
+			blockers = append(blockers, node)
+		}
+	}
+	return blockers
+}
+
+// SubIssueProgress は中心のIssueの子のIssueのクローズ数を返す（子がない場合はnil）
+func (g *IssueLinkGraph) SubIssueProgress() *TaskProgress {
+	statuses := make(map[int64]string, len(g.Nodes))
+	for _, node := range g.Nodes {
+		statuses[node.ID] = node.Status
+	}
+
+	var progress TaskProgress
+	for _, edge := range g.Edges {
+		if edge.Type != IssueLinkParentOf || edge.SourceIssueID != g.IssueID {
+			continue
+		}
+		progress.Total++
+		if statuses[edge.TargetIssueID] == "closed" {
+			progress.Completed++
+		}
+	}
+	if progress.Total == 0 {
+		return nil
+	}
+	return &progress
+}
+
+// NewSubIssueProgress はステータスごとの子のIssue数からクローズ数を作成する（子がない場合はnil）
+func NewSubIssueProgress(counts map[string]int) *TaskProgress {
+	var progress TaskProgress
+	for status, count := range counts {
+		progress.Total += count
+		if status == "closed" {
+			progress.Completed += count
+		}
+	}
+	if progress.Total == 0 {
+		return nil
+	}
+	return &progress
+}
+
+// AutoMigrateIssueLink はIssueLinkテーブルを作成・更新します
+func AutoMigrateIssueLink(db *gorm.DB) error {
+	return db.AutoMigrate(&IssueLink{})
+}
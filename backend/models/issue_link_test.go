@@ -0,0 +1,101 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIssueLink(t *testing.T) {
+	tests := []struct {
+		name       string
+		relation   models.IssueLinkType
+		wantSource int64
+		wantTarget int64
+		wantType   models.IssueLinkType
+		wantErr    error
+	}{
+		{"保存される向きの種類", models.IssueLinkBlocks, 1, 2, models.IssueLinkBlocks, nil},
+		{"逆向きの種類は入れ替えて保存", models.IssueLinkBlockedBy, 2, 1, models.IssueLinkBlocks, nil},
+		{"子から親への関連", models.IssueLinkChildOf, 2, 1, models.IssueLinkParentOf, nil},
+		{"向きのない関連", models.IssueLinkRelatesTo, 1, 2, models.IssueLinkRelatesTo, nil},
+		{"不正な種類", "follows", 0, 0, "", models.ErrInvalidIssueLinkType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, err := models.NewIssueLink(1, 2, tt.relation, 10)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSource, link.SourceIssueID)
+			assert.Equal(t, tt.wantTarget, link.TargetIssueID)
+			assert.Equal(t, tt.wantType, link.Type)
+			assert.Equal(t, tt.relation, link.RelationFrom(1))
+		})
+	}
+
+	_, err := models.NewIssueLink(1, 1, models.IssueLinkBlocks, 10)
+	assert.ErrorIs(t, err, models.ErrIssueLinkToSelf)
+}
+
+func TestIssueLinkCreatesCycle(t *testing.T) {
+	link := func(source, target int64, linkType models.IssueLinkType) *models.IssueLink {
+		return &models.IssueLink{SourceIssueID: source, TargetIssueID: target, Type: linkType}
+	}
+	// 1 → 2 → 3 のブロック関係と 4 → 1 の親子関係
+	links := []*models.IssueLink{
+		link(1, 2, models.IssueLinkBlocks),
+		link(2, 3, models.IssueLinkBlocks),
+		link(4, 1, models.IssueLinkParentOf),
+	}
+
+	tests := []struct {
+		name string
+		link *models.IssueLink
+		want bool
+	}{
+		{"直接の循環", link(2, 1, models.IssueLinkBlocks), true},
+		{"間接の循環", link(3, 1, models.IssueLinkBlocks), true},
+		{"循環しない追加", link(1, 3, models.IssueLinkBlocks), false},
+		{"種類が異なる関連は辿らない", link(3, 1, models.IssueLinkParentOf), false},
+		{"親子関係の循環", link(1, 4, models.IssueLinkParentOf), true},
+		{"向きのない関連は循環しない", link(3, 1, models.IssueLinkRelatesTo), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.IssueLinkCreatesCycle(links, tt.link))
+		})
+	}
+}
+
+func TestIssueLinkGraph_OpenBlockers(t *testing.T) {
+	issue := &models.Issue{ID: 1, Status: "open"}
+	issues := []*models.Issue{
+		{ID: 2, Status: "open"},
+		{ID: 3, Status: "closed"},
+		{ID: 4, Status: "open"},
+	}
+	links := []*models.IssueLink{
+		{ID: 1, SourceIssueID: 2, TargetIssueID: 1, Type: models.IssueLinkBlocks},
+		{ID: 2, SourceIssueID: 3, TargetIssueID: 1, Type: models.IssueLinkBlocks},
+		{ID: 3, SourceIssueID: 1, TargetIssueID: 4, Type: models.IssueLinkBlocks},
+		// 削除済みのIssueとの関連は含めない
+		{ID: 4, SourceIssueID: 5, TargetIssueID: 1, Type: models.IssueLinkBlocks},
+	}
+
+	graph := models.BuildIssueLinkGraph(issue, links, issues)
+	assert.Len(t, graph.Nodes, 4)
+	assert.Len(t, graph.Edges, 3)
+	assert.Equal(t, models.IssueLinkBlockedBy, graph.Edges[0].Relation)
+	assert.Equal(t, models.IssueLinkBlocks, graph.Edges[2].Relation)
+
+	blockers := graph.OpenBlockers()
+	if assert.Len(t, blockers, 1) {
+		assert.Equal(t, int64(2), blockers[0].ID)
+	}
+}
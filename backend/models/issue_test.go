@@ -112,3 +112,46 @@ func TestIssue_Labels(t *testing.T) {
 	issue.RemoveLabel("存在しないラベル")
 	assert.Len(t, issue.Labels, 1)
 }
+
+func TestIssue_Assignees(t *testing.T) {
+	issue := models.NewIssue("テストIssue", "説明", 1)
+	assert.Empty(t, issue.Assignees())
+
+	// 担当者追加
+	issue.AddAssignee(2)
+	issue.AddAssignee(3)
+	assert.Equal(t, []int64{2, 3}, issue.Assignees())
+	assert.Equal(t, int64(2), issue.AssigneeID)
+
+	// 同じ担当者や0を追加しても変わらないことを確認
+	issue.AddAssignee(3)
+	issue.AddAssignee(0)
+	assert.Equal(t, []int64{2, 3}, issue.Assignees())
+
+	// 最初の担当者を削除すると assignee_id は次の担当者になる
+	issue.RemoveAssignee(2)
+	assert.Equal(t, []int64{3}, issue.Assignees())
+	assert.Equal(t, int64(3), issue.AssigneeID)
+	assert.False(t, issue.HasAssignee(2))
+
+	// 担当者の置き換え（重複は除外）
+	issue.SetAssignees([]int64{4, 5, 4})
+	assert.Equal(t, []int64{4, 5}, issue.Assignees())
+	assert.Equal(t, int64(4), issue.AssigneeID)
+
+	// すべての担当者を削除
+	issue.SetAssignees(nil)
+	assert.Empty(t, issue.Assignees())
+	assert.Equal(t, int64(0), issue.AssigneeID)
+}
+
+func TestIssue_Assignees_LegacyAssigneeID(t *testing.T) {
+	// assignee_id のみ設定された旧形式のIssue
+	issue := models.Issue{AssigneeID: 2}
+	assert.Equal(t, []int64{2}, issue.Assignees())
+	assert.True(t, issue.HasAssignee(2))
+
+	issue.AddAssignee(3)
+	assert.Equal(t, []int64{2, 3}, issue.Assignees())
+	assert.Equal(t, int64(2), issue.AssigneeID)
+}
@@ -1,18 +1,38 @@
 package models
 
 import (
+	"fmt"
+	"strings"
 	"time"
+
+	"gorm.io/gorm"
+)
+
+// ラベルの種類（付与できる対象）
+const (
+	LabelTypeIssue      = "issue"
+	LabelTypeDiscussion = "discussion"
+	LabelTypeBoth       = "both"
 )
 
+// DefaultLabelColor は色が指定されていないラベルの色
+const DefaultLabelColor = "#ededed"
+
+// LabelScopeSeparator はラベル名のスコープと値の区切り文字（priority/high のスコープは priority）
+const LabelScopeSeparator = "/"
+
 // Label はラベル情報を表す構造体
 type Label struct {
-	ID          int64     `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Color       string    `json:"color"`
-	Type        string    `json:"type"` // issue/discussion/both
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID           int64     `json:"id"`
+	RepositoryID int64     `gorm:"not null;default:0;index" json:"repository_id"` // ラベル名の名前空間となるリポジトリ
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	Color        string    `json:"color"`
+	Type         string    `json:"type"`                                    // issue/discussion/both
+	Scope        string    `gorm:"not null;default:'';index" json:"scope"`  // ラベル名の最後の区切り文字より前の部分（スコープなしは空）
+	Exclusive    bool      `gorm:"not null;default:false" json:"exclusive"` // 同じスコープのラベルを1つだけ付与できるか
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 // NewLabel は新しいLabelインスタンスを作成する
@@ -23,6 +43,7 @@ func NewLabel(name, description, color, labelType string) *Label {
 		Description: description,
 		Color:       color,
 		Type:        labelType,
+		Scope:       LabelScope(name),
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
@@ -35,12 +56,19 @@ func (l *Label) IsValid() bool {
 
 // isValidLabelType はラベルタイプが有効かどうかを検証する
 func isValidLabelType(labelType string) bool {
-	return labelType == "issue" || labelType == "discussion" || labelType == "both"
+	return labelType == LabelTypeIssue || labelType == LabelTypeDiscussion || labelType == LabelTypeBoth
+}
+
+// AppliesTo はラベルを指定した対象（issue/discussion）に付与できるかを判定する
+func (l *Label) AppliesTo(targetType string) bool {
+	return l.Type == LabelTypeBoth || l.Type == targetType
 }
 
 // Update はラベル情報を更新する
-func (l *Label) Update(name, description, color, labelType string) {
+func (l *Label) Update(name, description, color, labelType string, exclusive bool) {
 	l.Name = name
+	l.Scope = LabelScope(name)
+	l.Exclusive = exclusive
 	l.Description = description
 	l.Color = color
 	if isValidLabelType(labelType) {
@@ -48,3 +76,93 @@ func (l *Label) Update(name, description, color, labelType string) {
 	}
 	l.UpdatedAt = time.Now()
 }
+
+// IsExclusiveWith は同じスコープの排他的なラベル同士で、同時に付与できないかを判定する
+func (l *Label) IsExclusiveWith(other *Label) bool {
+	return l.Exclusive && other.Exclusive && l.Scope != "" && l.Scope == other.Scope && l.Name != other.Name
+}
+
+// LabelScope はラベル名からスコープを取得する（区切り文字を含まない場合は空）
+func LabelScope(name string) string {
+	i := strings.LastIndex(name, LabelScopeSeparator)
+	if i <= 0 || i == len(name)-1 {
+		return ""
+	}
+	return name[:i]
+}
+
+// ResolveExclusiveLabels は排他的なスコープのラベルが1つだけになるように付与するラベルを決定する
+// 同じスコープのラベルが複数指定された場合は新たに追加されたラベルを残し、以前から付与されていたラベルを外す
+// 新たに追加されたラベル同士が競合する場合はエラーを返す
+func ResolveExclusiveLabels(previous, requested []string, labels map[string]*Label) ([]string, error) {
+	wasApplied := make(map[string]bool, len(previous))
+	for _, name := range previous {
+		wasApplied[name] = true
+	}
+
+	removed := make(map[string]bool)
+	for i, name := range requested {
+		label, ok := labels[name]
+		if !ok {
+			continue
+		}
+		for _, otherName := range requested[i+1:] {
+			other, ok := labels[otherName]
+			if !ok || !label.IsExclusiveWith(other) {
+				continue
+			}
+			switch {
+			case !wasApplied[name] && !wasApplied[otherName]:
+				return nil, fmt.Errorf("labels %q and %q are mutually exclusive in scope %q", name, otherName, label.Scope)
+			case wasApplied[name] && !wasApplied[otherName]:
+				removed[name] = true
+			case !wasApplied[name] && wasApplied[otherName]:
+				removed[otherName] = true
+			default:
+				// 以前から両方付与されていた場合は後に指定されたラベルを残す
+				removed[name] = true
+			}
+		}
+	}
+
+	resolved := make([]string, 0, len(requested))
+	for _, name := range requested {
+		if !removed[name] {
+			resolved = append(resolved, name)
+		}
+	}
+	return resolved, nil
+}
+
+// LabelScopeGroup はスコープごとにまとめたラベルの一覧
+type LabelScopeGroup struct {
+	Scope     string   `json:"scope"`
+	Exclusive bool     `json:"exclusive"`
+	Labels    []*Label `json:"labels"`
+}
+
+// GroupLabelsByScope はラベルをスコープごとにまとめる（スコープのないラベルは除く）
+// スコープ内のすべてのラベルが排他的な場合、そのスコープは排他的として扱う
+func GroupLabelsByScope(labels []*Label) []*LabelScopeGroup {
+	groups := []*LabelScopeGroup{}
+	byScope := make(map[string]*LabelScopeGroup)
+	for _, label := range labels {
+		if label.Scope == "" {
+			continue
+		}
+		group, ok := byScope[label.Scope]
+		if !ok {
+			group = &LabelScopeGroup{Scope: label.Scope, Exclusive: true, Labels: []*Label{}}
+			byScope[label.Scope] = group
+			groups = append(groups, group)
+		}
+		group.Exclusive = group.Exclusive && label.Exclusive
+		group.Labels = append(group.Labels, label)
+	}
+	return groups
+}
+
+// AutoMigrateLabel はLabelテーブルを作成・更新します
+func AutoMigrateLabel(db *gorm.DB) error {
+	return db.AutoMigrate(&Label{})
+}
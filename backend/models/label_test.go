@@ -0,0 +1,118 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabel_AppliesTo(t *testing.T) {
+	tests := []struct {
+		name       string
+		labelType  string
+		targetType string
+		want       bool
+	}{
+		{"Issue用のラベルをIssueに適用", models.LabelTypeIssue, models.LabelTypeIssue, true},
+		{"Issue用のラベルをDiscussionに適用", models.LabelTypeIssue, models.LabelTypeDiscussion, false},
+		{"Discussion用のラベルをDiscussionに適用", models.LabelTypeDiscussion, models.LabelTypeDiscussion, true},
+		{"Discussion用のラベルをIssueに適用", models.LabelTypeDiscussion, models.LabelTypeIssue, false},
+		{"共通のラベルをIssueに適用", models.LabelTypeBoth, models.LabelTypeIssue, true},
+		{"共通のラベルをDiscussionに適用", models.LabelTypeBoth, models.LabelTypeDiscussion, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label := &models.Label{Name: "bug", Type: tt.labelType}
+			assert.Equal(t, tt.want, label.AppliesTo(tt.targetType))
+		})
+	}
+}
+
+func TestLabelScope(t *testing.T) {
+	tests := []struct {
+		name  string
+		label string
+		want  string
+	}{
+		{"スコープなし", "bug", ""},
+		{"スコープあり", "priority/high", "priority"},
+		{"入れ子のスコープ", "area/ui/button", "area/ui"},
+		{"先頭の区切り文字", "/high", ""},
+		{"末尾の区切り文字", "priority/", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.LabelScope(tt.label))
+		})
+	}
+}
+
+func TestResolveExclusiveLabels(t *testing.T) {
+	newLabel := func(name string, exclusive bool) *models.Label {
+		label := models.NewLabel(name, "", models.DefaultLabelColor, models.LabelTypeBoth)
+		label.Exclusive = exclusive
+		return label
+	}
+	labels := map[string]*models.Label{}
+	for _, label := range []*models.Label{
+		newLabel("bug", false),
+		newLabel("priority/high", true),
+		newLabel("priority/low", true),
+		newLabel("type/bug", false),
+		newLabel("type/feature", false),
+	} {
+		labels[label.Name] = label
+	}
+
+	tests := []struct {
+		name      string
+		previous  []string
+		requested []string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "追加したラベルが同じスコープのラベルを置き換える",
+			previous:  []string{"bug", "priority/low"},
+			requested: []string{"bug", "priority/low", "priority/high"},
+			want:      []string{"bug", "priority/high"},
+		},
+		{
+			name:      "排他的でないスコープは複数付与できる",
+			previous:  []string{"type/bug"},
+			requested: []string{"type/bug", "type/feature"},
+			want:      []string{"type/bug", "type/feature"},
+		},
+		{
+			name:      "同時に追加したラベル同士の競合",
+			requested: []string{"priority/high", "priority/low"},
+			wantErr:   true,
+		},
+		{
+			name:      "以前から両方付与されている場合は後のラベルを残す",
+			previous:  []string{"priority/high", "priority/low"},
+			requested: []string{"priority/high", "priority/low"},
+			want:      []string{"priority/low"},
+		},
+		{
+			name:      "登録されていないラベルはそのまま",
+			requested: []string{"unknown", "priority/high"},
+			want:      []string{"unknown", "priority/high"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := models.ResolveExclusiveLabels(tt.previous, tt.requested, labels)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LoginThrottleScope はログインの失敗を数える単位
+type LoginThrottleScope string
+
+const (
+	// LoginThrottleAccount はアカウントごとの失敗回数
+	LoginThrottleAccount LoginThrottleScope = "account"
+	// LoginThrottleIP はIPアドレスごとの失敗回数
+	LoginThrottleIP LoginThrottleScope = "ip"
+)
+
+// LoginThrottle はアカウントまたはIPアドレスごとのログインの失敗回数とロックの状態を表す構造体
+type LoginThrottle struct {
+	ID           int64              `gorm:"primaryKey" json:"id"`
+	Scope        LoginThrottleScope `gorm:"size:16;not null;uniqueIndex:idx_login_throttles_scope_identifier" json:"scope"`
+	Identifier   string             `gorm:"size:255;not null;uniqueIndex:idx_login_throttles_scope_identifier" json:"identifier"` // アカウントの識別子またはIPアドレス
+	FailedCount  int                `gorm:"not null;default:0" json:"failed_count"`                                               // 連続した失敗回数
+	LastFailedAt time.Time          `json:"last_failed_at"`
+	LockedUntil  *time.Time         `json:"locked_until,omitempty"` // ロックの解除日時
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+// NewLoginThrottle は新しいLoginThrottleインスタンスを作成する
+func NewLoginThrottle(scope LoginThrottleScope, identifier string) *LoginThrottle {
+	now := time.Now()
+	return &LoginThrottle{
+		Scope:      scope,
+		Identifier: identifier,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// AccountThrottleIdentifier は登録済みのユーザーのアカウントの識別子を返す
+func AccountThrottleIdentifier(userID int64) string {
+	return "user:" + strconv.FormatInt(userID, 10)
+}
+
+// UnknownAccountThrottleIdentifier は存在しないユーザー名・メールアドレスのアカウントの識別子を返す
+// 存在しないアカウントも同じように数えて、応答の違いからアカウントの有無を推測されないようにする
+func UnknownAccountThrottleIdentifier(usernameOrEmail string) string {
+	return "name:" + strings.ToLower(strings.TrimSpace(usernameOrEmail))
+}
+
+// RecordFailure は失敗を記録する
+// 前回の失敗から resetAfter 以上経過している場合は1回目として数え直す
+func (t *LoginThrottle) RecordFailure(now time.Time, resetAfter time.Duration) {
+	if t.FailedCount > 0 && now.Sub(t.LastFailedAt) >= resetAfter {
+		t.FailedCount = 0
+	}
+	t.FailedCount++
+	t.LastFailedAt = now
+	t.UpdatedAt = now
+}
+
+// Lock は until までログインできないようにロックする
+func (t *LoginThrottle) Lock(until time.Time) {
+	t.LockedUntil = &until
+	t.UpdatedAt = time.Now()
+}
+
+// IsLocked は now の時点でロックされているかどうかを判定する
+func (t *LoginThrottle) IsLocked(now time.Time) bool {
+	return t.LockedUntil != nil && now.Before(*t.LockedUntil)
+}
+
+// BackoffDelay は失敗回数に応じた次の試行までの待ち時間を返す
+// 1回目の失敗後は base、以降は失敗のたびに2倍にし、max を上限とする（max が base より小さい場合は base）
+func (t *LoginThrottle) BackoffDelay(base, max time.Duration) time.Duration {
+	if t.FailedCount <= 0 || base <= 0 {
+		return 0
+	}
+	if max < base {
+		max = base
+	}
+	delay := base
+	for i := 1; i < t.FailedCount && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
+
+// RetryAfter は now から次に試行できるまでの時間を返す（すぐに試行できる場合は0）
+func (t *LoginThrottle) RetryAfter(now time.Time, base, max time.Duration) time.Duration {
+	var wait time.Duration
+	if t.IsLocked(now) {
+		wait = t.LockedUntil.Sub(now)
+	}
+	if backoff := t.LastFailedAt.Add(t.BackoffDelay(base, max)).Sub(now); backoff > wait {
+		wait = backoff
+	}
+	return wait
+}
+
+// AutoMigrateLoginThrottle はLoginThrottleテーブルのマイグレーションを実行します
+func AutoMigrateLoginThrottle(db *gorm.DB) error {
+	return db.AutoMigrate(&LoginThrottle{})
+}
@@ -0,0 +1,94 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginThrottle_BackoffDelay(t *testing.T) {
+	tests := []struct {
+		name        string
+		failedCount int
+		base        time.Duration
+		max         time.Duration
+		want        time.Duration
+	}{
+		{name: "失敗なし", failedCount: 0, base: time.Second, max: time.Minute, want: 0},
+		{name: "1回目", failedCount: 1, base: time.Second, max: time.Minute, want: time.Second},
+		{name: "3回目", failedCount: 3, base: time.Second, max: time.Minute, want: 4 * time.Second},
+		{name: "上限", failedCount: 10, base: time.Second, max: time.Minute, want: time.Minute},
+		{name: "上限が基準より小さい", failedCount: 3, base: time.Second, max: 0, want: time.Second},
+		{name: "待ち時間なし", failedCount: 3, base: 0, max: time.Minute, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			throttle := &models.LoginThrottle{FailedCount: tt.failedCount}
+			assert.Equal(t, tt.want, throttle.BackoffDelay(tt.base, tt.max))
+		})
+	}
+}
+
+func TestLoginThrottle_RecordFailure(t *testing.T) {
+	now := time.Now()
+	throttle := models.NewLoginThrottle(models.LoginThrottleAccount, models.AccountThrottleIdentifier(7))
+	assert.Equal(t, "user:7", throttle.Identifier)
+
+	throttle.RecordFailure(now, 15*time.Minute)
+	throttle.RecordFailure(now.Add(time.Minute), 15*time.Minute)
+	assert.Equal(t, 2, throttle.FailedCount)
+	assert.Equal(t, now.Add(time.Minute), throttle.LastFailedAt)
+
+	// 最後の失敗から一定時間が経過した場合は数え直す
+	throttle.RecordFailure(now.Add(20*time.Minute), 15*time.Minute)
+	assert.Equal(t, 1, throttle.FailedCount)
+}
+
+func TestLoginThrottle_RetryAfter(t *testing.T) {
+	now := time.Now()
+	throttle := models.NewLoginThrottle(models.LoginThrottleIP, "192.0.2.1")
+	assert.Zero(t, throttle.RetryAfter(now, time.Second, time.Minute))
+
+	throttle.RecordFailure(now, 15*time.Minute)
+	throttle.RecordFailure(now, 15*time.Minute)
+	assert.Equal(t, 2*time.Second, throttle.RetryAfter(now, time.Second, time.Minute))
+	assert.Zero(t, throttle.RetryAfter(now.Add(3*time.Second), time.Second, time.Minute))
+	assert.False(t, throttle.IsLocked(now))
+
+	// ロック中はロックの解除までの時間
+	throttle.Lock(now.Add(15 * time.Minute))
+	assert.True(t, throttle.IsLocked(now))
+	assert.Equal(t, 15*time.Minute, throttle.RetryAfter(now, time.Second, time.Minute))
+	assert.False(t, throttle.IsLocked(now.Add(15*time.Minute)))
+}
+
+func TestUnknownAccountThrottleIdentifier(t *testing.T) {
+	assert.Equal(t, "name:alice@example.com", models.UnknownAccountThrottleIdentifier(" Alice@Example.com "))
+	assert.NotEqual(t, models.AccountThrottleIdentifier(1), models.UnknownAccountThrottleIdentifier("1"))
+}
+
+func TestSystemSettings_UpdateLoginPolicy(t *testing.T) {
+	settings := models.NewDefaultSystemSettings()
+	assert.Equal(t, 5, settings.LoginMaxFailedAttempts)
+
+	// JSONから読み込んだ数値は float64 になる
+	settings.Update(map[string]interface{}{
+		"login_max_failed_attempts":    float64(3),
+		"login_lockout_minutes":        30,
+		"login_backoff_base_seconds":   float64(0),
+		"login_backoff_max_seconds":    1.5,
+		"login_ip_max_failed_attempts": float64(-1),
+	})
+	assert.Equal(t, 3, settings.LoginMaxFailedAttempts)
+	assert.Equal(t, 30, settings.LoginLockoutMinutes)
+	assert.Equal(t, 0, settings.LoginBackoffBaseSeconds)
+	assert.Equal(t, 60, settings.LoginBackoffMaxSeconds, "小数は無視する")
+	assert.Equal(t, 20, settings.LoginIPMaxFailedAttempts, "負の数は無視する")
+
+	// ロックする時間は失敗回数を数え直すまでの時間も兼ねるため、0は受け付けない
+	settings.Update(map[string]interface{}{"login_lockout_minutes": float64(0)})
+	assert.Equal(t, 30, settings.LoginLockoutMinutes)
+}
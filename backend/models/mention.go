@@ -0,0 +1,75 @@
+package models
+
+import (
+	"regexp"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// mentionPattern は@username形式のメンションに一致する（メールアドレス等は除外）
+	mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_@./-])@([A-Za-z0-9][A-Za-z0-9_-]*)`)
+	// codeBlockPattern はメンション対象外とするコードブロック・インラインコードに一致する
+	codeBlockPattern = regexp.MustCompile("(?s)```.*?```|`[^`\n]*`")
+)
+
+// Mention は本文中の@メンション情報を表す構造体
+type Mention struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`     // メンションされたユーザー
+	SourceType string     `json:"source_type"` // issue/discussion/comment
+	SourceID   int64      `json:"source_id"`
+	ActorID    int64      `json:"actor_id"` // メンションしたユーザー
+	CreatedAt  time.Time  `json:"created_at"`
+	RemovedAt  *time.Time `gorm:"index" json:"removed_at,omitempty"` // 本文から削除された日時（再度メンションされた際に再通知しないため行は残す）
+}
+
+// NewMention は新しいMentionインスタンスを作成する
+func NewMention(userID int64, sourceType string, sourceID, actorID int64) *Mention {
+	return &Mention{
+		UserID:     userID,
+		SourceType: sourceType,
+		SourceID:   sourceID,
+		ActorID:    actorID,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// IsRemoved は本文から削除されたメンションかどうかを判定する
+func (m *Mention) IsRemoved() bool {
+	return m.RemovedAt != nil
+}
+
+// MarkRemoved は本文から削除されたメンションとして記録する
+func (m *Mention) MarkRemoved() {
+	now := time.Now()
+	m.RemovedAt = &now
+}
+
+// Restore は再度メンションされたメンションを有効に戻す
+func (m *Mention) Restore() {
+	m.RemovedAt = nil
+}
+
+// ParseMentions は本文から重複を除いたメンションのユーザー名を出現順に抽出する
+func ParseMentions(body string) []string {
+	body = codeBlockPattern.ReplaceAllString(body, " ")
+
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		username := match[1]
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+	}
+	return usernames
+}
+
+// AutoMigrateMention はMentionのテーブルを作成・更新します
+func AutoMigrateMention(db *gorm.DB) error {
+	return db.AutoMigrate(&Mention{})
+}
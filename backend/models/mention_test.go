@@ -0,0 +1,61 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "単一のメンション",
+			body: "@alice 確認お願いします",
+			want: []string{"alice"},
+		},
+		{
+			name: "複数のメンションと重複",
+			body: "cc @bob, @carol-1 と @bob",
+			want: []string{"bob", "carol-1"},
+		},
+		{
+			name: "メールアドレスは除外",
+			body: "連絡先: dev@example.com",
+			want: nil,
+		},
+		{
+			name: "コードブロック内は除外",
+			body: "`@inline` と\n```\n@block\n```\n@dave",
+			want: []string{"dave"},
+		},
+		{
+			name: "メンションなし",
+			body: "普通の本文です",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.ParseMentions(tt.body))
+		})
+	}
+}
+
+func TestMention_MarkRemovedAndRestore(t *testing.T) {
+	mention := models.NewMention(1, "issue", 2, 3)
+	assert.False(t, mention.IsRemoved())
+
+	mention.MarkRemoved()
+	assert.True(t, mention.IsRemoved())
+	assert.NotNil(t, mention.RemovedAt)
+
+	mention.Restore()
+	assert.False(t, mention.IsRemoved())
+	assert.Nil(t, mention.RemovedAt)
+}
@@ -2,19 +2,28 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Milestone はマイルストーン情報を表す構造体
 type Milestone struct {
-	ID          int64     `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	DueDate     time.Time `json:"due_date,omitempty"`
-	Status      string    `json:"status"` // open/closed
-	CreatorID   int64     `json:"creator_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	CompletedAt time.Time `json:"completed_at,omitempty"`
+	ID           int64     `json:"id"`
+	RepositoryID int64     `gorm:"not null;default:0;index" json:"repository_id"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	DueDate      time.Time `json:"due_date,omitempty"`
+	Status       string    `json:"status"` // open/closed
+	CreatorID    int64     `json:"creator_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	CompletedAt  time.Time `json:"completed_at,omitempty"`
+
+	// 取得時に集計する進捗
+	OpenIssues      int  `gorm:"-" json:"open_issues"`
+	ClosedIssues    int  `gorm:"-" json:"closed_issues"`
+	PercentComplete int  `gorm:"-" json:"percent_complete"` // クローズ済みのIssueの割合（0〜100）
+	IsOverdue       bool `gorm:"-" json:"is_overdue"`       // オープンのまま期日を過ぎているか
 }
 
 // NewMilestone は新しいMilestoneインスタンスを作成する
@@ -52,6 +61,25 @@ func (m *Milestone) Reopen() {
 	m.UpdatedAt = time.Now()
 }
 
+// SetProgress はIssue数から進捗と期日超過を設定する
+func (m *Milestone) SetProgress(openIssues, closedIssues int, now time.Time) {
+	m.OpenIssues = openIssues
+	m.ClosedIssues = closedIssues
+	m.PercentComplete = 0
+	if total := openIssues + closedIssues; total > 0 {
+		m.PercentComplete = closedIssues * 100 / total
+	}
+	m.IsOverdue = m.IsOverdueAt(now)
+}
+
+// IsOverdueAt は指定日時にオープンのまま期日（その日の終わり）を過ぎているかを判定する
+func (m *Milestone) IsOverdueAt(now time.Time) bool {
+	if m.Status != "open" || m.DueDate.IsZero() {
+		return false
+	}
+	return !now.Before(truncateDay(m.DueDate).AddDate(0, 0, 1))
+}
+
 // Update はマイルストーン情報を更新する
 func (m *Milestone) Update(title, description string, dueDate time.Time) {
 	m.Title = title
@@ -59,3 +87,8 @@ func (m *Milestone) Update(title, description string, dueDate time.Time) {
 	m.DueDate = dueDate
 	m.UpdatedAt = time.Now()
 }
+
+// AutoMigrateMilestone はMilestoneテーブルを作成・更新します
+func AutoMigrateMilestone(db *gorm.DB) error {
+	return db.AutoMigrate(&Milestone{})
+}
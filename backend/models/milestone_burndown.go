@@ -0,0 +1,185 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// burndownDateLayout はバーンダウンチャートの日付の形式
+const burndownDateLayout = "2006-01-02"
+
+// MilestoneBurndown はマイルストーンのバーンダウンチャートのデータ
+type MilestoneBurndown struct {
+	MilestoneID int64           `json:"milestone_id"`
+	StartDate   string          `json:"start_date"` // マイルストーンの作成日
+	EndDate     string          `json:"end_date"`   // 期日（期日がない場合は今日）
+	Points      []BurndownPoint `json:"points"`
+}
+
+// BurndownPoint はバーンダウンチャートの1日分の値（日付はUTC）
+type BurndownPoint struct {
+	Date        string  `json:"date"`         // YYYY-MM-DD
+	OpenIssues  *int    `json:"open_issues"`  // その日の終わりのオープンなIssue数（未来の日はnull）
+	TotalIssues *int    `json:"total_issues"` // その日の終わりにマイルストーンに含まれるIssue数（未来の日はnull）
+	Ideal       float64 `json:"ideal"`        // 初日のオープンなIssue数から期日に0になる理想線
+}
+
+// burndownChange はIssueの状態の変化
+type burndownChange struct {
+	at          time.Time
+	inMilestone *bool
+	open        *bool
+}
+
+// burndownIssue は変更履歴から再構成したIssueの状態の推移
+type burndownIssue struct {
+	startAt     time.Time // Issueとして公開された日時（下書きから変換された場合は変換日時）
+	inMilestone bool      // 作成時にマイルストーンに含まれていたか
+	changes     []burndownChange
+}
+
+// stateAt は指定日時より前の変化を反映した状態を返す
+func (b *burndownIssue) stateAt(at time.Time) (exists, inMilestone, open bool) {
+	if !b.startAt.Before(at) {
+		return false, false, false
+	}
+	inMilestone, open = b.inMilestone, true
+	for _, change := range b.changes {
+		if !change.at.Before(at) {
+			break
+		}
+		if change.inMilestone != nil {
+			inMilestone = *change.inMilestone
+		}
+		if change.open != nil {
+			open = *change.open
+		}
+	}
+	return true, inMilestone, open
+}
+
+// BuildMilestoneBurndown はIssueのクローズ・再オープン・マイルストーンの変更履歴から日ごとのオープンなIssue数を再構成する
+// issues にはマイルストーンに含まれる、または含まれていたIssue、events にはそれらの変更履歴を指定する
+func BuildMilestoneBurndown(milestone *Milestone, issues []*Issue, events []*IssueEvent, now time.Time) *MilestoneBurndown {
+	start := truncateDay(milestone.CreatedAt)
+	today := truncateDay(now)
+	end := today
+	if !milestone.DueDate.IsZero() {
+		end = truncateDay(milestone.DueDate)
+	}
+	if end.Before(start) {
+		end = start
+	}
+
+	eventsByIssue := make(map[int64][]*IssueEvent)
+	for _, event := range events {
+		eventsByIssue[event.IssueID] = append(eventsByIssue[event.IssueID], event)
+	}
+	timelines := make([]*burndownIssue, 0, len(issues))
+	for _, issue := range issues {
+		if issue.IsDraft {
+			continue
+		}
+		timelines = append(timelines, newBurndownIssue(issue, milestone.ID, eventsByIssue[issue.ID]))
+	}
+
+	burndown := &MilestoneBurndown{
+		MilestoneID: milestone.ID,
+		StartDate:   start.Format(burndownDateLayout),
+		EndDate:     end.Format(burndownDateLayout),
+		Points:      []BurndownPoint{},
+	}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		point := BurndownPoint{Date: day.Format(burndownDateLayout)}
+		if !day.After(today) {
+			// その日の終わり（今日の場合は現在）の状態を集計
+			at := day.AddDate(0, 0, 1)
+			if at.After(now) {
+				at = now
+			}
+			open, total := 0, 0
+			for _, timeline := range timelines {
+				exists, inMilestone, isOpen := timeline.stateAt(at)
+				if !exists || !inMilestone {
+					continue
+				}
+				total++
+				if isOpen {
+					open++
+				}
+			}
+			point.OpenIssues, point.TotalIssues = &open, &total
+		}
+		burndown.Points = append(burndown.Points, point)
+	}
+
+	// 理想線（初日のオープンなIssue数から最終日に0）
+	if days := len(burndown.Points) - 1; days > 0 && burndown.Points[0].OpenIssues != nil {
+		initial := float64(*burndown.Points[0].OpenIssues)
+		for i := range burndown.Points {
+			burndown.Points[i].Ideal = math.Round(initial*float64(days-i)/float64(days)*100) / 100
+		}
+	}
+	return burndown
+}
+
+// newBurndownIssue はIssueの変更履歴から状態の推移を作成する
+func newBurndownIssue(issue *Issue, milestoneID int64, events []*IssueEvent) *burndownIssue {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+
+	milestone := strconv.FormatInt(milestoneID, 10)
+	b := &burndownIssue{startAt: issue.CreatedAt, inMilestone: issue.MilestoneID == milestoneID}
+	membershipKnown := false
+	open := true
+	for _, event := range events {
+		change := burndownChange{at: event.CreatedAt}
+		switch event.Type {
+		case IssueEventClosed, IssueEventReopened:
+			isOpen := event.Type == IssueEventReopened
+			change.open = &isOpen
+			open = isOpen
+		case IssueEventMilestoned, IssueEventDemilestoned:
+			var entered bool
+			switch {
+			case event.Type == IssueEventMilestoned && event.NewValue == milestone:
+				entered = true
+			case event.OldValue == milestone:
+				entered = false
+			default:
+				continue
+			}
+			// 最初の変更より前の所属は変更前の状態
+			if !membershipKnown {
+				b.inMilestone = !entered
+				membershipKnown = true
+			}
+			change.inMilestone = &entered
+		case IssueEventConvertedFromDraft:
+			b.startAt = event.CreatedAt
+			continue
+		default:
+			continue
+		}
+		b.changes = append(b.changes, change)
+	}
+
+	// 変更履歴のないクローズ（変更履歴の記録前のデータ）は最終更新日時にクローズされたものとする
+	if closed := issue.Status == "closed"; closed == open {
+		isOpen := !closed
+		b.changes = append(b.changes, burndownChange{at: issue.UpdatedAt, open: &isOpen})
+		sort.SliceStable(b.changes, func(i, j int) bool {
+			return b.changes[i].at.Before(b.changes[j].at)
+		})
+	}
+	return b
+}
+
+// truncateDay は日時をUTCの日の始まりに切り捨てる
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,103 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMilestone_SetProgress(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	date := func(value string) time.Time {
+		d, _ := time.Parse("2006-01-02", value)
+		return d
+	}
+
+	tests := []struct {
+		name        string
+		dueDate     time.Time
+		status      string
+		open        int
+		closed      int
+		wantPercent int
+		wantOverdue bool
+	}{
+		{name: "Issueなし", status: "open", wantPercent: 0},
+		{name: "一部クローズ", status: "open", open: 2, closed: 1, wantPercent: 33},
+		{name: "すべてクローズ", status: "open", closed: 4, wantPercent: 100},
+		{name: "期日の当日は期日超過ではない", dueDate: date("2026-03-10"), status: "open", open: 1, wantOverdue: false},
+		{name: "期日の翌日は期日超過", dueDate: date("2026-03-09"), status: "open", open: 1, wantOverdue: true},
+		{name: "クローズ済みは期日超過ではない", dueDate: date("2026-03-01"), status: "closed", closed: 1, wantPercent: 100, wantOverdue: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			milestone := models.NewMilestone("v1.0", "", tt.dueDate, 1)
+			milestone.Status = tt.status
+			milestone.SetProgress(tt.open, tt.closed, now)
+			assert.Equal(t, tt.open, milestone.OpenIssues)
+			assert.Equal(t, tt.closed, milestone.ClosedIssues)
+			assert.Equal(t, tt.wantPercent, milestone.PercentComplete)
+			assert.Equal(t, tt.wantOverdue, milestone.IsOverdue)
+		})
+	}
+}
+
+func TestBuildMilestoneBurndown(t *testing.T) {
+	at := func(value string) time.Time {
+		d, _ := time.Parse("2006-01-02 15:04", value)
+		return d
+	}
+	milestone := &models.Milestone{ID: 7, Status: "open", CreatedAt: at("2026-03-01 09:00"), DueDate: at("2026-03-05 00:00")}
+	now := at("2026-03-03 12:00")
+
+	issue := func(id int64, milestoneID int64, status string, createdAt, updatedAt time.Time) *models.Issue {
+		return &models.Issue{ID: id, MilestoneID: milestoneID, Status: status, CreatedAt: createdAt, UpdatedAt: updatedAt}
+	}
+	event := func(issueID int64, eventType models.IssueEventType, oldValue, newValue string, createdAt time.Time) *models.IssueEvent {
+		return &models.IssueEvent{IssueID: issueID, Type: eventType, OldValue: oldValue, NewValue: newValue, CreatedAt: createdAt}
+	}
+
+	issues := []*models.Issue{
+		// 2日目にクローズ
+		issue(1, 7, "closed", at("2026-03-01 10:00"), at("2026-03-02 10:00")),
+		// 2日目にクローズ、3日目に再オープン
+		issue(2, 7, "open", at("2026-03-01 10:00"), at("2026-03-03 10:00")),
+		// 2日目にマイルストーンに追加
+		issue(3, 7, "open", at("2026-03-01 10:00"), at("2026-03-02 10:00")),
+		// 3日目にマイルストーンから削除
+		issue(4, 0, "open", at("2026-03-01 10:00"), at("2026-03-03 10:00")),
+		// 変更履歴のないクローズ（最終更新日時にクローズ）
+		issue(5, 7, "closed", at("2026-03-01 10:00"), at("2026-03-03 10:00")),
+		// 下書きは含めない
+		{ID: 6, MilestoneID: 7, Status: "open", IsDraft: true, CreatedAt: at("2026-03-01 10:00")},
+	}
+	events := []*models.IssueEvent{
+		event(1, models.IssueEventClosed, "open", "closed", at("2026-03-02 10:00")),
+		event(2, models.IssueEventClosed, "open", "closed", at("2026-03-02 10:00")),
+		event(2, models.IssueEventReopened, "closed", "open", at("2026-03-03 10:00")),
+		event(3, models.IssueEventMilestoned, "", "7", at("2026-03-02 10:00")),
+		event(4, models.IssueEventDemilestoned, "7", "", at("2026-03-03 10:00")),
+	}
+
+	burndown := models.BuildMilestoneBurndown(milestone, issues, events, now)
+	assert.Equal(t, "2026-03-01", burndown.StartDate)
+	assert.Equal(t, "2026-03-05", burndown.EndDate)
+
+	wantOpen := []int{4, 3, 2}
+	wantTotal := []int{4, 5, 4}
+	assert.Len(t, burndown.Points, 5)
+	for i, point := range burndown.Points {
+		if i < len(wantOpen) {
+			assert.Equal(t, wantOpen[i], *point.OpenIssues, point.Date)
+			assert.Equal(t, wantTotal[i], *point.TotalIssues, point.Date)
+		} else {
+			// 未来の日は値なし
+			assert.Nil(t, point.OpenIssues, point.Date)
+		}
+	}
+	assert.Equal(t, 4.0, burndown.Points[0].Ideal)
+	assert.Equal(t, 0.0, burndown.Points[4].Ideal)
+}
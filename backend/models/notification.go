@@ -4,6 +4,14 @@ import (
 	"time"
 )
 
+// 通知タイプ（UserSettings.NotificationTypesで指定する値と対応）
+const (
+	NotificationTypeMention = "mention"
+	NotificationTypeAssign  = "assign"
+	NotificationTypeComment = "comment"
+	NotificationTypeUpdate  = "update"
+)
+
 // Notification は通知情報を表す構造体
 type Notification struct {
 	ID         int64     `json:"id"`
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// usernameMinLength・usernameMaxLength はユーザー登録と同じユーザー名の長さの制限
+	usernameMinLength = 3
+	usernameMaxLength = 50
+)
+
+// UserIdentity は外部のIDプロバイダーのアカウントとユーザーの紐付けを表す構造体
+type UserIdentity struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `gorm:"not null;index" json:"user_id"`
+	Provider    string    `gorm:"not null;uniqueIndex:idx_user_identity_subject" json:"provider"`
+	Subject     string    `gorm:"not null;uniqueIndex:idx_user_identity_subject" json:"subject"` // IDトークンの sub クレーム
+	Email       string    `json:"email"`                                                         // 最後のログイン時のメールアドレス
+	CreatedAt   time.Time `json:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
+
+// NewUserIdentity は新しいUserIdentityインスタンスを作成する
+func NewUserIdentity(userID int64, provider, subject, email string) *UserIdentity {
+	now := time.Now()
+	return &UserIdentity{
+		UserID:      userID,
+		Provider:    provider,
+		Subject:     subject,
+		Email:       email,
+		CreatedAt:   now,
+		LastLoginAt: now,
+	}
+}
+
+// RecordLogin はIDプロバイダーでのログイン日時とメールアドレスを記録する
+func (i *UserIdentity) RecordLogin(email string) {
+	i.Email = email
+	i.LastLoginAt = time.Now()
+}
+
+// OIDCLoginState は認可リクエストからコールバックまでの間保持するログインの状態を表す構造体
+// state パラメータで検索し、PKCEのcode_verifierとIDトークンのnonceの検証に使用します
+type OIDCLoginState struct {
+	State        string    `gorm:"primaryKey" json:"state"`
+	Provider     string    `gorm:"not null" json:"provider"`
+	CodeVerifier string    `gorm:"not null" json:"-"`
+	Nonce        string    `gorm:"not null" json:"-"`
+	ExpiresAt    time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewOIDCLoginState は新しいOIDCLoginStateインスタンスを作成する
+func NewOIDCLoginState(state, provider, codeVerifier, nonce string, expiresIn time.Duration) *OIDCLoginState {
+	now := time.Now()
+	return &OIDCLoginState{
+		State:        state,
+		Provider:     provider,
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+		ExpiresAt:    now.Add(expiresIn),
+		CreatedAt:    now,
+	}
+}
+
+// IsExpired はログインの状態が期限切れかどうかを判定する
+func (s *OIDCLoginState) IsExpired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
+
+// CodeChallenge はPKCEのcode_verifierからS256方式のcode_challengeを計算する
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// SuggestUsername はIDトークンのpreferred_usernameまたはメールアドレスからユーザー名の候補を作成する
+// 英数字・ハイフン・アンダースコア以外の文字は取り除き（ドット・空白はアンダースコアに置き換え）、ユーザー登録と同じ長さに収めます
+func SuggestUsername(preferredUsername, email string) string {
+	candidate := preferredUsername
+	if at := strings.Index(candidate, "@"); at >= 0 {
+		candidate = candidate[:at]
+	}
+	if candidate == "" {
+		candidate = email
+		if at := strings.Index(candidate, "@"); at >= 0 {
+			candidate = candidate[:at]
+		}
+	}
+
+	var b strings.Builder
+	for _, r := range candidate {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		case r == '.' || r == ' ':
+			b.WriteRune('_')
+		}
+	}
+
+	username := b.String()
+	if username == "" {
+		username = "user"
+	}
+	if len(username) > usernameMaxLength {
+		username = username[:usernameMaxLength]
+	}
+	for len(username) < usernameMinLength {
+		username += "_"
+	}
+	return username
+}
+
+// HasAnyGroup はグループの一覧に指定したグループのいずれかが含まれるかどうかを判定する
+func HasAnyGroup(groups, targets []string) bool {
+	for _, target := range targets {
+		if containsString(groups, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// AutoMigrateOIDC はIDプロバイダーとの紐付けとログインの状態のテーブルを作成・更新します
+func AutoMigrateOIDC(db *gorm.DB) error {
+	return db.AutoMigrate(&UserIdentity{}, &OIDCLoginState{})
+}
@@ -0,0 +1,53 @@
+package models_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodeChallenge(t *testing.T) {
+	// RFC 7636 Appendix B の例
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", models.CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
+
+func TestSuggestUsername(t *testing.T) {
+	tests := []struct {
+		name              string
+		preferredUsername string
+		email             string
+		want              string
+	}{
+		{name: "preferred_usernameを使用", preferredUsername: "alice", email: "a@example.com", want: "alice"},
+		{name: "preferred_usernameのドメインを除去", preferredUsername: "alice@corp.example.com", email: "", want: "alice"},
+		{name: "メールアドレスから作成", preferredUsername: "", email: "bob.smith@example.com", want: "bob_smith"},
+		{name: "使用できない文字を除去", preferredUsername: "山田 taro+dev", email: "", want: "_tarodev"},
+		{name: "短いユーザー名を補完", preferredUsername: "jo", email: "", want: "jo_"},
+		{name: "候補がない場合", preferredUsername: "山田", email: "", want: "user"},
+		{name: "長いユーザー名を切り詰め", preferredUsername: strings.Repeat("a", 60), email: "", want: strings.Repeat("a", 50)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.SuggestUsername(tt.preferredUsername, tt.email))
+		})
+	}
+}
+
+func TestHasAnyGroup(t *testing.T) {
+	assert.True(t, models.HasAnyGroup([]string{"dev", "tickethub-admins"}, []string{"tickethub-admins"}))
+	assert.False(t, models.HasAnyGroup([]string{"dev"}, []string{"tickethub-admins"}))
+	assert.False(t, models.HasAnyGroup(nil, []string{"tickethub-admins"}))
+	assert.False(t, models.HasAnyGroup([]string{"dev"}, nil))
+}
+
+func TestOIDCLoginState_IsExpired(t *testing.T) {
+	state := models.NewOIDCLoginState("state", "company", "verifier", "nonce", 10*time.Minute)
+	assert.False(t, state.IsExpired())
+
+	state.ExpiresAt = time.Now().Add(-time.Second)
+	assert.True(t, state.IsExpired())
+}
@@ -0,0 +1,166 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// パーソナルアクセストークンのスコープ
+// :write は同じ種類の :read を含み、admin はすべてのスコープを含みます
+const (
+	ScopeIssuesRead       = "issues:read"       // Issue・タスク・Issue間の関連の閲覧
+	ScopeIssuesWrite      = "issues:write"      // Issue・タスク・Issue間の関連・担当者・Issueのドラフトの変更
+	ScopeDiscussionsRead  = "discussions:read"  // Discussionの閲覧
+	ScopeDiscussionsWrite = "discussions:write" // Discussion・Discussionのドラフトの変更
+	ScopeCommentsRead     = "comments:read"     // コメント・編集履歴の閲覧
+	ScopeCommentsWrite    = "comments:write"    // コメント・リアクションの変更
+	ScopeReposRead        = "repos:read"        // リポジトリ・メンバー・ラベル・マイルストーン・テンプレートの閲覧と横断検索
+	ScopeReposWrite       = "repos:write"       // リポジトリ・メンバー・ラベル・マイルストーン・テンプレートの変更
+	ScopeUserRead         = "user:read"         // 通知・メンション・ドラフト一覧・保存済み検索の閲覧
+	ScopeUserWrite        = "user:write"        // 通知・保存済み検索の変更
+	ScopeAdmin            = "admin"             // 管理者用のエンドポイントを含むすべての操作
+)
+
+const (
+	// PersonalAccessTokenPrefix はパーソナルアクセストークンの先頭に付ける文字列（JWTとの判別に使用）
+	PersonalAccessTokenPrefix = "thp_"
+	// PersonalAccessTokenMaxLifetime はパーソナルアクセストークンの最長の有効期間
+	PersonalAccessTokenMaxLifetime = 365 * 24 * time.Hour
+	// personalAccessTokenDisplayLength は一覧に表示するトークンの先頭部分の長さ
+	personalAccessTokenDisplayLength = 8
+)
+
+// PersonalAccessTokenScopes は指定できるスコープの一覧
+var PersonalAccessTokenScopes = []string{
+	ScopeIssuesRead, ScopeIssuesWrite,
+	ScopeDiscussionsRead, ScopeDiscussionsWrite,
+	ScopeCommentsRead, ScopeCommentsWrite,
+	ScopeReposRead, ScopeReposWrite,
+	ScopeUserRead, ScopeUserWrite,
+	ScopeAdmin,
+}
+
+// PersonalAccessToken はCIなどから利用するパーソナルアクセストークンを表す構造体
+// トークン文字列はハッシュ値のみを保存し、作成時のレスポンスでのみ平文を返します
+type PersonalAccessToken struct {
+	ID          int64      `json:"id"`
+	UserID      int64      `gorm:"not null;index" json:"user_id"`
+	Name        string     `gorm:"not null" json:"name"`
+	TokenHash   string     `gorm:"not null;uniqueIndex" json:"-"`
+	TokenPrefix string     `gorm:"not null" json:"token_prefix"` // 識別用のトークンの先頭部分
+	Scopes      []string   `gorm:"type:text;serializer:json" json:"scopes"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	LastUsedIP  string     `json:"last_used_ip"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// NewPersonalAccessToken は平文のトークンから新しいPersonalAccessTokenインスタンスを作成する
+func NewPersonalAccessToken(userID int64, name, token string, scopes []string, expiresAt time.Time) *PersonalAccessToken {
+	prefix := token
+	if len(prefix) > personalAccessTokenDisplayLength {
+		prefix = prefix[:personalAccessTokenDisplayLength]
+	}
+	return &PersonalAccessToken{
+		UserID:      userID,
+		Name:        strings.TrimSpace(name),
+		TokenHash:   HashPersonalAccessToken(token),
+		TokenPrefix: prefix,
+		Scopes:      normalizeScopes(scopes),
+		ExpiresAt:   expiresAt,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// HashPersonalAccessToken はトークン文字列の保存・検索用のハッシュ値を返す
+func HashPersonalAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsPersonalAccessToken はトークン文字列がパーソナルアクセストークンの形式かどうかを判定する
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
+
+// Validate はトークンの名前・スコープ・有効期限を検証する
+func (t *PersonalAccessToken) Validate() error {
+	if t.Name == "" {
+		return errors.New("token name is required")
+	}
+	if len(t.Scopes) == 0 {
+		return errors.New("at least one scope is required")
+	}
+	for _, scope := range t.Scopes {
+		if !IsValidScope(scope) {
+			return fmt.Errorf("invalid scope %q. Must be one of %s", scope, strings.Join(PersonalAccessTokenScopes, ", "))
+		}
+	}
+	if !t.ExpiresAt.After(t.CreatedAt) {
+		return errors.New("expiration must be in the future")
+	}
+	if t.ExpiresAt.Sub(t.CreatedAt) > PersonalAccessTokenMaxLifetime {
+		return errors.New("expiration must be within 365 days")
+	}
+	return nil
+}
+
+// IsExpired はトークンが期限切れかどうかを判定する
+func (t *PersonalAccessToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// RecordUse は最終利用日時と接続元のIPアドレスを記録する
+func (t *PersonalAccessToken) RecordUse(ip string) {
+	now := time.Now()
+	t.LastUsedAt = &now
+	t.LastUsedIP = ip
+}
+
+// NeedsUsageUpdate は最終利用の記録を更新する必要があるかどうかを判定する
+// リクエストごとの書き込みを避けるため、同じIPアドレスからの利用は interval ごとに記録します
+func (t *PersonalAccessToken) NeedsUsageUpdate(ip string, interval time.Duration) bool {
+	return t.LastUsedAt == nil || t.LastUsedIP != ip || time.Since(*t.LastUsedAt) >= interval
+}
+
+// IsValidScope はスコープが指定できるものかどうかを判定する
+func IsValidScope(scope string) bool {
+	return containsString(PersonalAccessTokenScopes, scope)
+}
+
+// ScopesAllow は付与されたスコープで指定したスコープの操作が許可されるかどうかを判定する
+func ScopesAllow(granted []string, required string) bool {
+	for _, scope := range granted {
+		if scope == required || scope == ScopeAdmin {
+			return true
+		}
+		// :write は同じ種類の :read を含む
+		if resource, ok := strings.CutSuffix(scope, ":write"); ok && required == resource+":read" {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeScopes はスコープの前後の空白と重複を取り除く
+func normalizeScopes(scopes []string) []string {
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope != "" && !containsString(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized
+}
+
+// AutoMigratePersonalAccessToken はPersonalAccessTokenテーブルを作成・更新します
+func AutoMigratePersonalAccessToken(db *gorm.DB) error {
+	return db.AutoMigrate(&PersonalAccessToken{})
+}
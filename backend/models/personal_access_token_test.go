@@ -0,0 +1,117 @@
+package models_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPersonalAccessToken(t *testing.T) {
+	expiresAt := time.Now().Add(24 * time.Hour)
+	token := models.NewPersonalAccessToken(3, "  ci  ", "thp_abcdefghijklmnop", []string{"issues:read", " issues:read ", "", "admin"}, expiresAt)
+
+	assert.Equal(t, int64(3), token.UserID)
+	assert.Equal(t, "ci", token.Name)
+	assert.Equal(t, "thp_abcd", token.TokenPrefix)
+	assert.Equal(t, models.HashPersonalAccessToken("thp_abcdefghijklmnop"), token.TokenHash)
+	assert.NotContains(t, token.TokenHash, "abcdefghijklmnop")
+	assert.Equal(t, []string{"issues:read", "admin"}, token.Scopes)
+	assert.Equal(t, expiresAt, token.ExpiresAt)
+	assert.Nil(t, token.LastUsedAt)
+}
+
+func TestHashPersonalAccessToken(t *testing.T) {
+	hash := models.HashPersonalAccessToken("thp_secret")
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, models.HashPersonalAccessToken("thp_secret"))
+	assert.NotEqual(t, hash, models.HashPersonalAccessToken("thp_other"))
+}
+
+func TestIsPersonalAccessToken(t *testing.T) {
+	assert.True(t, models.IsPersonalAccessToken("thp_abc"))
+	assert.False(t, models.IsPersonalAccessToken("eyJhbGciOiJIUzI1NiJ9.e30.sig"))
+	assert.False(t, models.IsPersonalAccessToken(""))
+}
+
+func TestPersonalAccessToken_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokenName string
+		scopes    []string
+		expiresIn time.Duration
+		wantErr   string
+	}{
+		{name: "有効", tokenName: "ci", scopes: []string{"issues:read", "issues:write"}, expiresIn: 30 * 24 * time.Hour},
+		{name: "最長の有効期限", tokenName: "ci", scopes: []string{"admin"}, expiresIn: models.PersonalAccessTokenMaxLifetime},
+		{name: "名前なし", tokenName: " ", scopes: []string{"issues:read"}, expiresIn: time.Hour, wantErr: "name is required"},
+		{name: "スコープなし", tokenName: "ci", scopes: nil, expiresIn: time.Hour, wantErr: "at least one scope"},
+		{name: "不明なスコープ", tokenName: "ci", scopes: []string{"issues:delete"}, expiresIn: time.Hour, wantErr: `invalid scope "issues:delete"`},
+		{name: "過去の有効期限", tokenName: "ci", scopes: []string{"issues:read"}, expiresIn: -time.Hour, wantErr: "in the future"},
+		{name: "長すぎる有効期限", tokenName: "ci", scopes: []string{"issues:read"}, expiresIn: models.PersonalAccessTokenMaxLifetime + time.Hour, wantErr: "within 365 days"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := models.NewPersonalAccessToken(1, tt.tokenName, "thp_token", tt.scopes, time.Time{})
+			token.ExpiresAt = token.CreatedAt.Add(tt.expiresIn)
+
+			err := token.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.Error(t, err) {
+				assert.True(t, strings.Contains(err.Error(), tt.wantErr), err.Error())
+			}
+		})
+	}
+}
+
+func TestScopesAllow(t *testing.T) {
+	tests := []struct {
+		name     string
+		granted  []string
+		required string
+		want     bool
+	}{
+		{name: "同じスコープ", granted: []string{"issues:read"}, required: "issues:read", want: true},
+		{name: "writeはreadを含む", granted: []string{"issues:write"}, required: "issues:read", want: true},
+		{name: "readはwriteを含まない", granted: []string{"issues:read"}, required: "issues:write", want: false},
+		{name: "別の種類のwriteは含まない", granted: []string{"discussions:write"}, required: "issues:read", want: false},
+		{name: "adminはすべてを含む", granted: []string{"admin"}, required: "repos:write", want: true},
+		{name: "adminの要求", granted: []string{"issues:write", "repos:write"}, required: "admin", want: false},
+		{name: "スコープなし", granted: nil, required: "issues:read", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.ScopesAllow(tt.granted, tt.required))
+		})
+	}
+}
+
+func TestPersonalAccessToken_NeedsUsageUpdate(t *testing.T) {
+	token := models.NewPersonalAccessToken(1, "ci", "thp_token", []string{"issues:read"}, time.Now().Add(time.Hour))
+	assert.True(t, token.NeedsUsageUpdate("10.0.0.1", time.Minute), "未使用")
+
+	token.RecordUse("10.0.0.1")
+	assert.NotNil(t, token.LastUsedAt)
+	assert.Equal(t, "10.0.0.1", token.LastUsedIP)
+	assert.False(t, token.NeedsUsageUpdate("10.0.0.1", time.Minute), "同じIPアドレスからの直後の利用")
+	assert.True(t, token.NeedsUsageUpdate("10.0.0.2", time.Minute), "別のIPアドレスからの利用")
+
+	past := time.Now().Add(-2 * time.Minute)
+	token.LastUsedAt = &past
+	assert.True(t, token.NeedsUsageUpdate("10.0.0.1", time.Minute), "間隔が経過した利用")
+}
+
+func TestPersonalAccessToken_IsExpired(t *testing.T) {
+	token := models.NewPersonalAccessToken(1, "ci", "thp_token", []string{"issues:read"}, time.Now().Add(time.Hour))
+	assert.False(t, token.IsExpired())
+
+	token.ExpiresAt = time.Now().Add(-time.Second)
+	assert.True(t, token.IsExpired())
+}
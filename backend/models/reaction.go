@@ -1,29 +1,83 @@
 package models
 
 import (
+	"sort"
 	"time"
+
+	"gorm.io/gorm"
+)
+
+// リアクションの対象の種類
+const (
+	ReactionTargetIssue      = "issue"
+	ReactionTargetDiscussion = "discussion"
+	ReactionTargetComment    = "comment"
 )
 
+// ReactionEmojis はリアクションに使用できる絵文字（表示順）
+var ReactionEmojis = []string{"+1", "-1", "laugh", "hooray", "confused", "heart", "rocket", "eyes"}
+
 // Reaction はリアクション情報を表す構造体
 type Reaction struct {
-	ID        int64     `json:"id"`
-	CommentID int64     `json:"comment_id"`
-	UserID    int64     `json:"user_id"`
-	Emoji     string    `json:"emoji"`
-	CreatedAt time.Time `json:"created_at"`
+	ID         int64     `json:"id"`
+	TargetType string    `gorm:"uniqueIndex:idx_reaction_target_user_emoji;index:idx_reaction_target" json:"target_type"` // issue/discussion/comment
+	TargetID   int64     `gorm:"uniqueIndex:idx_reaction_target_user_emoji;index:idx_reaction_target" json:"target_id"`
+	UserID     int64     `gorm:"uniqueIndex:idx_reaction_target_user_emoji" json:"user_id"`
+	Emoji      string    `gorm:"uniqueIndex:idx_reaction_target_user_emoji" json:"emoji"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ReactionSummary は対象ごと・絵文字ごとのリアクションの集計を表す構造体
+type ReactionSummary struct {
+	Emoji       string `json:"emoji"`
+	Count       int    `json:"count"`
+	ReactedByMe bool   `json:"reacted_by_me"`
 }
 
 // NewReaction は新しいReactionインスタンスを作成する
-func NewReaction(commentID, userID int64, emoji string) *Reaction {
+func NewReaction(targetType string, targetID, userID int64, emoji string) *Reaction {
 	return &Reaction{
-		CommentID: commentID,
-		UserID:    userID,
-		Emoji:     emoji,
-		CreatedAt: time.Now(),
+		TargetType: targetType,
+		TargetID:   targetID,
+		UserID:     userID,
+		Emoji:      emoji,
+		CreatedAt:  time.Now(),
 	}
 }
 
 // IsValid はリアクション情報の検証を行う
 func (r *Reaction) IsValid() bool {
-	return r.CommentID > 0 && r.UserID > 0 && r.Emoji != ""
+	return IsValidReactionTargetType(r.TargetType) && r.TargetID > 0 && r.UserID > 0 && IsAllowedReactionEmoji(r.Emoji)
+}
+
+// IsValidReactionTargetType はリアクションの対象の種類が有効かどうかを検証する
+func IsValidReactionTargetType(targetType string) bool {
+	return targetType == ReactionTargetIssue || targetType == ReactionTargetDiscussion || targetType == ReactionTargetComment
+}
+
+// IsAllowedReactionEmoji はリアクションに使用できる絵文字かどうかを判定する
+func IsAllowedReactionEmoji(emoji string) bool {
+	return reactionEmojiOrder(emoji) >= 0
+}
+
+// SortReactionSummaries はリアクションの集計を絵文字の表示順に並べ替える
+func SortReactionSummaries(summaries []ReactionSummary) {
+	sort.Slice(summaries, func(i, j int) bool {
+		return reactionEmojiOrder(summaries[i].Emoji) < reactionEmojiOrder(summaries[j].Emoji)
+	})
+}
+
+// reactionEmojiOrder は絵文字の表示順を返す（使用できない絵文字の場合は-1）
+func reactionEmojiOrder(emoji string) int {
+	for i, allowed := range ReactionEmojis {
+		if allowed == emoji {
+			return i
+		}
+	}
+	return -1
+}
+
+// AutoMigrateReaction はReactionテーブルを作成・更新します
+func AutoMigrateReaction(db *gorm.DB) error {
+	return db.AutoMigrate(&Reaction{})
 }
@@ -18,6 +18,9 @@ const (
 	InternalRepo RepositoryType = "internal"
 )
 
+// DefaultRepositoryName はリポジトリを指定せずに作成されたIssue・Discussion・ラベル・マイルストーンが属するリポジトリの名前
+const DefaultRepositoryName = "default"
+
 // Repository はリポジトリ情報を表す構造体
 type Repository struct {
 	ID          int64          `json:"id"`
@@ -27,6 +30,7 @@ type Repository struct {
 	OwnerID     int64          `json:"owner_id"`
 	OwnerName   string         `json:"owner_name,omitempty"` // JOINなどで取得する場合用
 	IsArchived  bool           `json:"is_archived"`
+	LastNumber  int64          `gorm:"not null;default:0" json:"-"` // 最後に採番したIssue・Discussionの番号（リポジトリ内で共通）
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 }
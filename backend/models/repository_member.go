@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RepositoryRole はリポジトリに対するロールを表す型
+type RepositoryRole string
+
+// リポジトリのロール（下にあるものほど強い権限を持ち、上位のロールは下位のロールの権限を含む）
+const (
+	// RepositoryRoleRead は閲覧とIssue・Discussion・コメントの作成ができる
+	RepositoryRoleRead RepositoryRole = "read"
+	// RepositoryRoleTriage はIssue・Discussionのステータス変更や担当者の割り当てができる
+	RepositoryRoleTriage RepositoryRole = "triage"
+	// RepositoryRoleWrite はラベル・マイルストーンの管理ができる
+	RepositoryRoleWrite RepositoryRole = "write"
+	// RepositoryRoleMaintain は他人のIssue・Discussion・コメントの編集・削除ができる
+	RepositoryRoleMaintain RepositoryRole = "maintain"
+	// RepositoryRoleAdmin はメンバーの管理ができる
+	RepositoryRoleAdmin RepositoryRole = "admin"
+)
+
+// repositoryRoleLevels はロールの強さ
+var repositoryRoleLevels = map[RepositoryRole]int{
+	RepositoryRoleRead:     1,
+	RepositoryRoleTriage:   2,
+	RepositoryRoleWrite:    3,
+	RepositoryRoleMaintain: 4,
+	RepositoryRoleAdmin:    5,
+}
+
+// IsValid はロールが有効かどうかを判定する
+func (r RepositoryRole) IsValid() bool {
+	_, ok := repositoryRoleLevels[r]
+	return ok
+}
+
+// Includes はロールが required の権限を含むかどうかを判定する
+func (r RepositoryRole) Includes(required RepositoryRole) bool {
+	return r.IsValid() && repositoryRoleLevels[r] >= repositoryRoleLevels[required]
+}
+
+// RepositoryMember はリポジトリのメンバー情報を表す構造体
+type RepositoryMember struct {
+	ID           int64          `json:"id"`
+	RepositoryID int64          `gorm:"not null;uniqueIndex:idx_repository_member" json:"repository_id"`
+	UserID       int64          `gorm:"not null;uniqueIndex:idx_repository_member;index" json:"user_id"`
+	Role         RepositoryRole `gorm:"not null" json:"role"`
+	Username     string         `gorm:"->;-:migration" json:"username,omitempty"` // 一覧取得時にusersテーブルから取得
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+// NewRepositoryMember は新しいRepositoryMemberインスタンスを作成する
+func NewRepositoryMember(repositoryID, userID int64, role RepositoryRole) *RepositoryMember {
+	now := time.Now()
+	return &RepositoryMember{
+		RepositoryID: repositoryID,
+		UserID:       userID,
+		Role:         role,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// IsValid はメンバー情報の検証を行う
+func (m *RepositoryMember) IsValid() bool {
+	return m.RepositoryID > 0 && m.UserID > 0 && m.Role.IsValid()
+}
+
+// AutoMigrateRepositoryMember はRepositoryMemberテーブルを作成・更新します
+func AutoMigrateRepositoryMember(db *gorm.DB) error {
+	return db.AutoMigrate(&RepositoryMember{})
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SavedSearch は保存された検索条件（個人用のビュー）を表す構造体
+type SavedSearch struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"` // 作成者
+	Name      string    `json:"name"`
+	Query     string    `json:"query"` // SearchService.ParseQuery が解釈する検索クエリ文字列
+	Sort      string    `json:"sort"`  // 並び順（例: updated-desc）。空の場合はクエリ内の sort: または関連度順
+	IsShared  bool      `json:"is_shared"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SavedSearchView はユーザーごとの保存済み検索の最終閲覧日時を表す構造体
+type SavedSearchView struct {
+	SavedSearchID int64     `gorm:"primaryKey" json:"saved_search_id"`
+	UserID        int64     `gorm:"primaryKey" json:"user_id"`
+	LastViewedAt  time.Time `json:"last_viewed_at"`
+}
+
+// NewSavedSearch は新しいSavedSearchインスタンスを作成する
+func NewSavedSearch(userID int64, name, query, sort string, isShared bool) *SavedSearch {
+	now := time.Now()
+	return &SavedSearch{
+		UserID:    userID,
+		Name:      name,
+		Query:     query,
+		Sort:      sort,
+		IsShared:  isShared,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Update は保存済み検索の内容を更新する
+func (s *SavedSearch) Update(name, query, sort string, isShared bool) {
+	s.Name = name
+	s.Query = query
+	s.Sort = sort
+	s.IsShared = isShared
+	s.UpdatedAt = time.Now()
+}
+
+// IsValid は保存済み検索が有効かどうかを検証する
+func (s *SavedSearch) IsValid() bool {
+	return s.UserID > 0 && s.Name != ""
+}
+
+// IsVisibleTo は指定したユーザーが閲覧できるかどうかを判定する
+func (s *SavedSearch) IsVisibleTo(userID int64) bool {
+	return s.IsShared || s.UserID == userID
+}
+
+// AutoMigrateSavedSearch はSavedSearch関連のテーブルを作成・更新します
+func AutoMigrateSavedSearch(db *gorm.DB) error {
+	return db.AutoMigrate(&SavedSearch{}, &SavedSearchView{})
+}
@@ -10,37 +10,74 @@ type SearchResultType string
 const (
 	// SearchResultTypeIssue は課題検索結果を表す
 	SearchResultTypeIssue SearchResultType = "issue"
-	// SearchResultTypeComment はコメント検索結果を表す
+	// SearchResultTypeComment はコメント検索結果を表す（Issueへのコメント）
 	SearchResultTypeComment SearchResultType = "comment"
+	// SearchResultTypeDiscussion はディスカッション検索結果を表す
+	SearchResultTypeDiscussion SearchResultType = "discussion"
+	// SearchResultTypeDiscussionComment はディスカッションへのコメント検索結果を表す
+	SearchResultTypeDiscussionComment SearchResultType = "discussion_comment"
+	// SearchResultTypeMilestone はマイルストーン検索結果を表す
+	SearchResultTypeMilestone SearchResultType = "milestone"
+	// SearchResultTypeLabel はラベル検索結果を表す
+	SearchResultTypeLabel SearchResultType = "label"
 )
 
+// AllSearchResultTypes は検索対象となるすべての結果種類
+var AllSearchResultTypes = []SearchResultType{
+	SearchResultTypeIssue,
+	SearchResultTypeComment,
+	SearchResultTypeDiscussion,
+	SearchResultTypeDiscussionComment,
+	SearchResultTypeMilestone,
+	SearchResultTypeLabel,
+}
+
+// IsValid は検索対象として定義された結果種類かどうかを判定する
+func (t SearchResultType) IsValid() bool {
+	for _, valid := range AllSearchResultTypes {
+		if t == valid {
+			return true
+		}
+	}
+	return false
+}
+
 // SearchQuery は検索クエリを表す構造体
 type SearchQuery struct {
-	Query      string   `json:"query"`       // 検索キーワード
-	Labels     []string `json:"labels"`      // ラベルによるフィルタ
-	Status     string   `json:"status"`      // ステータスによるフィルタ (open/closed/all)
-	AssigneeID int64    `json:"assignee_id"` // 担当者IDによるフィルタ
-	CreatorID  int64    `json:"creator_id"`  // 作成者IDによるフィルタ
-	Limit      int      `json:"limit"`       // 結果の上限数
-	Offset     int      `json:"offset"`      // 結果のオフセット
+	Query         string             `json:"query"`       // 検索キーワード
+	Labels        []string           `json:"labels"`      // ラベルによるフィルタ
+	Status        string             `json:"status"`      // ステータスによるフィルタ (open/closed/all)
+	AssigneeID    int64              `json:"assignee_id"` // 担当者IDによるフィルタ
+	CreatorID     int64              `json:"creator_id"`  // 作成者IDによるフィルタ
+	Category      string             `json:"category"`    // カテゴリによるフィルタ (Discussionの場合)
+	Repository    string             `json:"repository"`  // リポジトリ名によるフィルタ
+	UpdatedSince  time.Time          `json:"-"`           // 指定日時以降に更新されたものに限定する（ゼロ値は無制限）
+	Types         []SearchResultType `json:"types"`       // 結果種類によるフィルタ（空の場合はすべて）
+	Sort          SearchSort         `json:"sort"`        // 並び順（空の場合は関連度順）
+	Expr          *SearchNode        `json:"-"`           // Queryを解析した検索式（nilの場合はSearch時に解析する）
+	ViewerID      int64              `json:"-"`           // 検索を実行するユーザーID（@meの解決に使用）
+	RepositoryIDs []int64            `json:"-"`           // 検索対象とするリポジトリID（閲覧できるリポジトリの制限。nilの場合は制限なし）
+	Limit         int                `json:"limit"`       // 結果の上限数
+	Offset        int                `json:"offset"`      // 結果のオフセット
 }
 
 // SearchResult は検索結果を表す構造体
 type SearchResult struct {
-	Type        SearchResultType `json:"type"`        // 結果の種類 (issue/comment)
-	ID          int64            `json:"id"`          // コンテンツのID
-	Title       string           `json:"title"`       // タイトル (Issueの場合)
-	Body        string           `json:"body"`        // 本文
-	Snippet     string           `json:"snippet"`     // 検索キーワードを含むスニペット
-	Labels      []string         `json:"labels"`      // ラベル (Issueの場合)
-	Status      string           `json:"status"`      // ステータス (Issueの場合)
-	AssigneeID  int64            `json:"assignee_id"` // 担当者ID (Issueの場合)
-	CreatorID   int64            `json:"creator_id"`  // 作成者ID
-	CreatedAt   time.Time        `json:"created_at"`  // 作成日時
-	UpdatedAt   time.Time        `json:"updated_at"`  // 更新日時
-	TargetID    int64            `json:"target_id"`   // 関連対象ID (Commentの場合)
-	Rank        float64          `json:"rank"`        // 検索ランキングスコア
-	Highlighted string           `json:"highlighted"` // ハイライト付きテキスト
+	Type        SearchResultType `json:"type"`               // 結果の種類 (issue/comment/discussion/...)
+	ID          int64            `json:"id"`                 // コンテンツのID
+	Title       string           `json:"title"`              // タイトル (Issueの場合)
+	Body        string           `json:"body"`               // 本文
+	Snippet     string           `json:"snippet"`            // 検索キーワードを含むスニペット
+	Labels      []string         `json:"labels"`             // ラベル (Issueの場合)
+	Status      string           `json:"status"`             // ステータス (Issue・Discussion・Milestoneの場合)
+	Category    string           `json:"category,omitempty"` // カテゴリ (Discussionの場合)
+	AssigneeID  int64            `json:"assignee_id"`        // 担当者ID (Issueの場合)
+	CreatorID   int64            `json:"creator_id"`         // 作成者ID
+	CreatedAt   time.Time        `json:"created_at"`         // 作成日時
+	UpdatedAt   time.Time        `json:"updated_at"`         // 更新日時
+	TargetID    int64            `json:"target_id"`          // 関連対象ID (コメントの場合)
+	Rank        float64          `json:"rank"`               // 検索ランキングスコア
+	Highlighted string           `json:"highlighted"`        // ハイライト付きテキスト
 }
 
 // SearchResults は検索結果のリストを表す構造体
@@ -50,13 +87,32 @@ type SearchResults struct {
 	CurrentPage int            `json:"current_page"` // 現在のページ
 	TotalPages  int            `json:"total_pages"`  // 総ページ数
 	Query       string         `json:"query"`        // 検索クエリ
+	Facets      SearchFacets   `json:"facets"`       // 絞り込み用の件数集計
+}
+
+// SearchFacets は検索結果全体（ページング前）の種類・ステータス・ラベル・カテゴリごとの件数を表す構造体
+type SearchFacets struct {
+	Types      map[SearchResultType]int `json:"types"`
+	Statuses   map[string]int           `json:"statuses"`
+	Labels     map[string]int           `json:"labels"`
+	Categories map[string]int           `json:"categories"`
+}
+
+// NewSearchFacets は空のSearchFacetsを作成する
+func NewSearchFacets() SearchFacets {
+	return SearchFacets{
+		Types:      map[SearchResultType]int{},
+		Statuses:   map[string]int{},
+		Labels:     map[string]int{},
+		Categories: map[string]int{},
+	}
 }
 
 // SearchIndex はFTS5インデックスのスキーマを表す構造体
 type SearchIndex struct {
 	ID        int64     `json:"id"`
 	DocID     int64     `json:"doc_id"`     // 対象ドキュメントのID
-	DocType   string    `json:"doc_type"`   // ドキュメントタイプ (issue/comment)
+	DocType   string    `json:"doc_type"`   // ドキュメントタイプ (issue/comment/discussion/milestone/label)
 	Title     string    `json:"title"`      // タイトル (Issueの場合)
 	Body      string    `json:"body"`       // 本文
 	CreatedAt time.Time `json:"created_at"` // インデックス作成日時
@@ -0,0 +1,629 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SearchNodeKind は検索式の構文木ノードの種類を表す
+type SearchNodeKind string
+
+const (
+	// SearchNodeAnd は子ノードすべてに一致することを表す
+	SearchNodeAnd SearchNodeKind = "and"
+	// SearchNodeOr は子ノードのいずれかに一致することを表す
+	SearchNodeOr SearchNodeKind = "or"
+	// SearchNodeNot は子ノードに一致しないことを表す
+	SearchNodeNot SearchNodeKind = "not"
+	// SearchNodeTerm は前方一致する単語を表す
+	SearchNodeTerm SearchNodeKind = "term"
+	// SearchNodePhrase は完全一致するフレーズを表す
+	SearchNodePhrase SearchNodeKind = "phrase"
+	// SearchNodeQualifier は key:value 形式の修飾子を表す
+	SearchNodeQualifier SearchNodeKind = "qualifier"
+)
+
+// SearchNode は検索式の構文木のノード
+type SearchNode struct {
+	Kind      SearchNodeKind
+	Children  []*SearchNode    // and/or/notの場合
+	Text      string           // term/phraseの場合
+	Qualifier *SearchQualifier // qualifierの場合
+	Pos       int              // 入力文字列中の位置（バイトオフセット）
+}
+
+// SearchQualifier は key:value 形式の修飾子
+type SearchQualifier struct {
+	Key   string
+	Value string
+	// From, To は日付修飾子（created/updated）の範囲 [From, To)。ゼロ値は無制限を表す
+	From time.Time
+	To   time.Time
+}
+
+// IsTextOnly は修飾子・否定を含まない全文検索用のノードかどうかを判定する
+func (n *SearchNode) IsTextOnly() bool {
+	switch n.Kind {
+	case SearchNodeTerm, SearchNodePhrase:
+		return true
+	case SearchNodeAnd, SearchNodeOr:
+		for _, child := range n.Children {
+			if !child.IsTextOnly() {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// NewSearchQualifierNode は修飾子ノードを作成する
+func NewSearchQualifierNode(key, value string) *SearchNode {
+	return &SearchNode{Kind: SearchNodeQualifier, Qualifier: &SearchQualifier{Key: key, Value: value}}
+}
+
+// SearchAnd はnilを除いたノードをANDで結合する（1つの場合はそのノードを返す）
+func SearchAnd(nodes ...*SearchNode) *SearchNode {
+	var children []*SearchNode
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+		if node.Kind == SearchNodeAnd {
+			children = append(children, node.Children...)
+			continue
+		}
+		children = append(children, node)
+	}
+
+	switch len(children) {
+	case 0:
+		return nil
+	case 1:
+		return children[0]
+	}
+	return &SearchNode{Kind: SearchNodeAnd, Children: children, Pos: children[0].Pos}
+}
+
+// SearchSortField は検索結果の並び順の基準を表す
+type SearchSortField string
+
+const (
+	// SearchSortRelevance は関連度順を表す
+	SearchSortRelevance SearchSortField = "relevance"
+	// SearchSortCreated は作成日時順を表す
+	SearchSortCreated SearchSortField = "created"
+	// SearchSortUpdated は更新日時順を表す
+	SearchSortUpdated SearchSortField = "updated"
+)
+
+// SearchSort は検索結果の並び順を表す構造体
+type SearchSort struct {
+	Field     SearchSortField `json:"field"`
+	Ascending bool            `json:"ascending"`
+}
+
+// SearchSyntaxError は検索クエリの構文エラーを表す
+type SearchSyntaxError struct {
+	Pos     int    `json:"position"` // 入力文字列中の位置（バイトオフセット）
+	Message string `json:"message"`
+}
+
+// Error はエラーメッセージを返す
+func (e *SearchSyntaxError) Error() string {
+	return fmt.Sprintf("search syntax error at position %d: %s", e.Pos, e.Message)
+}
+
+// searchQualifierKeys は修飾子として解釈するキー（それ以外の key:value は単語として扱う）
+var searchQualifierKeys = map[string]bool{
+	"label":     true,
+	"status":    true,
+	"is":        true,
+	"no":        true,
+	"assignee":  true,
+	"creator":   true,
+	"author":    true,
+	"milestone": true,
+	"category":  true,
+	"repo":      true,
+	"created":   true,
+	"updated":   true,
+	"sort":      true,
+}
+
+// searchUserValueRegex はユーザー指定（@me・ID・ユーザー名）の形式
+var searchUserValueRegex = regexp.MustCompile(`^(@me|[A-Za-z0-9][A-Za-z0-9_-]*)$`)
+
+// ParseSearchQuery は検索クエリ文字列を解析する
+//
+// 対応する構文:
+//   - 単語（前方一致）と "完全一致フレーズ"
+//   - 空白区切りによるAND、OR、括弧によるグループ化、先頭の - による否定
+//   - label:, status:, is:draft|open|closed, no:assignee|milestone|label,
+//     assignee:, creator:（author:）, milestone:, category:, repo:
+//   - label: の値の * によるワイルドカード（label:priority/* はスコープ priority のいずれかのラベル）
+//   - scope:* によるスコープのいずれかのラベルの指定（priority:* は label:priority/* と同じ）
+//   - created:, updated: の日付比較（>, >=, <, <=, a..b。日付は YYYY, YYYY-MM, YYYY-MM-DD）
+//   - sort:created|updated|relevance[-asc|-desc]
+func ParseSearchQuery(input string) (SearchQuery, error) {
+	return ParseSearchQueryWithLabelScopes(input, nil)
+}
+
+// ParseSearchQueryWithLabelScopes はラベルのスコープを考慮して検索クエリ文字列を解析する
+// labelScopes に含まれるスコープは scope:value の形式で指定でき、label:scope/value として扱う
+func ParseSearchQueryWithLabelScopes(input string, labelScopes []string) (SearchQuery, error) {
+	query := SearchQuery{
+		Query:  input,
+		Status: "all", // デフォルト値
+		Limit:  20,    // デフォルト値
+	}
+
+	scopes := make(map[string]bool, len(labelScopes))
+	for _, scope := range labelScopes {
+		scopes[scope] = true
+	}
+
+	tokens, err := tokenizeSearchQuery(input, scopes)
+	if err != nil {
+		return query, err
+	}
+
+	p := &searchParser{tokens: tokens}
+	root, err := p.parse()
+	if err != nil {
+		return query, err
+	}
+
+	root, sort, err := extractSearchSort(root)
+	if err != nil {
+		return query, err
+	}
+
+	query.Expr = root
+	if sort != nil {
+		query.Sort = *sort
+	}
+	return query, nil
+}
+
+// searchTokenKind は字句の種類を表す
+type searchTokenKind int
+
+const (
+	searchTokenEOF searchTokenKind = iota
+	searchTokenWord
+	searchTokenPhrase
+	searchTokenQualifier
+	searchTokenLParen
+	searchTokenRParen
+	searchTokenOr
+	searchTokenNot
+)
+
+// searchToken は検索クエリの字句
+type searchToken struct {
+	kind searchTokenKind
+	text string // 単語・フレーズ・修飾子の値
+	key  string // 修飾子のキー
+	pos  int
+}
+
+// tokenizeSearchQuery は検索クエリ文字列を字句に分割する
+func tokenizeSearchQuery(input string, labelScopes map[string]bool) ([]searchToken, error) {
+	var tokens []searchToken
+	i := 0
+	for i < len(input) {
+		c := input[i]
+		switch {
+		case isSearchSpace(c):
+			i++
+		case c == '(':
+			tokens = append(tokens, searchToken{kind: searchTokenLParen, pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, searchToken{kind: searchTokenRParen, pos: i})
+			i++
+		case c == '-':
+			if i+1 >= len(input) || isSearchSpace(input[i+1]) || input[i+1] == ')' {
+				return nil, &SearchSyntaxError{Pos: i, Message: "'-' must be followed by a term"}
+			}
+			tokens = append(tokens, searchToken{kind: searchTokenNot, pos: i})
+			i++
+		case c == '"':
+			text, next, err := readSearchQuoted(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, searchToken{kind: searchTokenPhrase, text: text, pos: i})
+			i = next
+		default:
+			start := i
+			for i < len(input) && !isSearchDelimiter(input[i]) {
+				i++
+			}
+			word := input[start:i]
+
+			if word == "OR" {
+				tokens = append(tokens, searchToken{kind: searchTokenOr, pos: start})
+				continue
+			}
+			if word == "AND" {
+				// 空白区切りがANDを表すため明示的なANDは読み飛ばす
+				continue
+			}
+
+			colon := strings.IndexByte(word, ':')
+			key := strings.ToLower(word[:max(colon, 0)])
+			// priority:high や priority:* はラベルのスコープの指定として扱う
+			scope := ""
+			if colon > 0 && !searchQualifierKeys[key] && (labelScopes[word[:colon]] || word[colon+1:] == "*") {
+				scope = word[:colon]
+			}
+			if colon <= 0 || (!searchQualifierKeys[key] && scope == "") {
+				tokens = append(tokens, searchToken{kind: searchTokenWord, text: word, pos: start})
+				continue
+			}
+
+			value := word[colon+1:]
+			if value == "" && i < len(input) && input[i] == '"' {
+				// milestone:"v2.0" のような引用符付きの値
+				quoted, next, err := readSearchQuoted(input, i)
+				if err != nil {
+					return nil, err
+				}
+				value = quoted
+				i = next
+			}
+			if value == "" {
+				return nil, &SearchSyntaxError{Pos: start, Message: fmt.Sprintf("missing value for qualifier %q", key)}
+			}
+			if scope != "" {
+				key, value = "label", scope+LabelScopeSeparator+value
+			}
+			tokens = append(tokens, searchToken{kind: searchTokenQualifier, key: key, text: value, pos: start})
+		}
+	}
+	tokens = append(tokens, searchToken{kind: searchTokenEOF, pos: len(input)})
+	return tokens, nil
+}
+
+// readSearchQuoted は start 位置の引用符で囲まれた文字列を読み取り、値と次の位置を返す
+func readSearchQuoted(input string, start int) (string, int, error) {
+	end := strings.IndexByte(input[start+1:], '"')
+	if end < 0 {
+		return "", 0, &SearchSyntaxError{Pos: start, Message: "unterminated quoted phrase"}
+	}
+	text := input[start+1 : start+1+end]
+	if strings.TrimSpace(text) == "" {
+		return "", 0, &SearchSyntaxError{Pos: start, Message: "empty quoted phrase"}
+	}
+	return text, start + end + 2, nil
+}
+
+// isSearchSpace はASCIIの空白文字かどうかを判定する
+// マルチバイト文字の途中のバイトを空白と誤判定しないようにバイト単位で判定する
+func isSearchSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+// isSearchDelimiter は単語の区切り文字かどうかを判定する
+func isSearchDelimiter(c byte) bool {
+	return isSearchSpace(c) || c == '(' || c == ')' || c == '"'
+}
+
+// searchParser は字句列から構文木を作成する再帰下降パーサー
+type searchParser struct {
+	tokens []searchToken
+	pos    int
+}
+
+// peek は現在の字句を返す
+func (p *searchParser) peek() searchToken {
+	return p.tokens[p.pos]
+}
+
+// next は現在の字句を返して次に進む
+func (p *searchParser) next() searchToken {
+	token := p.tokens[p.pos]
+	if token.kind != searchTokenEOF {
+		p.pos++
+	}
+	return token
+}
+
+// parse はクエリ全体を解析する
+func (p *searchParser) parse() (*SearchNode, error) {
+	if p.peek().kind == searchTokenEOF {
+		return nil, nil
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if token := p.peek(); token.kind != searchTokenEOF {
+		return nil, &SearchSyntaxError{Pos: token.pos, Message: "unexpected ')'"}
+	}
+	return node, nil
+}
+
+// parseOr は OR で区切られた式を解析する
+func (p *searchParser) parseOr() (*SearchNode, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []*SearchNode{first}
+	for p.peek().kind == searchTokenOr {
+		p.next()
+		child, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	if len(children) == 1 {
+		return first, nil
+	}
+	return &SearchNode{Kind: SearchNodeOr, Children: children, Pos: first.Pos}, nil
+}
+
+// parseAnd は空白で区切られた式を解析する
+func (p *searchParser) parseAnd() (*SearchNode, error) {
+	var children []*SearchNode
+	for {
+		switch token := p.peek(); token.kind {
+		case searchTokenEOF, searchTokenRParen, searchTokenOr:
+			if len(children) == 0 {
+				return nil, &SearchSyntaxError{Pos: token.pos, Message: "expected a search term"}
+			}
+			return SearchAnd(children...), nil
+		}
+
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+}
+
+// parseUnary は否定を含む式を解析する
+func (p *searchParser) parseUnary() (*SearchNode, error) {
+	if token := p.peek(); token.kind == searchTokenNot {
+		p.next()
+		child, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return &SearchNode{Kind: SearchNodeNot, Children: []*SearchNode{child}, Pos: token.pos}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary は単語・フレーズ・修飾子・括弧で囲まれた式を解析する
+func (p *searchParser) parsePrimary() (*SearchNode, error) {
+	token := p.next()
+	switch token.kind {
+	case searchTokenWord:
+		return &SearchNode{Kind: SearchNodeTerm, Text: token.text, Pos: token.pos}, nil
+	case searchTokenPhrase:
+		return &SearchNode{Kind: SearchNodePhrase, Text: token.text, Pos: token.pos}, nil
+	case searchTokenQualifier:
+		qualifier, err := newSearchQualifier(token)
+		if err != nil {
+			return nil, err
+		}
+		return &SearchNode{Kind: SearchNodeQualifier, Qualifier: qualifier, Pos: token.pos}, nil
+	case searchTokenLParen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != searchTokenRParen {
+			return nil, &SearchSyntaxError{Pos: token.pos, Message: "missing closing ')'"}
+		}
+		p.next()
+		return node, nil
+	case searchTokenRParen:
+		return nil, &SearchSyntaxError{Pos: token.pos, Message: "unexpected ')'"}
+	case searchTokenNot:
+		return nil, &SearchSyntaxError{Pos: token.pos, Message: "double negation is not supported"}
+	}
+	return nil, &SearchSyntaxError{Pos: token.pos, Message: "expected a search term"}
+}
+
+// newSearchQualifier は修飾子の値を検証して SearchQualifier を作成する
+func newSearchQualifier(token searchToken) (*SearchQualifier, error) {
+	qualifier := &SearchQualifier{Key: token.key, Value: token.text}
+	invalid := func(format string, args ...interface{}) error {
+		return &SearchSyntaxError{Pos: token.pos, Message: fmt.Sprintf(format, args...)}
+	}
+
+	switch token.key {
+	case "author":
+		qualifier.Key = "creator"
+		fallthrough
+	case "assignee", "creator":
+		if !searchUserValueRegex.MatchString(token.text) {
+			return nil, invalid("invalid user %q for qualifier %s", token.text, token.key)
+		}
+	case "is":
+		qualifier.Value = strings.ToLower(token.text)
+		switch qualifier.Value {
+		case "draft", "open", "closed":
+		default:
+			return nil, invalid("unknown value %q for qualifier is (expected draft, open or closed)", token.text)
+		}
+	case "no":
+		qualifier.Value = strings.ToLower(token.text)
+		switch qualifier.Value {
+		case "assignee", "milestone", "label":
+		default:
+			return nil, invalid("unknown value %q for qualifier no (expected assignee, milestone or label)", token.text)
+		}
+	case "created", "updated":
+		from, to, err := parseSearchDateRange(token.text)
+		if err != nil {
+			return nil, invalid("invalid date %q for qualifier %s: %v", token.text, token.key, err)
+		}
+		qualifier.From, qualifier.To = from, to
+	case "sort":
+		if _, err := ParseSearchSort(token.text); err != nil {
+			return nil, invalid("%v", err)
+		}
+	}
+	return qualifier, nil
+}
+
+// parseSearchDateRange は日付条件を [from, to) の範囲に変換する
+func parseSearchDateRange(value string) (time.Time, time.Time, error) {
+	if before, after, found := strings.Cut(value, ".."); found {
+		var from, to time.Time
+		if before != "*" {
+			start, _, err := parseSearchDate(before)
+			if err != nil {
+				return time.Time{}, time.Time{}, err
+			}
+			from = start
+		}
+		if after != "*" {
+			_, end, err := parseSearchDate(after)
+			if err != nil {
+				return time.Time{}, time.Time{}, err
+			}
+			to = end
+		}
+		if from.IsZero() && to.IsZero() {
+			return time.Time{}, time.Time{}, fmt.Errorf("range must have at least one bound")
+		}
+		if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+			return time.Time{}, time.Time{}, fmt.Errorf("range start must be before its end")
+		}
+		return from, to, nil
+	}
+
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if !strings.HasPrefix(value, op) {
+			continue
+		}
+		start, end, err := parseSearchDate(value[len(op):])
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		switch op {
+		case ">=":
+			return start, time.Time{}, nil
+		case "<=":
+			return time.Time{}, end, nil
+		case ">":
+			return end, time.Time{}, nil
+		case "<":
+			return time.Time{}, start, nil
+		}
+		return start, end, nil
+	}
+
+	return parseSearchDate(value)
+}
+
+// parseSearchDate は YYYY, YYYY-MM, YYYY-MM-DD 形式の日付をその期間 [start, end) に変換する
+func parseSearchDate(value string) (time.Time, time.Time, error) {
+	layouts := []struct {
+		layout string
+		years  int
+		months int
+		days   int
+	}{
+		{"2006-01-02", 0, 0, 1},
+		{"2006-01", 0, 1, 0},
+		{"2006", 1, 0, 0},
+	}
+	for _, l := range layouts {
+		if len(value) != len(l.layout) {
+			continue
+		}
+		start, err := time.Parse(l.layout, value)
+		if err != nil {
+			continue
+		}
+		return start, start.AddDate(l.years, l.months, l.days), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("expected YYYY, YYYY-MM or YYYY-MM-DD")
+}
+
+// ParseSearchSort は sort: の値を解析する
+func ParseSearchSort(value string) (SearchSort, error) {
+	field, direction, _ := strings.Cut(strings.ToLower(value), "-")
+
+	sort := SearchSort{Field: SearchSortField(field)}
+	switch sort.Field {
+	case SearchSortRelevance, SearchSortCreated, SearchSortUpdated:
+	default:
+		return sort, fmt.Errorf("unknown sort field %q (expected created, updated or relevance)", field)
+	}
+
+	switch direction {
+	case "", "desc":
+	case "asc":
+		sort.Ascending = true
+	default:
+		return sort, fmt.Errorf("unknown sort direction %q (expected asc or desc)", direction)
+	}
+	return sort, nil
+}
+
+// extractSearchSort は構文木から sort: 修飾子を取り除いて返す
+// sort: は最上位のANDにのみ指定でき、否定やORの中では使用できない
+func extractSearchSort(root *SearchNode) (*SearchNode, *SearchSort, error) {
+	if root == nil {
+		return nil, nil, nil
+	}
+
+	topLevel := []*SearchNode{root}
+	if root.Kind == SearchNodeAnd {
+		topLevel = root.Children
+	}
+
+	var sort *SearchSort
+	var rest []*SearchNode
+	for _, node := range topLevel {
+		if !isSearchSortNode(node) {
+			if pos, found := findSearchSort(node); found {
+				return nil, nil, &SearchSyntaxError{Pos: pos, Message: "sort: cannot be negated or used inside OR"}
+			}
+			rest = append(rest, node)
+			continue
+		}
+		if sort != nil {
+			return nil, nil, &SearchSyntaxError{Pos: node.Pos, Message: "sort: specified more than once"}
+		}
+		parsed, _ := ParseSearchSort(node.Qualifier.Value)
+		sort = &parsed
+	}
+
+	return SearchAnd(rest...), sort, nil
+}
+
+// isSearchSortNode は sort: 修飾子のノードかどうかを判定する
+func isSearchSortNode(node *SearchNode) bool {
+	return node.Kind == SearchNodeQualifier && node.Qualifier.Key == "sort"
+}
+
+// findSearchSort は部分木に含まれる sort: 修飾子の位置を返す
+func findSearchSort(node *SearchNode) (int, bool) {
+	if isSearchSortNode(node) {
+		return node.Pos, true
+	}
+	for _, child := range node.Children {
+		if pos, found := findSearchSort(child); found {
+			return pos, true
+		}
+	}
+	return 0, false
+}
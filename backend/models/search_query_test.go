@@ -0,0 +1,176 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	date := func(value string) time.Time {
+		d, _ := time.Parse("2006-01-02", value)
+		return d
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		check func(t *testing.T, query models.SearchQuery)
+	}{
+		{
+			name:  "空のクエリ",
+			input: "  ",
+			check: func(t *testing.T, query models.SearchQuery) {
+				assert.Nil(t, query.Expr)
+				assert.Equal(t, models.SearchSort{}, query.Sort)
+			},
+		},
+		{
+			name:  "単語とフレーズ",
+			input: `login "exact phrase"`,
+			check: func(t *testing.T, query models.SearchQuery) {
+				assert.Equal(t, models.SearchNodeAnd, query.Expr.Kind)
+				assert.Equal(t, models.SearchNodeTerm, query.Expr.Children[0].Kind)
+				assert.Equal(t, "login", query.Expr.Children[0].Text)
+				assert.Equal(t, models.SearchNodePhrase, query.Expr.Children[1].Kind)
+				assert.Equal(t, "exact phrase", query.Expr.Children[1].Text)
+				assert.True(t, query.Expr.IsTextOnly())
+			},
+		},
+		{
+			name:  "否定された修飾子",
+			input: "-label:wontfix",
+			check: func(t *testing.T, query models.SearchQuery) {
+				assert.Equal(t, models.SearchNodeNot, query.Expr.Kind)
+				qualifier := query.Expr.Children[0].Qualifier
+				assert.Equal(t, "label", qualifier.Key)
+				assert.Equal(t, "wontfix", qualifier.Value)
+			},
+		},
+		{
+			name:  "引用符付きの修飾子の値",
+			input: `milestone:"v2.0 beta"`,
+			check: func(t *testing.T, query models.SearchQuery) {
+				assert.Equal(t, "milestone", query.Expr.Qualifier.Key)
+				assert.Equal(t, "v2.0 beta", query.Expr.Qualifier.Value)
+			},
+		},
+		{
+			name:  "リポジトリの指定",
+			input: "repo:backend crash",
+			check: func(t *testing.T, query models.SearchQuery) {
+				assert.Equal(t, models.SearchNodeAnd, query.Expr.Kind)
+				assert.Equal(t, "repo", query.Expr.Children[0].Qualifier.Key)
+				assert.Equal(t, "backend", query.Expr.Children[0].Qualifier.Value)
+			},
+		},
+		{
+			name:  "日付の比較",
+			input: "created:>2026-01-01",
+			check: func(t *testing.T, query models.SearchQuery) {
+				assert.Equal(t, date("2026-01-02"), query.Expr.Qualifier.From)
+				assert.True(t, query.Expr.Qualifier.To.IsZero())
+			},
+		},
+		{
+			name:  "月単位の日付範囲",
+			input: "updated:2026-01..2026-03",
+			check: func(t *testing.T, query models.SearchQuery) {
+				assert.Equal(t, date("2026-01-01"), query.Expr.Qualifier.From)
+				assert.Equal(t, date("2026-04-01"), query.Expr.Qualifier.To)
+			},
+		},
+		{
+			name:  "ORグループと並び順",
+			input: "(label:bug OR label:feature) assignee:@me no:milestone sort:updated-asc",
+			check: func(t *testing.T, query models.SearchQuery) {
+				assert.Equal(t, models.SearchSort{Field: models.SearchSortUpdated, Ascending: true}, query.Sort)
+				assert.Equal(t, models.SearchNodeAnd, query.Expr.Kind)
+				assert.Len(t, query.Expr.Children, 3)
+				assert.Equal(t, models.SearchNodeOr, query.Expr.Children[0].Kind)
+				assert.Equal(t, "@me", query.Expr.Children[1].Qualifier.Value)
+				assert.Equal(t, "no", query.Expr.Children[2].Qualifier.Key)
+			},
+		},
+		{
+			name:  "未知のキーは単語として扱う",
+			input: "http://example.com is:draft",
+			check: func(t *testing.T, query models.SearchQuery) {
+				assert.Equal(t, models.SearchNodeTerm, query.Expr.Children[0].Kind)
+				assert.Equal(t, "http://example.com", query.Expr.Children[0].Text)
+				assert.Equal(t, "draft", query.Expr.Children[1].Qualifier.Value)
+			},
+		},
+		{
+			name:  "スコープのワイルドカード",
+			input: "priority:* -label:type/*",
+			check: func(t *testing.T, query models.SearchQuery) {
+				assert.Equal(t, "label", query.Expr.Children[0].Qualifier.Key)
+				assert.Equal(t, "priority/*", query.Expr.Children[0].Qualifier.Value)
+				assert.Equal(t, models.SearchNodeNot, query.Expr.Children[1].Kind)
+				assert.Equal(t, "type/*", query.Expr.Children[1].Children[0].Qualifier.Value)
+			},
+		},
+		{
+			name:  "日本語の単語",
+			input: "ログイン 不具合",
+			check: func(t *testing.T, query models.SearchQuery) {
+				assert.Len(t, query.Expr.Children, 2)
+				assert.Equal(t, "ログイン", query.Expr.Children[0].Text)
+				assert.Equal(t, "不具合", query.Expr.Children[1].Text)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := models.ParseSearchQuery(tt.input)
+			assert.NoError(t, err)
+			tt.check(t, query)
+		})
+	}
+}
+
+func TestParseSearchQueryWithLabelScopes(t *testing.T) {
+	query, err := models.ParseSearchQueryWithLabelScopes(`priority:high area:"user settings" severity:low`, []string{"priority", "area"})
+	assert.NoError(t, err)
+	assert.Len(t, query.Expr.Children, 3)
+	assert.Equal(t, "label", query.Expr.Children[0].Qualifier.Key)
+	assert.Equal(t, "priority/high", query.Expr.Children[0].Qualifier.Value)
+	assert.Equal(t, "area/user settings", query.Expr.Children[1].Qualifier.Value)
+	// 登録されていないスコープは単語として扱う
+	assert.Equal(t, models.SearchNodeTerm, query.Expr.Children[2].Kind)
+	assert.Equal(t, "severity:low", query.Expr.Children[2].Text)
+}
+
+func TestParseSearchQuery_SyntaxError(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		pos   int
+	}{
+		{name: "閉じていない引用符", input: `crash "login page`, pos: 6},
+		{name: "閉じていない括弧", input: "crash (a OR b", pos: 6},
+		{name: "余分な閉じ括弧", input: "crash)", pos: 5},
+		{name: "ORの後に項がない", input: "crash OR", pos: 8},
+		{name: "単独のハイフン", input: "crash - login", pos: 6},
+		{name: "修飾子の値がない", input: "crash label:", pos: 6},
+		{name: "不正な日付", input: "created:>2026-13-01", pos: 0},
+		{name: "不正な並び順", input: "crash sort:votes", pos: 6},
+		{name: "否定された並び順", input: "crash -sort:created", pos: 7},
+		{name: "OR内の並び順", input: "crash OR sort:created", pos: 9},
+		{name: "不正なis", input: "is:merged", pos: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := models.ParseSearchQuery(tt.input)
+			var syntaxErr *models.SearchSyntaxError
+			if assert.ErrorAs(t, err, &syntaxErr) {
+				assert.Equal(t, tt.pos, syntaxErr.Pos)
+			}
+		})
+	}
+}
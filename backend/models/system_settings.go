@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"time"
 
 	"gorm.io/gorm"
@@ -8,59 +9,70 @@ import (
 
 // SystemSettings はシステム全体の設定を表す構造体
 type SystemSettings struct {
-	ID                  int64     `json:"id"`
-	SiteName            string    `json:"site_name"`
-	SiteDescription     string    `json:"site_description"`
-	SiteURL             string    `json:"site_url"`
-	AllowSignup         bool      `json:"allow_signup"`
-	DefaultLanguage     string    `json:"default_language"`
-	DefaultTheme        string    `json:"default_theme"`
-	EmailEnabled        bool      `json:"email_enabled"`
-	EmailFromAddress    string    `json:"email_from_address"`
-	EmailFromName       string    `json:"email_from_name"`
-	SMTPHost            string    `json:"smtp_host,omitempty"`
-	SMTPPort            int       `json:"smtp_port,omitempty"`
-	SMTPUsername        string    `json:"smtp_username,omitempty"`
-	SMTPPassword        string    `json:"smtp_password,omitempty"`
-	SMTPUseTLS          bool      `json:"smtp_use_tls"`
-	MaxFileUploadSize   int64     `json:"max_file_upload_size"`
-	RequireEmailVerify  bool      `json:"require_email_verify"`
-	AllowGuestAccess    bool      `json:"allow_guest_access"`
-	MaintenanceMode     bool      `json:"maintenance_mode"`
-	MaintenanceMessage  string    `json:"maintenance_message,omitempty"`
-	BackupRetentionDays int       `json:"backup_retention_days"`
-	LogRetentionDays    int       `json:"log_retention_days"`
-	CreatedAt           time.Time `json:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at"`
+	ID                  int64  `json:"id"`
+	SiteName            string `json:"site_name"`
+	SiteDescription     string `json:"site_description"`
+	SiteURL             string `json:"site_url"`
+	AllowSignup         bool   `json:"allow_signup"`
+	DefaultLanguage     string `json:"default_language"`
+	DefaultTheme        string `json:"default_theme"`
+	EmailEnabled        bool   `json:"email_enabled"`
+	EmailFromAddress    string `json:"email_from_address"`
+	EmailFromName       string `json:"email_from_name"`
+	SMTPHost            string `json:"smtp_host,omitempty"`
+	SMTPPort            int    `json:"smtp_port,omitempty"`
+	SMTPUsername        string `json:"smtp_username,omitempty"`
+	SMTPPassword        string `json:"smtp_password,omitempty"`
+	SMTPUseTLS          bool   `json:"smtp_use_tls"`
+	MaxFileUploadSize   int64  `json:"max_file_upload_size"`
+	RequireEmailVerify  bool   `json:"require_email_verify"`
+	AllowGuestAccess    bool   `json:"allow_guest_access"`
+	MaintenanceMode     bool   `json:"maintenance_mode"`
+	MaintenanceMessage  string `json:"maintenance_message,omitempty"`
+	BackupRetentionDays int    `json:"backup_retention_days"`
+	LogRetentionDays    int    `json:"log_retention_days"`
+	// ログインの試行の制限
+	LoginMaxFailedAttempts   int       `json:"login_max_failed_attempts" gorm:"not null;default:5"`     // アカウントをロックするまでの連続した失敗回数（0の場合はロックしない）
+	LoginIPMaxFailedAttempts int       `json:"login_ip_max_failed_attempts" gorm:"not null;default:20"` // IPアドレスからのログインを止めるまでの失敗回数（0の場合は止めない）
+	LoginLockoutMinutes      int       `json:"login_lockout_minutes" gorm:"not null;default:15"`        // ロックする時間（分）。最後の失敗からこの時間が経過すると失敗回数を数え直す（1以上）
+	LoginBackoffBaseSeconds  int       `json:"login_backoff_base_seconds" gorm:"not null;default:1"`    // 1回目の失敗後に次の試行まで待たせる時間（秒）。失敗のたびに2倍にする（0の場合は待たせない）
+	LoginBackoffMaxSeconds   int       `json:"login_backoff_max_seconds" gorm:"not null;default:60"`    // 次の試行まで待たせる時間の上限（秒）
+	CreatedAt                time.Time `json:"created_at"`
+	UpdatedAt                time.Time `json:"updated_at"`
 }
 
 // NewDefaultSystemSettings はデフォルトのシステム設定を作成する
 func NewDefaultSystemSettings() *SystemSettings {
 	now := time.Now()
 	return &SystemSettings{
-		SiteName:            "TicketHub",
-		SiteDescription:     "GitHub-like ticket management system",
-		SiteURL:             "http://localhost:3000",
-		AllowSignup:         true,
-		DefaultLanguage:     "en",
-		DefaultTheme:        "light",
-		EmailEnabled:        false,
-		EmailFromAddress:    "noreply@tickethub.local",
-		EmailFromName:       "TicketHub",
-		SMTPHost:            "",
-		SMTPPort:            587,
-		SMTPUsername:        "",
-		SMTPPassword:        "",
-		SMTPUseTLS:          true,
-		MaxFileUploadSize:   10 * 1024 * 1024, // 10MB
-		RequireEmailVerify:  false,
-		AllowGuestAccess:    false,
-		MaintenanceMode:     false,
-		MaintenanceMessage:  "",
-		BackupRetentionDays: 30,
-		LogRetentionDays:    90,
-		CreatedAt:           now,
-		UpdatedAt:           now,
+		SiteName:                 "TicketHub",
+		SiteDescription:          "GitHub-like ticket management system",
+		SiteURL:                  "http://localhost:3000",
+		AllowSignup:              true,
+		DefaultLanguage:          "en",
+		DefaultTheme:             "light",
+		EmailEnabled:             false,
+		EmailFromAddress:         "noreply@tickethub.local",
+		EmailFromName:            "TicketHub",
+		SMTPHost:                 "",
+		SMTPPort:                 587,
+		SMTPUsername:             "",
+		SMTPPassword:             "",
+		SMTPUseTLS:               true,
+		MaxFileUploadSize:        10 * 1024 * 1024, // 10MB
+		RequireEmailVerify:       false,
+		AllowGuestAccess:         false,
+		MaintenanceMode:          false,
+		MaintenanceMessage:       "",
+		BackupRetentionDays:      30,
+		LogRetentionDays:         90,
+		LoginMaxFailedAttempts:   5,
+		LoginIPMaxFailedAttempts: 20,
+		LoginLockoutMinutes:      15,
+		LoginBackoffBaseSeconds:  1,
+		LoginBackoffMaxSeconds:   60,
+		CreatedAt:                now,
+		UpdatedAt:                now,
 	}
 }
 
@@ -173,6 +185,46 @@ func (s *SystemSettings) Update(settings map[string]interface{}) {
 			s.LogRetentionDays = i
 		}
 	}
+	if val, exists := settings["login_max_failed_attempts"]; exists {
+		if i, ok := intSettingValue(val); ok {
+			s.LoginMaxFailedAttempts = i
+		}
+	}
+	if val, exists := settings["login_ip_max_failed_attempts"]; exists {
+		if i, ok := intSettingValue(val); ok {
+			s.LoginIPMaxFailedAttempts = i
+		}
+	}
+	if val, exists := settings["login_lockout_minutes"]; exists {
+		// 0の場合は失敗のたびに数え直すことになり、待ち時間とロックが働かなくなるため無視する
+		if i, ok := intSettingValue(val); ok && i > 0 {
+			s.LoginLockoutMinutes = i
+		}
+	}
+	if val, exists := settings["login_backoff_base_seconds"]; exists {
+		if i, ok := intSettingValue(val); ok {
+			s.LoginBackoffBaseSeconds = i
+		}
+	}
+	if val, exists := settings["login_backoff_max_seconds"]; exists {
+		if i, ok := intSettingValue(val); ok {
+			s.LoginBackoffMaxSeconds = i
+		}
+	}
+}
+
+// intSettingValue は設定値を0以上の整数に変換する
+// JSONから読み込んだ数値は float64 になるため、小数部のない float64 も受け付ける
+func intSettingValue(val interface{}) (int, bool) {
+	switch v := val.(type) {
+	case int:
+		return v, v >= 0
+	case float64:
+		if v >= 0 && v == math.Trunc(v) {
+			return int(v), true
+		}
+	}
+	return 0, false
 }
 
 // AutoMigrateSystemSettings はSystemSettingsテーブルのマイグレーションを実行します
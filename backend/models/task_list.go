@@ -0,0 +1,101 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// ErrTaskListItemNotFound は指定された番号のチェックリスト項目が本文に存在しない場合のエラー
+var ErrTaskListItemNotFound = errors.New("task list item not found")
+
+// taskListItemPattern はMarkdownのチェックリスト項目（- [ ] / * [x] / 1. [ ] など）に一致する正規表現
+var taskListItemPattern = regexp.MustCompile(`^(\s*(?:[-*+]|\d{1,9}[.)])\s+\[)([ xX])(\]\s+)(\S.*)$`)
+
+// codeFencePattern はコードブロックの開始・終了行に一致する正規表現
+var codeFencePattern = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+
+// TaskListItem は本文中のチェックリスト項目を表す構造体
+type TaskListItem struct {
+	Index   int    `json:"index"` // 本文中のチェックリスト項目の通し番号（0始まり）
+	Line    int    `json:"line"`  // 本文中の行番号（0始まり）
+	Text    string `json:"text"`
+	Checked bool   `json:"checked"`
+}
+
+// TaskProgress はチェックリストやサブIssueの完了数を表す構造体
+type TaskProgress struct {
+	Completed int `json:"completed"`
+	Total     int `json:"total"`
+}
+
+// ParseTaskList は本文からチェックリスト項目を抽出する（コードブロック内は除く）
+func ParseTaskList(body string) []TaskListItem {
+	items := []TaskListItem{}
+	fence := ""
+	for i, line := range strings.Split(body, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+
+		// コードブロックの開始・終了
+		if m := codeFencePattern.FindStringSubmatch(line); m != nil {
+			switch {
+			case fence == "":
+				fence = m[1]
+			case m[1][0] == fence[0] && len(m[1]) >= len(fence) && strings.TrimSpace(line) == m[1]:
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+
+		if m := taskListItemPattern.FindStringSubmatch(line); m != nil {
+			items = append(items, TaskListItem{
+				Index:   len(items),
+				Line:    i,
+				Text:    strings.TrimSpace(m[4]),
+				Checked: m[2] != " ",
+			})
+		}
+	}
+	return items
+}
+
+// ComputeTaskProgress は本文のチェックリストの完了数を集計する
+func ComputeTaskProgress(body string) TaskProgress {
+	var progress TaskProgress
+	for _, item := range ParseTaskList(body) {
+		progress.Total++
+		if item.Checked {
+			progress.Completed++
+		}
+	}
+	return progress
+}
+
+// ReplaceTaskListItemText は本文の指定された番号のチェックリスト項目のテキストを置き換える
+// 箇条書きの記号・インデント・チェック状態はそのまま残します
+func ReplaceTaskListItemText(body string, index int, text string) (string, error) {
+	var target *TaskListItem
+	for _, item := range ParseTaskList(body) {
+		if item.Index == index {
+			item := item
+			target = &item
+			break
+		}
+	}
+	if target == nil {
+		return "", ErrTaskListItemNotFound
+	}
+
+	lines := strings.Split(body, "\n")
+	line := lines[target.Line]
+	suffix := ""
+	if strings.HasSuffix(line, "\r") {
+		line, suffix = strings.TrimSuffix(line, "\r"), "\r"
+	}
+	m := taskListItemPattern.FindStringSubmatch(line)
+	lines[target.Line] = m[1] + m[2] + m[3] + text + suffix
+	return strings.Join(lines, "\n"), nil
+}
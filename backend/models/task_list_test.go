@@ -0,0 +1,40 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeTaskProgress(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want models.TaskProgress
+	}{
+		{"チェックリストなし", "本文のみ\n- 箇条書き", models.TaskProgress{}},
+		{"未完了と完了", "- [ ] a\n- [x] b\n* [X] c", models.TaskProgress{Completed: 2, Total: 3}},
+		{"入れ子と番号付きリスト", "1. [ ] a\n   - [x] b\n2) [ ] c", models.TaskProgress{Completed: 1, Total: 3}},
+		{"コードブロック内は除く", "- [ ] a\n```\n- [x] b\n```\n~~~~\n- [ ] c\n~~~\n~~~~", models.TaskProgress{Total: 1}},
+		{"テキストのない項目は除く", "- [ ]\n- [x] \n- [] a", models.TaskProgress{}},
+		{"CRLFの改行", "- [x] a\r\n- [ ] b\r\n", models.TaskProgress{Completed: 1, Total: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.ComputeTaskProgress(tt.body))
+		})
+	}
+}
+
+func TestReplaceTaskListItemText(t *testing.T) {
+	body := "概要\n- [x] 設計\n  - [ ] 実装\r\n```\n- [ ] コード\n```"
+
+	got, err := models.ReplaceTaskListItemText(body, 1, "#12")
+	assert.NoError(t, err)
+	assert.Equal(t, "概要\n- [x] 設計\n  - [ ] #12\r\n```\n- [ ] コード\n```", got)
+
+	_, err = models.ReplaceTaskListItemText(body, 2, "#12")
+	assert.ErrorIs(t, err, models.ErrTaskListItemNotFound)
+}
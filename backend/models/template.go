@@ -0,0 +1,221 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// テンプレートの対象の種類
+const (
+	TemplateTypeIssue      = "issue"
+	TemplateTypeDiscussion = "discussion"
+)
+
+// フォームの項目の種類
+const (
+	TemplateFieldText     = "text"     // 自由入力
+	TemplateFieldDropdown = "dropdown" // 選択肢から1つを選択
+	TemplateFieldCheckbox = "checkbox" // 確認のチェック（必須の場合はチェックが必要）
+)
+
+// templateFieldIDPattern はフォームの項目IDに使用できる文字の正規表現
+var templateFieldIDPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// TemplateField はテンプレートのフォームの項目を表す構造体
+type TemplateField struct {
+	ID          string   `json:"id"` // 送信時のキー（英小文字・数字・_・-）
+	Type        string   `json:"type"`
+	Label       string   `json:"label"`
+	Description string   `json:"description,omitempty"`
+	Required    bool     `json:"required"`
+	Options     []string `json:"options,omitempty"` // dropdownの選択肢
+}
+
+// Template はIssue・Discussionの作成時に使用するテンプレートを表す構造体
+type Template struct {
+	ID                int64           `json:"id"`
+	RepositoryID      int64           `gorm:"not null;uniqueIndex:idx_template_name" json:"repository_id"`
+	Type              string          `gorm:"not null;uniqueIndex:idx_template_name" json:"type"` // issue/discussion
+	Name              string          `gorm:"not null;uniqueIndex:idx_template_name" json:"name"`
+	Description       string          `json:"description"`
+	Category          string          `gorm:"not null;default:''" json:"category,omitempty"` // Discussionテンプレートの対象カテゴリ
+	TitlePrefix       string          `json:"title_prefix"`
+	Body              string          `gorm:"type:text" json:"body"` // 本文の雛形
+	DefaultLabels     []string        `gorm:"type:text;serializer:json" json:"default_labels"`
+	DefaultAssigneeID int64           `gorm:"not null;default:0" json:"default_assignee_id,omitempty"` // Issueテンプレートのみ
+	Fields            []TemplateField `gorm:"type:text;serializer:json" json:"fields"`
+	CreatorID         int64           `gorm:"not null" json:"creator_id"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+// NewTemplate は新しいTemplateインスタンスを作成する
+func NewTemplate(repositoryID int64, templateType, name string, creatorID int64) *Template {
+	now := time.Now()
+	return &Template{
+		RepositoryID:  repositoryID,
+		Type:          templateType,
+		Name:          name,
+		DefaultLabels: []string{},
+		Fields:        []TemplateField{},
+		CreatorID:     creatorID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// Validate はテンプレートの設定を検証する
+func (t *Template) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("template name is required")
+	}
+	switch t.Type {
+	case TemplateTypeIssue:
+		if t.Category != "" {
+			return errors.New("category can only be set on discussion templates")
+		}
+	case TemplateTypeDiscussion:
+		if !isValidCategory(t.Category) {
+			return errors.New("discussion templates require a valid category (general/question/announcement/idea)")
+		}
+		if t.DefaultAssigneeID != 0 {
+			return errors.New("default assignee can only be set on issue templates")
+		}
+	default:
+		return errors.New("invalid template type. Must be 'issue' or 'discussion'")
+	}
+
+	seen := make(map[string]bool, len(t.Fields))
+	for _, field := range t.Fields {
+		if !templateFieldIDPattern.MatchString(field.ID) {
+			return fmt.Errorf("invalid field id %q", field.ID)
+		}
+		if seen[field.ID] {
+			return fmt.Errorf("duplicate field id %q", field.ID)
+		}
+		seen[field.ID] = true
+		if strings.TrimSpace(field.Label) == "" {
+			return fmt.Errorf("field %q requires a label", field.ID)
+		}
+		switch field.Type {
+		case TemplateFieldText, TemplateFieldCheckbox:
+			if len(field.Options) > 0 {
+				return fmt.Errorf("field %q of type %s cannot have options", field.ID, field.Type)
+			}
+		case TemplateFieldDropdown:
+			if len(field.Options) == 0 {
+				return fmt.Errorf("dropdown field %q requires options", field.ID)
+			}
+		default:
+			return fmt.Errorf("invalid type %q for field %q. Must be text, dropdown or checkbox", field.Type, field.ID)
+		}
+	}
+	return nil
+}
+
+// ValidateSubmission はフォームの送信内容を検証し、項目IDごとのエラーを返す（問題がない場合は空）
+// text・dropdownは文字列、checkboxは真偽値で送信します
+func (t *Template) ValidateSubmission(values map[string]interface{}) map[string]string {
+	errs := map[string]string{}
+	fields := make(map[string]TemplateField, len(t.Fields))
+	for _, field := range t.Fields {
+		fields[field.ID] = field
+	}
+	for id := range values {
+		if _, ok := fields[id]; !ok {
+			errs[id] = "unknown field"
+		}
+	}
+
+	for _, field := range t.Fields {
+		value, ok := values[field.ID]
+		if !ok || value == nil {
+			if field.Required {
+				errs[field.ID] = "this field is required"
+			}
+			continue
+		}
+
+		switch field.Type {
+		case TemplateFieldCheckbox:
+			checked, isBool := value.(bool)
+			if !isBool {
+				errs[field.ID] = "must be a boolean"
+			} else if field.Required && !checked {
+				errs[field.ID] = "this field must be checked"
+			}
+		default:
+			text, isString := value.(string)
+			switch {
+			case !isString:
+				errs[field.ID] = "must be a string"
+			case strings.TrimSpace(text) == "":
+				if field.Required {
+					errs[field.ID] = "this field is required"
+				}
+			case field.Type == TemplateFieldDropdown && !containsString(field.Options, text):
+				errs[field.ID] = fmt.Sprintf("must be one of %s", strings.Join(field.Options, ", "))
+			}
+		}
+	}
+	return errs
+}
+
+// ApplyTitle はタイトルにテンプレートの接頭辞を付ける（既に付いている場合はそのまま）
+func (t *Template) ApplyTitle(title string) string {
+	if t.TitlePrefix == "" || strings.HasPrefix(title, t.TitlePrefix) {
+		return title
+	}
+	return t.TitlePrefix + title
+}
+
+// RenderBody は本文（空の場合は雛形）の後にフォームの送信内容を見出しごとに追記した本文を作成する
+func (t *Template) RenderBody(body string, values map[string]interface{}) string {
+	if strings.TrimSpace(body) == "" {
+		body = t.Body
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(body, "\r\n"))
+	for _, field := range t.Fields {
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString("### " + field.Label + "\n\n")
+
+		answer := "_No response_"
+		switch value := values[field.ID].(type) {
+		case bool:
+			answer = "No"
+			if value {
+				answer = "Yes"
+			}
+		case string:
+			if strings.TrimSpace(value) != "" {
+				answer = strings.TrimSpace(value)
+			}
+		}
+		b.WriteString(answer)
+	}
+	return b.String()
+}
+
+// containsString はスライスに文字列が含まれるかどうかを返す
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// AutoMigrateTemplate はTemplateテーブルを作成・更新します
+func AutoMigrateTemplate(db *gorm.DB) error {
+	return db.AutoMigrate(&Template{})
+}
@@ -0,0 +1,148 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplate_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(template *models.Template)
+		category string
+		wantErr  bool
+	}{
+		{name: "有効なIssueテンプレート", modify: func(template *models.Template) {}},
+		{name: "名前が空", modify: func(template *models.Template) { template.Name = " " }, wantErr: true},
+		{name: "不正な種類", modify: func(template *models.Template) { template.Type = "pull_request" }, wantErr: true},
+		{name: "Issueテンプレートにカテゴリ", modify: func(template *models.Template) { template.Category = "question" }, wantErr: true},
+		{name: "有効なDiscussionテンプレート", modify: func(template *models.Template) {
+			template.Type = models.TemplateTypeDiscussion
+			template.Category = "question"
+		}},
+		{name: "Discussionテンプレートのカテゴリが不正", modify: func(template *models.Template) {
+			template.Type = models.TemplateTypeDiscussion
+			template.Category = "bug"
+		}, wantErr: true},
+		{name: "Discussionテンプレートに既定の担当者", modify: func(template *models.Template) {
+			template.Type = models.TemplateTypeDiscussion
+			template.Category = "idea"
+			template.DefaultAssigneeID = 2
+		}, wantErr: true},
+		{name: "項目IDが不正", modify: func(template *models.Template) {
+			template.Fields = []models.TemplateField{{ID: "Steps To", Type: models.TemplateFieldText, Label: "Steps"}}
+		}, wantErr: true},
+		{name: "項目IDが重複", modify: func(template *models.Template) {
+			template.Fields = []models.TemplateField{
+				{ID: "steps", Type: models.TemplateFieldText, Label: "Steps"},
+				{ID: "steps", Type: models.TemplateFieldText, Label: "Steps again"},
+			}
+		}, wantErr: true},
+		{name: "選択肢のないドロップダウン", modify: func(template *models.Template) {
+			template.Fields = []models.TemplateField{{ID: "os", Type: models.TemplateFieldDropdown, Label: "OS"}}
+		}, wantErr: true},
+		{name: "選択肢のあるテキスト", modify: func(template *models.Template) {
+			template.Fields = []models.TemplateField{{ID: "steps", Type: models.TemplateFieldText, Label: "Steps", Options: []string{"a"}}}
+		}, wantErr: true},
+		{name: "不正な項目の種類", modify: func(template *models.Template) {
+			template.Fields = []models.TemplateField{{ID: "date", Type: "date", Label: "Date"}}
+		}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := models.NewTemplate(1, models.TemplateTypeIssue, "Bug report", 1)
+			tt.modify(template)
+			err := template.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTemplate_ValidateSubmission(t *testing.T) {
+	template := models.NewTemplate(1, models.TemplateTypeIssue, "Bug report", 1)
+	template.Fields = []models.TemplateField{
+		{ID: "steps", Type: models.TemplateFieldText, Label: "Steps to reproduce", Required: true},
+		{ID: "os", Type: models.TemplateFieldDropdown, Label: "OS", Options: []string{"Linux", "macOS"}},
+		{ID: "agree", Type: models.TemplateFieldCheckbox, Label: "I searched existing issues", Required: true},
+	}
+
+	tests := []struct {
+		name       string
+		values     map[string]interface{}
+		wantFields []string
+	}{
+		{name: "必須項目のみ", values: map[string]interface{}{"steps": "1. open", "agree": true}},
+		{name: "すべての項目", values: map[string]interface{}{"steps": "1. open", "os": "Linux", "agree": true}},
+		{name: "未送信", values: nil, wantFields: []string{"steps", "agree"}},
+		{name: "必須のテキストが空白", values: map[string]interface{}{"steps": "  ", "agree": true}, wantFields: []string{"steps"}},
+		{name: "必須のチェックがない", values: map[string]interface{}{"steps": "1. open", "agree": false}, wantFields: []string{"agree"}},
+		{name: "選択肢にない値", values: map[string]interface{}{"steps": "1. open", "os": "Windows", "agree": true}, wantFields: []string{"os"}},
+		{name: "型が不正", values: map[string]interface{}{"steps": 1.0, "agree": "yes"}, wantFields: []string{"steps", "agree"}},
+		{name: "未定義の項目", values: map[string]interface{}{"steps": "1. open", "agree": true, "extra": "x"}, wantFields: []string{"extra"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := template.ValidateSubmission(tt.values)
+			assert.Len(t, errs, len(tt.wantFields))
+			for _, id := range tt.wantFields {
+				assert.Contains(t, errs, id)
+			}
+		})
+	}
+}
+
+func TestTemplate_ApplyTitle(t *testing.T) {
+	template := models.NewTemplate(1, models.TemplateTypeIssue, "Bug report", 1)
+	assert.Equal(t, "crash", template.ApplyTitle("crash"))
+
+	template.TitlePrefix = "[Bug] "
+	assert.Equal(t, "[Bug] crash", template.ApplyTitle("crash"))
+	assert.Equal(t, "[Bug] crash", template.ApplyTitle("[Bug] crash"))
+}
+
+func TestTemplate_RenderBody(t *testing.T) {
+	template := models.NewTemplate(1, models.TemplateTypeIssue, "Bug report", 1)
+	template.Body = "Describe the bug.\n"
+	template.Fields = []models.TemplateField{
+		{ID: "steps", Type: models.TemplateFieldText, Label: "Steps"},
+		{ID: "os", Type: models.TemplateFieldDropdown, Label: "OS", Options: []string{"Linux"}},
+		{ID: "agree", Type: models.TemplateFieldCheckbox, Label: "Searched"},
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		values map[string]interface{}
+		want   string
+	}{
+		{
+			name:   "本文が空の場合は雛形を使用",
+			values: map[string]interface{}{"steps": " 1. open ", "os": "Linux", "agree": true},
+			want:   "Describe the bug.\n\n### Steps\n\n1. open\n\n### OS\n\nLinux\n\n### Searched\n\nYes",
+		},
+		{
+			name:   "入力された本文と未回答の項目",
+			body:   "It crashes.",
+			values: map[string]interface{}{"agree": false},
+			want:   "It crashes.\n\n### Steps\n\n_No response_\n\n### OS\n\n_No response_\n\n### Searched\n\nNo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, template.RenderBody(tt.body, tt.values))
+		})
+	}
+
+	// 項目も雛形もない場合は本文のまま
+	empty := models.NewTemplate(1, models.TemplateTypeIssue, "Blank", 1)
+	assert.Equal(t, "text", empty.RenderBody("text\n", nil))
+}
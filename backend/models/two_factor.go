@@ -0,0 +1,168 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// TOTPDigits はTOTPのコードの桁数
+	TOTPDigits = 6
+	// TOTPPeriod はTOTPのコードが切り替わる間隔（秒）
+	TOTPPeriod = 30
+	// RecoveryCodeCount は一度に発行するリカバリーコードの数
+	RecoveryCodeCount = 10
+	// totpSkewSteps は時刻のずれを許容する前後のステップ数
+	totpSkewSteps = 1
+)
+
+// UserTwoFactor はユーザーのTOTPによる二要素認証の設定を表す構造体
+// 登録の開始時に無効な状態で作成し、認証アプリのコードで確認してから有効にします
+type UserTwoFactor struct {
+	UserID       int64      `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	Secret       string     `gorm:"not null" json:"-"` // base32でエンコードしたTOTPの共有鍵
+	Enabled      bool       `gorm:"not null;default:false" json:"enabled"`
+	EnabledAt    *time.Time `json:"enabled_at"`
+	LastUsedStep int64      `gorm:"not null;default:0" json:"-"` // 最後に使用したコードのステップ（同じコードの再利用を防ぐ）
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// NewUserTwoFactor は無効な状態の新しいUserTwoFactorインスタンスを作成する
+func NewUserTwoFactor(userID int64, secret string) *UserTwoFactor {
+	now := time.Now()
+	return &UserTwoFactor{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Enable は二要素認証を有効にする
+func (f *UserTwoFactor) Enable() {
+	now := time.Now()
+	f.Enabled = true
+	f.EnabledAt = &now
+	f.UpdatedAt = now
+}
+
+// RecoveryCode は認証アプリを使用できない場合に一度だけ使用できるリカバリーコードを表す構造体
+// コードはハッシュ値のみを保存し、発行時のレスポンスでのみ平文を返します
+type RecoveryCode struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `gorm:"not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// NewRecoveryCode は平文のコードから新しいRecoveryCodeインスタンスを作成する
+func NewRecoveryCode(userID int64, code string) *RecoveryCode {
+	return &RecoveryCode{
+		UserID:    userID,
+		CodeHash:  HashRecoveryCode(code),
+		CreatedAt: time.Now(),
+	}
+}
+
+// HashRecoveryCode はリカバリーコードの保存・検索用のハッシュ値を返す
+// 大文字・小文字、ハイフン、空白の違いは無視します
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsTOTPCode はコードがTOTPのコードの形式（6桁の数字）かどうかを判定する
+func IsTOTPCode(code string) bool {
+	if len(code) != TOTPDigits {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// TOTPStep は時刻に対応するTOTPのステップを返す
+func TOTPStep(t time.Time) int64 {
+	return t.Unix() / TOTPPeriod
+}
+
+// GenerateTOTPCode はRFC 6238（HMAC-SHA1）に従ってステップに対応するTOTPのコードを生成する
+func GenerateTOTPCode(secret string, step int64) (string, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// 動的切り捨て（RFC 4226 5.3）
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", TOTPDigits, value%1000000), nil
+}
+
+// MatchTOTPCode はコードが現在時刻の前後のステップのいずれかのコードと一致するかどうかを判定し、一致したステップを返す
+func MatchTOTPCode(secret, code string, now time.Time) (int64, bool) {
+	if !IsTOTPCode(code) {
+		return 0, false
+	}
+	current := TOTPStep(now)
+	for step := current - totpSkewSteps; step <= current+totpSkewSteps; step++ {
+		expected, err := GenerateTOTPCode(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// TOTPURI は認証アプリに登録するための otpauth:// 形式のURIを作成する
+func TOTPURI(issuer, accountName, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(TOTPDigits))
+	query.Set("period", fmt.Sprint(TOTPPeriod))
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(accountName)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// decodeTOTPSecret はbase32でエンコードされた共有鍵をデコードする（パディングの有無と小文字を許容）
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	normalized := strings.TrimRight(strings.ToUpper(strings.ReplaceAll(secret, " ", "")), "=")
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("invalid totp secret: %w", err)
+	}
+	return key, nil
+}
+
+// AutoMigrateTwoFactor は二要素認証の設定とリカバリーコードのテーブルを作成・更新します
+func AutoMigrateTwoFactor(db *gorm.DB) error {
+	return db.AutoMigrate(&UserTwoFactor{}, &RecoveryCode{})
+}
@@ -0,0 +1,108 @@
+package models_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// rfc6238Secret は RFC 6238 Appendix B の共有鍵 "12345678901234567890" をbase32でエンコードしたもの
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateTOTPCode(t *testing.T) {
+	tests := []struct {
+		name string
+		unix int64
+		want string
+	}{
+		{name: "T=59", unix: 59, want: "287082"},
+		{name: "T=1111111109", unix: 1111111109, want: "081804"},
+		{name: "T=1234567890", unix: 1234567890, want: "005924"},
+		{name: "T=2000000000", unix: 2000000000, want: "279037"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := models.GenerateTOTPCode(rfc6238Secret, models.TOTPStep(time.Unix(tt.unix, 0)))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, code)
+		})
+	}
+
+	// 小文字・パディング付きの共有鍵も受け付ける
+	code, err := models.GenerateTOTPCode(strings.ToLower(rfc6238Secret)+"====", models.TOTPStep(time.Unix(59, 0)))
+	assert.NoError(t, err)
+	assert.Equal(t, "287082", code)
+
+	_, err = models.GenerateTOTPCode("not base32!", 1)
+	assert.Error(t, err)
+}
+
+func TestMatchTOTPCode(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	step := models.TOTPStep(now)
+	code := func(step int64) string {
+		c, _ := models.GenerateTOTPCode(rfc6238Secret, step)
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "現在のステップ", code: code(step), wantStep: step, wantOK: true},
+		{name: "1つ前のステップ", code: code(step - 1), wantStep: step - 1, wantOK: true},
+		{name: "1つ後のステップ", code: code(step + 1), wantStep: step + 1, wantOK: true},
+		{name: "2つ前のステップ", code: code(step - 2), wantOK: false},
+		{name: "数字以外", code: "abcdef", wantOK: false},
+		{name: "桁数の不足", code: "12345", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, ok := models.MatchTOTPCode(rfc6238Secret, tt.code, now)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.wantStep, gotStep)
+			}
+		})
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := models.TOTPURI("TicketHub", "alice smith", rfc6238Secret)
+	assert.Equal(t, "otpauth://totp/TicketHub:alice%20smith?algorithm=SHA1&digits=6&issuer=TicketHub&period=30&secret="+rfc6238Secret, uri)
+}
+
+func TestHashRecoveryCode(t *testing.T) {
+	hash := models.HashRecoveryCode("abcd2345-efgh6789")
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, models.HashRecoveryCode("ABCD2345EFGH6789"), "大文字・ハイフンなし")
+	assert.Equal(t, hash, models.HashRecoveryCode(" abcd2345 efgh6789 "), "空白区切り")
+	assert.NotEqual(t, hash, models.HashRecoveryCode("abcd2345-efgh6788"))
+
+	code := models.NewRecoveryCode(1, "abcd2345-efgh6789")
+	assert.Equal(t, hash, code.CodeHash)
+	assert.Nil(t, code.UsedAt)
+}
+
+func TestUserTwoFactor_Enable(t *testing.T) {
+	twoFactor := models.NewUserTwoFactor(1, rfc6238Secret)
+	assert.False(t, twoFactor.Enabled)
+	assert.Nil(t, twoFactor.EnabledAt)
+
+	twoFactor.Enable()
+	assert.True(t, twoFactor.Enabled)
+	assert.NotNil(t, twoFactor.EnabledAt)
+}
+
+func TestIsTOTPCode(t *testing.T) {
+	assert.True(t, models.IsTOTPCode("012345"))
+	assert.False(t, models.IsTOTPCode("01234"))
+	assert.False(t, models.IsTOTPCode("abcd2345-efgh6789"))
+}
@@ -6,17 +6,18 @@ import (
 
 // User はユーザー情報を表す構造体
 type User struct {
-	ID        int64     `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	Password  string    `json:"-"` // パスワードはJSONに含めない
-	FullName  string    `json:"full_name"`
-	AvatarURL string    `json:"avatar_url"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	LastLogin time.Time `json:"last_login,omitempty"`
-	IsAdmin   bool      `json:"is_admin"`
-	IsActive  bool      `json:"is_active"`
+	ID              int64      `json:"id"`
+	Username        string     `json:"username"`
+	Email           string     `json:"email"`
+	Password        string     `json:"-"` // パスワードはJSONに含めない
+	FullName        string     `json:"full_name"`
+	AvatarURL       string     `json:"avatar_url"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	LastLogin       time.Time  `json:"last_login,omitempty"`
+	IsAdmin         bool       `json:"is_admin"`
+	IsActive        bool       `json:"is_active"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"` // メールアドレスの確認日時（未確認の場合はnil）
 }
 
 // NewUser は新しいUserインスタンスを作成する
@@ -70,6 +71,18 @@ func (u *User) SetAdmin(isAdmin bool) {
 	u.UpdatedAt = time.Now()
 }
 
+// IsEmailVerified はメールアドレスが確認済みかどうかを判定する
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// MarkEmailVerified はメールアドレスを確認済みに設定する
+func (u *User) MarkEmailVerified() {
+	now := time.Now()
+	u.EmailVerifiedAt = &now
+	u.UpdatedAt = now
+}
+
 // RecordLogin はログイン日時を記録する
 func (u *User) RecordLogin() {
 	u.LastLogin = time.Now()
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
@@ -58,3 +59,18 @@ func (us *UserSettings) UpdateNotificationTypes(types string) {
 	us.NotificationTypes = types
 	us.UpdatedAt = time.Now()
 }
+
+// AcceptsNotificationType は指定した通知タイプを受け取る設定かどうかを判定する
+// 未設定の場合はデフォルト（all）として扱う
+func (us *UserSettings) AcceptsNotificationType(notificationType string) bool {
+	if strings.TrimSpace(us.NotificationTypes) == "" {
+		return true
+	}
+	for _, t := range strings.Split(us.NotificationTypes, ",") {
+		t = strings.TrimSpace(t)
+		if t == "all" || t == notificationType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserSettings_AcceptsNotificationType(t *testing.T) {
+	tests := []struct {
+		name              string
+		notificationTypes string
+		notificationType  string
+		want              bool
+	}{
+		{name: "未設定はすべて受け取る", notificationTypes: "", notificationType: models.NotificationTypeComment, want: true},
+		{name: "all", notificationTypes: "all", notificationType: models.NotificationTypeUpdate, want: true},
+		{name: "含まれる種類", notificationTypes: "mention,comment", notificationType: models.NotificationTypeComment, want: true},
+		{name: "空白を含む指定", notificationTypes: " mention , assign ", notificationType: models.NotificationTypeAssign, want: true},
+		{name: "含まれない種類", notificationTypes: "mention,comment", notificationType: models.NotificationTypeAssign, want: false},
+		{name: "部分一致は受け取らない", notificationTypes: "comments", notificationType: models.NotificationTypeComment, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := &models.UserSettings{NotificationTypes: tt.notificationTypes}
+			assert.Equal(t, tt.want, settings.AcceptsNotificationType(tt.notificationType))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"gorm.io/gorm"
+)
+
+type bodyRevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewBodyRevisionRepository は新しいBodyRevisionRepositoryを作成します
+func NewBodyRevisionRepository(db *gorm.DB) *bodyRevisionRepository {
+	return &bodyRevisionRepository{db: db}
+}
+
+func (r *bodyRevisionRepository) Create(ctx context.Context, revision *models.BodyRevision) error {
+	return r.db.WithContext(ctx).Create(revision).Error
+}
+
+func (r *bodyRevisionRepository) GetByID(ctx context.Context, id int64) (*models.BodyRevision, error) {
+	var revision models.BodyRevision
+	err := r.db.WithContext(ctx).First(&revision, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *bodyRevisionRepository) ListByTarget(ctx context.Context, targetType string, targetID int64) ([]*models.BodyRevision, error) {
+	var revisions []*models.BodyRevision
+	err := r.db.WithContext(ctx).
+		Select("body_revisions.*, users.username AS editor_username").
+		Joins("LEFT JOIN users ON users.id = body_revisions.editor_id").
+		Where("body_revisions.target_type = ? AND body_revisions.target_id = ?", targetType, targetID).
+		Order("body_revisions.created_at ASC, body_revisions.id ASC").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *bodyRevisionRepository) Update(ctx context.Context, revision *models.BodyRevision) error {
+	return r.db.WithContext(ctx).Save(revision).Error
+}
+
+func (r *bodyRevisionRepository) DeleteByTarget(ctx context.Context, targetType string, targetID int64) error {
+	return r.db.WithContext(ctx).
+		Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Delete(&models.BodyRevision{}).Error
+}
+
+func (r *bodyRevisionRepository) MoveTarget(ctx context.Context, fromType string, fromID int64, toType string, toID int64) error {
+	return r.db.WithContext(ctx).Model(&models.BodyRevision{}).
+		Where("target_type = ? AND target_id = ?", fromType, fromID).
+		UpdateColumns(map[string]interface{}{"target_type": toType, "target_id": toID}).Error
+}
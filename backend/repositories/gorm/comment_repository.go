@@ -42,6 +42,16 @@ func (r *commentRepository) ListByTarget(ctx context.Context, targetID int64, ta
 	return comments, int(total), err
 }
 
+func (r *commentRepository) ListAllByTarget(ctx context.Context, targetID int64, targetType string) ([]*models.Comment, error) {
+	var comments []*models.Comment
+	err := r.db.WithContext(ctx).
+		Where("target_id = ? AND (type = ? OR (type = 'reply' AND parent_comment_id IN (?)))", targetID, targetType,
+			r.db.Model(&models.Comment{}).Select("id").Where("target_id = ? AND type = ?", targetID, targetType)).
+		Order("created_at ASC, id ASC").
+		Find(&comments).Error
+	return comments, err
+}
+
 func (r *commentRepository) ListReplies(ctx context.Context, parentCommentID int64, page, limit int) ([]*models.Comment, int, error) {
 	var comments []*models.Comment
 	var total int64
@@ -106,3 +116,61 @@ func (r *commentRepository) CountComments(ctx context.Context) (int64, error) {
 	}
 	return count, nil
 }
+
+// ListCommenterIDs はターゲットにコメント（返信を含む）したユーザーIDの一覧を取得します
+func (r *commentRepository) ListCommenterIDs(ctx context.Context, targetID int64, targetType string) ([]int64, error) {
+	var ids []int64
+
+	// 返信はtypeが"reply"になるため、親コメント経由でターゲットを判定する
+	parentIDs := r.db.Model(&models.Comment{}).Select("id").Where("type = ? AND target_id = ?", targetType, targetID)
+	err := r.db.WithContext(ctx).Model(&models.Comment{}).
+		Where("type = ? AND target_id = ?", targetType, targetID).
+		Or("type = ? AND parent_comment_id IN (?)", "reply", parentIDs).
+		Distinct().
+		Pluck("creator_id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commenter ids: %w", err)
+	}
+	return ids, nil
+}
+
+// MoveTarget はターゲットのコメントを返信のツリーごと別のターゲットに移動します
+// 返信は種類（reply）と親コメントを維持し、ターゲットIDのみを書き換えます
+func (r *commentRepository) MoveTarget(ctx context.Context, fromType string, fromID int64, toType string, toID int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var parentIDs []int64
+		if err := tx.Model(&models.Comment{}).
+			Where("type = ? AND target_id = ?", fromType, fromID).
+			Pluck("id", &parentIDs).Error; err != nil {
+			return fmt.Errorf("failed to list comments: %w", err)
+		}
+		if len(parentIDs) == 0 {
+			return nil
+		}
+		if err := tx.Model(&models.Comment{}).
+			Where("id IN ?", parentIDs).
+			UpdateColumns(map[string]interface{}{"type": toType, "target_id": toID}).Error; err != nil {
+			return fmt.Errorf("failed to move comments: %w", err)
+		}
+
+		// 返信は親コメントから階層ごとに辿って移動する
+		for len(parentIDs) > 0 {
+			var replyIDs []int64
+			if err := tx.Model(&models.Comment{}).
+				Where("type = ? AND parent_comment_id IN ?", "reply", parentIDs).
+				Pluck("id", &replyIDs).Error; err != nil {
+				return fmt.Errorf("failed to list replies: %w", err)
+			}
+			if len(replyIDs) == 0 {
+				break
+			}
+			if err := tx.Model(&models.Comment{}).
+				Where("id IN ?", replyIDs).
+				UpdateColumn("target_id", toID).Error; err != nil {
+				return fmt.Errorf("failed to move replies: %w", err)
+			}
+			parentIDs = replyIDs
+		}
+		return nil
+	})
+}
@@ -0,0 +1,104 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"gorm.io/gorm"
+)
+
+// conversionRepository はGORMを使用したIssueとDiscussionの相互変換の実装
+// 各リポジトリの書き込みを同じトランザクションで実行します
+type conversionRepository struct {
+	db *gorm.DB
+}
+
+// NewConversionRepository は新しいConversionRepositoryを作成します
+func NewConversionRepository(db *gorm.DB) *conversionRepository {
+	return &conversionRepository{db: db}
+}
+
+func (r *conversionRepository) IssueToDiscussion(ctx context.Context, issue *models.Issue, discussion *models.Discussion, redirect *models.Redirect) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 変換後のDiscussionの作成
+		if err := NewDiscussionRepository(tx).Create(ctx, discussion); err != nil {
+			return fmt.Errorf("failed to create discussion: %w", err)
+		}
+
+		// コメント・リアクション・編集履歴の移動
+		if err := moveConvertedContent(ctx, tx, models.RedirectTypeIssue, issue.ID, models.RedirectTypeDiscussion, discussion.ID); err != nil {
+			return err
+		}
+
+		// 他のIssueとの関連の削除
+		if err := NewIssueLinkRepository(tx).DeleteByIssue(ctx, issue.ID); err != nil {
+			return fmt.Errorf("failed to remove issue links: %w", err)
+		}
+
+		// リダイレクトの記録
+		redirect.TargetID = discussion.ID
+		if err := recordRedirect(ctx, tx, redirect); err != nil {
+			return err
+		}
+
+		// 変換前のIssueの削除（番号を引き継ぐため物理削除）
+		if err := NewIssueRepository(tx).Purge(ctx, issue.ID); err != nil {
+			return fmt.Errorf("failed to delete converted issue: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *conversionRepository) DiscussionToIssue(ctx context.Context, discussion *models.Discussion, issue *models.Issue, redirect *models.Redirect) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 変換後のIssueの作成
+		if err := NewIssueRepository(tx).Create(ctx, issue); err != nil {
+			return fmt.Errorf("failed to create issue: %w", err)
+		}
+
+		// コメント・リアクション・編集履歴の移動
+		if err := moveConvertedContent(ctx, tx, models.RedirectTypeDiscussion, discussion.ID, models.RedirectTypeIssue, issue.ID); err != nil {
+			return err
+		}
+
+		// リダイレクトの記録
+		redirect.TargetID = issue.ID
+		if err := recordRedirect(ctx, tx, redirect); err != nil {
+			return err
+		}
+
+		// 変換前のDiscussionの削除
+		if err := NewDiscussionRepository(tx).Delete(ctx, discussion.ID); err != nil {
+			return fmt.Errorf("failed to delete converted discussion: %w", err)
+		}
+		return nil
+	})
+}
+
+// moveConvertedContent はコメントのツリー・本文のリアクション・編集履歴を変換後のリソースに移動します
+func moveConvertedContent(ctx context.Context, tx *gorm.DB, fromType string, fromID int64, toType string, toID int64) error {
+	if err := NewCommentRepository(tx).MoveTarget(ctx, fromType, fromID, toType, toID); err != nil {
+		return fmt.Errorf("failed to move comments: %w", err)
+	}
+	if err := NewReactionRepository(tx).MoveTarget(ctx, fromType, fromID, toType, toID); err != nil {
+		return fmt.Errorf("failed to move reactions: %w", err)
+	}
+	if err := NewBodyRevisionRepository(tx).MoveTarget(ctx, fromType, fromID, toType, toID); err != nil {
+		return fmt.Errorf("failed to move revisions: %w", err)
+	}
+	return nil
+}
+
+// recordRedirect は変換前のリソースから変換後のリソースへのリダイレクトを記録します
+// 変換前のリソースを指していた以前のリダイレクトも変換後のリソースを指すように更新します
+func recordRedirect(ctx context.Context, tx *gorm.DB, redirect *models.Redirect) error {
+	redirectRepo := NewRedirectRepository(tx)
+	if err := redirectRepo.Retarget(ctx, redirect.SourceType, redirect.SourceID, redirect.TargetType, redirect.TargetID); err != nil {
+		return fmt.Errorf("failed to update redirects: %w", err)
+	}
+	if err := redirectRepo.Create(ctx, redirect); err != nil {
+		return fmt.Errorf("failed to create redirect: %w", err)
+	}
+	return nil
+}
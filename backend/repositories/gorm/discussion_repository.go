@@ -2,7 +2,9 @@ package gorm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/shimauma0312/module-tickethub/backend/models"
 	"gorm.io/gorm"
@@ -17,13 +19,48 @@ func NewDiscussionRepository(db *gorm.DB) *discussionRepository {
 }
 
 func (r *discussionRepository) Create(ctx context.Context, discussion *models.Discussion) error {
-	return r.db.WithContext(ctx).Create(discussion).Error
+	if discussion.RepositoryID == 0 {
+		return errors.New("discussion must belong to a repository")
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// リポジトリ内の番号の採番
+		if discussion.Number == 0 {
+			number, err := nextRepositoryNumber(tx, discussion.RepositoryID)
+			if err != nil {
+				return err
+			}
+			discussion.Number = number
+		}
+		if err := tx.Create(discussion).Error; err != nil {
+			return err
+		}
+		return replaceDiscussionLabels(tx, discussion)
+	})
 }
 
 func (r *discussionRepository) GetByID(ctx context.Context, id int64) (*models.Discussion, error) {
 	var discussion models.Discussion
 	err := r.db.WithContext(ctx).First(&discussion, id).Error
-	return &discussion, err
+	if err != nil {
+		return &discussion, err
+	}
+	return &discussion, attachDiscussionLabels(r.db.WithContext(ctx), []*models.Discussion{&discussion})
+}
+
+func (r *discussionRepository) GetByNumber(ctx context.Context, repositoryID, number int64) (*models.Discussion, error) {
+	var discussion models.Discussion
+	err := r.db.WithContext(ctx).Where("repository_id = ? AND number = ?", repositoryID, number).First(&discussion).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := attachDiscussionLabels(r.db.WithContext(ctx), []*models.Discussion{&discussion}); err != nil {
+		return nil, err
+	}
+	return &discussion, nil
 }
 
 func (r *discussionRepository) List(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*models.Discussion, int, error) {
@@ -31,8 +68,17 @@ func (r *discussionRepository) List(ctx context.Context, filter map[string]inter
 	var total int64
 
 	query := r.db.WithContext(ctx).Model(&models.Discussion{})
-	if filter != nil {
-		query = query.Where(filter)
+	conditions := make(map[string]interface{}, len(filter))
+	for k, v := range filter {
+		if k == "label" {
+			// ラベルでフィルタリング（サブクエリ使用）
+			query = query.Where("id IN (SELECT dl.discussion_id FROM discussion_labels dl JOIN labels l ON l.id = dl.label_id WHERE l.name = ?)", v)
+			continue
+		}
+		conditions[k] = v
+	}
+	if len(conditions) > 0 {
+		query = query.Where(conditions)
 	}
 
 	err := query.Count(&total).Error
@@ -42,26 +88,48 @@ func (r *discussionRepository) List(ctx context.Context, filter map[string]inter
 
 	offset := (page - 1) * limit
 	err = query.Offset(offset).Limit(limit).Find(&discussions).Error
-	return discussions, int(total), err
+	if err != nil {
+		return nil, 0, err
+	}
+	return discussions, int(total), attachDiscussionLabels(r.db.WithContext(ctx), discussions)
 }
 
 func (r *discussionRepository) Update(ctx context.Context, discussion *models.Discussion) error {
-	return r.db.WithContext(ctx).Save(discussion).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(discussion).Error; err != nil {
+			return err
+		}
+		return replaceDiscussionLabels(tx, discussion)
+	})
 }
 
 func (r *discussionRepository) Delete(ctx context.Context, id int64) error {
 	return r.db.WithContext(ctx).Delete(&models.Discussion{}, id).Error
 }
 
-func (r *discussionRepository) Search(ctx context.Context, queryString string, page, limit int) ([]*models.Discussion, int, error) {
-	// Implement search logic if necessary, or return not implemented
-	// This is a placeholder
+func (r *discussionRepository) Search(ctx context.Context, queryString string, filter map[string]interface{}, page, limit int) ([]*models.Discussion, int, error) {
 	var discussions []*models.Discussion
 	var total int64
-	// Example: r.db.WithContext(ctx).Where("title LIKE ?", "%"+queryString+"%").Find(&discussions)
-	// Count total records for pagination
-	// r.db.WithContext(ctx).Model(&models.Discussion{}).Where("title LIKE ?", "%"+queryString+"%").Count(&total)
-	return discussions, int(total), gorm.ErrNotImplemented // Placeholder
+
+	searchQuery := "%" + strings.Replace(queryString, "%", "\\%", -1) + "%"
+	query := r.db.WithContext(ctx).Model(&models.Discussion{}).
+		Where("title LIKE ? OR body LIKE ?", searchQuery, searchQuery)
+
+	// リポジトリの絞り込み
+	if repositoryID, ok := filter["repository_id"]; ok {
+		query = whereRepository(query, repositoryID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("updated_at DESC").Offset(offset).Limit(limit).Find(&discussions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return discussions, int(total), attachDiscussionLabels(r.db.WithContext(ctx), discussions)
 }
 
 // CountDiscussions は総Discussion数を取得します
@@ -83,3 +151,53 @@ func (r *discussionRepository) CountOpenDiscussions(ctx context.Context) (int64,
 	}
 	return count, nil
 }
+
+// replaceDiscussionLabels はDiscussionのラベルを置き換えます（古いラベルを削除して新しいラベルを挿入）
+func replaceDiscussionLabels(tx *gorm.DB, discussion *models.Discussion) error {
+	if err := tx.Where("discussion_id = ?", discussion.ID).Delete(&models.DiscussionLabel{}).Error; err != nil {
+		return fmt.Errorf("failed to delete old labels: %w", err)
+	}
+
+	labelIDs, err := resolveLabelIDs(tx, discussion.RepositoryID, discussion.Labels, models.LabelTypeDiscussion)
+	if err != nil || len(labelIDs) == 0 {
+		return err
+	}
+
+	labels := make([]models.DiscussionLabel, 0, len(labelIDs))
+	for _, labelID := range labelIDs {
+		labels = append(labels, models.DiscussionLabel{
+			DiscussionID: discussion.ID,
+			LabelID:      labelID,
+		})
+	}
+	if err := tx.Create(&labels).Error; err != nil {
+		return fmt.Errorf("failed to create discussion labels: %w", err)
+	}
+	return nil
+}
+
+// attachDiscussionLabels はDiscussionにラベル名を設定します
+func attachDiscussionLabels(db *gorm.DB, discussions []*models.Discussion) error {
+	if len(discussions) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*models.Discussion, len(discussions))
+	ids := make([]int64, 0, len(discussions))
+	for _, discussion := range discussions {
+		discussion.Labels = []string{}
+		byID[discussion.ID] = discussion
+		ids = append(ids, discussion.ID)
+	}
+
+	var rows []models.DiscussionLabel
+	if err := db.Preload("Label").Where("discussion_id IN ?", ids).Find(&rows).Error; err != nil {
+		return fmt.Errorf("failed to get discussion labels: %w", err)
+	}
+	for _, row := range rows {
+		if row.Label != nil {
+			byID[row.DiscussionID].Labels = append(byID[row.DiscussionID].Labels, row.Label.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"gorm.io/gorm"
+)
+
+type emailVerificationRepository struct {
+	db *gorm.DB
+}
+
+// NewEmailVerificationRepository は新しいEmailVerificationRepositoryを作成します
+func NewEmailVerificationRepository(db *gorm.DB) *emailVerificationRepository {
+	return &emailVerificationRepository{db: db}
+}
+
+func (r *emailVerificationRepository) Create(ctx context.Context, verification *models.EmailVerification) error {
+	return r.db.WithContext(ctx).Create(verification).Error
+}
+
+func (r *emailVerificationRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.EmailVerification, error) {
+	var verification models.EmailVerification
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&verification).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+func (r *emailVerificationRepository) Update(ctx context.Context, verification *models.EmailVerification) error {
+	return r.db.WithContext(ctx).Save(verification).Error
+}
+
+func (r *emailVerificationRepository) ListCreatedSince(ctx context.Context, userID int64, since time.Time) ([]*models.EmailVerification, error) {
+	var verifications []*models.EmailVerification
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Order("created_at DESC").
+		Find(&verifications).Error
+	return verifications, err
+}
+
+func (r *emailVerificationRepository) RevokeAllForUser(ctx context.Context, userID int64) error {
+	return r.db.WithContext(ctx).Model(&models.EmailVerification{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
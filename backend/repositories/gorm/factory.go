@@ -73,11 +73,76 @@ func (f *RepositoryFactory) NewReactionRepository() (repositories.ReactionReposi
 	return NewReactionRepository(f.db), nil
 }
 
+// NewIssueEventRepository はGORM用IssueEventRepositoryを作成します
+func (f *RepositoryFactory) NewIssueEventRepository() (repositories.IssueEventRepository, error) {
+	return NewIssueEventRepository(f.db), nil
+}
+
+// NewIssueLinkRepository はGORM用IssueLinkRepositoryを作成します
+func (f *RepositoryFactory) NewIssueLinkRepository() (repositories.IssueLinkRepository, error) {
+	return NewIssueLinkRepository(f.db), nil
+}
+
+// NewTemplateRepository はGORM用TemplateRepositoryを作成します
+func (f *RepositoryFactory) NewTemplateRepository() (repositories.TemplateRepository, error) {
+	return NewTemplateRepository(f.db), nil
+}
+
+// NewRedirectRepository はGORM用RedirectRepositoryを作成します
+func (f *RepositoryFactory) NewRedirectRepository() (repositories.RedirectRepository, error) {
+	return NewRedirectRepository(f.db), nil
+}
+
+// NewPersonalAccessTokenRepository はGORM用PersonalAccessTokenRepositoryを作成します
+func (f *RepositoryFactory) NewPersonalAccessTokenRepository() (repositories.PersonalAccessTokenRepository, error) {
+	return NewPersonalAccessTokenRepository(f.db), nil
+}
+
+// NewUserIdentityRepository はGORM用UserIdentityRepositoryを作成します
+func (f *RepositoryFactory) NewUserIdentityRepository() (repositories.UserIdentityRepository, error) {
+	return NewUserIdentityRepository(f.db), nil
+}
+
+// NewOIDCLoginStateRepository はGORM用OIDCLoginStateRepositoryを作成します
+func (f *RepositoryFactory) NewOIDCLoginStateRepository() (repositories.OIDCLoginStateRepository, error) {
+	return NewOIDCLoginStateRepository(f.db), nil
+}
+
+// NewTwoFactorRepository はGORM用TwoFactorRepositoryを作成します
+func (f *RepositoryFactory) NewTwoFactorRepository() (repositories.TwoFactorRepository, error) {
+	return NewTwoFactorRepository(f.db), nil
+}
+
+// NewEmailVerificationRepository はGORM用EmailVerificationRepositoryを作成します
+func (f *RepositoryFactory) NewEmailVerificationRepository() (repositories.EmailVerificationRepository, error) {
+	return NewEmailVerificationRepository(f.db), nil
+}
+
+// NewLoginThrottleRepository はGORM用LoginThrottleRepositoryを作成します
+func (f *RepositoryFactory) NewLoginThrottleRepository() (repositories.LoginThrottleRepository, error) {
+	return NewLoginThrottleRepository(f.db), nil
+}
+
+// NewBodyRevisionRepository はGORM用BodyRevisionRepositoryを作成します
+func (f *RepositoryFactory) NewBodyRevisionRepository() (repositories.BodyRevisionRepository, error) {
+	return NewBodyRevisionRepository(f.db), nil
+}
+
 // NewNotificationRepository はGORM用NotificationRepositoryを作成します
 func (f *RepositoryFactory) NewNotificationRepository() (repositories.NotificationRepository, error) {
 	return NewNotificationRepository(f.db), nil
 }
 
+// NewMentionRepository はGORM用MentionRepositoryを作成します
+func (f *RepositoryFactory) NewMentionRepository() (repositories.MentionRepository, error) {
+	return NewMentionRepository(f.db), nil
+}
+
+// NewSavedSearchRepository はGORM用SavedSearchRepositoryを作成します
+func (f *RepositoryFactory) NewSavedSearchRepository() (repositories.SavedSearchRepository, error) {
+	return NewSavedSearchRepository(f.db), nil
+}
+
 // NewPushSubscriptionRepository はGORM用PushSubscriptionRepositoryを作成します
 func (f *RepositoryFactory) NewPushSubscriptionRepository() (repositories.PushSubscriptionRepository, error) {
 	return NewPushSubscriptionRepository(f.db), nil
@@ -108,6 +173,11 @@ func (f *RepositoryFactory) NewRepositoryRepository() (repositories.RepositoryRe
 	return NewRepositoryRepository(f.db), nil
 }
 
+// NewRepositoryMemberRepository はGORM用RepositoryMemberRepositoryを作成します
+func (f *RepositoryFactory) NewRepositoryMemberRepository() (repositories.RepositoryMemberRepository, error) {
+	return NewRepositoryMemberRepository(f.db), nil
+}
+
 // Close はデータベース接続をクローズします
 func (f *RepositoryFactory) Close() error {
 	db, err := f.db.DB()
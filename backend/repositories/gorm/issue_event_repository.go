@@ -0,0 +1,40 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"gorm.io/gorm"
+)
+
+type issueEventRepository struct {
+	db *gorm.DB
+}
+
+// NewIssueEventRepository は新しいIssueEventRepositoryを作成します
+func NewIssueEventRepository(db *gorm.DB) *issueEventRepository {
+	return &issueEventRepository{db: db}
+}
+
+func (r *issueEventRepository) ListByIssue(ctx context.Context, issueID int64) ([]*models.IssueEvent, error) {
+	var events []*models.IssueEvent
+	err := r.db.WithContext(ctx).
+		Select("issue_events.*, users.username AS actor_username").
+		Joins("LEFT JOIN users ON users.id = issue_events.actor_id").
+		Where("issue_events.issue_id = ?", issueID).
+		Order("issue_events.created_at ASC, issue_events.id ASC").
+		Find(&events).Error
+	return events, err
+}
+
+func (r *issueEventRepository) ListByIssues(ctx context.Context, issueIDs []int64, types []models.IssueEventType) ([]*models.IssueEvent, error) {
+	var events []*models.IssueEvent
+	if len(issueIDs) == 0 {
+		return events, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("issue_id IN ? AND type IN ?", issueIDs, types).
+		Order("created_at ASC, id ASC").
+		Find(&events).Error
+	return events, err
+}
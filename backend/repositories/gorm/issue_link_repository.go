@@ -0,0 +1,93 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"gorm.io/gorm"
+)
+
+type issueLinkRepository struct {
+	db *gorm.DB
+}
+
+// NewIssueLinkRepository は新しいIssueLinkRepositoryを作成します
+func NewIssueLinkRepository(db *gorm.DB) *issueLinkRepository {
+	return &issueLinkRepository{db: db}
+}
+
+func (r *issueLinkRepository) Create(ctx context.Context, link *models.IssueLink) error {
+	return r.db.WithContext(ctx).Create(link).Error
+}
+
+func (r *issueLinkRepository) GetByID(ctx context.Context, id int64) (*models.IssueLink, error) {
+	var link models.IssueLink
+	err := r.db.WithContext(ctx).First(&link, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *issueLinkRepository) ListByIssue(ctx context.Context, issueID int64) ([]*models.IssueLink, error) {
+	var links []*models.IssueLink
+	err := r.db.WithContext(ctx).
+		Where("source_issue_id = ? OR target_issue_id = ?", issueID, issueID).
+		Order("created_at ASC, id ASC").
+		Find(&links).Error
+	return links, err
+}
+
+func (r *issueLinkRepository) ListByRepository(ctx context.Context, repositoryID int64, linkType models.IssueLinkType) ([]*models.IssueLink, error) {
+	var links []*models.IssueLink
+	err := r.db.WithContext(ctx).
+		Select("issue_links.*").
+		Joins("JOIN issues ON issues.id = issue_links.source_issue_id AND issues.deleted_at IS NULL").
+		Where("issues.repository_id = ? AND issue_links.type = ?", repositoryID, linkType).
+		Find(&links).Error
+	return links, err
+}
+
+func (r *issueLinkRepository) CountChildren(ctx context.Context, parentIDs []int64) (map[int64]map[string]int, error) {
+	counts := make(map[int64]map[string]int, len(parentIDs))
+	if len(parentIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		SourceIssueID int64
+		Status        string
+		Count         int
+	}
+	err := r.db.WithContext(ctx).Model(&models.IssueLink{}).
+		Select("issue_links.source_issue_id, issues.status, COUNT(*) AS count").
+		Joins("JOIN issues ON issues.id = issue_links.target_issue_id AND issues.deleted_at IS NULL").
+		Where("issue_links.source_issue_id IN ? AND issue_links.type = ?", parentIDs, models.IssueLinkParentOf).
+		Group("issue_links.source_issue_id, issues.status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if counts[row.SourceIssueID] == nil {
+			counts[row.SourceIssueID] = make(map[string]int)
+		}
+		counts[row.SourceIssueID][row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *issueLinkRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&models.IssueLink{}, id).Error
+}
+
+func (r *issueLinkRepository) DeleteByIssue(ctx context.Context, issueID int64) error {
+	return r.db.WithContext(ctx).
+		Where("source_issue_id = ? OR target_issue_id = ?", issueID, issueID).
+		Delete(&models.IssueLink{}).Error
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -34,46 +35,41 @@ func (r *IssueRepository) Create(ctx context.Context, issue *models.Issue) error
 		issue.UpdatedAt = now
 	}
 
-	// モデル変換
-	gormIssue := models.IssueFromModel(issue)
-
-	// トランザクション開始
-	tx := r.db.WithContext(ctx).Begin()
-	if tx.Error != nil {
-		return fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	if issue.RepositoryID == 0 {
+		return errors.New("issue must belong to a repository")
 	}
-	defer tx.Rollback()
 
-	// Issueの保存
-	if err := tx.Create(gormIssue).Error; err != nil {
-		return fmt.Errorf("failed to create issue: %w", err)
-	}
+	// Discussionからの変換では呼び出し元のトランザクション内で実行されるため、ネストできる Transaction を使う
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// リポジトリ内の番号の採番
+		if issue.Number == 0 {
+			number, err := nextRepositoryNumber(tx, issue.RepositoryID)
+			if err != nil {
+				return err
+			}
+			issue.Number = number
+		}
 
-	// IDを設定
-	issue.ID = gormIssue.ID
+		// チェックリストの集計とモデル変換
+		issue.RefreshTaskProgress()
+		gormIssue := models.IssueFromModel(issue)
 
-	// ラベルの保存
-	if len(issue.Labels) > 0 {
-		// ラベルのGORMモデルを作成
-		labels := make([]models.IssueLabel, 0, len(issue.Labels))
-		for _, label := range issue.Labels {
-			labels = append(labels, models.IssueLabel{
-				IssueID: issue.ID,
-				Label:   label,
-			})
+		// Issueの保存（ラベルと担当者は後で保存）
+		if err := tx.Omit("Labels", "Assignees").Create(gormIssue).Error; err != nil {
+			return fmt.Errorf("failed to create issue: %w", err)
 		}
 
-		if err := tx.Create(&labels).Error; err != nil {
-			return fmt.Errorf("failed to create issue labels: %w", err)
-		}
-	}
+		// IDを設定
+		issue.ID = gormIssue.ID
 
-	// トランザクションをコミット
-	if err := tx.Commit().Error; err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
+		// 担当者の保存
+		if err := replaceIssueAssignees(tx, issue); err != nil {
+			return err
+		}
 
-	return nil
+		// ラベルの保存
+		return replaceIssueLabels(tx, issue)
+	})
 }
 
 // GetByID はIDによってIssueを取得します
@@ -82,12 +78,13 @@ func (r *IssueRepository) GetByID(ctx context.Context, id int64) (*models.Issue,
 
 	// Issueの取得（ラベルも同時に取得）
 	err := r.db.WithContext(ctx).
-		Preload("Labels").
+		Preload("Labels.Label").
+		Preload("Assignees").
 		First(&gormIssue, id).Error
 
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("issue with id %d not found", id)
+			return nil, fmt.Errorf("issue with id %d not found: %w", id, err)
 		}
 		return nil, fmt.Errorf("failed to get issue: %w", err)
 	}
@@ -96,6 +93,26 @@ func (r *IssueRepository) GetByID(ctx context.Context, id int64) (*models.Issue,
 	return gormIssue.ToModel(), nil
 }
 
+// GetByNumber はリポジトリ内の番号によってIssueを取得します
+func (r *IssueRepository) GetByNumber(ctx context.Context, repositoryID, number int64) (*models.Issue, error) {
+	var gormIssue models.IssueGorm
+
+	err := r.db.WithContext(ctx).
+		Preload("Labels.Label").
+		Preload("Assignees").
+		Where("repository_id = ? AND number = ?", repositoryID, number).
+		First(&gormIssue).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get issue: %w", err)
+	}
+
+	return gormIssue.ToModel(), nil
+}
+
 // List は条件に一致するIssueの一覧を取得します
 func (r *IssueRepository) List(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*models.Issue, int, error) {
 	var gormIssues []models.IssueGorm
@@ -108,10 +125,13 @@ func (r *IssueRepository) List(ctx context.Context, filter map[string]interface{
 	if filter != nil {
 		for k, v := range filter {
 			switch k {
+			case "repository_id":
+				query = whereRepository(query, v)
 			case "status":
 				query = query.Where("status = ?", v)
 			case "assignee_id":
-				query = query.Where("assignee_id = ?", v)
+				// いずれかの担当者に一致するIssue（サブクエリ使用）
+				query = query.Where("id IN (SELECT issue_id FROM issue_assignees WHERE user_id = ?)", v)
 			case "creator_id":
 				query = query.Where("creator_id = ?", v)
 			case "milestone_id":
@@ -120,7 +140,7 @@ func (r *IssueRepository) List(ctx context.Context, filter map[string]interface{
 				query = query.Where("is_draft = ?", v)
 			case "label":
 				// ラベルでフィルタリング（サブクエリ使用）
-				query = query.Where("id IN (SELECT issue_id FROM issue_labels WHERE label = ?)", v)
+				query = query.Where("id IN (SELECT il.issue_id FROM issue_labels il JOIN labels l ON l.id = il.label_id WHERE l.name = ?)", v)
 			}
 		}
 	}
@@ -132,7 +152,8 @@ func (r *IssueRepository) List(ctx context.Context, filter map[string]interface{
 
 	// データ取得
 	err := query.
-		Preload("Labels").
+		Preload("Labels.Label").
+		Preload("Assignees").
 		Order("updated_at DESC").
 		Limit(limit).
 		Offset((page - 1) * limit).
@@ -153,6 +174,11 @@ func (r *IssueRepository) List(ctx context.Context, filter map[string]interface{
 
 // Update は既存のIssueを更新します
 func (r *IssueRepository) Update(ctx context.Context, issue *models.Issue) error {
+	return r.UpdateWithEvents(ctx, issue, nil)
+}
+
+// UpdateWithEvents は既存のIssueを更新し、変更履歴を同じトランザクションで記録します
+func (r *IssueRepository) UpdateWithEvents(ctx context.Context, issue *models.Issue, events []*models.IssueEvent) error {
 	// 更新日時を更新
 	issue.UpdatedAt = time.Now()
 
@@ -163,7 +189,8 @@ func (r *IssueRepository) Update(ctx context.Context, issue *models.Issue) error
 	}
 	defer tx.Rollback()
 
-	// GORMモデルに変換
+	// チェックリストの集計とGORMモデルへの変換
+	issue.RefreshTaskProgress()
 	gormIssue := models.IssueFromModel(issue)
 
 	// Issueの更新
@@ -177,27 +204,26 @@ func (r *IssueRepository) Update(ctx context.Context, issue *models.Issue) error
 			"updated_at":   issue.UpdatedAt,
 			"is_draft":     issue.IsDraft,
 			"milestone_id": gormIssue.MilestoneID,
+			"task_checked": gormIssue.TaskChecked,
+			"task_total":   gormIssue.TaskTotal,
 		}).Error; err != nil {
 		return fmt.Errorf("failed to update issue: %w", err)
 	}
 
-	// ラベルの更新（古いラベルを削除して新しいラベルを挿入）
-	if err := tx.Where("issue_id = ?", issue.ID).Delete(&models.IssueLabel{}).Error; err != nil {
-		return fmt.Errorf("failed to delete old labels: %w", err)
+	// ラベルの更新
+	if err := replaceIssueLabels(tx, issue); err != nil {
+		return err
 	}
 
-	// 新しいラベルを挿入
-	if len(issue.Labels) > 0 {
-		labels := make([]models.IssueLabel, 0, len(issue.Labels))
-		for _, label := range issue.Labels {
-			labels = append(labels, models.IssueLabel{
-				IssueID: issue.ID,
-				Label:   label,
-			})
-		}
+	// 担当者の更新
+	if err := replaceIssueAssignees(tx, issue); err != nil {
+		return err
+	}
 
-		if err := tx.Create(&labels).Error; err != nil {
-			return fmt.Errorf("failed to create issue labels: %w", err)
+	// 変更履歴の記録
+	if len(events) > 0 {
+		if err := tx.Create(&events).Error; err != nil {
+			return fmt.Errorf("failed to create issue events: %w", err)
 		}
 	}
 
@@ -209,6 +235,46 @@ func (r *IssueRepository) Update(ctx context.Context, issue *models.Issue) error
 	return nil
 }
 
+// replaceIssueLabels はIssueのラベルを置き換えます（古いラベルを削除して新しいラベルを挿入）
+func replaceIssueLabels(tx *gorm.DB, issue *models.Issue) error {
+	if err := tx.Where("issue_id = ?", issue.ID).Delete(&models.IssueLabel{}).Error; err != nil {
+		return fmt.Errorf("failed to delete old labels: %w", err)
+	}
+
+	labelIDs, err := resolveLabelIDs(tx, issue.RepositoryID, issue.Labels, models.LabelTypeIssue)
+	if err != nil || len(labelIDs) == 0 {
+		return err
+	}
+
+	labels := make([]models.IssueLabel, 0, len(labelIDs))
+	for _, labelID := range labelIDs {
+		labels = append(labels, models.IssueLabel{
+			IssueID: issue.ID,
+			LabelID: labelID,
+		})
+	}
+	if err := tx.Create(&labels).Error; err != nil {
+		return fmt.Errorf("failed to create issue labels: %w", err)
+	}
+	return nil
+}
+
+// replaceIssueAssignees はIssueの担当者を置き換えます（古い担当者を削除して新しい担当者を挿入）
+func replaceIssueAssignees(tx *gorm.DB, issue *models.Issue) error {
+	if err := tx.Where("issue_id = ?", issue.ID).Delete(&models.IssueAssignee{}).Error; err != nil {
+		return fmt.Errorf("failed to delete old assignees: %w", err)
+	}
+
+	assignees := models.IssueFromModel(issue).Assignees
+	if len(assignees) == 0 {
+		return nil
+	}
+	if err := tx.Create(&assignees).Error; err != nil {
+		return fmt.Errorf("failed to create issue assignees: %w", err)
+	}
+	return nil
+}
+
 // Delete はIssueを削除します
 func (r *IssueRepository) Delete(ctx context.Context, id int64) error {
 	// GORMの論理削除を使用
@@ -218,8 +284,24 @@ func (r *IssueRepository) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
+// Purge はIssueをラベル・担当者とともに物理削除します
+func (r *IssueRepository) Purge(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("issue_id = ?", id).Delete(&models.IssueLabel{}).Error; err != nil {
+			return fmt.Errorf("failed to delete issue labels: %w", err)
+		}
+		if err := tx.Where("issue_id = ?", id).Delete(&models.IssueAssignee{}).Error; err != nil {
+			return fmt.Errorf("failed to delete issue assignees: %w", err)
+		}
+		if err := tx.Unscoped().Delete(&models.IssueGorm{}, id).Error; err != nil {
+			return fmt.Errorf("failed to purge issue: %w", err)
+		}
+		return nil
+	})
+}
+
 // Search はIssueの全文検索を行います
-func (r *IssueRepository) Search(ctx context.Context, query string, page, limit int) ([]*models.Issue, int, error) {
+func (r *IssueRepository) Search(ctx context.Context, query string, filter map[string]interface{}, page, limit int) ([]*models.Issue, int, error) {
 	var gormIssues []models.IssueGorm
 	var total int64
 
@@ -231,6 +313,11 @@ func (r *IssueRepository) Search(ctx context.Context, query string, page, limit
 		Model(&models.IssueGorm{}).
 		Where("title LIKE ? OR body LIKE ?", searchQuery, searchQuery)
 
+	// リポジトリの絞り込み
+	if repositoryID, ok := filter["repository_id"]; ok {
+		dbQuery = whereRepository(dbQuery, repositoryID)
+	}
+
 	// 総件数を取得
 	if err := dbQuery.Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
@@ -238,7 +325,8 @@ func (r *IssueRepository) Search(ctx context.Context, query string, page, limit
 
 	// データ取得
 	err := dbQuery.
-		Preload("Labels").
+		Preload("Labels.Label").
+		Preload("Assignees").
 		Order("updated_at DESC").
 		Limit(limit).
 		Offset((page - 1) * limit).
@@ -263,7 +351,8 @@ func (r *IssueRepository) GetAll(ctx context.Context) ([]*models.Issue, error) {
 
 	// すべてのIssueを取得
 	err := r.db.WithContext(ctx).
-		Preload("Labels").
+		Preload("Labels.Label").
+		Preload("Assignees").
 		Find(&gormIssues).Error
 
 	if err != nil {
@@ -279,6 +368,83 @@ func (r *IssueRepository) GetAll(ctx context.Context) ([]*models.Issue, error) {
 	return issues, nil
 }
 
+// ListByIDs は複数のIDによってIssueを取得します（存在しないIDは含まれません）
+func (r *IssueRepository) ListByIDs(ctx context.Context, ids []int64) ([]*models.Issue, error) {
+	if len(ids) == 0 {
+		return []*models.Issue{}, nil
+	}
+
+	var gormIssues []models.IssueGorm
+	err := r.db.WithContext(ctx).
+		Preload("Labels.Label").
+		Preload("Assignees").
+		Where("id IN ?", ids).
+		Order("id ASC").
+		Find(&gormIssues).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues by ids: %w", err)
+	}
+
+	issues := make([]*models.Issue, len(gormIssues))
+	for i, gormIssue := range gormIssues {
+		issues[i] = gormIssue.ToModel()
+	}
+	return issues, nil
+}
+
+// ListByMilestoneHistory はマイルストーンに含まれる、または変更履歴上含まれていたIssueを取得します
+func (r *IssueRepository) ListByMilestoneHistory(ctx context.Context, milestoneID int64) ([]*models.Issue, error) {
+	var gormIssues []models.IssueGorm
+
+	milestone := strconv.FormatInt(milestoneID, 10)
+	err := r.db.WithContext(ctx).
+		Preload("Labels.Label").
+		Preload("Assignees").
+		Where("milestone_id = ? OR id IN (SELECT issue_id FROM issue_events WHERE type IN ? AND (old_value = ? OR new_value = ?))",
+			milestoneID, []models.IssueEventType{models.IssueEventMilestoned, models.IssueEventDemilestoned}, milestone, milestone).
+		Order("id ASC").
+		Find(&gormIssues).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues by milestone history: %w", err)
+	}
+
+	issues := make([]*models.Issue, len(gormIssues))
+	for i, gormIssue := range gormIssues {
+		issues[i] = gormIssue.ToModel()
+	}
+	return issues, nil
+}
+
+// CountByMilestones はマイルストーンごと・ステータスごとの下書きを除くIssue数を取得します
+func (r *IssueRepository) CountByMilestones(ctx context.Context, milestoneIDs []int64) (map[int64]map[string]int, error) {
+	counts := make(map[int64]map[string]int, len(milestoneIDs))
+	if len(milestoneIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		MilestoneID int64
+		Status      string
+		Count       int
+	}
+	err := r.db.WithContext(ctx).Model(&models.IssueGorm{}).
+		Select("milestone_id, status, COUNT(*) AS count").
+		Where("milestone_id IN ? AND is_draft = ?", milestoneIDs, false).
+		Group("milestone_id, status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count issues by milestones: %w", err)
+	}
+
+	for _, row := range rows {
+		if counts[row.MilestoneID] == nil {
+			counts[row.MilestoneID] = make(map[string]int)
+		}
+		counts[row.MilestoneID][row.Status] = row.Count
+	}
+	return counts, nil
+}
+
 // CountIssues は総Issue数を取得します
 func (r *IssueRepository) CountIssues(ctx context.Context) (int64, error) {
 	var count int64
@@ -35,19 +35,19 @@ func (r *LabelRepository) GetByID(ctx context.Context, id int64) (*models.Label,
 	var label models.Label
 	if err := r.db.WithContext(ctx).First(&label, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("label with id %d not found", id)
+			return nil, fmt.Errorf("label with id %d not found: %w", id, err)
 		}
 		return nil, fmt.Errorf("failed to get label by id: %w", err)
 	}
 	return &label, nil
 }
 
-// GetByName は名前とタイプによってLabelを取得します
-func (r *LabelRepository) GetByName(ctx context.Context, name, labelType string) (*models.Label, error) {
+// GetByName はリポジトリ内の名前とタイプによってLabelを取得します
+func (r *LabelRepository) GetByName(ctx context.Context, repositoryID int64, name, labelType string) (*models.Label, error) {
 	var label models.Label
-	if err := r.db.WithContext(ctx).Where("name = ? AND type = ?", name, labelType).First(&label).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("repository_id = ? AND name = ? AND type = ?", repositoryID, name, labelType).First(&label).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("label with name '%s' and type '%s' not found", name, labelType)
+			return nil, nil
 		}
 		return nil, fmt.Errorf("failed to get label by name and type: %w", err)
 	}
@@ -62,6 +62,10 @@ func (r *LabelRepository) List(ctx context.Context, filter map[string]interface{
 	query := r.db.WithContext(ctx).Model(&models.Label{})
 
 	for key, value := range filter {
+		if key == "repository_id" {
+			query = whereRepository(query, value)
+			continue
+		}
 		query = query.Where(fmt.Sprintf("%s = ?", key), value)
 	}
 
@@ -85,12 +89,133 @@ func (r *LabelRepository) Update(ctx context.Context, label *models.Label) error
 	return nil
 }
 
-// Delete はLabelを削除します
+// ListScoped は条件に一致するスコープを持つLabelをスコープ・名前順にすべて取得します
+func (r *LabelRepository) ListScoped(ctx context.Context, filter map[string]interface{}) ([]*models.Label, error) {
+	var labels []*models.Label
+
+	query := r.db.WithContext(ctx).Model(&models.Label{}).Where("scope <> ''")
+	for key, value := range filter {
+		if key == "repository_id" {
+			query = whereRepository(query, value)
+			continue
+		}
+		query = query.Where(fmt.Sprintf("%s = ?", key), value)
+	}
+
+	if err := query.Order("scope ASC, name ASC").Find(&labels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list scoped labels: %w", err)
+	}
+	return labels, nil
+}
+
+// ListByNames はリポジトリ内の指定された名前のLabelを種類を問わず取得します
+func (r *LabelRepository) ListByNames(ctx context.Context, repositoryID int64, names []string) ([]*models.Label, error) {
+	var labels []*models.Label
+	if len(names) == 0 {
+		return labels, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("repository_id = ? AND name IN ?", repositoryID, names).
+		Order("id ASC").
+		Find(&labels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list labels by names: %w", err)
+	}
+	return labels, nil
+}
+
+// CountUsage はLabelが付与されているIssueとDiscussionの数を取得します
+func (r *LabelRepository) CountUsage(ctx context.Context, id int64) (issues int64, discussions int64, err error) {
+	db := r.db.WithContext(ctx)
+	if err := db.Model(&models.IssueLabel{}).Where("label_id = ?", id).Count(&issues).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to count issue labels: %w", err)
+	}
+	if err := db.Model(&models.DiscussionLabel{}).Where("label_id = ?", id).Count(&discussions).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to count discussion labels: %w", err)
+	}
+	return issues, discussions, nil
+}
+
+// Delete はLabelを削除し、IssueとDiscussionからも取り除きます
 func (r *LabelRepository) Delete(ctx context.Context, id int64) error {
-	if err := r.db.WithContext(ctx).Delete(&models.Label{}, id).Error; err != nil {
-		return fmt.Errorf("failed to delete label: %w", err)
+	return r.delete(ctx, id, 0, false)
+}
+
+// DeleteWithEvents はLabelを削除し、取り除いたIssueの変更履歴を同じトランザクションで記録します
+func (r *LabelRepository) DeleteWithEvents(ctx context.Context, id int64, actorID int64) error {
+	return r.delete(ctx, id, actorID, true)
+}
+
+// delete はLabelと付与状況を削除します
+func (r *LabelRepository) delete(ctx context.Context, id int64, actorID int64, recordEvents bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var label models.Label
+		if err := tx.First(&label, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("label with id %d not found", id)
+			}
+			return fmt.Errorf("failed to get label by id: %w", err)
+		}
+
+		// ラベルが付与されていたIssueの変更履歴の記録
+		if recordEvents {
+			var issueIDs []int64
+			if err := tx.Model(&models.IssueLabel{}).Where("label_id = ?", id).Pluck("issue_id", &issueIDs).Error; err != nil {
+				return fmt.Errorf("failed to list labeled issues: %w", err)
+			}
+			events := make([]*models.IssueEvent, 0, len(issueIDs))
+			for _, issueID := range issueIDs {
+				events = append(events, models.NewIssueEvent(issueID, actorID, models.IssueEventUnlabeled, label.Name, ""))
+			}
+			if len(events) > 0 {
+				if err := tx.Create(&events).Error; err != nil {
+					return fmt.Errorf("failed to create issue events: %w", err)
+				}
+			}
+		}
+
+		if err := tx.Where("label_id = ?", id).Delete(&models.IssueLabel{}).Error; err != nil {
+			return fmt.Errorf("failed to delete issue labels: %w", err)
+		}
+		if err := tx.Where("label_id = ?", id).Delete(&models.DiscussionLabel{}).Error; err != nil {
+			return fmt.Errorf("failed to delete discussion labels: %w", err)
+		}
+		if err := tx.Delete(&label).Error; err != nil {
+			return fmt.Errorf("failed to delete label: %w", err)
+		}
+		return nil
+	})
+}
+
+// resolveLabelIDs はリポジトリ内のラベル名を対象（issue/discussion）に付与できるラベルのIDに変換します
+// 同じ名前のラベルが複数ある場合は対象の種類と一致するラベルを優先します
+func resolveLabelIDs(tx *gorm.DB, repositoryID int64, names []string, targetType string) ([]int64, error) {
+	if len(names) == 0 {
+		return nil, nil
 	}
-	return nil
+
+	var labels []models.Label
+	if err := tx.Where("repository_id = ? AND name IN ? AND type IN ?", repositoryID, names, []string{targetType, models.LabelTypeBoth}).
+		Order("id ASC").
+		Find(&labels).Error; err != nil {
+		return nil, fmt.Errorf("failed to resolve labels: %w", err)
+	}
+
+	byName := make(map[string]models.Label, len(labels))
+	for _, label := range labels {
+		if existing, ok := byName[label.Name]; !ok || (existing.Type != targetType && label.Type == targetType) {
+			byName[label.Name] = label
+		}
+	}
+
+	ids := make([]int64, 0, len(names))
+	for _, name := range names {
+		label, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("label %q cannot be applied to %s in repository %d", name, targetType, repositoryID)
+		}
+		ids = append(ids, label.ID)
+	}
+	return ids, nil
 }
 
 // Search はLabelの全文検索を行います (簡易的なLIKE検索の例)
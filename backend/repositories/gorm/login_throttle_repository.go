@@ -0,0 +1,75 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type loginThrottleRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginThrottleRepository は新しいLoginThrottleRepositoryを作成します
+func NewLoginThrottleRepository(db *gorm.DB) *loginThrottleRepository {
+	return &loginThrottleRepository{db: db}
+}
+
+func (r *loginThrottleRepository) Get(ctx context.Context, scope models.LoginThrottleScope, identifier string) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	err := r.db.WithContext(ctx).Where("scope = ? AND identifier = ?", scope, identifier).First(&throttle).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// RecordFailure は同時に失敗したリクエストの回数を取りこぼさないよう、読み込まずに1つのUPSERTで失敗回数を増やす
+func (r *loginThrottleRepository) RecordFailure(ctx context.Context, scope models.LoginThrottleScope, identifier string, now time.Time, resetAfter time.Duration) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		failure := models.NewLoginThrottle(scope, identifier)
+		failure.FailedCount = 1
+		failure.LastFailedAt = now
+		failure.CreatedAt = now
+		failure.UpdatedAt = now
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "scope"}, {Name: "identifier"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"failed_count":   gorm.Expr("CASE WHEN login_throttles.last_failed_at <= ? THEN 1 ELSE login_throttles.failed_count + 1 END", now.Add(-resetAfter)),
+				"last_failed_at": now,
+				"updated_at":     now,
+			}),
+		}).Create(failure).Error; err != nil {
+			return err
+		}
+		return tx.Where("scope = ? AND identifier = ?", scope, identifier).First(&throttle).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// Lock は条件付きのUPDATEでロックし、同時に上限に達したリクエストのうち1つだけがロックしたことになるようにする
+func (r *loginThrottleRepository) Lock(ctx context.Context, scope models.LoginThrottleScope, identifier string, until, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.LoginThrottle{}).
+		Where("scope = ? AND identifier = ?", scope, identifier).
+		Where("locked_until IS NULL OR locked_until <= ?", now).
+		Updates(map[string]interface{}{"locked_until": until, "updated_at": now})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *loginThrottleRepository) Delete(ctx context.Context, scope models.LoginThrottleScope, identifier string) error {
+	return r.db.WithContext(ctx).Where("scope = ? AND identifier = ?", scope, identifier).Delete(&models.LoginThrottle{}).Error
+}
@@ -0,0 +1,52 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"gorm.io/gorm"
+)
+
+type mentionRepository struct {
+	db *gorm.DB
+}
+
+func NewMentionRepository(db *gorm.DB) *mentionRepository {
+	return &mentionRepository{db: db}
+}
+
+func (r *mentionRepository) Create(ctx context.Context, mention *models.Mention) error {
+	return r.db.WithContext(ctx).Create(mention).Error
+}
+
+func (r *mentionRepository) ListBySource(ctx context.Context, sourceType string, sourceID int64) ([]*models.Mention, error) {
+	var mentions []*models.Mention
+	err := r.db.WithContext(ctx).
+		Where("source_type = ? AND source_id = ?", sourceType, sourceID).
+		Find(&mentions).Error
+	return mentions, err
+}
+
+func (r *mentionRepository) ListByUser(ctx context.Context, userID int64, page, limit int) ([]*models.Mention, int, error) {
+	var mentions []*models.Mention
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Mention{}).Where("user_id = ? AND removed_at IS NULL", userID)
+
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err = query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&mentions).Error
+	return mentions, int(total), err
+}
+
+func (r *mentionRepository) Update(ctx context.Context, mention *models.Mention) error {
+	return r.db.WithContext(ctx).Save(mention).Error
+}
+
+func (r *mentionRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&models.Mention{}, id).Error
+}
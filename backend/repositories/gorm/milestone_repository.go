@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/shimauma0312/module-tickethub/backend/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // MilestoneRepository はGORMベースのMilestoneリポジトリ実装
@@ -35,7 +37,7 @@ func (r *MilestoneRepository) GetByID(ctx context.Context, id int64) (*models.Mi
 	var milestone models.Milestone
 	if err := r.db.WithContext(ctx).First(&milestone, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("milestone with id %d not found", id)
+			return nil, fmt.Errorf("milestone with id %d not found: %w", id, err)
 		}
 		return nil, fmt.Errorf("failed to get milestone by id: %w", err)
 	}
@@ -50,6 +52,10 @@ func (r *MilestoneRepository) List(ctx context.Context, filter map[string]interf
 	query := r.db.WithContext(ctx).Model(&models.Milestone{})
 
 	for key, value := range filter {
+		if key == "repository_id" {
+			query = whereRepository(query, value)
+			continue
+		}
 		query = query.Where(fmt.Sprintf("%s = ?", key), value)
 	}
 
@@ -57,8 +63,11 @@ func (r *MilestoneRepository) List(ctx context.Context, filter map[string]interf
 		return nil, 0, fmt.Errorf("failed to count milestones: %w", err)
 	}
 
+	// 期日の近い順（期日のないマイルストーンは最後）
 	offset := (page - 1) * limit
-	if err := query.Limit(limit).Offset(offset).Order("due_date ASC").Find(&milestones).Error; err != nil {
+	if err := query.Limit(limit).Offset(offset).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "CASE WHEN due_date > ? THEN 0 ELSE 1 END, due_date ASC, id ASC", Vars: []interface{}{time.Time{}}, WithoutParentheses: true}}).
+		Find(&milestones).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to list milestones: %w", err)
 	}
 
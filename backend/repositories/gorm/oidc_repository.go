@@ -0,0 +1,80 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"gorm.io/gorm"
+)
+
+type userIdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewUserIdentityRepository は新しいUserIdentityRepositoryを作成します
+func NewUserIdentityRepository(db *gorm.DB) *userIdentityRepository {
+	return &userIdentityRepository{db: db}
+}
+
+func (r *userIdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) error {
+	return r.db.WithContext(ctx).Create(identity).Error
+}
+
+func (r *userIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	err := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *userIdentityRepository) ListByUser(ctx context.Context, userID int64) ([]*models.UserIdentity, error) {
+	var identities []*models.UserIdentity
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id ASC").Find(&identities).Error
+	return identities, err
+}
+
+func (r *userIdentityRepository) Update(ctx context.Context, identity *models.UserIdentity) error {
+	return r.db.WithContext(ctx).Save(identity).Error
+}
+
+type oidcLoginStateRepository struct {
+	db *gorm.DB
+}
+
+// NewOIDCLoginStateRepository は新しいOIDCLoginStateRepositoryを作成します
+func NewOIDCLoginStateRepository(db *gorm.DB) *oidcLoginStateRepository {
+	return &oidcLoginStateRepository{db: db}
+}
+
+func (r *oidcLoginStateRepository) Create(ctx context.Context, state *models.OIDCLoginState) error {
+	return r.db.WithContext(ctx).Create(state).Error
+}
+
+func (r *oidcLoginStateRepository) Consume(ctx context.Context, state string) (*models.OIDCLoginState, error) {
+	var loginState models.OIDCLoginState
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("state = ?", state).First(&loginState).Error; err != nil {
+			return err
+		}
+		// 同じ state の再利用を防ぐため取得と同時に削除する
+		return tx.Where("state = ?", state).Delete(&models.OIDCLoginState{}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &loginState, nil
+}
+
+func (r *oidcLoginStateRepository) DeleteExpired(ctx context.Context) error {
+	return r.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&models.OIDCLoginState{}).Error
+}
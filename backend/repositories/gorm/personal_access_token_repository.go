@@ -0,0 +1,62 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"gorm.io/gorm"
+)
+
+type personalAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewPersonalAccessTokenRepository は新しいPersonalAccessTokenRepositoryを作成します
+func NewPersonalAccessTokenRepository(db *gorm.DB) *personalAccessTokenRepository {
+	return &personalAccessTokenRepository{db: db}
+}
+
+func (r *personalAccessTokenRepository) Create(ctx context.Context, token *models.PersonalAccessToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *personalAccessTokenRepository) GetByID(ctx context.Context, id int64) (*models.PersonalAccessToken, error) {
+	return r.first(r.db.WithContext(ctx).Where("id = ?", id))
+}
+
+func (r *personalAccessTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*models.PersonalAccessToken, error) {
+	return r.first(r.db.WithContext(ctx).Where("token_hash = ?", tokenHash))
+}
+
+func (r *personalAccessTokenRepository) ListByUser(ctx context.Context, userID int64) ([]*models.PersonalAccessToken, error) {
+	var tokens []*models.PersonalAccessToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *personalAccessTokenRepository) RecordUse(ctx context.Context, token *models.PersonalAccessToken) error {
+	return r.db.WithContext(ctx).Model(&models.PersonalAccessToken{}).
+		Where("id = ?", token.ID).
+		UpdateColumns(map[string]interface{}{"last_used_at": token.LastUsedAt, "last_used_ip": token.LastUsedIP}).Error
+}
+
+func (r *personalAccessTokenRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&models.PersonalAccessToken{}, id).Error
+}
+
+// first は条件に一致するパーソナルアクセストークンを取得します（存在しない場合はnil）
+func (r *personalAccessTokenRepository) first(query *gorm.DB) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	err := query.First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
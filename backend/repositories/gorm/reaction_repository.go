@@ -2,6 +2,7 @@ package gorm
 
 import (
 	"context"
+	"errors"
 
 	"github.com/shimauma0312/module-tickethub/backend/models"
 	"gorm.io/gorm"
@@ -25,18 +26,73 @@ func (r *reactionRepository) GetByID(ctx context.Context, id int64) (*models.Rea
 	return &reaction, err
 }
 
-func (r *reactionRepository) GetByUserAndComment(ctx context.Context, userID, commentID int64, emoji string) (*models.Reaction, error) {
+func (r *reactionRepository) GetByUserAndTarget(ctx context.Context, userID int64, targetType string, targetID int64, emoji string) (*models.Reaction, error) {
 	var reaction models.Reaction
-	err := r.db.WithContext(ctx).Where("user_id = ? AND comment_id = ? AND emoji = ?", userID, commentID, emoji).First(&reaction).Error
-	return &reaction, err
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND target_type = ? AND target_id = ? AND emoji = ?", userID, targetType, targetID, emoji).
+		First(&reaction).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reaction, nil
 }
 
-func (r *reactionRepository) ListByComment(ctx context.Context, commentID int64) ([]*models.Reaction, error) {
+func (r *reactionRepository) ListByTarget(ctx context.Context, targetType string, targetID int64) ([]*models.Reaction, error) {
 	var reactions []*models.Reaction
-	err := r.db.WithContext(ctx).Where("comment_id = ?", commentID).Find(&reactions).Error
+	err := r.db.WithContext(ctx).Where("target_type = ? AND target_id = ?", targetType, targetID).Order("created_at").Find(&reactions).Error
 	return reactions, err
 }
 
+// reactionCount は絵文字ごとの集計行
+type reactionCount struct {
+	TargetID    int64
+	Emoji       string
+	Count       int
+	ReactedByMe bool
+}
+
+func (r *reactionRepository) SummarizeByTargets(ctx context.Context, targetType string, targetIDs []int64, viewerID int64) (map[int64][]models.ReactionSummary, error) {
+	summaries := make(map[int64][]models.ReactionSummary)
+	if len(targetIDs) == 0 {
+		return summaries, nil
+	}
+
+	var rows []reactionCount
+	err := r.db.WithContext(ctx).Model(&models.Reaction{}).
+		Select("target_id, emoji, COUNT(*) AS count, MAX(CASE WHEN user_id = ? THEN 1 ELSE 0 END) = 1 AS reacted_by_me", viewerID).
+		Where("target_type = ? AND target_id IN ?", targetType, targetIDs).
+		Group("target_id, emoji").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		summaries[row.TargetID] = append(summaries[row.TargetID], models.ReactionSummary{
+			Emoji:       row.Emoji,
+			Count:       row.Count,
+			ReactedByMe: row.ReactedByMe,
+		})
+	}
+	for _, s := range summaries {
+		models.SortReactionSummaries(s)
+	}
+	return summaries, nil
+}
+
 func (r *reactionRepository) Delete(ctx context.Context, id int64) error {
 	return r.db.WithContext(ctx).Delete(&models.Reaction{}, id).Error
 }
+
+func (r *reactionRepository) DeleteByTarget(ctx context.Context, targetType string, targetID int64) error {
+	return r.db.WithContext(ctx).Where("target_type = ? AND target_id = ?", targetType, targetID).Delete(&models.Reaction{}).Error
+}
+
+func (r *reactionRepository) MoveTarget(ctx context.Context, fromType string, fromID int64, toType string, toID int64) error {
+	return r.db.WithContext(ctx).Model(&models.Reaction{}).
+		Where("target_type = ? AND target_id = ?", fromType, fromID).
+		UpdateColumns(map[string]interface{}{"target_type": toType, "target_id": toID}).Error
+}
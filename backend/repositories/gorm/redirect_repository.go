@@ -0,0 +1,49 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"gorm.io/gorm"
+)
+
+type redirectRepository struct {
+	db *gorm.DB
+}
+
+// NewRedirectRepository は新しいRedirectRepositoryを作成します
+func NewRedirectRepository(db *gorm.DB) *redirectRepository {
+	return &redirectRepository{db: db}
+}
+
+func (r *redirectRepository) Create(ctx context.Context, redirect *models.Redirect) error {
+	return r.db.WithContext(ctx).Create(redirect).Error
+}
+
+func (r *redirectRepository) GetBySource(ctx context.Context, sourceType string, sourceID int64) (*models.Redirect, error) {
+	return r.first(r.db.WithContext(ctx).Where("source_type = ? AND source_id = ?", sourceType, sourceID))
+}
+
+func (r *redirectRepository) GetBySourceNumber(ctx context.Context, repositoryID int64, sourceType string, number int64) (*models.Redirect, error) {
+	return r.first(r.db.WithContext(ctx).Where("repository_id = ? AND number = ? AND source_type = ?", repositoryID, number, sourceType))
+}
+
+func (r *redirectRepository) Retarget(ctx context.Context, fromType string, fromID int64, toType string, toID int64) error {
+	return r.db.WithContext(ctx).Model(&models.Redirect{}).
+		Where("target_type = ? AND target_id = ?", fromType, fromID).
+		UpdateColumns(map[string]interface{}{"target_type": toType, "target_id": toID}).Error
+}
+
+// first は条件に一致する最新のリダイレクトを取得します（存在しない場合はnil）
+func (r *redirectRepository) first(query *gorm.DB) (*models.Redirect, error) {
+	var redirect models.Redirect
+	err := query.Order("id DESC").First(&redirect).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &redirect, nil
+}
@@ -0,0 +1,65 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repositoryMemberRepository struct {
+	db *gorm.DB
+}
+
+// NewRepositoryMemberRepository は新しいRepositoryMemberRepositoryを作成します
+func NewRepositoryMemberRepository(db *gorm.DB) *repositoryMemberRepository {
+	return &repositoryMemberRepository{db: db}
+}
+
+func (r *repositoryMemberRepository) Save(ctx context.Context, member *models.RepositoryMember) error {
+	member.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "repository_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "updated_at"}),
+	}).Create(member).Error
+}
+
+func (r *repositoryMemberRepository) Get(ctx context.Context, repositoryID, userID int64) (*models.RepositoryMember, error) {
+	var member models.RepositoryMember
+	err := r.db.WithContext(ctx).
+		Where("repository_id = ? AND user_id = ?", repositoryID, userID).
+		First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *repositoryMemberRepository) ListByRepository(ctx context.Context, repositoryID int64) ([]*models.RepositoryMember, error) {
+	var members []*models.RepositoryMember
+	err := r.db.WithContext(ctx).
+		Select("repository_members.*, users.username").
+		Joins("LEFT JOIN users ON users.id = repository_members.user_id").
+		Where("repository_members.repository_id = ?", repositoryID).
+		Order("repository_members.created_at ASC").
+		Find(&members).Error
+	return members, err
+}
+
+func (r *repositoryMemberRepository) Delete(ctx context.Context, repositoryID, userID int64) error {
+	return r.db.WithContext(ctx).
+		Where("repository_id = ? AND user_id = ?", repositoryID, userID).
+		Delete(&models.RepositoryMember{}).Error
+}
+
+func (r *repositoryMemberRepository) DeleteByRepository(ctx context.Context, repositoryID int64) error {
+	return r.db.WithContext(ctx).
+		Where("repository_id = ?", repositoryID).
+		Delete(&models.RepositoryMember{}).Error
+}
@@ -2,6 +2,8 @@ package gorm
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/shimauma0312/module-tickethub/backend/models"
 	"github.com/shimauma0312/module-tickethub/backend/repositories"
@@ -48,11 +50,71 @@ func (r *repositoryRepository) GetByID(ctx context.Context, id int64) (*models.R
 func (r *repositoryRepository) GetByName(ctx context.Context, name string) (*models.Repository, error) {
 	var repo models.Repository
 	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&repo).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
 		return nil, err
 	}
 	return &repo, nil
 }
 
+// GetDefault は既定のリポジトリを取得し、存在しない場合は作成します
+func (r *repositoryRepository) GetDefault(ctx context.Context) (*models.Repository, error) {
+	repo := models.NewRepository(models.DefaultRepositoryName, "", models.PublicRepo, 0)
+	if err := r.db.WithContext(ctx).Where("name = ?", models.DefaultRepositoryName).FirstOrCreate(repo).Error; err != nil {
+		return nil, fmt.Errorf("failed to get default repository: %w", err)
+	}
+	return repo, nil
+}
+
+// ListVisibleIDs はユーザーが閲覧できるリポジトリのIDを取得します
+func (r *repositoryRepository) ListVisibleIDs(ctx context.Context, userID int64) ([]int64, error) {
+	visibleTypes := []models.RepositoryType{models.PublicRepo}
+	if userID > 0 {
+		visibleTypes = append(visibleTypes, models.InternalRepo)
+	}
+
+	ids := []int64{}
+	err := r.db.WithContext(ctx).Model(&models.Repository{}).
+		Where("type IN ?", visibleTypes).
+		Or("owner_id = ? AND owner_id <> 0", userID).
+		Or("id IN (?)", r.db.Model(&models.RepositoryMember{}).Select("repository_id").Where("user_id = ?", userID)).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list visible repositories: %w", err)
+	}
+	return ids, nil
+}
+
+// whereRepository はリポジトリIDの絞り込み条件を適用します
+// 値には単一のID、または閲覧できるリポジトリの絞り込みに使うIDのスライスを指定できます
+func whereRepository(query *gorm.DB, value interface{}) *gorm.DB {
+	if ids, ok := value.([]int64); ok {
+		return query.Where("repository_id IN ?", ids)
+	}
+	return query.Where("repository_id = ?", value)
+}
+
+// nextRepositoryNumber はリポジトリ内のIssue・Discussionの次の番号を採番します
+// 呼び出し側のトランザクション内で実行し、リポジトリ行の更新ロックで同時採番による重複を防ぎます
+func nextRepositoryNumber(tx *gorm.DB, repositoryID int64) (int64, error) {
+	result := tx.Model(&models.Repository{}).
+		Where("id = ?", repositoryID).
+		UpdateColumn("last_number", gorm.Expr("last_number + 1"))
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to allocate number: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return 0, fmt.Errorf("repository with id %d not found", repositoryID)
+	}
+
+	var number int64
+	if err := tx.Model(&models.Repository{}).Select("last_number").Where("id = ?", repositoryID).Scan(&number).Error; err != nil {
+		return 0, fmt.Errorf("failed to allocate number: %w", err)
+	}
+	return number, nil
+}
+
 // List は条件に一致するリポジトリの一覧を取得します
 func (r *repositoryRepository) List(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*models.Repository, int64, error) {
 	var repos []*models.Repository
@@ -72,6 +134,8 @@ func (r *repositoryRepository) List(ctx context.Context, filter map[string]inter
 				query = query.Where("type = ?", value)
 			case "is_archived":
 				query = query.Where("is_archived = ?", value)
+			case "ids":
+				query = query.Where("id IN ?", value)
 			}
 		}
 	}
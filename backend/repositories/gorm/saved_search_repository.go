@@ -0,0 +1,80 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type savedSearchRepository struct {
+	db *gorm.DB
+}
+
+func NewSavedSearchRepository(db *gorm.DB) *savedSearchRepository {
+	return &savedSearchRepository{db: db}
+}
+
+func (r *savedSearchRepository) Create(ctx context.Context, savedSearch *models.SavedSearch) error {
+	return r.db.WithContext(ctx).Create(savedSearch).Error
+}
+
+func (r *savedSearchRepository) GetByID(ctx context.Context, id int64) (*models.SavedSearch, error) {
+	var savedSearch models.SavedSearch
+	err := r.db.WithContext(ctx).First(&savedSearch, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &savedSearch, nil
+}
+
+func (r *savedSearchRepository) ListVisible(ctx context.Context, userID int64) ([]*models.SavedSearch, error) {
+	var savedSearches []*models.SavedSearch
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? OR is_shared = ?", userID, true).
+		Order("name ASC").
+		Find(&savedSearches).Error
+	return savedSearches, err
+}
+
+func (r *savedSearchRepository) Update(ctx context.Context, savedSearch *models.SavedSearch) error {
+	return r.db.WithContext(ctx).Save(savedSearch).Error
+}
+
+func (r *savedSearchRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("saved_search_id = ?", id).Delete(&models.SavedSearchView{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.SavedSearch{}, id).Error
+	})
+}
+
+func (r *savedSearchRepository) GetLastViewedAt(ctx context.Context, savedSearchID, userID int64) (time.Time, error) {
+	var view models.SavedSearchView
+	err := r.db.WithContext(ctx).
+		Where("saved_search_id = ? AND user_id = ?", savedSearchID, userID).
+		First(&view).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, nil
+	}
+	return view.LastViewedAt, err
+}
+
+func (r *savedSearchRepository) MarkViewed(ctx context.Context, savedSearchID, userID int64, viewedAt time.Time) error {
+	view := models.SavedSearchView{
+		SavedSearchID: savedSearchID,
+		UserID:        userID,
+		LastViewedAt:  viewedAt,
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "saved_search_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_viewed_at"}),
+	}).Create(&view).Error
+}
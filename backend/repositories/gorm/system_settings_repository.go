@@ -38,11 +38,15 @@ func (r *systemSettingsRepository) CreateOrUpdate(ctx context.Context, settings
 	var existing models.SystemSettings
 
 	if err := r.db.WithContext(ctx).First(&existing).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			// レコードが存在しない場合は新規作成
-			return r.db.WithContext(ctx).Create(settings).Error
+		if err != gorm.ErrRecordNotFound {
+			return err
+		}
+		// レコードが存在しない場合はデフォルト設定で新規作成してから更新する
+		// （作成時は default タグのある項目の0がデフォルト値に置き換えられるため）
+		existing = *models.NewDefaultSystemSettings()
+		if err := r.db.WithContext(ctx).Create(&existing).Error; err != nil {
+			return err
 		}
-		return err
 	}
 
 	// 既存レコードを更新
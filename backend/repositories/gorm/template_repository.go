@@ -0,0 +1,74 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"gorm.io/gorm"
+)
+
+type templateRepository struct {
+	db *gorm.DB
+}
+
+// NewTemplateRepository は新しいTemplateRepositoryを作成します
+func NewTemplateRepository(db *gorm.DB) *templateRepository {
+	return &templateRepository{db: db}
+}
+
+func (r *templateRepository) Create(ctx context.Context, template *models.Template) error {
+	return r.db.WithContext(ctx).Create(template).Error
+}
+
+func (r *templateRepository) GetByID(ctx context.Context, id int64) (*models.Template, error) {
+	var template models.Template
+	err := r.db.WithContext(ctx).First(&template, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *templateRepository) GetByName(ctx context.Context, repositoryID int64, templateType, name string) (*models.Template, error) {
+	var template models.Template
+	err := r.db.WithContext(ctx).
+		Where("repository_id = ? AND type = ? AND name = ?", repositoryID, templateType, name).
+		First(&template).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *templateRepository) List(ctx context.Context, filter map[string]interface{}) ([]*models.Template, error) {
+	query := r.db.WithContext(ctx).Model(&models.Template{})
+	for k, v := range filter {
+		switch k {
+		case "repository_id":
+			query = whereRepository(query, v)
+		case "type":
+			query = query.Where("type = ?", v)
+		case "category":
+			query = query.Where("category = ?", v)
+		}
+	}
+
+	var templates []*models.Template
+	err := query.Order("name ASC, id ASC").Find(&templates).Error
+	return templates, err
+}
+
+func (r *templateRepository) Update(ctx context.Context, template *models.Template) error {
+	return r.db.WithContext(ctx).Save(template).Error
+}
+
+func (r *templateRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&models.Template{}, id).Error
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/shimauma0312/module-tickethub/backend/models"
 )
@@ -10,17 +11,30 @@ import (
 type IssueRepository interface {
 	// Create は新しいIssueを作成します
 	Create(ctx context.Context, issue *models.Issue) error
-	// GetByID はIDによってIssueを取得します
+	// GetByID はIDによってIssueを取得します（存在しない場合は gorm.ErrRecordNotFound）
 	GetByID(ctx context.Context, id int64) (*models.Issue, error)
+	// GetByNumber はリポジトリ内の番号によってIssueを取得します（存在しない場合はnil）
+	GetByNumber(ctx context.Context, repositoryID, number int64) (*models.Issue, error)
 	// List は条件に一致するIssueの一覧を取得します
 	List(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*models.Issue, int, error)
 	// Update は既存のIssueを更新します
-	Update(ctx context.Context, issue *models.Issue) error // Delete はIssueを削除します
+	Update(ctx context.Context, issue *models.Issue) error
+	// UpdateWithEvents は既存のIssueを更新し、変更履歴を同じトランザクションで記録します
+	UpdateWithEvents(ctx context.Context, issue *models.Issue, events []*models.IssueEvent) error
+	// Delete はIssueを削除します
 	Delete(ctx context.Context, id int64) error
+	// Purge はIssueをラベル・担当者とともに物理削除します（Discussionへの変換で番号を引き継ぐ場合）
+	Purge(ctx context.Context, id int64) error
 	// Search はIssueの全文検索を行います
-	Search(ctx context.Context, query string, page, limit int) ([]*models.Issue, int, error)
+	Search(ctx context.Context, query string, filter map[string]interface{}, page, limit int) ([]*models.Issue, int, error)
 	// GetAll はすべてのIssueを取得します（検索インデックス構築用）
 	GetAll(ctx context.Context) ([]*models.Issue, error)
+	// ListByIDs は複数のIDによってIssueを取得します（存在しないIDは含まれません）
+	ListByIDs(ctx context.Context, ids []int64) ([]*models.Issue, error)
+	// ListByMilestoneHistory はマイルストーンに含まれる、または変更履歴上含まれていたIssueを取得します
+	ListByMilestoneHistory(ctx context.Context, milestoneID int64) ([]*models.Issue, error)
+	// CountByMilestones はマイルストーンごと・ステータスごとの下書きを除くIssue数を取得します
+	CountByMilestones(ctx context.Context, milestoneIDs []int64) (map[int64]map[string]int, error)
 	// CountIssues は総Issue数を取得します
 	CountIssues(ctx context.Context) (int64, error)
 	// CountOpenIssues はオープンなIssue数を取得します
@@ -78,6 +92,88 @@ type AuthTokenRepository interface {
 	RevokeAllForUser(ctx context.Context, userID int64) error
 }
 
+// PersonalAccessTokenRepository はパーソナルアクセストークンのデータベース操作を抽象化するインターフェース
+type PersonalAccessTokenRepository interface {
+	// Create は新しいパーソナルアクセストークンを作成します
+	Create(ctx context.Context, token *models.PersonalAccessToken) error
+	// GetByID はIDによってパーソナルアクセストークンを取得します（存在しない場合はnil）
+	GetByID(ctx context.Context, id int64) (*models.PersonalAccessToken, error)
+	// GetByHash はトークン文字列のハッシュ値によってパーソナルアクセストークンを取得します（存在しない場合はnil）
+	GetByHash(ctx context.Context, tokenHash string) (*models.PersonalAccessToken, error)
+	// ListByUser はユーザーのパーソナルアクセストークンの一覧を作成日時の新しい順に取得します
+	ListByUser(ctx context.Context, userID int64) ([]*models.PersonalAccessToken, error)
+	// RecordUse は最終利用日時と接続元のIPアドレスを更新します
+	RecordUse(ctx context.Context, token *models.PersonalAccessToken) error
+	// Delete はパーソナルアクセストークンを削除します
+	Delete(ctx context.Context, id int64) error
+}
+
+// UserIdentityRepository は外部のIDプロバイダーのアカウントとの紐付けのデータベース操作を抽象化するインターフェース
+type UserIdentityRepository interface {
+	// Create は新しい紐付けを作成します
+	Create(ctx context.Context, identity *models.UserIdentity) error
+	// GetByProviderSubject はプロバイダー名とIDトークンの sub によって紐付けを取得します（存在しない場合はnil）
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error)
+	// ListByUser はユーザーの紐付けの一覧を取得します
+	ListByUser(ctx context.Context, userID int64) ([]*models.UserIdentity, error)
+	// Update は既存の紐付けを更新します
+	Update(ctx context.Context, identity *models.UserIdentity) error
+}
+
+// OIDCLoginStateRepository はOpenID Connectのログインの状態のデータベース操作を抽象化するインターフェース
+type OIDCLoginStateRepository interface {
+	// Create は新しいログインの状態を作成します
+	Create(ctx context.Context, state *models.OIDCLoginState) error
+	// Consume は state パラメータによってログインの状態を取得して削除します（存在しない場合はnil）
+	Consume(ctx context.Context, state string) (*models.OIDCLoginState, error)
+	// DeleteExpired は期限切れのログインの状態を削除します
+	DeleteExpired(ctx context.Context) error
+}
+
+// TwoFactorRepository は二要素認証の設定とリカバリーコードのデータベース操作を抽象化するインターフェース
+type TwoFactorRepository interface {
+	// Get はユーザーの二要素認証の設定を取得します（存在しない場合はnil）
+	Get(ctx context.Context, userID int64) (*models.UserTwoFactor, error)
+	// Save は二要素認証の設定を作成または更新します
+	Save(ctx context.Context, twoFactor *models.UserTwoFactor) error
+	// Delete はユーザーの二要素認証の設定とリカバリーコードを削除します
+	Delete(ctx context.Context, userID int64) error
+	// UseStep はTOTPのステップを使用済みにします（同じか古いステップを使用済みの場合はfalse）
+	UseStep(ctx context.Context, userID, step int64) (bool, error)
+	// ReplaceRecoveryCodes はユーザーのリカバリーコードを置き換えます
+	ReplaceRecoveryCodes(ctx context.Context, userID int64, codes []*models.RecoveryCode) error
+	// UseRecoveryCode は未使用のリカバリーコードを使用済みにします（該当するコードがない場合はfalse）
+	UseRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error)
+	// CountUnusedRecoveryCodes は未使用のリカバリーコードの数を取得します
+	CountUnusedRecoveryCodes(ctx context.Context, userID int64) (int64, error)
+}
+
+// LoginThrottleRepository はログインの失敗回数とロックの状態のデータベース操作を抽象化するインターフェース
+type LoginThrottleRepository interface {
+	// Get はアカウントまたはIPアドレスの失敗回数を取得します（存在しない場合はnil）
+	Get(ctx context.Context, scope models.LoginThrottleScope, identifier string) (*models.LoginThrottle, error)
+	// RecordFailure は失敗回数を原子的に1増やし、更新後の失敗回数を返します（前回の失敗から resetAfter 以上経過している場合は1に戻します）
+	RecordFailure(ctx context.Context, scope models.LoginThrottleScope, identifier string, now time.Time, resetAfter time.Duration) (*models.LoginThrottle, error)
+	// Lock はロックされていない場合に until までロックします（ロックした場合はtrue）
+	Lock(ctx context.Context, scope models.LoginThrottleScope, identifier string, until, now time.Time) (bool, error)
+	// Delete はアカウントまたはIPアドレスの失敗回数とロックを削除します
+	Delete(ctx context.Context, scope models.LoginThrottleScope, identifier string) error
+}
+
+// EmailVerificationRepository はメールアドレスの確認トークンのデータベース操作を抽象化するインターフェース
+type EmailVerificationRepository interface {
+	// Create は新しい確認トークンを作成します
+	Create(ctx context.Context, verification *models.EmailVerification) error
+	// GetByTokenHash はトークンのハッシュ値によって確認トークンを取得します（存在しない場合はnil）
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.EmailVerification, error)
+	// Update は既存の確認トークンを更新します
+	Update(ctx context.Context, verification *models.EmailVerification) error
+	// ListCreatedSince はユーザーに since 以降に発行した確認トークンを新しい順に取得します
+	ListCreatedSince(ctx context.Context, userID int64, since time.Time) ([]*models.EmailVerification, error)
+	// RevokeAllForUser はユーザーの未使用の確認トークンをすべて無効化します
+	RevokeAllForUser(ctx context.Context, userID int64) error
+}
+
 // PasswordResetRepository はパスワードリセット関連のデータベース操作を抽象化するインターフェース
 type PasswordResetRepository interface {
 	// Create は新しいPasswordResetを作成します
@@ -100,16 +196,24 @@ type PasswordResetRepository interface {
 type LabelRepository interface {
 	// Create は新しいLabelを作成します
 	Create(ctx context.Context, label *models.Label) error
-	// GetByID はIDによってLabelを取得します
+	// GetByID はIDによってLabelを取得します（存在しない場合は gorm.ErrRecordNotFound）
 	GetByID(ctx context.Context, id int64) (*models.Label, error)
-	// GetByName は名前とタイプによってLabelを取得します
-	GetByName(ctx context.Context, name, labelType string) (*models.Label, error)
+	// GetByName はリポジトリ内の名前とタイプによってLabelを取得します（存在しない場合はnil）
+	GetByName(ctx context.Context, repositoryID int64, name, labelType string) (*models.Label, error)
 	// List は条件に一致するLabelの一覧を取得します
 	List(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*models.Label, int, error)
 	// Update は既存のLabelを更新します
 	Update(ctx context.Context, label *models.Label) error
-	// Delete はLabelを削除します
+	// ListScoped は条件に一致するスコープを持つLabelをスコープ・名前順にすべて取得します
+	ListScoped(ctx context.Context, filter map[string]interface{}) ([]*models.Label, error)
+	// ListByNames はリポジトリ内の指定された名前のLabelを種類を問わず取得します
+	ListByNames(ctx context.Context, repositoryID int64, names []string) ([]*models.Label, error)
+	// CountUsage はLabelが付与されているIssueとDiscussionの数を取得します
+	CountUsage(ctx context.Context, id int64) (issues int64, discussions int64, err error)
+	// Delete はLabelを削除し、IssueとDiscussionからも取り除きます
 	Delete(ctx context.Context, id int64) error
+	// DeleteWithEvents はLabelを削除し、取り除いたIssueの変更履歴を同じトランザクションで記録します
+	DeleteWithEvents(ctx context.Context, id int64, actorID int64) error
 	// Search はLabelの全文検索を行います
 	Search(ctx context.Context, query string, page, limit int) ([]*models.Label, int, error)
 }
@@ -118,7 +222,7 @@ type LabelRepository interface {
 type MilestoneRepository interface {
 	// Create は新しいMilestoneを作成します
 	Create(ctx context.Context, milestone *models.Milestone) error
-	// GetByID はIDによってMilestoneを取得します
+	// GetByID はIDによってMilestoneを取得します（存在しない場合は gorm.ErrRecordNotFound）
 	GetByID(ctx context.Context, id int64) (*models.Milestone, error)
 	// List は条件に一致するMilestoneの一覧を取得します
 	List(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*models.Milestone, int, error)
@@ -134,15 +238,17 @@ type MilestoneRepository interface {
 type DiscussionRepository interface {
 	// Create は新しいDiscussionを作成します
 	Create(ctx context.Context, discussion *models.Discussion) error
-	// GetByID はIDによってDiscussionを取得します
+	// GetByID はIDによってDiscussionを取得します（存在しない場合は gorm.ErrRecordNotFound）
 	GetByID(ctx context.Context, id int64) (*models.Discussion, error)
+	// GetByNumber はリポジトリ内の番号によってDiscussionを取得します（存在しない場合はnil）
+	GetByNumber(ctx context.Context, repositoryID, number int64) (*models.Discussion, error)
 	// List は条件に一致するDiscussionの一覧を取得します
 	List(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*models.Discussion, int, error)
 	// Update は既存のDiscussionを更新します
 	Update(ctx context.Context, discussion *models.Discussion) error // Delete はDiscussionを削除します
 	Delete(ctx context.Context, id int64) error
 	// Search はDiscussionの全文検索を行います
-	Search(ctx context.Context, query string, page, limit int) ([]*models.Discussion, int, error)
+	Search(ctx context.Context, query string, filter map[string]interface{}, page, limit int) ([]*models.Discussion, int, error)
 	// CountDiscussions は総Discussion数を取得します
 	CountDiscussions(ctx context.Context) (int64, error)
 	// CountOpenDiscussions はオープンなDiscussion数を取得します
@@ -153,10 +259,12 @@ type DiscussionRepository interface {
 type CommentRepository interface {
 	// Create は新しいCommentを作成します
 	Create(ctx context.Context, comment *models.Comment) error
-	// GetByID はIDによってCommentを取得します
+	// GetByID はIDによってCommentを取得します（存在しない場合は gorm.ErrRecordNotFound）
 	GetByID(ctx context.Context, id int64) (*models.Comment, error)
 	// ListByTarget はターゲットIDとタイプによってCommentの一覧を取得します
 	ListByTarget(ctx context.Context, targetID int64, targetType string, page, limit int) ([]*models.Comment, int, error)
+	// ListAllByTarget はターゲットのすべてのCommentを作成順に取得します（返信を含む）
+	ListAllByTarget(ctx context.Context, targetID int64, targetType string) ([]*models.Comment, error)
 	// ListReplies は親コメントIDによって返信コメントの一覧を取得します
 	ListReplies(ctx context.Context, parentCommentID int64, page, limit int) ([]*models.Comment, int, error)
 	// Update は既存のCommentを更新します
@@ -168,6 +276,10 @@ type CommentRepository interface {
 	GetAllOfType(ctx context.Context, commentType string) ([]*models.Comment, error)
 	// CountComments は総コメント数を取得します
 	CountComments(ctx context.Context) (int64, error)
+	// ListCommenterIDs はターゲットにコメント（返信を含む）したユーザーIDの一覧を取得します
+	ListCommenterIDs(ctx context.Context, targetID int64, targetType string) ([]int64, error)
+	// MoveTarget はターゲットのコメントを返信のツリーごと別のターゲットに移動します
+	MoveTarget(ctx context.Context, fromType string, fromID int64, toType string, toID int64) error
 }
 
 // ReactionRepository はReaction関連のデータベース操作を抽象化するインターフェース
@@ -176,12 +288,98 @@ type ReactionRepository interface {
 	Create(ctx context.Context, reaction *models.Reaction) error
 	// GetByID はIDによってReactionを取得します
 	GetByID(ctx context.Context, id int64) (*models.Reaction, error)
-	// GetByUserAndComment はユーザーIDとコメントIDによってReactionを取得します
-	GetByUserAndComment(ctx context.Context, userID, commentID int64, emoji string) (*models.Reaction, error)
-	// ListByComment はコメントIDによってReactionの一覧を取得します
-	ListByComment(ctx context.Context, commentID int64) ([]*models.Reaction, error)
+	// GetByUserAndTarget はユーザー・対象・絵文字によってReactionを取得します（存在しない場合はnil）
+	GetByUserAndTarget(ctx context.Context, userID int64, targetType string, targetID int64, emoji string) (*models.Reaction, error)
+	// ListByTarget は対象のReactionの一覧を取得します
+	ListByTarget(ctx context.Context, targetType string, targetID int64) ([]*models.Reaction, error)
+	// SummarizeByTargets は複数の対象のReactionを絵文字ごとに1回のクエリで集計します
+	SummarizeByTargets(ctx context.Context, targetType string, targetIDs []int64, viewerID int64) (map[int64][]models.ReactionSummary, error)
 	// Delete はReactionを削除します
 	Delete(ctx context.Context, id int64) error
+	// DeleteByTarget は対象のReactionをすべて削除します
+	DeleteByTarget(ctx context.Context, targetType string, targetID int64) error
+	// MoveTarget は対象のReactionをすべて別の対象に移動します
+	MoveTarget(ctx context.Context, fromType string, fromID int64, toType string, toID int64) error
+}
+
+// BodyRevisionRepository は本文の編集履歴のデータベース操作を抽象化するインターフェース
+type BodyRevisionRepository interface {
+	// Create は新しい版を作成します
+	Create(ctx context.Context, revision *models.BodyRevision) error
+	// GetByID はIDによって版を取得します（存在しない場合はnil）
+	GetByID(ctx context.Context, id int64) (*models.BodyRevision, error)
+	// ListByTarget は対象の版を古い順に取得します
+	ListByTarget(ctx context.Context, targetType string, targetID int64) ([]*models.BodyRevision, error)
+	// Update は既存の版を更新します
+	Update(ctx context.Context, revision *models.BodyRevision) error
+	// DeleteByTarget は対象の版をすべて削除します
+	DeleteByTarget(ctx context.Context, targetType string, targetID int64) error
+	// MoveTarget は対象の版をすべて別の対象に移動します
+	MoveTarget(ctx context.Context, fromType string, fromID int64, toType string, toID int64) error
+}
+
+// IssueEventRepository はIssueの変更履歴のデータベース操作を抽象化するインターフェース
+// 変更履歴の記録は IssueRepository.UpdateWithEvents でIssueの更新と同時に行います
+type IssueEventRepository interface {
+	// ListByIssue はIssueの変更履歴を古い順に取得します
+	ListByIssue(ctx context.Context, issueID int64) ([]*models.IssueEvent, error)
+	// ListByIssues は複数のIssueの指定された種類の変更履歴を古い順に取得します
+	ListByIssues(ctx context.Context, issueIDs []int64, types []models.IssueEventType) ([]*models.IssueEvent, error)
+}
+
+// IssueLinkRepository はIssue間の関連のデータベース操作を抽象化するインターフェース
+type IssueLinkRepository interface {
+	// Create は新しい関連を作成します
+	Create(ctx context.Context, link *models.IssueLink) error
+	// GetByID はIDによって関連を取得します（存在しない場合はnil）
+	GetByID(ctx context.Context, id int64) (*models.IssueLink, error)
+	// ListByIssue はIssueが含まれる関連を作成順に取得します
+	ListByIssue(ctx context.Context, issueID int64) ([]*models.IssueLink, error)
+	// ListByRepository はリポジトリ内のIssue間の指定された種類の関連を取得します
+	ListByRepository(ctx context.Context, repositoryID int64, linkType models.IssueLinkType) ([]*models.IssueLink, error)
+	// CountChildren は親のIssueごと・ステータスごとの子のIssue数を取得します
+	CountChildren(ctx context.Context, parentIDs []int64) (map[int64]map[string]int, error)
+	// Delete は関連を削除します
+	Delete(ctx context.Context, id int64) error
+	// DeleteByIssue はIssueが含まれる関連をすべて削除します
+	DeleteByIssue(ctx context.Context, issueID int64) error
+}
+
+// TemplateRepository はIssue・Discussionのテンプレートのデータベース操作を抽象化するインターフェース
+type TemplateRepository interface {
+	// Create は新しいテンプレートを作成します
+	Create(ctx context.Context, template *models.Template) error
+	// GetByID はIDによってテンプレートを取得します（存在しない場合はnil）
+	GetByID(ctx context.Context, id int64) (*models.Template, error)
+	// GetByName はリポジトリ内の種類と名前によってテンプレートを取得します（存在しない場合はnil）
+	GetByName(ctx context.Context, repositoryID int64, templateType, name string) (*models.Template, error)
+	// List は条件（repository_id/type/category）に一致するテンプレートを名前順に取得します
+	List(ctx context.Context, filter map[string]interface{}) ([]*models.Template, error)
+	// Update は既存のテンプレートを更新します
+	Update(ctx context.Context, template *models.Template) error
+	// Delete はテンプレートを削除します
+	Delete(ctx context.Context, id int64) error
+}
+
+// RedirectRepository はIssue・Discussionの変換のリダイレクトのデータベース操作を抽象化するインターフェース
+type RedirectRepository interface {
+	// Create は新しいリダイレクトを作成します
+	Create(ctx context.Context, redirect *models.Redirect) error
+	// GetBySource は変換前の種類とIDによって最新のリダイレクトを取得します（存在しない場合はnil）
+	GetBySource(ctx context.Context, sourceType string, sourceID int64) (*models.Redirect, error)
+	// GetBySourceNumber は変換前の種類とリポジトリ内の番号によって最新のリダイレクトを取得します（存在しない場合はnil）
+	GetBySourceNumber(ctx context.Context, repositoryID int64, sourceType string, number int64) (*models.Redirect, error)
+	// Retarget は指定されたリソースを指すリダイレクトを変換後のリソースを指すように更新します
+	Retarget(ctx context.Context, fromType string, fromID int64, toType string, toID int64) error
+}
+
+// ConversionRepository はIssueとDiscussionの相互変換の書き込みを1つのトランザクションで行うインターフェース
+// 変換後のリソースの作成・コメントのツリーとリアクションと編集履歴の移動・リダイレクトの記録・変換前のリソースの削除のいずれかに失敗した場合はすべて取り消します
+type ConversionRepository interface {
+	// IssueToDiscussion はIssueをDiscussionに変換し、他のIssueとの関連を削除します（redirect の変換先IDは作成したDiscussionのIDに設定されます）
+	IssueToDiscussion(ctx context.Context, issue *models.Issue, discussion *models.Discussion, redirect *models.Redirect) error
+	// DiscussionToIssue はDiscussionをIssueに変換します（redirect の変換先IDは作成したIssueのIDに設定されます）
+	DiscussionToIssue(ctx context.Context, discussion *models.Discussion, issue *models.Issue, redirect *models.Redirect) error
 }
 
 // NotificationRepository はNotification関連のデータベース操作を抽象化するインターフェース
@@ -200,6 +398,38 @@ type NotificationRepository interface {
 	Delete(ctx context.Context, id int64) error
 }
 
+// MentionRepository はMention関連のデータベース操作を抽象化するインターフェース
+type MentionRepository interface {
+	// Create は新しいMentionを作成します
+	Create(ctx context.Context, mention *models.Mention) error
+	// ListBySource はメンション元（Issue・Discussion・コメント）によってMentionの一覧を取得します（本文から削除されたものを含む）
+	ListBySource(ctx context.Context, sourceType string, sourceID int64) ([]*models.Mention, error)
+	// ListByUser はメンションされたユーザーIDによってMentionの一覧を取得します（本文から削除されたものを除く）
+	ListByUser(ctx context.Context, userID int64, page, limit int) ([]*models.Mention, int, error)
+	// Update はMentionを更新します
+	Update(ctx context.Context, mention *models.Mention) error
+	// Delete はMentionを削除します
+	Delete(ctx context.Context, id int64) error
+}
+
+// SavedSearchRepository はSavedSearch関連のデータベース操作を抽象化するインターフェース
+type SavedSearchRepository interface {
+	// Create は新しいSavedSearchを作成します
+	Create(ctx context.Context, savedSearch *models.SavedSearch) error
+	// GetByID はIDによってSavedSearchを取得します（存在しない場合はnilを返します）
+	GetByID(ctx context.Context, id int64) (*models.SavedSearch, error)
+	// ListVisible は指定したユーザーが作成した、または共有されたSavedSearchの一覧を取得します
+	ListVisible(ctx context.Context, userID int64) ([]*models.SavedSearch, error)
+	// Update は既存のSavedSearchを更新します
+	Update(ctx context.Context, savedSearch *models.SavedSearch) error
+	// Delete はSavedSearchと閲覧履歴を削除します
+	Delete(ctx context.Context, id int64) error
+	// GetLastViewedAt はユーザーの最終閲覧日時を取得します（未閲覧の場合はゼロ値を返します）
+	GetLastViewedAt(ctx context.Context, savedSearchID, userID int64) (time.Time, error)
+	// MarkViewed はユーザーの最終閲覧日時を記録します
+	MarkViewed(ctx context.Context, savedSearchID, userID int64, viewedAt time.Time) error
+}
+
 // PushSubscriptionRepository はPushSubscription関連のデータベース操作を抽象化するインターフェース
 type PushSubscriptionRepository interface {
 	// Create は新しいPushSubscriptionを作成します
@@ -281,8 +511,34 @@ type RepositoryFactory interface {
 	NewCommentRepository() (CommentRepository, error)
 	// NewReactionRepository はReactionRepositoryの新しいインスタンスを生成します
 	NewReactionRepository() (ReactionRepository, error)
+	// NewIssueEventRepository はIssueEventRepositoryの新しいインスタンスを生成します
+	NewIssueEventRepository() (IssueEventRepository, error)
+	// NewBodyRevisionRepository はBodyRevisionRepositoryの新しいインスタンスを生成します
+	NewBodyRevisionRepository() (BodyRevisionRepository, error)
+	// NewIssueLinkRepository はIssueLinkRepositoryの新しいインスタンスを生成します
+	NewIssueLinkRepository() (IssueLinkRepository, error)
+	// NewTemplateRepository はTemplateRepositoryの新しいインスタンスを生成します
+	NewTemplateRepository() (TemplateRepository, error)
+	// NewRedirectRepository はRedirectRepositoryの新しいインスタンスを生成します
+	NewRedirectRepository() (RedirectRepository, error)
+	// NewPersonalAccessTokenRepository はPersonalAccessTokenRepositoryの新しいインスタンスを生成します
+	NewPersonalAccessTokenRepository() (PersonalAccessTokenRepository, error)
+	// NewUserIdentityRepository はUserIdentityRepositoryの新しいインスタンスを生成します
+	NewUserIdentityRepository() (UserIdentityRepository, error)
+	// NewOIDCLoginStateRepository はOIDCLoginStateRepositoryの新しいインスタンスを生成します
+	NewOIDCLoginStateRepository() (OIDCLoginStateRepository, error)
+	// NewTwoFactorRepository はTwoFactorRepositoryの新しいインスタンスを生成します
+	NewTwoFactorRepository() (TwoFactorRepository, error)
+	// NewEmailVerificationRepository はEmailVerificationRepositoryの新しいインスタンスを生成します
+	NewEmailVerificationRepository() (EmailVerificationRepository, error)
+	// NewLoginThrottleRepository はLoginThrottleRepositoryの新しいインスタンスを生成します
+	NewLoginThrottleRepository() (LoginThrottleRepository, error)
 	// NewNotificationRepository はNotificationRepositoryの新しいインスタンスを生成します
 	NewNotificationRepository() (NotificationRepository, error)
+	// NewMentionRepository はMentionRepositoryの新しいインスタンスを生成します
+	NewMentionRepository() (MentionRepository, error)
+	// NewSavedSearchRepository はSavedSearchRepositoryの新しいインスタンスを生成します
+	NewSavedSearchRepository() (SavedSearchRepository, error)
 	// NewPushSubscriptionRepository はPushSubscriptionRepositoryの新しいインスタンスを生成します
 	NewPushSubscriptionRepository() (PushSubscriptionRepository, error)
 	// NewNotificationTemplateRepository はNotificationTemplateRepositoryの新しいインスタンスを生成します
@@ -294,6 +550,8 @@ type RepositoryFactory interface {
 	NewBackupRepository() (BackupRepository, error)
 	// NewRepositoryRepository はRepositoryRepositoryの新しいインスタンスを生成します
 	NewRepositoryRepository() (RepositoryRepository, error)
+	// NewRepositoryMemberRepository はRepositoryMemberRepositoryの新しいインスタンスを生成します
+	NewRepositoryMemberRepository() (RepositoryMemberRepository, error)
 	// Close はデータベース接続をクローズします
 	Close() error
 }
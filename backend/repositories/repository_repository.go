@@ -16,8 +16,27 @@ type RepositoryRepository interface {
 	Delete(ctx context.Context, id int64) error
 	// GetByID はIDによってリポジトリを取得します
 	GetByID(ctx context.Context, id int64) (*models.Repository, error)
-	// GetByName は名前によってリポジトリを取得します
+	// GetByName は名前によってリポジトリを取得します（存在しない場合はnil）
 	GetByName(ctx context.Context, name string) (*models.Repository, error)
+	// GetDefault はリポジトリを指定しない場合に使用する既定のリポジトリを取得します（存在しない場合は作成）
+	GetDefault(ctx context.Context) (*models.Repository, error)
 	// List は条件に一致するリポジトリの一覧を取得します
 	List(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*models.Repository, int64, error)
+	// ListVisibleIDs はユーザーが閲覧できるリポジトリのIDを取得します
+	// 公開リポジトリ、ログインしている場合は組織内公開リポジトリ、所有またはメンバーとして参加しているリポジトリが対象です
+	ListVisibleIDs(ctx context.Context, userID int64) ([]int64, error)
+}
+
+// RepositoryMemberRepository はリポジトリのメンバーの管理を行うインターフェース
+type RepositoryMemberRepository interface {
+	// Save はメンバーを追加し、既に参加している場合はロールを更新します
+	Save(ctx context.Context, member *models.RepositoryMember) error
+	// Get はリポジトリとユーザーによってメンバーを取得します（存在しない場合はnil）
+	Get(ctx context.Context, repositoryID, userID int64) (*models.RepositoryMember, error)
+	// ListByRepository はリポジトリのメンバーの一覧を取得します
+	ListByRepository(ctx context.Context, repositoryID int64) ([]*models.RepositoryMember, error)
+	// Delete はメンバーをリポジトリから外します
+	Delete(ctx context.Context, repositoryID, userID int64) error
+	// DeleteByRepository はリポジトリのメンバーをすべて削除します
+	DeleteByRepository(ctx context.Context, repositoryID int64) error
 }
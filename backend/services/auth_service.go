@@ -25,6 +25,8 @@ const (
 	bcryptCost = 12
 	// CSRFトークンの有効期限（1時間）
 	csrfTokenExpiration = 1 * time.Hour
+	// 二要素認証の入力を待つ一時トークンの有効期限（5分）
+	mfaPendingTokenExpiration = 5 * time.Minute
 )
 
 var (
@@ -44,6 +46,8 @@ var (
 	ErrTokenRevoked = errors.New("token has been revoked")
 	// ErrPasswordResetTokenInvalid はパスワードリセットトークンが無効なエラー
 	ErrPasswordResetTokenInvalid = errors.New("password reset token is invalid or expired")
+	// ErrTwoFactorRequired はパスワードの認証に成功し、二要素認証のコードの入力が必要な場合のエラー
+	ErrTwoFactorRequired = errors.New("two-factor authentication required")
 )
 
 // JWTClaims はJWTトークンのクレーム
@@ -60,20 +64,27 @@ type AuthService struct {
 	userRepo          repositories.UserRepository
 	tokenRepo         repositories.AuthTokenRepository
 	passwordResetRepo repositories.PasswordResetRepository
+	twoFactorRepo     repositories.TwoFactorRepository
+	loginThrottle     *LoginThrottleService
 	jwtSecret         []byte
 }
 
 // NewAuthService は新しいAuthServiceを作成します
+// loginThrottle がnilの場合はログインの試行を制限しません
 func NewAuthService(
 	userRepo repositories.UserRepository,
 	tokenRepo repositories.AuthTokenRepository,
 	passwordResetRepo repositories.PasswordResetRepository,
+	twoFactorRepo repositories.TwoFactorRepository,
+	loginThrottle *LoginThrottleService,
 	jwtSecret string,
 ) *AuthService {
 	return &AuthService{
 		userRepo:          userRepo,
 		tokenRepo:         tokenRepo,
 		passwordResetRepo: passwordResetRepo,
+		twoFactorRepo:     twoFactorRepo,
+		loginThrottle:     loginThrottle,
 		jwtSecret:         []byte(jwtSecret),
 	}
 }
@@ -108,6 +119,8 @@ func (s *AuthService) Register(ctx context.Context, username, email, password, f
 }
 
 // Login はユーザーログインを行います
+// 二要素認証が有効なユーザーの場合はトークンを発行せずに ErrTwoFactorRequired を返します
+// 失敗が続いている場合は *LoginThrottledError を返し、パスワードが誤っている場合は（存在する場合）ユーザーと ErrInvalidCredentials を返します
 func (s *AuthService) Login(ctx context.Context, usernameOrEmail, password, userAgent, ipAddress string) (*models.User, string, string, error) {
 	// ユーザー名またはメールアドレスでユーザーを検索
 	var user *models.User
@@ -118,11 +131,23 @@ func (s *AuthService) Login(ctx context.Context, usernameOrEmail, password, user
 	if err != nil || user == nil {
 		// ユーザー名の場合
 		user, err = s.userRepo.GetByUsername(ctx, usernameOrEmail)
-		if err != nil || user == nil {
-			return nil, "", "", ErrInvalidCredentials
+		if err != nil {
+			user = nil
 		}
 	}
 
+	// 失敗が続いている場合は試行を制限（存在しないアカウントも同じように数える）
+	accountID := models.UnknownAccountThrottleIdentifier(usernameOrEmail)
+	if user != nil {
+		accountID = models.AccountThrottleIdentifier(user.ID)
+	}
+	if err := s.checkLoginThrottle(ctx, accountID, ipAddress); err != nil {
+		return nil, "", "", err
+	}
+	if user == nil {
+		return nil, "", "", s.loginFailed(ctx, accountID, ipAddress, ErrInvalidCredentials)
+	}
+
 	// アカウントが有効かチェック
 	if !user.IsActive {
 		return nil, "", "", ErrUserDisabled
@@ -130,7 +155,21 @@ func (s *AuthService) Login(ctx context.Context, usernameOrEmail, password, user
 
 	// パスワードの検証
 	if !s.CheckPasswordHash(password, user.Password) {
-		return nil, "", "", ErrInvalidCredentials
+		return user, "", "", s.loginFailed(ctx, accountID, ipAddress, ErrInvalidCredentials)
+	}
+
+	// 二要素認証が有効な場合はコードの入力が必要
+	twoFactorEnabled, err := s.IsTwoFactorEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if twoFactorEnabled {
+		// 失敗回数は二要素認証の完了まで消去しない
+		return user, "", "", ErrTwoFactorRequired
+	}
+
+	if err := s.loginSucceeded(ctx, accountID); err != nil {
+		return nil, "", "", err
 	}
 
 	// 最終ログイン日時を更新
@@ -139,16 +178,59 @@ func (s *AuthService) Login(ctx context.Context, usernameOrEmail, password, user
 		return nil, "", "", fmt.Errorf("failed to update last login: %w", err)
 	}
 
+	// トークンの発行
+	accessToken, refreshToken, err := s.IssueTokens(ctx, user, userAgent, ipAddress)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return user, accessToken, refreshToken, nil
+}
+
+// checkLoginThrottle はアカウントとIPアドレスからログインを試行できるかどうかを確認します
+func (s *AuthService) checkLoginThrottle(ctx context.Context, accountID, ipAddress string) error {
+	if s.loginThrottle == nil {
+		return nil
+	}
+	return s.loginThrottle.Check(ctx, accountID, ipAddress)
+}
+
+// loginFailed はログインの失敗を記録し、cause を返します
+// この失敗でアカウントをロックした場合は cause と ErrAccountLocked の両方に該当するエラーを返します
+func (s *AuthService) loginFailed(ctx context.Context, accountID, ipAddress string, cause error) error {
+	if s.loginThrottle == nil {
+		return cause
+	}
+	locked, err := s.loginThrottle.RecordFailure(ctx, accountID, ipAddress)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return fmt.Errorf("%w: %w", cause, ErrAccountLocked)
+	}
+	return cause
+}
+
+// loginSucceeded はログインに成功したアカウントの失敗回数を消去します
+func (s *AuthService) loginSucceeded(ctx context.Context, accountID string) error {
+	if s.loginThrottle == nil {
+		return nil
+	}
+	return s.loginThrottle.RecordSuccess(ctx, accountID)
+}
+
+// IssueTokens は認証済みのユーザーにアクセストークンとリフレッシュトークンを発行します
+func (s *AuthService) IssueTokens(ctx context.Context, user *models.User, userAgent, ipAddress string) (string, string, error) {
 	// アクセストークンの生成
 	accessToken, err := s.GenerateJWT(user.ID, user.Username, user.IsAdmin, string(models.AccessToken), accessTokenExpiration)
 	if err != nil {
-		return nil, "", "", fmt.Errorf("failed to generate access token: %w", err)
+		return "", "", fmt.Errorf("failed to generate access token: %w", err)
 	}
 
 	// リフレッシュトークンの生成
 	refreshToken, err := s.GenerateRandomToken(32)
 	if err != nil {
-		return nil, "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
 	// トークンをデータベースに保存
@@ -161,10 +243,49 @@ func (s *AuthService) Login(ctx context.Context, usernameOrEmail, password, user
 		ipAddress,
 	)
 	if err := s.tokenRepo.Create(ctx, refreshTokenModel); err != nil {
-		return nil, "", "", fmt.Errorf("failed to save refresh token: %w", err)
+		return "", "", fmt.Errorf("failed to save refresh token: %w", err)
 	}
 
-	return user, accessToken, refreshToken, nil
+	return accessToken, refreshToken, nil
+}
+
+// IsTwoFactorEnabled はユーザーの二要素認証が有効かどうかを返します
+// パスワードとシングルサインオンのどちらのログインでも、有効な場合はトークンを発行する前にコードの入力を求めます
+func (s *AuthService) IsTwoFactorEnabled(ctx context.Context, userID int64) (bool, error) {
+	twoFactor, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+	return twoFactor != nil && twoFactor.Enabled, nil
+}
+
+// IssueMFAPendingToken はパスワードの認証に成功したユーザーに二要素認証の入力を待つ一時トークンを発行します
+func (s *AuthService) IssueMFAPendingToken(user *models.User) (string, int, error) {
+	token, err := s.GenerateJWT(user.ID, user.Username, user.IsAdmin, string(models.MFAPendingToken), mfaPendingTokenExpiration)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to generate mfa pending token: %w", err)
+	}
+	return token, int(mfaPendingTokenExpiration.Seconds()), nil
+}
+
+// ValidateMFAPendingToken は二要素認証の入力を待つ一時トークンを検証し、ユーザー情報を返します
+func (s *AuthService) ValidateMFAPendingToken(ctx context.Context, tokenString string) (*models.User, error) {
+	claims, err := s.VerifyJWT(tokenString)
+	if err != nil {
+		return nil, ErrTokenInvalid
+	}
+	if claims.TokenType != string(models.MFAPendingToken) {
+		return nil, ErrTokenInvalid
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil || user == nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.IsActive {
+		return nil, ErrUserDisabled
+	}
+	return user, nil
 }
 
 // Logout はユーザーログアウトを行います
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+)
+
+// ErrInvalidDiscussionCategory は変換先のDiscussionのカテゴリが不正な場合のエラー
+var ErrInvalidDiscussionCategory = errors.New("invalid category. Must be one of general, question, announcement, idea")
+
+// ConversionService はIssueとDiscussionの相互変換と変換前のIDのリダイレクトを扱うサービス
+// 変換後のリソースは同じ番号で作成し、本文・ラベル・コメントのツリー・リアクション・編集履歴を引き継ぎます
+type ConversionService struct {
+	conversionRepo repositories.ConversionRepository
+	labelRepo      repositories.LabelRepository
+	redirectRepo   repositories.RedirectRepository
+}
+
+// NewConversionService は新しいConversionServiceを作成します
+// 変換の書き込みは conversionRepo の1つのトランザクションで行い、検索インデックスはコミット後にインデックス更新デコレーターを通じて更新されます
+func NewConversionService(
+	conversionRepo repositories.ConversionRepository,
+	labelRepo repositories.LabelRepository,
+	redirectRepo repositories.RedirectRepository,
+) *ConversionService {
+	return &ConversionService{
+		conversionRepo: conversionRepo,
+		labelRepo:      labelRepo,
+		redirectRepo:   redirectRepo,
+	}
+}
+
+// ConversionResult は変換の結果を表す構造体
+type ConversionResult struct {
+	Issue         *models.Issue      `json:"issue,omitempty"`
+	Discussion    *models.Discussion `json:"discussion,omitempty"`
+	Redirect      *models.Redirect   `json:"redirect"`
+	DroppedLabels []string           `json:"dropped_labels"` // 変換先の種類に適用できないため外したラベル
+}
+
+// IssueToDiscussion はIssueを指定されたカテゴリのDiscussionに変換します
+// 担当者・マイルストーン・他のIssueとの関連は引き継がずに削除します
+func (s *ConversionService) IssueToDiscussion(ctx context.Context, issue *models.Issue, category string, actorID int64) (*ConversionResult, error) {
+	discussion := issue.ToDiscussion(category)
+	if !discussion.IsValid() {
+		return nil, ErrInvalidDiscussionCategory
+	}
+
+	labels, dropped, err := s.applicableLabels(ctx, issue.RepositoryID, issue.Labels, models.LabelTypeDiscussion)
+	if err != nil {
+		return nil, err
+	}
+	discussion.Labels = labels
+
+	// 変換後のDiscussionの作成から変換前のIssueの削除までを1つのトランザクションで行う
+	redirect := models.NewRedirect(issue.RepositoryID, issue.Number, models.RedirectTypeIssue, issue.ID, models.RedirectTypeDiscussion, 0, actorID)
+	if err := s.conversionRepo.IssueToDiscussion(ctx, issue, discussion, redirect); err != nil {
+		return nil, fmt.Errorf("failed to convert issue: %w", err)
+	}
+
+	return &ConversionResult{Discussion: discussion, Redirect: redirect, DroppedLabels: dropped}, nil
+}
+
+// DiscussionToIssue はDiscussionをIssueに変換します
+// 回答済みのDiscussionはクローズ済みのIssueになります
+func (s *ConversionService) DiscussionToIssue(ctx context.Context, discussion *models.Discussion, actorID int64) (*ConversionResult, error) {
+	issue := discussion.ToIssue()
+
+	labels, dropped, err := s.applicableLabels(ctx, discussion.RepositoryID, discussion.Labels, models.LabelTypeIssue)
+	if err != nil {
+		return nil, err
+	}
+	issue.Labels = labels
+
+	// 変換後のIssueの作成から変換前のDiscussionの削除までを1つのトランザクションで行う
+	redirect := models.NewRedirect(discussion.RepositoryID, discussion.Number, models.RedirectTypeDiscussion, discussion.ID, models.RedirectTypeIssue, 0, actorID)
+	if err := s.conversionRepo.DiscussionToIssue(ctx, discussion, issue, redirect); err != nil {
+		return nil, fmt.Errorf("failed to convert discussion: %w", err)
+	}
+
+	return &ConversionResult{Issue: issue, Redirect: redirect, DroppedLabels: dropped}, nil
+}
+
+// Resolve は変換前の種類とIDのリダイレクトを取得します（変換されていない場合はnil）
+func (s *ConversionService) Resolve(ctx context.Context, sourceType string, sourceID int64) (*models.Redirect, error) {
+	return s.redirectRepo.GetBySource(ctx, sourceType, sourceID)
+}
+
+// ResolveNumber は変換前の種類とリポジトリ内の番号のリダイレクトを取得します（変換されていない場合はnil）
+func (s *ConversionService) ResolveNumber(ctx context.Context, repositoryID int64, sourceType string, number int64) (*models.Redirect, error) {
+	return s.redirectRepo.GetBySourceNumber(ctx, repositoryID, sourceType, number)
+}
+
+// applicableLabels はラベルを変換先の種類に適用できるものとできないものに分けます
+func (s *ConversionService) applicableLabels(ctx context.Context, repositoryID int64, names []string, targetType string) ([]string, []string, error) {
+	kept, dropped := []string{}, []string{}
+	if len(names) == 0 {
+		return kept, dropped, nil
+	}
+
+	labels, err := s.labelRepo.ListByNames(ctx, repositoryID, names)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list labels: %w", err)
+	}
+	applicable := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		if label.AppliesTo(targetType) {
+			applicable[label.Name] = true
+		}
+	}
+	for _, name := range names {
+		if applicable[name] {
+			kept = append(kept, name)
+		} else {
+			dropped = append(dropped, name)
+		}
+	}
+	return kept, dropped, nil
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+)
+
+const (
+	// emailVerificationExpiration は確認トークンの有効期限
+	emailVerificationExpiration = 24 * time.Hour
+	// emailVerificationResendInterval は確認メールを再送信できるまでの間隔
+	emailVerificationResendInterval = time.Minute
+	// emailVerificationMaxPerHour は1時間あたりに送信できる確認メールの上限
+	emailVerificationMaxPerHour = 5
+)
+
+var (
+	// ErrEmailAlreadyVerified はメールアドレスが確認済みの場合のエラー
+	ErrEmailAlreadyVerified = errors.New("email address is already verified")
+	// ErrEmailVerificationTokenInvalid は確認トークンが無効なエラー
+	ErrEmailVerificationTokenInvalid = errors.New("email verification token is invalid or expired")
+)
+
+// EmailVerificationRateLimitError は確認メールの送信が続いたため次の送信まで待つ必要がある場合のエラー
+type EmailVerificationRateLimitError struct {
+	RetryAfter time.Duration // 次に送信できるまでの時間
+}
+
+func (e *EmailVerificationRateLimitError) Error() string {
+	return fmt.Sprintf("too many verification emails, retry after %s", e.RetryAfter)
+}
+
+// EmailVerificationService はメールアドレスの確認を管理するサービス
+// システム設定の RequireEmailVerify が有効な場合、登録時に確認メールを送信します
+type EmailVerificationService struct {
+	verificationRepo repositories.EmailVerificationRepository
+	userRepo         repositories.UserRepository
+	settingsRepo     repositories.SystemSettingsRepository
+	mailSender       MailSender
+}
+
+// NewEmailVerificationService は新しいEmailVerificationServiceを作成します
+func NewEmailVerificationService(
+	verificationRepo repositories.EmailVerificationRepository,
+	userRepo repositories.UserRepository,
+	settingsRepo repositories.SystemSettingsRepository,
+	mailSender MailSender,
+) *EmailVerificationService {
+	return &EmailVerificationService{
+		verificationRepo: verificationRepo,
+		userRepo:         userRepo,
+		settingsRepo:     settingsRepo,
+		mailSender:       mailSender,
+	}
+}
+
+// IsRequired はシステム設定でメールアドレスの確認が必須かどうかを返します
+func (s *EmailVerificationService) IsRequired(ctx context.Context) (bool, error) {
+	settings, err := s.settingsRepo.Get(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get system settings: %w", err)
+	}
+	return settings.RequireEmailVerify, nil
+}
+
+// StartVerification は登録したユーザーに確認メールを送信します
+// メールアドレスの確認が必須でない場合や確認済みの場合は送信せず、falseを返します
+func (s *EmailVerificationService) StartVerification(ctx context.Context, user *models.User) (bool, error) {
+	if user.IsEmailVerified() {
+		return false, nil
+	}
+	settings, err := s.settingsRepo.Get(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get system settings: %w", err)
+	}
+	if !settings.RequireEmailVerify {
+		return false, nil
+	}
+	if err := s.send(ctx, user, settings); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Resend は確認メールを再送信します
+// 以前に送信したトークンは無効になります。送信が続いている場合は *EmailVerificationRateLimitError を返します
+func (s *EmailVerificationService) Resend(ctx context.Context, userID int64) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return ErrUserNotFound
+	}
+	if user.IsEmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+
+	// 送信間隔と1時間あたりの送信回数を確認
+	now := time.Now()
+	recent, err := s.verificationRepo.ListCreatedSince(ctx, user.ID, now.Add(-time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to list email verifications: %w", err)
+	}
+	if wait := resendRetryAfter(recent, now); wait > 0 {
+		return &EmailVerificationRateLimitError{RetryAfter: wait}
+	}
+
+	settings, err := s.settingsRepo.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get system settings: %w", err)
+	}
+	return s.send(ctx, user, settings)
+}
+
+// Verify は確認トークンを検証し、ユーザーのメールアドレスを確認済みにします
+// トークンの発行後にメールアドレスが変更された場合は無効として扱います
+func (s *EmailVerificationService) Verify(ctx context.Context, token string) (*models.User, error) {
+	verification, err := s.verificationRepo.GetByTokenHash(ctx, models.HashEmailVerificationToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email verification: %w", err)
+	}
+	if verification == nil {
+		return nil, ErrEmailVerificationTokenInvalid
+	}
+
+	user, err := s.userRepo.GetByID(ctx, verification.UserID)
+	if err != nil || user == nil {
+		return nil, ErrEmailVerificationTokenInvalid
+	}
+	if !verification.IsValid(user) {
+		return nil, ErrEmailVerificationTokenInvalid
+	}
+
+	// トークンを使用済みにしてからメールアドレスを確認済みにする
+	verification.MarkAsUsed()
+	if err := s.verificationRepo.Update(ctx, verification); err != nil {
+		return nil, fmt.Errorf("failed to update email verification: %w", err)
+	}
+	if !user.IsEmailVerified() {
+		user.MarkEmailVerified()
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, fmt.Errorf("failed to update user: %w", err)
+		}
+	}
+	return user, nil
+}
+
+// send は以前のトークンを無効化し、新しいトークンを発行して確認メールを送信します
+func (s *EmailVerificationService) send(ctx context.Context, user *models.User, settings *models.SystemSettings) error {
+	if err := s.verificationRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to revoke existing email verifications: %w", err)
+	}
+
+	token, err := randomURLSafeString(32)
+	if err != nil {
+		return fmt.Errorf("failed to generate email verification token: %w", err)
+	}
+	verification := models.NewEmailVerification(user.ID, user.Email, token, emailVerificationExpiration)
+	if err := s.verificationRepo.Create(ctx, verification); err != nil {
+		return fmt.Errorf("failed to create email verification: %w", err)
+	}
+
+	link := strings.TrimRight(settings.SiteURL, "/") + "/verify-email?token=" + url.QueryEscape(token)
+	message := &MailMessage{
+		To:      user.Email,
+		Subject: fmt.Sprintf("[%s] Verify your email address", settings.SiteName),
+		Body: fmt.Sprintf("Hello %s,\r\n\r\nPlease verify your email address by opening the link below.\r\n\r\n%s\r\n\r\nThe link expires in %d hours. If you did not create an account, you can ignore this email.\r\n",
+			user.Username, link, int(emailVerificationExpiration.Hours())),
+	}
+	if err := s.mailSender.Send(ctx, message); err != nil {
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+	return nil
+}
+
+// resendRetryAfter は直近1時間に発行したトークン（新しい順）から、次に送信できるまでの時間を返します（すぐに送信できる場合は0）
+func resendRetryAfter(recent []*models.EmailVerification, now time.Time) time.Duration {
+	if len(recent) == 0 {
+		return 0
+	}
+	var wait time.Duration
+	if interval := recent[0].CreatedAt.Add(emailVerificationResendInterval).Sub(now); interval > wait {
+		wait = interval
+	}
+	if len(recent) >= emailVerificationMaxPerHour {
+		// 上限に達した場合は上限の回数前の送信から1時間経過するまで待つ
+		oldest := recent[emailVerificationMaxPerHour-1]
+		if hourly := oldest.CreatedAt.Add(time.Hour).Sub(now); hourly > wait {
+			wait = hourly
+		}
+	}
+	return wait
+}
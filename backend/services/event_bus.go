@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+)
+
+// EventType はドメインイベントの種類を表す型
+type EventType string
+
+const (
+	// EventIssueCreated はIssueが作成されたことを表します
+	EventIssueCreated EventType = "issue.created"
+	// EventIssueUpdated はIssueの内容が更新されたことを表します
+	EventIssueUpdated EventType = "issue.updated"
+	// EventIssueAssigned はIssueに担当者が追加されたことを表します
+	EventIssueAssigned EventType = "issue.assigned"
+	// EventIssueStatusChanged はIssueのステータスが変更されたことを表します
+	EventIssueStatusChanged EventType = "issue.status_changed"
+	// EventDiscussionCreated はDiscussionが作成されたことを表します
+	EventDiscussionCreated EventType = "discussion.created"
+	// EventDiscussionUpdated はDiscussionの内容が更新されたことを表します
+	EventDiscussionUpdated EventType = "discussion.updated"
+	// EventCommentCreated はコメント（返信を含む）が作成されたことを表します
+	EventCommentCreated EventType = "comment.created"
+	// EventCommentUpdated はコメントが編集されたことを表します
+	EventCommentUpdated EventType = "comment.updated"
+)
+
+// DomainEvent はハンドラーから発行されるドメインイベント
+type DomainEvent struct {
+	Type        EventType
+	ActorID     int64
+	TargetType  string // issue/discussion
+	TargetID    int64
+	Issue       *models.Issue      // Issue関連イベントの場合に設定
+	Discussion  *models.Discussion // Discussion関連イベントの場合に設定
+	Comment     *models.Comment    // コメント関連イベントの場合に設定
+	AssigneeIDs []int64            // 担当者の追加イベントの場合に追加された担当者を設定
+	OccurredAt  time.Time
+}
+
+// NewIssueEvent はIssue関連のドメインイベントを作成します
+func NewIssueEvent(eventType EventType, actorID int64, issue *models.Issue) *DomainEvent {
+	return &DomainEvent{
+		Type:       eventType,
+		ActorID:    actorID,
+		TargetType: "issue",
+		TargetID:   issue.ID,
+		Issue:      issue,
+		OccurredAt: time.Now(),
+	}
+}
+
+// NewIssueAssignedEvent はIssueに担当者が追加されたことを表すドメインイベントを作成します
+func NewIssueAssignedEvent(actorID int64, issue *models.Issue, assigneeIDs []int64) *DomainEvent {
+	event := NewIssueEvent(EventIssueAssigned, actorID, issue)
+	event.AssigneeIDs = assigneeIDs
+	return event
+}
+
+// NewDiscussionEvent はDiscussion関連のドメインイベントを作成します
+func NewDiscussionEvent(eventType EventType, actorID int64, discussion *models.Discussion) *DomainEvent {
+	return &DomainEvent{
+		Type:       eventType,
+		ActorID:    actorID,
+		TargetType: "discussion",
+		TargetID:   discussion.ID,
+		Discussion: discussion,
+		OccurredAt: time.Now(),
+	}
+}
+
+// NewCommentEvent はコメント関連のドメインイベントを作成します
+func NewCommentEvent(eventType EventType, actorID int64, targetType string, targetID int64, comment *models.Comment) *DomainEvent {
+	return &DomainEvent{
+		Type:       eventType,
+		ActorID:    actorID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Comment:    comment,
+		OccurredAt: time.Now(),
+	}
+}
+
+// EventHandler はドメインイベントを処理する関数
+type EventHandler func(ctx context.Context, event *DomainEvent) error
+
+// EventBus はドメインイベントを購読者へ配信するサービス
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers map[EventType][]EventHandler
+}
+
+// NewEventBus は新しいEventBusを作成します
+func NewEventBus() *EventBus {
+	return &EventBus{
+		handlers: make(map[EventType][]EventHandler),
+	}
+}
+
+// Subscribe は指定したイベントタイプの購読者を登録します
+func (b *EventBus) Subscribe(eventType EventType, handler EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish はイベントを購読者へ配信します
+// 購読者のエラーはログに記録し、発行元の処理は失敗させません
+func (b *EventBus) Publish(ctx context.Context, event *DomainEvent) {
+	if b == nil || event == nil {
+		return
+	}
+
+	b.mu.RLock()
+	handlers := append([]EventHandler(nil), b.handlers[event.Type]...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			log.Printf("Failed to handle event %s: %v", event.Type, err)
+		}
+	}
+}
@@ -56,6 +56,16 @@ func (f *RepositoryFactory) NewNotificationRepository() (repositories.Notificati
 	return gormrepo.NewNotificationRepository(f.gormDB), nil
 }
 
+// NewMentionRepository はMentionRepositoryを作成します
+func (f *RepositoryFactory) NewMentionRepository() (repositories.MentionRepository, error) {
+	return gormrepo.NewMentionRepository(f.gormDB), nil
+}
+
+// NewSavedSearchRepository はSavedSearchRepositoryを作成します
+func (f *RepositoryFactory) NewSavedSearchRepository() (repositories.SavedSearchRepository, error) {
+	return gormrepo.NewSavedSearchRepository(f.gormDB), nil
+}
+
 // NewPushSubscriptionRepository はPushSubscriptionRepositoryを作成します
 func (f *RepositoryFactory) NewPushSubscriptionRepository() (repositories.PushSubscriptionRepository, error) {
 	return gormrepo.NewPushSubscriptionRepository(f.gormDB), nil
@@ -108,6 +118,66 @@ func (f *RepositoryFactory) NewReactionRepository() (repositories.ReactionReposi
 	return gormrepo.NewReactionRepository(f.gormDB), nil
 }
 
+// NewIssueEventRepository はIssueEventRepositoryを作成します
+func (f *RepositoryFactory) NewIssueEventRepository() (repositories.IssueEventRepository, error) {
+	return gormrepo.NewIssueEventRepository(f.gormDB), nil
+}
+
+// NewBodyRevisionRepository はBodyRevisionRepositoryを作成します
+func (f *RepositoryFactory) NewBodyRevisionRepository() (repositories.BodyRevisionRepository, error) {
+	return gormrepo.NewBodyRevisionRepository(f.gormDB), nil
+}
+
+// NewIssueLinkRepository はIssueLinkRepositoryを作成します
+func (f *RepositoryFactory) NewIssueLinkRepository() (repositories.IssueLinkRepository, error) {
+	return gormrepo.NewIssueLinkRepository(f.gormDB), nil
+}
+
+// NewTemplateRepository はTemplateRepositoryを作成します
+func (f *RepositoryFactory) NewTemplateRepository() (repositories.TemplateRepository, error) {
+	return gormrepo.NewTemplateRepository(f.gormDB), nil
+}
+
+// NewRedirectRepository はRedirectRepositoryを作成します
+func (f *RepositoryFactory) NewRedirectRepository() (repositories.RedirectRepository, error) {
+	return gormrepo.NewRedirectRepository(f.gormDB), nil
+}
+
+// NewConversionRepository はConversionRepositoryを作成します
+func (f *RepositoryFactory) NewConversionRepository() (repositories.ConversionRepository, error) {
+	return gormrepo.NewConversionRepository(f.gormDB), nil
+}
+
+// NewPersonalAccessTokenRepository はPersonalAccessTokenRepositoryを作成します
+func (f *RepositoryFactory) NewPersonalAccessTokenRepository() (repositories.PersonalAccessTokenRepository, error) {
+	return gormrepo.NewPersonalAccessTokenRepository(f.gormDB), nil
+}
+
+// NewUserIdentityRepository はUserIdentityRepositoryを作成します
+func (f *RepositoryFactory) NewUserIdentityRepository() (repositories.UserIdentityRepository, error) {
+	return gormrepo.NewUserIdentityRepository(f.gormDB), nil
+}
+
+// NewOIDCLoginStateRepository はOIDCLoginStateRepositoryを作成します
+func (f *RepositoryFactory) NewOIDCLoginStateRepository() (repositories.OIDCLoginStateRepository, error) {
+	return gormrepo.NewOIDCLoginStateRepository(f.gormDB), nil
+}
+
+// NewTwoFactorRepository はTwoFactorRepositoryを作成します
+func (f *RepositoryFactory) NewTwoFactorRepository() (repositories.TwoFactorRepository, error) {
+	return gormrepo.NewTwoFactorRepository(f.gormDB), nil
+}
+
+// NewEmailVerificationRepository はEmailVerificationRepositoryを作成します
+func (f *RepositoryFactory) NewEmailVerificationRepository() (repositories.EmailVerificationRepository, error) {
+	return gormrepo.NewEmailVerificationRepository(f.gormDB), nil
+}
+
+// NewLoginThrottleRepository はLoginThrottleRepositoryを作成します
+func (f *RepositoryFactory) NewLoginThrottleRepository() (repositories.LoginThrottleRepository, error) {
+	return gormrepo.NewLoginThrottleRepository(f.gormDB), nil
+}
+
 // NewSearchService は検索サービスを作成します
 func (f *RepositoryFactory) NewSearchService() (SearchService, error) {
 	issueRepo, err := f.NewIssueRepository()
@@ -120,7 +190,22 @@ func (f *RepositoryFactory) NewSearchService() (SearchService, error) {
 		return nil, err
 	}
 
-	return NewSearchService(f.gormDB, issueRepo, commentRepo)
+	discussionRepo, err := f.NewDiscussionRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	milestoneRepo, err := f.NewMilestoneRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	labelRepo, err := f.NewLabelRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSearchService(f.gormDB, issueRepo, commentRepo, discussionRepo, milestoneRepo, labelRepo)
 }
 
 // NewSystemSettingsRepository はSystemSettingsRepositoryを作成します
@@ -143,6 +228,11 @@ func (f *RepositoryFactory) NewRepositoryRepository() (repositories.RepositoryRe
 	return gormrepo.NewRepositoryRepository(f.gormDB), nil
 }
 
+// NewRepositoryMemberRepository はRepositoryMemberRepositoryを作成します
+func (f *RepositoryFactory) NewRepositoryMemberRepository() (repositories.RepositoryMemberRepository, error) {
+	return gormrepo.NewRepositoryMemberRepository(f.gormDB), nil
+}
+
 // Close はデータベース接続をクローズします (GORMでは通常不要ですが、インターフェース互換性のために残すことも検討)
 // GORMでは *gorm.DB のクローズは sql.DB 経由で行うため、このファクトリレベルでの明示的なCloseは不要かもしれません。
 // もしアプリケーション終了時にDB接続を確実に閉じる必要がある場合は、main関数などで *gorm.DB から sql.DB を取得して Close() を呼び出してください。
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+)
+
+var (
+	// ErrIssueLinkNotFound は指定された関連がIssueに存在しない場合のエラー
+	ErrIssueLinkNotFound = errors.New("issue link not found")
+	// ErrIssueLinkTargetNotFound は関連先のIssueが同じリポジトリに存在しない場合のエラー
+	ErrIssueLinkTargetNotFound = errors.New("linked issue not found in the same repository")
+	// ErrIssueLinkExists は同じ関連が既に存在する場合のエラー
+	ErrIssueLinkExists = errors.New("issue link already exists")
+	// ErrIssueLinkCycle は関連を追加すると循環する場合のエラー
+	ErrIssueLinkCycle = errors.New("issue link would create a cycle")
+	// ErrIssueLinkParentExists は子のIssueに既に親が設定されている場合のエラー
+	ErrIssueLinkParentExists = errors.New("issue already has a parent")
+)
+
+// IssueLinkService はIssue間の関連の追加・削除と関連グラフの作成を行うサービス
+type IssueLinkService struct {
+	linkRepo  repositories.IssueLinkRepository
+	issueRepo repositories.IssueRepository
+}
+
+// NewIssueLinkService は新しいIssueLinkServiceを作成します
+func NewIssueLinkService(linkRepo repositories.IssueLinkRepository, issueRepo repositories.IssueRepository) *IssueLinkService {
+	return &IssueLinkService{linkRepo: linkRepo, issueRepo: issueRepo}
+}
+
+// Create はIssueから見た関連の種類で同じリポジトリ内のIssueとの関連を追加します
+// 重複する関連、子のIssueへの2つ目の親、同じ種類の関連の循環は追加できません
+func (s *IssueLinkService) Create(ctx context.Context, issue *models.Issue, otherIssueID int64, relation models.IssueLinkType, creatorID int64) (*models.IssueLink, error) {
+	link, err := models.NewIssueLink(issue.ID, otherIssueID, relation, creatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 関連先のIssueの確認
+	others, err := s.issueRepo.ListByIDs(ctx, []int64{otherIssueID})
+	if err != nil {
+		return nil, err
+	}
+	if len(others) == 0 || others[0].RepositoryID != issue.RepositoryID {
+		return nil, ErrIssueLinkTargetNotFound
+	}
+
+	// 重複の確認
+	existing, err := s.linkRepo.ListByIssue(ctx, issue.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issue links: %w", err)
+	}
+	for _, l := range existing {
+		if l.Duplicates(link) {
+			return nil, ErrIssueLinkExists
+		}
+	}
+
+	// 親は1つのみ
+	if link.Type == models.IssueLinkParentOf {
+		parents, err := s.linkRepo.ListByIssue(ctx, link.TargetIssueID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issue links: %w", err)
+		}
+		for _, l := range parents {
+			if l.Type == models.IssueLinkParentOf && l.TargetIssueID == link.TargetIssueID {
+				return nil, ErrIssueLinkParentExists
+			}
+		}
+	}
+
+	// 循環の確認
+	if link.Type.IsAcyclic() {
+		links, err := s.linkRepo.ListByRepository(ctx, issue.RepositoryID, link.Type)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issue links: %w", err)
+		}
+		if models.IssueLinkCreatesCycle(links, link) {
+			return nil, ErrIssueLinkCycle
+		}
+	}
+
+	if err := s.linkRepo.Create(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to create issue link: %w", err)
+	}
+	link.Relation = relation
+	return link, nil
+}
+
+// Delete はIssueに含まれる関連を削除します
+func (s *IssueLinkService) Delete(ctx context.Context, issueID, linkID int64) error {
+	link, err := s.linkRepo.GetByID(ctx, linkID)
+	if err != nil {
+		return err
+	}
+	if link == nil || !link.Involves(issueID) {
+		return ErrIssueLinkNotFound
+	}
+	return s.linkRepo.Delete(ctx, linkID)
+}
+
+// Graph はIssueとその直接の関連先からなる関連グラフを作成します
+func (s *IssueLinkService) Graph(ctx context.Context, issue *models.Issue) (*models.IssueLinkGraph, error) {
+	links, err := s.linkRepo.ListByIssue(ctx, issue.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issue links: %w", err)
+	}
+
+	ids := make([]int64, 0, len(links))
+	for _, link := range links {
+		ids = append(ids, link.OtherIssueID(issue.ID))
+	}
+	issues, err := s.issueRepo.ListByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.BuildIssueLinkGraph(issue, links, issues), nil
+}
+
+// OpenBlockers はIssueの完了を妨げているオープンなIssueを取得します
+func (s *IssueLinkService) OpenBlockers(ctx context.Context, issue *models.Issue) ([]*models.IssueLinkNode, error) {
+	graph, err := s.Graph(ctx, issue)
+	if err != nil {
+		return nil, err
+	}
+	return graph.OpenBlockers(), nil
+}
+
+// AttachSubIssueProgress はIssueの一覧に子のIssueのクローズ数を設定します
+func (s *IssueLinkService) AttachSubIssueProgress(ctx context.Context, issues []*models.Issue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	counts, err := s.linkRepo.CountChildren(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to count sub-issues: %w", err)
+	}
+
+	for _, issue := range issues {
+		issue.SubIssueProgress = models.NewSubIssueProgress(counts[issue.ID])
+	}
+	return nil
+}
+
+// RemoveAll はIssueが含まれる関連をすべて削除します
+func (s *IssueLinkService) RemoveAll(ctx context.Context, issueID int64) error {
+	return s.linkRepo.DeleteByIssue(ctx, issueID)
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+)
+
+var (
+	// ErrAccountLocked はログインの失敗が続いたためアカウントが一時的にロックされている場合のエラー
+	ErrAccountLocked = errors.New("account is temporarily locked due to too many failed login attempts")
+	// ErrTooManyLoginAttempts はログインの失敗が続いたため次の試行まで待つ必要がある場合のエラー
+	ErrTooManyLoginAttempts = errors.New("too many failed login attempts")
+)
+
+// LoginThrottledError はログインの試行を制限している場合のエラー
+type LoginThrottledError struct {
+	Err        error         // ErrAccountLocked または ErrTooManyLoginAttempts
+	RetryAfter time.Duration // 次に試行できるまでの時間
+}
+
+func (e *LoginThrottledError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return e.Err
+}
+
+// loginPolicy はシステム設定から読み込んだログインの試行の制限
+type loginPolicy struct {
+	maxFailedAttempts   int
+	ipMaxFailedAttempts int
+	lockout             time.Duration
+	backoffBase         time.Duration
+	backoffMax          time.Duration
+}
+
+// LoginThrottleService はアカウントとIPアドレスごとにログインの失敗を数え、試行を制限するサービス
+// 失敗のたびに次の試行までの待ち時間を2倍にし、失敗回数が上限に達したアカウントは一定時間ロックします
+type LoginThrottleService struct {
+	throttleRepo repositories.LoginThrottleRepository
+	settingsRepo repositories.SystemSettingsRepository
+}
+
+// NewLoginThrottleService は新しいLoginThrottleServiceを作成します
+func NewLoginThrottleService(throttleRepo repositories.LoginThrottleRepository, settingsRepo repositories.SystemSettingsRepository) *LoginThrottleService {
+	return &LoginThrottleService{
+		throttleRepo: throttleRepo,
+		settingsRepo: settingsRepo,
+	}
+}
+
+// Check はアカウントとIPアドレスからログインを試行できるかどうかを確認します
+// 試行できない場合は *LoginThrottledError を返します
+func (s *LoginThrottleService) Check(ctx context.Context, accountID, ipAddress string) error {
+	policy, err := s.policy(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	account, err := s.throttleRepo.Get(ctx, models.LoginThrottleAccount, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to get login failures: %w", err)
+	}
+	if account != nil {
+		wait := account.RetryAfter(now, policy.backoffBase, policy.backoffMax)
+		if account.IsLocked(now) {
+			return &LoginThrottledError{Err: ErrAccountLocked, RetryAfter: wait}
+		}
+		if wait > 0 {
+			return &LoginThrottledError{Err: ErrTooManyLoginAttempts, RetryAfter: wait}
+		}
+	}
+
+	if ipAddress == "" {
+		return nil
+	}
+	ip, err := s.throttleRepo.Get(ctx, models.LoginThrottleIP, ipAddress)
+	if err != nil {
+		return fmt.Errorf("failed to get login failures: %w", err)
+	}
+	if ip != nil {
+		// IPアドレスのロックはアカウントのロックと区別せずに待ち時間として返す
+		if wait := ip.RetryAfter(now, policy.backoffBase, policy.backoffMax); wait > 0 {
+			return &LoginThrottledError{Err: ErrTooManyLoginAttempts, RetryAfter: wait}
+		}
+	}
+	return nil
+}
+
+// RecordFailure はアカウントとIPアドレスのログインの失敗を記録し、失敗回数が上限に達した場合はロックします
+// この失敗でアカウントをロックした場合はtrueを返します
+func (s *LoginThrottleService) RecordFailure(ctx context.Context, accountID, ipAddress string) (bool, error) {
+	policy, err := s.policy(ctx)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+
+	locked, err := s.recordFailure(ctx, models.LoginThrottleAccount, accountID, policy.maxFailedAttempts, policy.lockout, now)
+	if err != nil {
+		return false, err
+	}
+	if ipAddress != "" {
+		if _, err := s.recordFailure(ctx, models.LoginThrottleIP, ipAddress, policy.ipMaxFailedAttempts, policy.lockout, now); err != nil {
+			return locked, err
+		}
+	}
+	return locked, nil
+}
+
+// RecordSuccess はログインに成功したアカウントの失敗回数を消去します
+// IPアドレスの失敗回数は、同じIPアドレスから別のアカウントを試す攻撃を止めるために残します
+func (s *LoginThrottleService) RecordSuccess(ctx context.Context, accountID string) error {
+	if err := s.throttleRepo.Delete(ctx, models.LoginThrottleAccount, accountID); err != nil {
+		return fmt.Errorf("failed to clear login failures: %w", err)
+	}
+	return nil
+}
+
+// Unlock は管理者がユーザーのアカウントのロックを解除し、失敗回数を消去します
+// ロックされていた場合はtrueを返します
+func (s *LoginThrottleService) Unlock(ctx context.Context, userID int64) (bool, error) {
+	accountID := models.AccountThrottleIdentifier(userID)
+	account, err := s.throttleRepo.Get(ctx, models.LoginThrottleAccount, accountID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get login failures: %w", err)
+	}
+	if account == nil {
+		return false, nil
+	}
+	if err := s.throttleRepo.Delete(ctx, models.LoginThrottleAccount, accountID); err != nil {
+		return false, fmt.Errorf("failed to unlock account: %w", err)
+	}
+	return account.IsLocked(time.Now()), nil
+}
+
+// recordFailure は失敗を記録し、失敗回数が maxFailedAttempts に達した場合は lockout の間ロックします（0の場合はロックしない）
+func (s *LoginThrottleService) recordFailure(ctx context.Context, scope models.LoginThrottleScope, identifier string, maxFailedAttempts int, lockout time.Duration, now time.Time) (bool, error) {
+	throttle, err := s.throttleRepo.RecordFailure(ctx, scope, identifier, now, lockout)
+	if err != nil {
+		return false, fmt.Errorf("failed to record login failure: %w", err)
+	}
+	if maxFailedAttempts <= 0 || throttle.FailedCount < maxFailedAttempts {
+		return false, nil
+	}
+	locked, err := s.throttleRepo.Lock(ctx, scope, identifier, now.Add(lockout), now)
+	if err != nil {
+		return false, fmt.Errorf("failed to lock account: %w", err)
+	}
+	return locked, nil
+}
+
+// policy はシステム設定からログインの試行の制限を読み込みます
+func (s *LoginThrottleService) policy(ctx context.Context) (*loginPolicy, error) {
+	settings, err := s.settingsRepo.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system settings: %w", err)
+	}
+	lockoutMinutes := settings.LoginLockoutMinutes
+	if lockoutMinutes <= 0 {
+		// 以前に保存された0が残っている場合も、失敗回数を数え直すまでの時間がなくならないよう既定値を使う
+		lockoutMinutes = models.NewDefaultSystemSettings().LoginLockoutMinutes
+	}
+	return &loginPolicy{
+		maxFailedAttempts:   settings.LoginMaxFailedAttempts,
+		ipMaxFailedAttempts: settings.LoginIPMaxFailedAttempts,
+		lockout:             time.Duration(lockoutMinutes) * time.Minute,
+		backoffBase:         time.Duration(settings.LoginBackoffBaseSeconds) * time.Second,
+		backoffMax:          time.Duration(settings.LoginBackoffMaxSeconds) * time.Second,
+	}, nil
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/smtp"
+	"sync"
+)
+
+// MailMessage は送信するメールを表す構造体
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+	HTML    bool // 本文がHTMLの場合はtrue
+}
+
+// MailSender はメールの送信を抽象化するインターフェース
+type MailSender interface {
+	// Send はメールを送信します
+	Send(ctx context.Context, message *MailMessage) error
+}
+
+// SMTPMailSender はSMTPサーバーを経由してメールを送信するMailSender
+type SMTPMailSender struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+}
+
+// NewSMTPMailSender は新しいSMTPMailSenderを作成します
+func NewSMTPMailSender(host string, port int, username, password, from string) *SMTPMailSender {
+	return &SMTPMailSender{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+// Send はSMTPサーバーを経由してメールを送信します
+func (s *SMTPMailSender) Send(ctx context.Context, message *MailMessage) error {
+	// SMTPサーバーに接続
+	auth := smtp.PlainAuth("", s.username, s.password, s.host)
+
+	contentType := "text/plain; charset=UTF-8"
+	if message.HTML {
+		contentType = "text/html; charset=UTF-8"
+	}
+
+	// メールヘッダーを設定
+	headers := [][2]string{
+		{"From", s.from},
+		{"To", message.To},
+		{"Subject", message.Subject},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", contentType},
+	}
+
+	// ヘッダーをメッセージに追加
+	body := ""
+	for _, header := range headers {
+		body += fmt.Sprintf("%s: %s\r\n", header[0], header[1])
+	}
+	body += "\r\n" + message.Body
+
+	// メールを送信
+	return smtp.SendMail(
+		fmt.Sprintf("%s:%d", s.host, s.port),
+		auth,
+		s.from,
+		[]string{message.To},
+		[]byte(body),
+	)
+}
+
+// LogMailSender はメールを送信せずにログへ出力するMailSender（SMTPサーバーを設定していない開発環境向け）
+type LogMailSender struct{}
+
+// NewLogMailSender は新しいLogMailSenderを作成します
+func NewLogMailSender() *LogMailSender {
+	return &LogMailSender{}
+}
+
+// Send はメールの内容をログに出力します
+func (s *LogMailSender) Send(ctx context.Context, message *MailMessage) error {
+	log.Printf("Mail to %s: %s\n%s", message.To, message.Subject, message.Body)
+	return nil
+}
+
+// MemoryMailSender は送信したメールをメモリに保持するMailSender（テスト向け）
+type MemoryMailSender struct {
+	mu       sync.Mutex
+	messages []MailMessage
+}
+
+// NewMemoryMailSender は新しいMemoryMailSenderを作成します
+func NewMemoryMailSender() *MemoryMailSender {
+	return &MemoryMailSender{}
+}
+
+// Send はメールをメモリに保持します
+func (s *MemoryMailSender) Send(ctx context.Context, message *MailMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, *message)
+	return nil
+}
+
+// Messages はこれまでに送信したメールを送信順に返します
+func (s *MemoryMailSender) Messages() []MailMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]MailMessage(nil), s.messages...)
+}
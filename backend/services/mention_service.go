@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+)
+
+// MentionService は本文中の@メンションを管理するサービス
+type MentionService struct {
+	mentionRepo         repositories.MentionRepository
+	userRepo            repositories.UserRepository
+	notificationService *NotificationService
+}
+
+// NewMentionService は新しいMentionServiceを作成します
+func NewMentionService(
+	mentionRepo repositories.MentionRepository,
+	userRepo repositories.UserRepository,
+	notificationService *NotificationService,
+) *MentionService {
+	return &MentionService{
+		mentionRepo:         mentionRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+	}
+}
+
+// Register はEventBusに本文の作成・編集イベントの購読者を登録します
+func (s *MentionService) Register(bus *EventBus) {
+	for _, eventType := range []EventType{
+		EventIssueCreated, EventIssueUpdated,
+		EventDiscussionCreated, EventDiscussionUpdated,
+		EventCommentCreated, EventCommentUpdated,
+	} {
+		bus.Subscribe(eventType, s.onBodyChanged)
+	}
+}
+
+// onBodyChanged はイベント対象の本文からメンションを同期します
+func (s *MentionService) onBodyChanged(ctx context.Context, event *DomainEvent) error {
+	switch {
+	case event.Comment != nil:
+		return s.SyncMentions(ctx, "comment", event.Comment.ID, event.ActorID, event.Comment.Body)
+	case event.Issue != nil:
+		return s.SyncMentions(ctx, "issue", event.Issue.ID, event.ActorID, event.Issue.Body)
+	case event.Discussion != nil:
+		return s.SyncMentions(ctx, "discussion", event.Discussion.ID, event.ActorID, event.Discussion.Body)
+	}
+	return nil
+}
+
+// SyncMentions は本文のメンションを保存済みのメンションと同期し、新たにメンションされたユーザーにのみ通知します
+// 本文から削除されたメンションは削除日時を記録して残し、再度メンションされた場合は通知せずに有効に戻します
+func (s *MentionService) SyncMentions(ctx context.Context, sourceType string, sourceID, actorID int64, body string) error {
+	existing, err := s.mentionRepo.ListBySource(ctx, sourceType, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to list mentions: %w", err)
+	}
+
+	existingByUser := make(map[int64]*models.Mention, len(existing))
+	for _, mention := range existing {
+		existingByUser[mention.UserID] = mention
+	}
+
+	// 本文のユーザー名を解決（存在しないユーザーは無視）
+	mentioned := make(map[int64]bool)
+	var added []int64
+	for _, username := range models.ParseMentions(body) {
+		user, err := s.userRepo.GetByUsername(ctx, username)
+		if err != nil || user == nil || mentioned[user.ID] {
+			continue
+		}
+		mentioned[user.ID] = true
+
+		if mention, ok := existingByUser[user.ID]; ok {
+			if mention.IsRemoved() {
+				mention.Restore()
+				if err := s.mentionRepo.Update(ctx, mention); err != nil {
+					return fmt.Errorf("failed to restore mention: %w", err)
+				}
+			}
+			continue
+		}
+		if err := s.mentionRepo.Create(ctx, models.NewMention(user.ID, sourceType, sourceID, actorID)); err != nil {
+			return fmt.Errorf("failed to create mention: %w", err)
+		}
+		added = append(added, user.ID)
+	}
+
+	// 本文から削除されたメンションを記録
+	for userID, mention := range existingByUser {
+		if mentioned[userID] || mention.IsRemoved() {
+			continue
+		}
+		mention.MarkRemoved()
+		if err := s.mentionRepo.Update(ctx, mention); err != nil {
+			return fmt.Errorf("failed to remove mention: %w", err)
+		}
+	}
+
+	var errs []error
+	message := fmt.Sprintf("You were mentioned in %s #%d", sourceType, sourceID)
+	for _, userID := range added {
+		if userID == actorID {
+			continue
+		}
+		err := s.notificationService.CreateNotification(
+			ctx, userID, models.NotificationTypeMention, sourceType, sourceID, actorID, message)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to notify user %d: %w", userID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ListMentions はユーザーがメンションされた一覧を取得します
+func (s *MentionService) ListMentions(ctx context.Context, userID int64, page, limit int) ([]*models.Mention, int, error) {
+	return s.mentionRepo.ListByUser(ctx, userID, page, limit)
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+)
+
+// NotificationDispatcher はドメインイベントを関係者への通知に変換するサービス
+type NotificationDispatcher struct {
+	notificationService *NotificationService
+	issueRepo           repositories.IssueRepository
+	discussionRepo      repositories.DiscussionRepository
+	commentRepo         repositories.CommentRepository
+}
+
+// NewNotificationDispatcher は新しいNotificationDispatcherを作成します
+func NewNotificationDispatcher(
+	notificationService *NotificationService,
+	issueRepo repositories.IssueRepository,
+	discussionRepo repositories.DiscussionRepository,
+	commentRepo repositories.CommentRepository,
+) *NotificationDispatcher {
+	return &NotificationDispatcher{
+		notificationService: notificationService,
+		issueRepo:           issueRepo,
+		discussionRepo:      discussionRepo,
+		commentRepo:         commentRepo,
+	}
+}
+
+// Register はEventBusに通知用の購読者を登録します
+func (d *NotificationDispatcher) Register(bus *EventBus) {
+	bus.Subscribe(EventIssueCreated, d.onIssueCreated)
+	bus.Subscribe(EventIssueAssigned, d.onIssueAssigned)
+	bus.Subscribe(EventIssueStatusChanged, d.onIssueStatusChanged)
+	bus.Subscribe(EventCommentCreated, d.onCommentCreated)
+}
+
+// onIssueCreated は作成されたIssueの担当者に通知します
+func (d *NotificationDispatcher) onIssueCreated(ctx context.Context, event *DomainEvent) error {
+	issue := event.Issue
+	message := fmt.Sprintf("You were assigned to issue #%d: %s", issue.ID, issue.Title)
+	return d.notify(ctx, event, models.NotificationTypeAssign, message, issue.Assignees()...)
+}
+
+// onIssueAssigned は追加された担当者とIssueの作成者に通知します
+func (d *NotificationDispatcher) onIssueAssigned(ctx context.Context, event *DomainEvent) error {
+	issue := event.Issue
+	if len(event.AssigneeIDs) == 0 {
+		return nil
+	}
+
+	assignMessage := fmt.Sprintf("You were assigned to issue #%d: %s", issue.ID, issue.Title)
+	err := d.notify(ctx, event, models.NotificationTypeAssign, assignMessage, event.AssigneeIDs...)
+
+	updateMessage := fmt.Sprintf("Issue #%d was assigned: %s", issue.ID, issue.Title)
+	recipients := excludeIDs([]int64{issue.CreatorID}, event.AssigneeIDs...)
+	return errors.Join(err, d.notify(ctx, event, models.NotificationTypeUpdate, updateMessage, recipients...))
+}
+
+// onIssueStatusChanged は担当者・作成者・過去のコメント投稿者に通知します
+func (d *NotificationDispatcher) onIssueStatusChanged(ctx context.Context, event *DomainEvent) error {
+	issue := event.Issue
+
+	commenterIDs, err := d.commentRepo.ListCommenterIDs(ctx, issue.ID, "issue")
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Issue #%d was %s: %s", issue.ID, issue.Status, issue.Title)
+	recipients := append(append([]int64{issue.CreatorID}, issue.Assignees()...), commenterIDs...)
+	return d.notify(ctx, event, models.NotificationTypeUpdate, message, recipients...)
+}
+
+// onCommentCreated はターゲットの関係者と過去のコメント投稿者に通知します
+func (d *NotificationDispatcher) onCommentCreated(ctx context.Context, event *DomainEvent) error {
+	var recipients []int64
+	var title string
+
+	switch event.TargetType {
+	case "issue":
+		issue, err := d.issueRepo.GetByID(ctx, event.TargetID)
+		if err != nil {
+			return err
+		}
+		recipients = append(recipients, issue.CreatorID)
+		recipients = append(recipients, issue.Assignees()...)
+		title = issue.Title
+	case "discussion":
+		discussion, err := d.discussionRepo.GetByID(ctx, event.TargetID)
+		if err != nil {
+			return err
+		}
+		recipients = append(recipients, discussion.CreatorID)
+		title = discussion.Title
+	default:
+		return fmt.Errorf("unsupported comment target type: %s", event.TargetType)
+	}
+
+	// 返信の場合は親コメントの投稿者にも通知する
+	if event.Comment.IsReply() {
+		parent, err := d.commentRepo.GetByID(ctx, event.Comment.ParentCommentID)
+		if err != nil {
+			return err
+		}
+		recipients = append(recipients, parent.CreatorID)
+	}
+
+	commenterIDs, err := d.commentRepo.ListCommenterIDs(ctx, event.TargetID, event.TargetType)
+	if err != nil {
+		return err
+	}
+	recipients = append(recipients, commenterIDs...)
+
+	message := fmt.Sprintf("New comment on %s #%d: %s", event.TargetType, event.TargetID, title)
+	return d.notify(ctx, event, models.NotificationTypeComment, message, recipients...)
+}
+
+// notify は重複とイベント発行者を除いた受信者に通知を作成します
+func (d *NotificationDispatcher) notify(
+	ctx context.Context,
+	event *DomainEvent,
+	notificationType string,
+	message string,
+	recipients ...int64) error {
+
+	var errs []error
+	seen := make(map[int64]bool)
+	for _, userID := range recipients {
+		if userID == 0 || userID == event.ActorID || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		err := d.notificationService.CreateNotification(
+			ctx, userID, notificationType, event.TargetType, event.TargetID, event.ActorID, message)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to notify user %d: %w", userID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// excludeIDs は指定したIDを除いたスライスを返します
+func excludeIDs(ids []int64, exclude ...int64) []int64 {
+	excluded := make(map[int64]bool, len(exclude))
+	for _, id := range exclude {
+		excluded[id] = true
+	}
+	result := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !excluded[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
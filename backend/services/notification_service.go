@@ -4,15 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
-	"net/smtp"
-	"strings"
 	"time"
 
 	"github.com/SherClockHolmes/webpush-go"
 	"github.com/shimauma0312/module-tickethub/backend/models"
 	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"gorm.io/gorm"
 )
 
 // NotificationService は通知関連の機能を提供するサービス
@@ -118,12 +118,6 @@ func (s *NotificationService) CreateNotification(
 		return fmt.Errorf("invalid notification data")
 	}
 
-	// 通知をDBに保存
-	err := s.notificationRepo.Create(ctx, notification)
-	if err != nil {
-		return err
-	}
-
 	// ユーザー設定を取得
 	userSettings, err := s.userSettingsRepo.GetByUserID(ctx, userID)
 	if err != nil {
@@ -131,21 +125,18 @@ func (s *NotificationService) CreateNotification(
 	}
 
 	// 通知の種類が通知設定に含まれているかチェック
-	notificationTypes := strings.Split(userSettings.NotificationTypes, ",")
-	shouldNotify := false
-	if userSettings.NotificationTypes == "all" {
-		shouldNotify = true
-	} else {
-		for _, t := range notificationTypes {
-			if t == notificationType {
-				shouldNotify = true
-				break
-			}
-		}
+	if !userSettings.AcceptsNotificationType(notificationType) {
+		// ユーザーが通知タイプを無効にしている場合は通知しない
+		return nil
 	}
 
-	if !shouldNotify {
-		// ユーザーが通知タイプを無効にしている場合は通知しない
+	// 通知をDBに保存
+	err = s.notificationRepo.Create(ctx, notification)
+	if err != nil {
+		return err
+	}
+
+	if !userSettings.PushNotification && !userSettings.EmailNotification {
 		return nil
 	}
 
@@ -155,14 +146,17 @@ func (s *NotificationService) CreateNotification(
 		return err
 	}
 
-	// テンプレートを取得
+	// テンプレートを取得（未登録の場合はアプリ内通知のみ）
 	template, err := s.notificationTemplateRepo.GetByType(ctx, notificationType)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
 		return err
 	}
 
 	if template == nil {
-		return fmt.Errorf("notification template not found for type: %s", notificationType)
+		return nil
 	}
 
 	// 非同期で通知を送信（ここではgoroutineで簡略化していますが、実際にはメッセージキューなどを使用するとよいでしょう）
@@ -182,7 +176,8 @@ func (s *NotificationService) CreateNotification(
 			subject, body := s.renderTemplate(template.EmailSubjectTemplate, template.EmailBodyTemplate, data)
 
 			// Eメール通知を送信
-			user, _ := s.userRepo.GetByID(ctx, userID)
+			// リクエストのコンテキストは応答後にキャンセルされるため新しいコンテキストを使用
+			user, _ := s.userRepo.GetByID(context.Background(), userID)
 			if user != nil {
 				s.sendEmailNotification(user.Email, subject, body)
 			}
@@ -346,35 +341,8 @@ func (s *NotificationService) sendWebPushNotification(endpoint, p256dh, auth str
 
 // sendEmailNotification はEメール通知を送信します
 func (s *NotificationService) sendEmailNotification(to, subject, body string) error {
-	// SMTPサーバーに接続
-	auth := smtp.PlainAuth("", s.smtpUsername, s.smtpPassword, s.smtpHost)
-
-	// メールヘッダーを設定
-	headers := map[string]string{
-		"From":         s.smtpFrom,
-		"To":           to,
-		"Subject":      subject,
-		"MIME-Version": "1.0",
-		"Content-Type": "text/html; charset=UTF-8",
-	}
-
-	// ヘッダーをメッセージに追加
-	message := ""
-	for key, value := range headers {
-		message += fmt.Sprintf("%s: %s\r\n", key, value)
-	}
-	message += "\r\n" + body
-
-	// メールを送信
-	err := smtp.SendMail(
-		fmt.Sprintf("%s:%d", s.smtpHost, s.smtpPort),
-		auth,
-		s.smtpFrom,
-		[]string{to},
-		[]byte(message),
-	)
-
-	return err
+	sender := NewSMTPMailSender(s.smtpHost, s.smtpPort, s.smtpUsername, s.smtpPassword, s.smtpFrom)
+	return sender.Send(context.Background(), &MailMessage{To: to, Subject: subject, Body: body, HTML: true})
 }
 
 // GetNotifications はユーザーの通知一覧を取得します
@@ -0,0 +1,395 @@
+package services
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// IDトークンの有効期限・発行日時の検証で許容する時刻のずれ
+	oidcClockSkew = 1 * time.Minute
+	// IDプロバイダーへのリクエストのタイムアウト
+	oidcRequestTimeout = 10 * time.Second
+	// 未知の鍵IDによるJWKSの再取得の最短間隔
+	oidcKeyRefreshInterval = 1 * time.Minute
+)
+
+var (
+	// ErrOIDCProviderUnavailable はIDプロバイダーのディスカバリー・トークン・JWKSのエンドポイントが利用できない場合のエラー
+	ErrOIDCProviderUnavailable = errors.New("identity provider is unavailable")
+	// ErrOIDCInvalidIDToken はIDトークンの署名・発行者・対象者・有効期限・nonceの検証に失敗した場合のエラー
+	ErrOIDCInvalidIDToken = errors.New("invalid id token")
+)
+
+// OIDCProviderConfig はOpenID ConnectのIDプロバイダーの設定
+type OIDCProviderConfig struct {
+	Name         string   // ルートで使用するプロバイダー名（例: company）
+	IssuerURL    string   // ディスカバリーに使用する発行者のURL
+	ClientID     string   // クライアントID
+	ClientSecret string   // クライアントシークレット（パブリッククライアントの場合は空）
+	RedirectURL  string   // コールバックのURL（/api/auth/oidc/{name}/callback）
+	Scopes       []string // 要求するスコープ（省略時は openid email profile）
+	GroupsClaim  string   // グループを表すクレーム名（省略時は groups）
+	AdminGroups  []string // 管理者にするグループ（空の場合は管理者権限を変更しない）
+}
+
+// OIDCIdentity はIDトークンから取り出したユーザーの情報
+type OIDCIdentity struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+	Groups            []string
+}
+
+// oidcDiscovery はディスカバリードキュメント（/.well-known/openid-configuration）のうち使用する項目
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// oidcTokenResponse はトークンエンドポイントのレスポンス
+type oidcTokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// oidcJWKS はJWKSエンドポイントのレスポンス
+type oidcJWKS struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+// OIDCProvider はディスカバリー・認可コードフロー（PKCE）・IDトークンの検証を行うIDプロバイダーのクライアント
+// ディスカバリードキュメントと署名鍵は初回の利用時に取得してキャッシュし、未知の鍵IDの場合は署名鍵を再取得します
+type OIDCProvider struct {
+	config OIDCProviderConfig
+	client *http.Client
+
+	mu            sync.Mutex
+	discovery     *oidcDiscovery
+	keys          map[string]*rsa.PublicKey
+	keysFetchedAt time.Time
+}
+
+// NewOIDCProvider は新しいOIDCProviderを作成します
+// client がnilの場合はタイムアウト付きの既定のクライアントを使用します
+func NewOIDCProvider(config OIDCProviderConfig, client *http.Client) *OIDCProvider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = "groups"
+	}
+	config.IssuerURL = strings.TrimSuffix(config.IssuerURL, "/")
+	if client == nil {
+		client = &http.Client{Timeout: oidcRequestTimeout}
+	}
+	return &OIDCProvider{
+		config: config,
+		client: client,
+	}
+}
+
+// Name はプロバイダー名を返します
+func (p *OIDCProvider) Name() string {
+	return p.config.Name
+}
+
+// AdminGroups は管理者にするグループを返します
+func (p *OIDCProvider) AdminGroups() []string {
+	return p.config.AdminGroups
+}
+
+// AuthCodeURL はPKCE（S256）付きの認可リクエストのURLを作成します
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(discovery.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid authorization endpoint: %v", ErrOIDCProviderUnavailable, err)
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+	return authURL.String(), nil
+}
+
+// Exchange は認可コードとPKCEのcode_verifierをトークンエンドポイントに送信し、IDトークンを取得します
+func (p *OIDCProvider) Exchange(ctx context.Context, code, codeVerifier string) (string, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrOIDCProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	var token oidcTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("%w: invalid token response: %v", ErrOIDCProviderUnavailable, err)
+	}
+	if resp.StatusCode != http.StatusOK || token.Error != "" {
+		return "", fmt.Errorf("%w: token exchange failed: %s %s", ErrOIDCInvalidIDToken, token.Error, token.ErrorDescription)
+	}
+	if token.IDToken == "" {
+		return "", fmt.Errorf("%w: token response has no id_token", ErrOIDCInvalidIDToken)
+	}
+	return token.IDToken, nil
+}
+
+// VerifyIDToken はIDトークンの署名をJWKSの公開鍵で検証し、発行者・対象者・有効期限・nonceを確認します
+func (p *OIDCProvider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*OIDCIdentity, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.publicKey(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512"}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(oidcClockSkew),
+	)
+	if err != nil {
+		if errors.Is(err, ErrOIDCProviderUnavailable) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrOIDCInvalidIDToken, err)
+	}
+
+	// 認可リクエストで送信したnonceとの一致を確認
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce == "" || tokenNonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrOIDCInvalidIDToken)
+	}
+	// 複数の対象者がある場合はazpがクライアントIDであることを確認
+	if audience, _ := claims.GetAudience(); len(audience) > 1 {
+		if azp, _ := claims["azp"].(string); azp != p.config.ClientID {
+			return nil, fmt.Errorf("%w: azp mismatch", ErrOIDCInvalidIDToken)
+		}
+	}
+
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return nil, fmt.Errorf("%w: missing sub claim", ErrOIDCInvalidIDToken)
+	}
+
+	identity := &OIDCIdentity{
+		Subject:           subject,
+		Email:             stringClaim(claims, "email"),
+		EmailVerified:     boolClaim(claims, "email_verified"),
+		Name:              stringClaim(claims, "name"),
+		PreferredUsername: stringClaim(claims, "preferred_username"),
+		Groups:            stringListClaim(claims, p.config.GroupsClaim),
+	}
+	return identity, nil
+}
+
+// discover はディスカバリードキュメントを取得します（取得済みの場合はキャッシュを返します）
+func (p *OIDCProvider) discover(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var discovery oidcDiscovery
+	if err := p.getJSON(ctx, p.config.IssuerURL+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, err
+	}
+	// 発行者はディスカバリーに使用したURLと一致する必要がある
+	if strings.TrimSuffix(discovery.Issuer, "/") != p.config.IssuerURL {
+		return nil, fmt.Errorf("%w: issuer mismatch: %s", ErrOIDCProviderUnavailable, discovery.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: discovery document is missing endpoints", ErrOIDCProviderUnavailable)
+	}
+
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// publicKey は鍵IDに対応する署名鍵を取得します
+// キャッシュにない場合はIDプロバイダーの鍵の更新に追従するためJWKSを再取得します（1分に1回まで）
+func (p *OIDCProvider) publicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key := p.lookupKey(kid); key != nil {
+		return key, nil
+	}
+	if time.Since(p.keysFetchedAt) < oidcKeyRefreshInterval {
+		return nil, fmt.Errorf("no signing key found for kid %q", kid)
+	}
+
+	var jwks oidcJWKS
+	if err := p.getJSON(ctx, discovery.JWKSURI, &jwks); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		key, err := parseRSAPublicKey(jwk.N, jwk.E)
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+
+	if key := p.lookupKey(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("no signing key found for kid %q", kid)
+}
+
+// lookupKey はキャッシュから署名鍵を取得します
+// 鍵IDが指定されていない場合は鍵が1つだけのときにその鍵を返します
+func (p *OIDCProvider) lookupKey(kid string) *rsa.PublicKey {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key
+		}
+	}
+	return p.keys[kid]
+}
+
+// getJSON はIDプロバイダーのエンドポイントからJSONを取得します
+func (p *OIDCProvider) getJSON(ctx context.Context, endpoint string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOIDCProviderUnavailable, err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOIDCProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s returned %d", ErrOIDCProviderUnavailable, endpoint, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("%w: invalid response from %s: %v", ErrOIDCProviderUnavailable, endpoint, err)
+	}
+	return nil
+}
+
+// parseRSAPublicKey はJWKのn・e（base64url）からRSA公開鍵を作成します
+func parseRSAPublicKey(n, e string) (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, err
+	}
+	exponent, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, err
+	}
+	if len(modulus) == 0 || len(exponent) == 0 || len(exponent) > 4 {
+		return nil, errors.New("invalid rsa key")
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(modulus),
+		E: int(new(big.Int).SetBytes(exponent).Int64()),
+	}, nil
+}
+
+// stringClaim は文字列のクレームを取得します
+func stringClaim(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// boolClaim は真偽値のクレームを取得します（"true" の文字列で返すプロバイダーにも対応）
+func boolClaim(claims jwt.MapClaims, name string) bool {
+	switch value := claims[name].(type) {
+	case bool:
+		return value
+	case string:
+		return value == "true"
+	}
+	return false
+}
+
+// stringListClaim は文字列の配列のクレームを取得します（単一の文字列の場合は1要素の配列）
+func stringListClaim(claims jwt.MapClaims, name string) []string {
+	switch value := claims[name].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		list := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
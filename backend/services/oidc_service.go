@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+)
+
+const (
+	// 認可リクエストからコールバックまでの有効期限（10分）
+	oidcLoginStateExpiration = 10 * time.Minute
+	// 自動作成するユーザーのユーザー名が重複した場合に試す連番の上限
+	oidcUsernameAttempts = 100
+)
+
+var (
+	// ErrOIDCProviderNotFound は指定されたIDプロバイダーが設定されていない場合のエラー
+	ErrOIDCProviderNotFound = errors.New("identity provider not found")
+	// ErrOIDCInvalidState は state パラメータが不明・期限切れ・使用済みの場合のエラー
+	ErrOIDCInvalidState = errors.New("invalid or expired login state")
+	// ErrOIDCEmailRequired はIDトークンにメールアドレスが含まれない場合のエラー
+	ErrOIDCEmailRequired = errors.New("identity provider did not return an email address")
+	// ErrOIDCEmailNotVerified は既存のアカウントと同じメールアドレスがIDプロバイダーまたは既存のアカウントで確認済みでないため紐付けできない場合のエラー
+	ErrOIDCEmailNotVerified = errors.New("an account with this email already exists, but the email has not been verified")
+)
+
+// OIDCService はOpenID Connectによるシングルサインオンを扱うサービス
+// IDプロバイダーのアカウントは初回のログイン時にユーザーを自動作成するか、検証済みのメールアドレスで既存のユーザーに紐付けます
+type OIDCService struct {
+	providers    map[string]*OIDCProvider
+	stateRepo    repositories.OIDCLoginStateRepository
+	identityRepo repositories.UserIdentityRepository
+	userRepo     repositories.UserRepository
+	authService  *AuthService
+}
+
+// NewOIDCService は新しいOIDCServiceを作成します
+func NewOIDCService(
+	stateRepo repositories.OIDCLoginStateRepository,
+	identityRepo repositories.UserIdentityRepository,
+	userRepo repositories.UserRepository,
+	authService *AuthService,
+	providers ...*OIDCProvider,
+) *OIDCService {
+	registered := make(map[string]*OIDCProvider, len(providers))
+	for _, provider := range providers {
+		registered[provider.Name()] = provider
+	}
+	return &OIDCService{
+		providers:    registered,
+		stateRepo:    stateRepo,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		authService:  authService,
+	}
+}
+
+// Providers は設定されているIDプロバイダー名の一覧を返します
+func (s *OIDCService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BeginLogin はログインの状態を保存し、IDプロバイダーの認可リクエストのURLと state を返します
+// state はログインを開始したブラウザに紐付けるため、呼び出し元でCookieに設定します
+func (s *OIDCService) BeginLogin(ctx context.Context, providerName string) (string, string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", "", ErrOIDCProviderNotFound
+	}
+
+	// state・PKCEのcode_verifier・nonceの生成
+	state, err := randomURLSafeString(32)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate state: %w", err)
+	}
+	codeVerifier, err := randomURLSafeString(32)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate code verifier: %w", err)
+	}
+	nonce, err := randomURLSafeString(32)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	// 期限切れのログインの状態を削除してから保存
+	if err := s.stateRepo.DeleteExpired(ctx); err != nil {
+		return "", "", fmt.Errorf("failed to delete expired login states: %w", err)
+	}
+	loginState := models.NewOIDCLoginState(state, providerName, codeVerifier, nonce, oidcLoginStateExpiration)
+	if err := s.stateRepo.Create(ctx, loginState); err != nil {
+		return "", "", fmt.Errorf("failed to save login state: %w", err)
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, models.CodeChallenge(codeVerifier))
+	if err != nil {
+		return "", "", err
+	}
+	return authURL, state, nil
+}
+
+// CompleteLogin は認可コードをIDトークンに交換して検証し、ユーザーにアクセストークンとリフレッシュトークンを発行します
+// 管理者にするグループが設定されている場合は、グループのクレームに応じてユーザーの管理者権限を更新します
+// 二要素認証が有効なユーザーの場合はトークンを発行せずに、ユーザーと ErrTwoFactorRequired を返します
+func (s *OIDCService) CompleteLogin(ctx context.Context, providerName, state, code, userAgent, ipAddress string) (*models.User, string, string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, "", "", ErrOIDCProviderNotFound
+	}
+
+	// state の検証（取得と同時に削除して再利用を防ぐ）
+	loginState, err := s.stateRepo.Consume(ctx, state)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to get login state: %w", err)
+	}
+	if loginState == nil || loginState.Provider != providerName || loginState.IsExpired() {
+		return nil, "", "", ErrOIDCInvalidState
+	}
+
+	// 認可コードの交換とIDトークンの検証
+	rawIDToken, err := provider.Exchange(ctx, code, loginState.CodeVerifier)
+	if err != nil {
+		return nil, "", "", err
+	}
+	identity, err := provider.VerifyIDToken(ctx, rawIDToken, loginState.Nonce)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	user, err := s.resolveUser(ctx, providerName, identity)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if !user.IsActive {
+		return nil, "", "", ErrUserDisabled
+	}
+
+	// グループのクレームによる管理者権限の反映
+	if adminGroups := provider.AdminGroups(); len(adminGroups) > 0 {
+		user.SetAdmin(models.HasAnyGroup(identity.Groups, adminGroups))
+	}
+
+	// 二要素認証が有効な場合はシングルサインオンでもコードの入力が必要（最終ログイン日時は二要素認証の完了時に更新）
+	twoFactorEnabled, err := s.authService.IsTwoFactorEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if twoFactorEnabled {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, "", "", fmt.Errorf("failed to update user: %w", err)
+		}
+		return user, "", "", ErrTwoFactorRequired
+	}
+
+	user.RecordLogin()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, "", "", fmt.Errorf("failed to update user: %w", err)
+	}
+
+	accessToken, refreshToken, err := s.authService.IssueTokens(ctx, user, userAgent, ipAddress)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return user, accessToken, refreshToken, nil
+}
+
+// IssueMFAPendingToken は二要素認証が有効なユーザーに、ログインの2段階目で使用する一時トークンを発行します
+func (s *OIDCService) IssueMFAPendingToken(user *models.User) (string, int, error) {
+	return s.authService.IssueMFAPendingToken(user)
+}
+
+// resolveUser はIDプロバイダーのアカウントに紐付くユーザーを取得します
+// 紐付けがない場合はIDプロバイダーと既存のユーザーの両方で確認済みのメールアドレスで紐付けるか、新しいユーザーを作成します
+func (s *OIDCService) resolveUser(ctx context.Context, providerName string, identity *OIDCIdentity) (*models.User, error) {
+	link, err := s.identityRepo.GetByProviderSubject(ctx, providerName, identity.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user identity: %w", err)
+	}
+	if link != nil {
+		user, err := s.userRepo.GetByID(ctx, link.UserID)
+		if err != nil || user == nil {
+			return nil, ErrUserNotFound
+		}
+		link.RecordLogin(identity.Email)
+		if err := s.identityRepo.Update(ctx, link); err != nil {
+			return nil, fmt.Errorf("failed to update user identity: %w", err)
+		}
+		return user, nil
+	}
+
+	if identity.Email == "" {
+		return nil, ErrOIDCEmailRequired
+	}
+
+	// 既存のユーザーへの紐付け（IDプロバイダーと既存のユーザーの両方でメールアドレスが確認済みの場合のみ）
+	// 既存のユーザーが未確認の場合は、他人のメールアドレスで先に登録したアカウントに紐付いて乗っ取られるのを防ぐため拒否する
+	user, err := s.userRepo.GetByEmail(ctx, identity.Email)
+	if err == nil && user != nil {
+		if !identity.EmailVerified || !user.IsEmailVerified() {
+			return nil, ErrOIDCEmailNotVerified
+		}
+	} else {
+		user, err = s.provisionUser(ctx, identity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.identityRepo.Create(ctx, models.NewUserIdentity(user.ID, providerName, identity.Subject, identity.Email)); err != nil {
+		return nil, fmt.Errorf("failed to link user identity: %w", err)
+	}
+	return user, nil
+}
+
+// provisionUser はIDプロバイダーのアカウントの情報から新しいユーザーを作成します
+// パスワードはランダムな値のハッシュを設定するため、パスワードでのログインにはパスワードリセットが必要です
+func (s *OIDCService) provisionUser(ctx context.Context, identity *OIDCIdentity) (*models.User, error) {
+	username, err := s.availableUsername(ctx, models.SuggestUsername(identity.PreferredUsername, identity.Email))
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := s.authService.GenerateRandomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate password: %w", err)
+	}
+	hashedPassword, err := s.authService.HashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := models.NewUser(username, identity.Email, hashedPassword, identity.Name)
+	if identity.EmailVerified {
+		user.MarkEmailVerified()
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	return user, nil
+}
+
+// availableUsername は使用されていないユーザー名を返します（重複する場合は末尾に連番を付けます）
+func (s *OIDCService) availableUsername(ctx context.Context, base string) (string, error) {
+	for i := 1; i <= oidcUsernameAttempts; i++ {
+		candidate := base
+		if i > 1 {
+			suffix := fmt.Sprint(i)
+			candidate = strings.TrimRight(base[:min(len(base), 50-len(suffix))], "_") + suffix
+		}
+		if existing, err := s.userRepo.GetByUsername(ctx, candidate); err != nil || existing == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("failed to find an available username for %s", base)
+}
+
+// randomURLSafeString はパディングなしのbase64urlでエンコードしたランダムな文字列を生成します
+// PKCEのcode_verifierは "=" を含められないため、GenerateRandomToken ではなくこちらを使用します
+func randomURLSafeString(length int) (string, error) {
+	b := make([]byte, length)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
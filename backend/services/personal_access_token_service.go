@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+)
+
+const (
+	// パーソナルアクセストークンの有効期限の既定値（30日）
+	personalAccessTokenDefaultLifetime = 30 * 24 * time.Hour
+	// 同じIPアドレスからの利用で最終利用日時を更新する間隔（1分）
+	personalAccessTokenUsageInterval = 1 * time.Minute
+)
+
+var (
+	// ErrInvalidPersonalAccessToken はパーソナルアクセストークンの名前・スコープ・有効期限が不正な場合のエラー
+	ErrInvalidPersonalAccessToken = errors.New("invalid personal access token")
+	// ErrPersonalAccessTokenNameTaken は同じ名前のパーソナルアクセストークンが既に存在する場合のエラー
+	ErrPersonalAccessTokenNameTaken = errors.New("a personal access token with this name already exists")
+	// ErrPersonalAccessTokenNotFound はパーソナルアクセストークンが見つからない場合のエラー
+	ErrPersonalAccessTokenNotFound = errors.New("personal access token not found")
+)
+
+// PersonalAccessTokenService はパーソナルアクセストークンの発行と認証を扱うサービス
+type PersonalAccessTokenService struct {
+	tokenRepo repositories.PersonalAccessTokenRepository
+	userRepo  repositories.UserRepository
+}
+
+// NewPersonalAccessTokenService は新しいPersonalAccessTokenServiceを作成します
+func NewPersonalAccessTokenService(
+	tokenRepo repositories.PersonalAccessTokenRepository,
+	userRepo repositories.UserRepository,
+) *PersonalAccessTokenService {
+	return &PersonalAccessTokenService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// Create はパーソナルアクセストークンを発行し、保存したトークンと平文のトークン文字列を返します
+// 有効期限が指定されていない場合は30日後に期限切れになります
+func (s *PersonalAccessTokenService) Create(ctx context.Context, userID int64, name string, scopes []string, expiresAt time.Time) (*models.PersonalAccessToken, string, error) {
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(personalAccessTokenDefaultLifetime)
+	}
+
+	plaintext, err := generatePersonalAccessToken()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate personal access token: %w", err)
+	}
+
+	token := models.NewPersonalAccessToken(userID, name, plaintext, scopes, expiresAt)
+	if err := token.Validate(); err != nil {
+		return nil, "", fmt.Errorf("%w: %s", ErrInvalidPersonalAccessToken, err)
+	}
+
+	// 名前の重複チェック
+	existing, err := s.tokenRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list personal access tokens: %w", err)
+	}
+	for _, other := range existing {
+		if other.Name == token.Name {
+			return nil, "", ErrPersonalAccessTokenNameTaken
+		}
+	}
+
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		return nil, "", fmt.Errorf("failed to create personal access token: %w", err)
+	}
+	return token, plaintext, nil
+}
+
+// List はユーザーのパーソナルアクセストークンの一覧を取得します
+func (s *PersonalAccessTokenService) List(ctx context.Context, userID int64) ([]*models.PersonalAccessToken, error) {
+	return s.tokenRepo.ListByUser(ctx, userID)
+}
+
+// Delete はユーザーのパーソナルアクセストークンを削除して無効化します
+func (s *PersonalAccessTokenService) Delete(ctx context.Context, userID, id int64) error {
+	token, err := s.tokenRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get personal access token: %w", err)
+	}
+	// 他のユーザーのトークンは存在しないものとして扱う
+	if token == nil || token.UserID != userID {
+		return ErrPersonalAccessTokenNotFound
+	}
+	return s.tokenRepo.Delete(ctx, id)
+}
+
+// Authenticate はパーソナルアクセストークンを検証し、ユーザーとトークンを返します
+// 検証に成功した場合は最終利用日時と接続元のIPアドレスを記録します
+func (s *PersonalAccessTokenService) Authenticate(ctx context.Context, plaintext, ipAddress string) (*models.User, *models.PersonalAccessToken, error) {
+	token, err := s.tokenRepo.GetByHash(ctx, models.HashPersonalAccessToken(plaintext))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get personal access token: %w", err)
+	}
+	if token == nil {
+		return nil, nil, ErrTokenInvalid
+	}
+	if token.IsExpired() {
+		return nil, nil, ErrTokenExpired
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil || user == nil {
+		return nil, nil, ErrUserNotFound
+	}
+	if !user.IsActive {
+		return nil, nil, ErrUserDisabled
+	}
+
+	// 最終利用の記録
+	if token.NeedsUsageUpdate(ipAddress, personalAccessTokenUsageInterval) {
+		token.RecordUse(ipAddress)
+		if err := s.tokenRepo.RecordUse(ctx, token); err != nil {
+			return nil, nil, fmt.Errorf("failed to record personal access token usage: %w", err)
+		}
+	}
+
+	return user, token, nil
+}
+
+// generatePersonalAccessToken はプレフィックス付きのランダムなトークン文字列を生成します
+func generatePersonalAccessToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return models.PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+)
+
+// ErrInvalidReactionEmoji は使用できない絵文字が指定された場合のエラー
+var ErrInvalidReactionEmoji = errors.New("invalid reaction emoji")
+
+// ReactionService はリアクションの切り替えと集計を行うサービス
+type ReactionService struct {
+	reactionRepo repositories.ReactionRepository
+}
+
+// NewReactionService は新しいReactionServiceを作成します
+func NewReactionService(reactionRepo repositories.ReactionRepository) *ReactionService {
+	return &ReactionService{reactionRepo: reactionRepo}
+}
+
+// Toggle はユーザーのリアクションを付け外しし、対象の最新の集計を返します
+// リアクションを付けた場合は true、外した場合は false を返します
+func (s *ReactionService) Toggle(ctx context.Context, targetType string, targetID, userID int64, emoji string) (bool, []models.ReactionSummary, error) {
+	if !models.IsAllowedReactionEmoji(emoji) {
+		return false, nil, ErrInvalidReactionEmoji
+	}
+
+	existing, err := s.reactionRepo.GetByUserAndTarget(ctx, userID, targetType, targetID, emoji)
+	if err != nil {
+		return false, nil, err
+	}
+
+	added := existing == nil
+	if added {
+		reaction := models.NewReaction(targetType, targetID, userID, emoji)
+		if !reaction.IsValid() {
+			return false, nil, fmt.Errorf("invalid reaction target: %s %d", targetType, targetID)
+		}
+		if err := s.reactionRepo.Create(ctx, reaction); err != nil {
+			// 同時に同じリアクションが付けられた場合（一意制約の違反）は付けた状態として扱う
+			if concurrent, getErr := s.reactionRepo.GetByUserAndTarget(ctx, userID, targetType, targetID, emoji); getErr != nil || concurrent == nil {
+				return false, nil, fmt.Errorf("failed to add reaction: %w", err)
+			}
+		}
+	} else if err := s.reactionRepo.Delete(ctx, existing.ID); err != nil {
+		return false, nil, fmt.Errorf("failed to remove reaction: %w", err)
+	}
+
+	summaries, err := s.reactionRepo.SummarizeByTargets(ctx, targetType, []int64{targetID}, userID)
+	if err != nil {
+		return false, nil, err
+	}
+	return added, summaries[targetID], nil
+}
+
+// AttachToIssues はIssueの一覧にリアクションの集計を設定します
+func (s *ReactionService) AttachToIssues(ctx context.Context, issues []*models.Issue, viewerID int64) error {
+	ids := make([]int64, 0, len(issues))
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+
+	summaries, err := s.reactionRepo.SummarizeByTargets(ctx, models.ReactionTargetIssue, ids, viewerID)
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		issue.Reactions = summaries[issue.ID]
+	}
+	return nil
+}
+
+// AttachToDiscussions はDiscussionの一覧にリアクションの集計を設定します
+func (s *ReactionService) AttachToDiscussions(ctx context.Context, discussions []*models.Discussion, viewerID int64) error {
+	ids := make([]int64, 0, len(discussions))
+	for _, discussion := range discussions {
+		ids = append(ids, discussion.ID)
+	}
+
+	summaries, err := s.reactionRepo.SummarizeByTargets(ctx, models.ReactionTargetDiscussion, ids, viewerID)
+	if err != nil {
+		return err
+	}
+	for _, discussion := range discussions {
+		discussion.Reactions = summaries[discussion.ID]
+	}
+	return nil
+}
+
+// AttachToComments はコメント（返信を含む）の一覧にリアクションの集計を設定します
+func (s *ReactionService) AttachToComments(ctx context.Context, comments []*models.Comment, viewerID int64) error {
+	ids := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+	}
+
+	summaries, err := s.reactionRepo.SummarizeByTargets(ctx, models.ReactionTargetComment, ids, viewerID)
+	if err != nil {
+		return err
+	}
+	for _, comment := range comments {
+		comment.Reactions = summaries[comment.ID]
+	}
+	return nil
+}
+
+// RemoveAll は対象に付けられたリアクションをすべて削除します
+func (s *ReactionService) RemoveAll(ctx context.Context, targetType string, targetID int64) error {
+	return s.reactionRepo.DeleteByTarget(ctx, targetType, targetID)
+}
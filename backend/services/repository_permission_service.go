@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+)
+
+var (
+	// ErrRepositoryNotFound はリポジトリが存在しない、または閲覧権限がない場合のエラー
+	// 非公開リポジトリの存在を隠すため、閲覧できない場合も存在しない場合と区別しません
+	ErrRepositoryNotFound = errors.New("repository not found")
+	// ErrRepositoryPermissionDenied はリポジトリを閲覧できるが操作に必要なロールがない場合のエラー
+	ErrRepositoryPermissionDenied = errors.New("insufficient repository permission")
+)
+
+// RepositoryPermissionService はリポジトリの公開範囲とメンバーのロールに基づく権限判定を行うサービス
+type RepositoryPermissionService struct {
+	repoRepo   repositories.RepositoryRepository
+	memberRepo repositories.RepositoryMemberRepository
+	userRepo   repositories.UserRepository
+}
+
+// NewRepositoryPermissionService は新しいRepositoryPermissionServiceを作成します
+func NewRepositoryPermissionService(
+	repoRepo repositories.RepositoryRepository,
+	memberRepo repositories.RepositoryMemberRepository,
+	userRepo repositories.UserRepository,
+) *RepositoryPermissionService {
+	return &RepositoryPermissionService{
+		repoRepo:   repoRepo,
+		memberRepo: memberRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// RoleFor はユーザーのリポジトリに対する実効ロールを返します（閲覧できない場合は空文字）
+//
+//   - システム管理者とリポジトリのオーナーは admin
+//   - メンバーは付与されたロール（公開範囲による閲覧権限より弱い場合は read）
+//   - 公開リポジトリは未ログインを含む全員が read、組織内公開リポジトリはログインユーザーが read
+func (s *RepositoryPermissionService) RoleFor(ctx context.Context, repo *models.Repository, userID int64) (models.RepositoryRole, error) {
+	var role models.RepositoryRole
+	if repo.IsPublic() || (repo.IsInternal() && userID > 0) {
+		role = models.RepositoryRoleRead
+	}
+	if userID == 0 {
+		return role, nil
+	}
+
+	if repo.OwnerID == userID {
+		return models.RepositoryRoleAdmin, nil
+	}
+	isAdmin, err := s.isSystemAdmin(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if isAdmin {
+		return models.RepositoryRoleAdmin, nil
+	}
+
+	member, err := s.memberRepo.Get(ctx, repo.ID, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository member: %w", err)
+	}
+	if member != nil && member.Role.Includes(models.RepositoryRoleRead) && !role.Includes(member.Role) {
+		role = member.Role
+	}
+	return role, nil
+}
+
+// Authorize はユーザーがリポジトリで required 以上のロールを持つか確認します
+// 閲覧できない場合は ErrRepositoryNotFound、ロールが不足している場合は ErrRepositoryPermissionDenied を返します
+func (s *RepositoryPermissionService) Authorize(ctx context.Context, repositoryID, userID int64, required models.RepositoryRole) (*models.Repository, error) {
+	repo, err := s.repoRepo.GetByID(ctx, repositoryID)
+	if err != nil || repo == nil {
+		return nil, ErrRepositoryNotFound
+	}
+	if err := s.AuthorizeRepository(ctx, repo, userID, required); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// AuthorizeRepository は取得済みのリポジトリに対して Authorize と同じ確認を行います
+func (s *RepositoryPermissionService) AuthorizeRepository(ctx context.Context, repo *models.Repository, userID int64, required models.RepositoryRole) error {
+	role, err := s.RoleFor(ctx, repo, userID)
+	if err != nil {
+		return err
+	}
+	if role == "" {
+		return ErrRepositoryNotFound
+	}
+	if !role.Includes(required) {
+		return ErrRepositoryPermissionDenied
+	}
+	return nil
+}
+
+// VisibleRepositoryIDs はユーザーが閲覧できるリポジトリのIDを返します
+// システム管理者はすべてのリポジトリを閲覧できるため nil を返します
+func (s *RepositoryPermissionService) VisibleRepositoryIDs(ctx context.Context, userID int64) ([]int64, error) {
+	isAdmin, err := s.isSystemAdmin(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if isAdmin {
+		return nil, nil
+	}
+	return s.repoRepo.ListVisibleIDs(ctx, userID)
+}
+
+// isSystemAdmin はユーザーがシステム管理者かどうかを判定します
+func (s *RepositoryPermissionService) isSystemAdmin(ctx context.Context, userID int64) (bool, error) {
+	if userID == 0 {
+		return false, nil
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user != nil && user.IsAdmin, nil
+}
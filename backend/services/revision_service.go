@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+)
+
+var (
+	// ErrRevisionNotFound は指定された版が対象に存在しない場合のエラー
+	ErrRevisionNotFound = errors.New("revision not found")
+	// ErrRevisionRedacted は削除済みの版の差分を求めた場合のエラー
+	ErrRevisionRedacted = errors.New("revision has been redacted")
+	// ErrRevisionIsCurrent は現在の本文である最新の版を削除しようとした場合のエラー
+	ErrRevisionIsCurrent = errors.New("the latest revision cannot be redacted")
+)
+
+// RevisionService は本文の編集履歴の記録・差分作成・削除を行うサービス
+type RevisionService struct {
+	revisionRepo repositories.BodyRevisionRepository
+}
+
+// NewRevisionService は新しいRevisionServiceを作成します
+func NewRevisionService(revisionRepo repositories.BodyRevisionRepository) *RevisionService {
+	return &RevisionService{revisionRepo: revisionRepo}
+}
+
+// Record は本文が変更された場合に編集後の版を記録します
+// 最初の編集時は編集前の本文を作成者・作成日時の版として先に記録します
+func (s *RevisionService) Record(ctx context.Context, targetType string, targetID int64, previousBody string, authorID int64, createdAt time.Time, body string, editorID int64) error {
+	if previousBody == body {
+		return nil
+	}
+
+	revisions, err := s.revisionRepo.ListByTarget(ctx, targetType, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to list revisions: %w", err)
+	}
+	if len(revisions) == 0 {
+		original := models.NewBodyRevision(targetType, targetID, previousBody, authorID, createdAt)
+		if err := s.revisionRepo.Create(ctx, original); err != nil {
+			return fmt.Errorf("failed to record original revision: %w", err)
+		}
+	}
+
+	revision := models.NewBodyRevision(targetType, targetID, body, editorID, time.Now())
+	if err := s.revisionRepo.Create(ctx, revision); err != nil {
+		return fmt.Errorf("failed to record revision: %w", err)
+	}
+	return nil
+}
+
+// List は対象の版を古い順に取得します
+func (s *RevisionService) List(ctx context.Context, targetType string, targetID int64) ([]*models.BodyRevision, error) {
+	revisions, err := s.revisionRepo.ListByTarget(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if revisions == nil {
+		revisions = []*models.BodyRevision{}
+	}
+	return revisions, nil
+}
+
+// Diff は対象の2つの版の本文の差分をunified形式で作成します
+func (s *RevisionService) Diff(ctx context.Context, targetType string, targetID, fromID, toID int64) (string, error) {
+	from, err := s.getTargetRevision(ctx, targetType, targetID, fromID)
+	if err != nil {
+		return "", err
+	}
+	to, err := s.getTargetRevision(ctx, targetType, targetID, toID)
+	if err != nil {
+		return "", err
+	}
+	if from.IsRedacted || to.IsRedacted {
+		return "", ErrRevisionRedacted
+	}
+
+	return models.UnifiedDiff(from.Body, to.Body, fmt.Sprintf("revision %d", from.ID), fmt.Sprintf("revision %d", to.ID)), nil
+}
+
+// Redact は版の本文を削除します（現在の本文である最新の版は削除できません）
+func (s *RevisionService) Redact(ctx context.Context, id, adminID int64) (*models.BodyRevision, error) {
+	revision, err := s.revisionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil {
+		return nil, ErrRevisionNotFound
+	}
+
+	revisions, err := s.revisionRepo.ListByTarget(ctx, revision.TargetType, revision.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) > 0 && revisions[len(revisions)-1].ID == revision.ID {
+		return nil, ErrRevisionIsCurrent
+	}
+
+	if !revision.IsRedacted {
+		revision.Redact(adminID)
+		if err := s.revisionRepo.Update(ctx, revision); err != nil {
+			return nil, fmt.Errorf("failed to redact revision: %w", err)
+		}
+	}
+	return revision, nil
+}
+
+// RemoveAll は対象の版をすべて削除します
+func (s *RevisionService) RemoveAll(ctx context.Context, targetType string, targetID int64) error {
+	return s.revisionRepo.DeleteByTarget(ctx, targetType, targetID)
+}
+
+// getTargetRevision は対象に属する版を取得します
+func (s *RevisionService) getTargetRevision(ctx context.Context, targetType string, targetID, id int64) (*models.BodyRevision, error) {
+	revision, err := s.revisionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil || revision.TargetType != targetType || revision.TargetID != targetID {
+		return nil, ErrRevisionNotFound
+	}
+	return revision, nil
+}
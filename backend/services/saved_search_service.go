@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+)
+
+// SavedSearchSummary は保存済み検索と前回の閲覧以降の新着件数
+type SavedSearchSummary struct {
+	*models.SavedSearch
+	NewCount int `json:"new_count"` // 前回の閲覧以降に作成・更新された件数（未閲覧の場合は全件数）
+}
+
+// SavedSearchService は保存済み検索の実行と新着件数の集計を行うサービス
+type SavedSearchService struct {
+	savedSearchRepo   repositories.SavedSearchRepository
+	searchService     SearchService
+	permissionService *RepositoryPermissionService
+}
+
+// NewSavedSearchService は新しいSavedSearchServiceを作成します
+func NewSavedSearchService(savedSearchRepo repositories.SavedSearchRepository, searchService SearchService, permissionService *RepositoryPermissionService) *SavedSearchService {
+	return &SavedSearchService{
+		savedSearchRepo:   savedSearchRepo,
+		searchService:     searchService,
+		permissionService: permissionService,
+	}
+}
+
+// Validate は保存する検索クエリと並び順を検証します
+// クエリの構文エラーの場合は位置を含む *models.SearchSyntaxError を返します
+func (s *SavedSearchService) Validate(savedSearch *models.SavedSearch) error {
+	if _, err := s.searchService.ParseQuery(savedSearch.Query); err != nil {
+		return err
+	}
+	if savedSearch.Sort != "" {
+		if _, err := models.ParseSearchSort(savedSearch.Sort); err != nil {
+			return fmt.Errorf("invalid sort: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListWithCounts はユーザーが閲覧できる保存済み検索を新着件数とともに取得します
+func (s *SavedSearchService) ListWithCounts(ctx context.Context, userID int64) ([]SavedSearchSummary, error) {
+	savedSearches, err := s.savedSearchRepo.ListVisible(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]SavedSearchSummary, 0, len(savedSearches))
+	for _, savedSearch := range savedSearches {
+		count, err := s.CountNew(ctx, savedSearch, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count new results for saved search %d: %w", savedSearch.ID, err)
+		}
+		summaries = append(summaries, SavedSearchSummary{SavedSearch: savedSearch, NewCount: count})
+	}
+	return summaries, nil
+}
+
+// CountNew はユーザーが前回閲覧した以降に作成・更新された検索結果の件数を取得します
+func (s *SavedSearchService) CountNew(ctx context.Context, savedSearch *models.SavedSearch, userID int64) (int, error) {
+	lastViewedAt, err := s.savedSearchRepo.GetLastViewedAt(ctx, savedSearch.ID, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	query, err := s.buildQuery(ctx, savedSearch, userID)
+	if err != nil {
+		return 0, err
+	}
+	query.UpdatedSince = lastViewedAt
+	query.Limit = 1
+
+	results, err := s.searchService.Search(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return results.TotalCount, nil
+}
+
+// Execute は保存済み検索を実行し、ユーザーの最終閲覧日時を更新します
+func (s *SavedSearchService) Execute(ctx context.Context, savedSearch *models.SavedSearch, userID int64, limit, offset int) (*models.SearchResults, error) {
+	query, err := s.buildQuery(ctx, savedSearch, userID)
+	if err != nil {
+		return nil, err
+	}
+	query.Limit = limit
+	query.Offset = offset
+
+	results, err := s.searchService.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.savedSearchRepo.MarkViewed(ctx, savedSearch.ID, userID, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to mark saved search as viewed: %w", err)
+	}
+	return results, nil
+}
+
+// buildQuery は保存済み検索から検索クエリを作成します
+// 保存された並び順はクエリ内の sort: より優先し、結果は実行するユーザーが閲覧できるリポジトリに限定します
+func (s *SavedSearchService) buildQuery(ctx context.Context, savedSearch *models.SavedSearch, userID int64) (models.SearchQuery, error) {
+	query, err := s.searchService.ParseQuery(savedSearch.Query)
+	if err != nil {
+		return query, err
+	}
+
+	if savedSearch.Sort != "" {
+		sort, err := models.ParseSearchSort(savedSearch.Sort)
+		if err != nil {
+			return query, err
+		}
+		query.Sort = sort
+	}
+	query.ViewerID = userID
+	query.RepositoryIDs, err = s.permissionService.VisibleRepositoryIDs(ctx, userID)
+	if err != nil {
+		return query, err
+	}
+	return query, nil
+}
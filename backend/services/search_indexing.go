@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+)
+
+// indexingIssueRepository は書き込み時に検索インデックスを更新するIssueRepositoryのデコレーター
+type indexingIssueRepository struct {
+	repositories.IssueRepository
+	searchService SearchService
+}
+
+// NewIndexingIssueRepository は作成・更新・削除時に検索インデックスを更新するIssueRepositoryを作成します
+// インデックスの更新に失敗しても書き込み自体は成功として扱い、エラーはログに記録します
+func NewIndexingIssueRepository(repo repositories.IssueRepository, searchService SearchService) repositories.IssueRepository {
+	return &indexingIssueRepository{
+		IssueRepository: repo,
+		searchService:   searchService,
+	}
+}
+
+// Create はIssueを作成し、インデックスに追加します
+func (r *indexingIssueRepository) Create(ctx context.Context, issue *models.Issue) error {
+	if err := r.IssueRepository.Create(ctx, issue); err != nil {
+		return err
+	}
+	if err := r.searchService.IndexIssue(ctx, issue); err != nil {
+		log.Printf("Failed to index issue %d: %v", issue.ID, err)
+	}
+	return nil
+}
+
+// Update はIssueを更新し、インデックスを更新します
+func (r *indexingIssueRepository) Update(ctx context.Context, issue *models.Issue) error {
+	if err := r.IssueRepository.Update(ctx, issue); err != nil {
+		return err
+	}
+	if err := r.searchService.IndexIssue(ctx, issue); err != nil {
+		log.Printf("Failed to index issue %d: %v", issue.ID, err)
+	}
+	return nil
+}
+
+// UpdateWithEvents はIssueを更新して変更履歴を記録し、インデックスを更新します
+func (r *indexingIssueRepository) UpdateWithEvents(ctx context.Context, issue *models.Issue, events []*models.IssueEvent) error {
+	if err := r.IssueRepository.UpdateWithEvents(ctx, issue, events); err != nil {
+		return err
+	}
+	if err := r.searchService.IndexIssue(ctx, issue); err != nil {
+		log.Printf("Failed to index issue %d: %v", issue.ID, err)
+	}
+	return nil
+}
+
+// Delete はIssueを削除し、インデックスから削除します
+func (r *indexingIssueRepository) Delete(ctx context.Context, id int64) error {
+	if err := r.IssueRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := r.searchService.DeleteFromIndex(ctx, "issue", id); err != nil {
+		log.Printf("Failed to remove issue %d from index: %v", id, err)
+	}
+	return nil
+}
+
+// Purge はIssueを物理削除し、インデックスから削除します
+func (r *indexingIssueRepository) Purge(ctx context.Context, id int64) error {
+	if err := r.IssueRepository.Purge(ctx, id); err != nil {
+		return err
+	}
+	if err := r.searchService.DeleteFromIndex(ctx, "issue", id); err != nil {
+		log.Printf("Failed to remove issue %d from index: %v", id, err)
+	}
+	return nil
+}
+
+// indexingCommentRepository は書き込み時に検索インデックスを更新するCommentRepositoryのデコレーター
+type indexingCommentRepository struct {
+	repositories.CommentRepository
+	searchService SearchService
+}
+
+// NewIndexingCommentRepository は作成・更新・削除時に検索インデックスを更新するCommentRepositoryを作成します
+// インデックスの更新に失敗しても書き込み自体は成功として扱い、エラーはログに記録します
+func NewIndexingCommentRepository(repo repositories.CommentRepository, searchService SearchService) repositories.CommentRepository {
+	return &indexingCommentRepository{
+		CommentRepository: repo,
+		searchService:     searchService,
+	}
+}
+
+// Create はコメントを作成し、インデックスに追加します
+func (r *indexingCommentRepository) Create(ctx context.Context, comment *models.Comment) error {
+	if err := r.CommentRepository.Create(ctx, comment); err != nil {
+		return err
+	}
+	if err := r.searchService.IndexComment(ctx, comment); err != nil {
+		log.Printf("Failed to index comment %d: %v", comment.ID, err)
+	}
+	return nil
+}
+
+// Update はコメントを更新し、インデックスを更新します
+func (r *indexingCommentRepository) Update(ctx context.Context, comment *models.Comment) error {
+	if err := r.CommentRepository.Update(ctx, comment); err != nil {
+		return err
+	}
+	if err := r.searchService.IndexComment(ctx, comment); err != nil {
+		log.Printf("Failed to index comment %d: %v", comment.ID, err)
+	}
+	return nil
+}
+
+// Delete はコメントを削除し、インデックスから削除します
+func (r *indexingCommentRepository) Delete(ctx context.Context, id int64) error {
+	if err := r.CommentRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := r.searchService.DeleteFromIndex(ctx, "comment", id); err != nil {
+		log.Printf("Failed to remove comment %d from index: %v", id, err)
+	}
+	return nil
+}
+
+// MoveTarget はコメントを別のターゲットに移動し、移動したコメントのインデックスを更新します
+func (r *indexingCommentRepository) MoveTarget(ctx context.Context, fromType string, fromID int64, toType string, toID int64) error {
+	if err := r.CommentRepository.MoveTarget(ctx, fromType, fromID, toType, toID); err != nil {
+		return err
+	}
+	indexMovedComments(ctx, r.CommentRepository, r.searchService, toType, toID)
+	return nil
+}
+
+// indexMovedComments は別のターゲットに移動したコメントのインデックスを更新します
+func indexMovedComments(ctx context.Context, commentRepo repositories.CommentRepository, searchService SearchService, targetType string, targetID int64) {
+	comments, err := commentRepo.ListAllByTarget(ctx, targetID, targetType)
+	if err != nil {
+		log.Printf("Failed to list moved comments of %s %d: %v", targetType, targetID, err)
+		return
+	}
+	for _, comment := range comments {
+		if err := searchService.IndexComment(ctx, comment); err != nil {
+			log.Printf("Failed to index comment %d: %v", comment.ID, err)
+		}
+	}
+}
+
+// indexingDiscussionRepository は書き込み時に検索インデックスを更新するDiscussionRepositoryのデコレーター
+type indexingDiscussionRepository struct {
+	repositories.DiscussionRepository
+	searchService SearchService
+}
+
+// NewIndexingDiscussionRepository は作成・更新・削除時に検索インデックスを更新するDiscussionRepositoryを作成します
+func NewIndexingDiscussionRepository(repo repositories.DiscussionRepository, searchService SearchService) repositories.DiscussionRepository {
+	return &indexingDiscussionRepository{
+		DiscussionRepository: repo,
+		searchService:        searchService,
+	}
+}
+
+// Create はDiscussionを作成し、インデックスに追加します
+func (r *indexingDiscussionRepository) Create(ctx context.Context, discussion *models.Discussion) error {
+	if err := r.DiscussionRepository.Create(ctx, discussion); err != nil {
+		return err
+	}
+	if err := r.searchService.IndexDiscussion(ctx, discussion); err != nil {
+		log.Printf("Failed to index discussion %d: %v", discussion.ID, err)
+	}
+	return nil
+}
+
+// Update はDiscussionを更新し、インデックスを更新します
+func (r *indexingDiscussionRepository) Update(ctx context.Context, discussion *models.Discussion) error {
+	if err := r.DiscussionRepository.Update(ctx, discussion); err != nil {
+		return err
+	}
+	if err := r.searchService.IndexDiscussion(ctx, discussion); err != nil {
+		log.Printf("Failed to index discussion %d: %v", discussion.ID, err)
+	}
+	return nil
+}
+
+// Delete はDiscussionを削除し、インデックスから削除します
+func (r *indexingDiscussionRepository) Delete(ctx context.Context, id int64) error {
+	if err := r.DiscussionRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := r.searchService.DeleteFromIndex(ctx, "discussion", id); err != nil {
+		log.Printf("Failed to remove discussion %d from index: %v", id, err)
+	}
+	return nil
+}
+
+// indexingMilestoneRepository は書き込み時に検索インデックスを更新するMilestoneRepositoryのデコレーター
+type indexingMilestoneRepository struct {
+	repositories.MilestoneRepository
+	searchService SearchService
+}
+
+// NewIndexingMilestoneRepository は作成・更新・削除時に検索インデックスを更新するMilestoneRepositoryを作成します
+func NewIndexingMilestoneRepository(repo repositories.MilestoneRepository, searchService SearchService) repositories.MilestoneRepository {
+	return &indexingMilestoneRepository{
+		MilestoneRepository: repo,
+		searchService:       searchService,
+	}
+}
+
+// Create はMilestoneを作成し、インデックスに追加します
+func (r *indexingMilestoneRepository) Create(ctx context.Context, milestone *models.Milestone) error {
+	if err := r.MilestoneRepository.Create(ctx, milestone); err != nil {
+		return err
+	}
+	if err := r.searchService.IndexMilestone(ctx, milestone); err != nil {
+		log.Printf("Failed to index milestone %d: %v", milestone.ID, err)
+	}
+	return nil
+}
+
+// Update はMilestoneを更新し、インデックスを更新します
+func (r *indexingMilestoneRepository) Update(ctx context.Context, milestone *models.Milestone) error {
+	if err := r.MilestoneRepository.Update(ctx, milestone); err != nil {
+		return err
+	}
+	if err := r.searchService.IndexMilestone(ctx, milestone); err != nil {
+		log.Printf("Failed to index milestone %d: %v", milestone.ID, err)
+	}
+	return nil
+}
+
+// Delete はMilestoneを削除し、インデックスから削除します
+func (r *indexingMilestoneRepository) Delete(ctx context.Context, id int64) error {
+	if err := r.MilestoneRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := r.searchService.DeleteFromIndex(ctx, "milestone", id); err != nil {
+		log.Printf("Failed to remove milestone %d from index: %v", id, err)
+	}
+	return nil
+}
+
+// indexingLabelRepository は書き込み時に検索インデックスを更新するLabelRepositoryのデコレーター
+type indexingLabelRepository struct {
+	repositories.LabelRepository
+	searchService SearchService
+}
+
+// NewIndexingLabelRepository は作成・更新・削除時に検索インデックスを更新するLabelRepositoryを作成します
+func NewIndexingLabelRepository(repo repositories.LabelRepository, searchService SearchService) repositories.LabelRepository {
+	return &indexingLabelRepository{
+		LabelRepository: repo,
+		searchService:   searchService,
+	}
+}
+
+// Create はLabelを作成し、インデックスに追加します
+func (r *indexingLabelRepository) Create(ctx context.Context, label *models.Label) error {
+	if err := r.LabelRepository.Create(ctx, label); err != nil {
+		return err
+	}
+	if err := r.searchService.IndexLabel(ctx, label); err != nil {
+		log.Printf("Failed to index label %d: %v", label.ID, err)
+	}
+	return nil
+}
+
+// Update はLabelを更新し、インデックスを更新します
+func (r *indexingLabelRepository) Update(ctx context.Context, label *models.Label) error {
+	if err := r.LabelRepository.Update(ctx, label); err != nil {
+		return err
+	}
+	if err := r.searchService.IndexLabel(ctx, label); err != nil {
+		log.Printf("Failed to index label %d: %v", label.ID, err)
+	}
+	return nil
+}
+
+// Delete はLabelを削除し、インデックスから削除します
+func (r *indexingLabelRepository) Delete(ctx context.Context, id int64) error {
+	if err := r.LabelRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := r.searchService.DeleteFromIndex(ctx, "label", id); err != nil {
+		log.Printf("Failed to remove label %d from index: %v", id, err)
+	}
+	return nil
+}
+
+// DeleteWithEvents はLabelを削除して変更履歴を記録し、インデックスから削除します
+func (r *indexingLabelRepository) DeleteWithEvents(ctx context.Context, id int64, actorID int64) error {
+	if err := r.LabelRepository.DeleteWithEvents(ctx, id, actorID); err != nil {
+		return err
+	}
+	if err := r.searchService.DeleteFromIndex(ctx, "label", id); err != nil {
+		log.Printf("Failed to remove label %d from index: %v", id, err)
+	}
+	return nil
+}
+
+// indexingConversionRepository は変換のコミット後に検索インデックスを更新するConversionRepositoryのデコレーター
+type indexingConversionRepository struct {
+	repositories.ConversionRepository
+	commentRepo   repositories.CommentRepository
+	searchService SearchService
+}
+
+// NewIndexingConversionRepository はIssueとDiscussionの変換のコミット後に検索インデックスを更新するConversionRepositoryを作成します
+// 変換が取り消された場合はインデックスを変更しません
+func NewIndexingConversionRepository(repo repositories.ConversionRepository, commentRepo repositories.CommentRepository, searchService SearchService) repositories.ConversionRepository {
+	return &indexingConversionRepository{
+		ConversionRepository: repo,
+		commentRepo:          commentRepo,
+		searchService:        searchService,
+	}
+}
+
+// IssueToDiscussion はIssueをDiscussionに変換し、インデックスを入れ替えます
+func (r *indexingConversionRepository) IssueToDiscussion(ctx context.Context, issue *models.Issue, discussion *models.Discussion, redirect *models.Redirect) error {
+	if err := r.ConversionRepository.IssueToDiscussion(ctx, issue, discussion, redirect); err != nil {
+		return err
+	}
+	if err := r.searchService.DeleteFromIndex(ctx, "issue", issue.ID); err != nil {
+		log.Printf("Failed to remove issue %d from index: %v", issue.ID, err)
+	}
+	if err := r.searchService.IndexDiscussion(ctx, discussion); err != nil {
+		log.Printf("Failed to index discussion %d: %v", discussion.ID, err)
+	}
+	indexMovedComments(ctx, r.commentRepo, r.searchService, models.RedirectTypeDiscussion, discussion.ID)
+	return nil
+}
+
+// DiscussionToIssue はDiscussionをIssueに変換し、インデックスを入れ替えます
+func (r *indexingConversionRepository) DiscussionToIssue(ctx context.Context, discussion *models.Discussion, issue *models.Issue, redirect *models.Redirect) error {
+	if err := r.ConversionRepository.DiscussionToIssue(ctx, discussion, issue, redirect); err != nil {
+		return err
+	}
+	if err := r.searchService.DeleteFromIndex(ctx, "discussion", discussion.ID); err != nil {
+		log.Printf("Failed to remove discussion %d from index: %v", discussion.ID, err)
+	}
+	if err := r.searchService.IndexIssue(ctx, issue); err != nil {
+		log.Printf("Failed to index issue %d: %v", issue.ID, err)
+	}
+	indexMovedComments(ctx, r.commentRepo, r.searchService, models.RedirectTypeIssue, issue.ID)
+	return nil
+}
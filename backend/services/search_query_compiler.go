@@ -0,0 +1,373 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+)
+
+// sqlFalse は常に偽となる条件（対象の種類に存在しない修飾子に使用する）
+const sqlFalse = "0 = 1"
+
+// searchTarget は結果種類ごとの検索対象テーブルと列の対応
+type searchTarget struct {
+	resultType models.SearchResultType
+	ftsTable   string
+	table      string // 本体テーブル
+	alias      string // 本体テーブルの別名
+	parent     string // コメントの場合の親テーブル（リポジトリの判定に使用）
+	labelTable string // ラベルの関連テーブル（ラベルのファセットの集計に使用）
+	labelKey   string // ラベルの関連テーブルの本体テーブルを参照する列
+	hasStatus  bool
+	hasCreator bool
+	hasDraft   bool
+}
+
+// from はFTS5テーブルと本体テーブルを結合したFROM句を返す
+func (t searchTarget) from() string {
+	return fmt.Sprintf("%s JOIN %s %s ON %s.doc_id = %s.id", t.ftsTable, t.table, t.alias, t.ftsTable, t.alias)
+}
+
+// searchTargets は結果種類ごとの検索対象
+var searchTargets = map[models.SearchResultType]searchTarget{
+	models.SearchResultTypeIssue: {
+		resultType: models.SearchResultTypeIssue, ftsTable: "issue_search", table: "issues", alias: "i",
+		labelTable: "issue_labels", labelKey: "issue_id",
+		hasStatus: true, hasCreator: true, hasDraft: true,
+	},
+	models.SearchResultTypeComment: {
+		resultType: models.SearchResultTypeComment, ftsTable: "comment_search", table: "comments", alias: "c", parent: "issues",
+		hasCreator: true,
+	},
+	models.SearchResultTypeDiscussion: {
+		resultType: models.SearchResultTypeDiscussion, ftsTable: "discussion_search", table: "discussions", alias: "d",
+		labelTable: "discussion_labels", labelKey: "discussion_id",
+		hasStatus: true, hasCreator: true, hasDraft: true,
+	},
+	models.SearchResultTypeDiscussionComment: {
+		resultType: models.SearchResultTypeDiscussionComment, ftsTable: "discussion_comment_search", table: "comments", alias: "c", parent: "discussions",
+		hasCreator: true,
+	},
+	models.SearchResultTypeMilestone: {
+		resultType: models.SearchResultTypeMilestone, ftsTable: "milestone_search", table: "milestones", alias: "m",
+		hasStatus: true, hasCreator: true,
+	},
+	models.SearchResultTypeLabel: {
+		resultType: models.SearchResultTypeLabel, ftsTable: "label_search", table: "labels", alias: "l",
+	},
+}
+
+// compiledSearch は検索式をSQLに変換した結果
+type compiledSearch struct {
+	conds    *sqlConditions
+	hasMatch bool   // 最上位の全文検索条件（MATCH）があるか
+	orderBy  string // ORDER BY句
+}
+
+// compileSearch は検索式を結果種類ごとのパラメータ化されたWHERE条件に変換する
+// 最上位の全文検索条件はランク付けとハイライトのためFTS5のMATCHにまとめ、
+// 否定やORに含まれる全文検索条件はサブクエリとして扱う
+func compileSearch(target searchTarget, root *models.SearchNode, sort models.SearchSort, viewerID int64) (*compiledSearch, error) {
+	c := &searchCompiler{target: target, viewerID: viewerID}
+	compiled := &compiledSearch{conds: &sqlConditions{}}
+
+	var topLevel []*models.SearchNode
+	if root != nil {
+		topLevel = []*models.SearchNode{root}
+		if root.Kind == models.SearchNodeAnd {
+			topLevel = root.Children
+		}
+	}
+
+	var matchParts []string
+	var filters []*models.SearchNode
+	for _, node := range topLevel {
+		if node.IsTextOnly() {
+			matchParts = append(matchParts, ftsExpression(node))
+		} else {
+			filters = append(filters, node)
+		}
+	}
+
+	if len(matchParts) > 0 {
+		compiled.hasMatch = true
+		compiled.conds.add(target.ftsTable+" MATCH ?", strings.Join(matchParts, " AND "))
+	}
+
+	for _, node := range filters {
+		clause, args, err := c.compile(node)
+		if err != nil {
+			return nil, err
+		}
+		compiled.conds.add(clause, args...)
+	}
+
+	compiled.orderBy = searchOrderBy(target, sort, compiled.hasMatch)
+	return compiled, nil
+}
+
+// repositoryRestriction は検索対象を指定されたリポジトリに限定する条件を作成する
+func repositoryRestriction(target searchTarget, repositoryIDs []int64) (string, []interface{}) {
+	if len(repositoryIDs) == 0 {
+		return sqlFalse, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(repositoryIDs)), ", ")
+	args := make([]interface{}, len(repositoryIDs))
+	for i, id := range repositoryIDs {
+		args[i] = id
+	}
+
+	if target.parent != "" {
+		return fmt.Sprintf("c.target_id IN (SELECT id FROM %s WHERE repository_id IN (%s))", target.parent, placeholders), args
+	}
+	return fmt.Sprintf("%s.repository_id IN (%s)", target.alias, placeholders), args
+}
+
+// searchOrderBy は並び順に対応するORDER BY句を作成する
+func searchOrderBy(target searchTarget, sort models.SearchSort, hasMatch bool) string {
+	direction := "DESC"
+	if sort.Ascending {
+		direction = "ASC"
+	}
+
+	switch sort.Field {
+	case models.SearchSortCreated:
+		return fmt.Sprintf("%s.created_at %s", target.alias, direction)
+	case models.SearchSortUpdated:
+		return fmt.Sprintf("%s.updated_at %s", target.alias, direction)
+	}
+
+	// 種類ごとの結果を統合する際の並び順（sortResults）と一致させる
+	if hasMatch {
+		return target.ftsTable + ".rank, " + target.alias + ".updated_at DESC"
+	}
+	return target.alias + ".updated_at DESC"
+}
+
+// searchCompiler は検索式のノードをSQL条件に変換する
+type searchCompiler struct {
+	target   searchTarget
+	viewerID int64
+}
+
+// compile はノードをSQL条件とパラメータに変換する
+func (c *searchCompiler) compile(node *models.SearchNode) (string, []interface{}, error) {
+	if node.IsTextOnly() {
+		return fmt.Sprintf("%s.id IN (SELECT doc_id FROM %s WHERE %s MATCH ?)",
+			c.target.alias, c.target.ftsTable, c.target.ftsTable), []interface{}{ftsExpression(node)}, nil
+	}
+
+	switch node.Kind {
+	case models.SearchNodeAnd, models.SearchNodeOr:
+		separator := " AND "
+		if node.Kind == models.SearchNodeOr {
+			separator = " OR "
+		}
+
+		clauses := make([]string, 0, len(node.Children))
+		var args []interface{}
+		for _, child := range node.Children {
+			clause, childArgs, err := c.compile(child)
+			if err != nil {
+				return "", nil, err
+			}
+			clauses = append(clauses, clause)
+			args = append(args, childArgs...)
+		}
+		return "(" + strings.Join(clauses, separator) + ")", args, nil
+
+	case models.SearchNodeNot:
+		clause, args, err := c.compile(node.Children[0])
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT (" + clause + ")", args, nil
+
+	case models.SearchNodeQualifier:
+		return c.compileQualifier(node)
+	}
+
+	return "", nil, &models.SearchSyntaxError{Pos: node.Pos, Message: fmt.Sprintf("unsupported expression %q", node.Kind)}
+}
+
+// compileQualifier は修飾子をSQL条件に変換する
+// 対象の種類に存在しない項目の修飾子は一致しない条件になる
+func (c *searchCompiler) compileQualifier(node *models.SearchNode) (string, []interface{}, error) {
+	q := node.Qualifier
+	alias := c.target.alias
+	isIssue := c.target.resultType == models.SearchResultTypeIssue
+
+	switch q.Key {
+	case "label":
+		cond, args := labelNameCondition(q.Value)
+		switch c.target.resultType {
+		case models.SearchResultTypeIssue:
+			return "EXISTS (SELECT 1 FROM issue_labels il JOIN labels l ON l.id = il.label_id WHERE il.issue_id = i.id AND " + cond + ")", args, nil
+		case models.SearchResultTypeDiscussion:
+			return "EXISTS (SELECT 1 FROM discussion_labels dl JOIN labels l ON l.id = dl.label_id WHERE dl.discussion_id = d.id AND " + cond + ")", args, nil
+		}
+		return sqlFalse, nil, nil
+
+	case "status":
+		if !c.target.hasStatus {
+			return sqlFalse, nil, nil
+		}
+		return alias + ".status = ?", []interface{}{q.Value}, nil
+
+	case "is":
+		if q.Value == "draft" {
+			if !c.target.hasDraft {
+				return sqlFalse, nil, nil
+			}
+			return alias + ".is_draft = ?", []interface{}{true}, nil
+		}
+		if !c.target.hasStatus {
+			return sqlFalse, nil, nil
+		}
+		return alias + ".status = ?", []interface{}{q.Value}, nil
+
+	case "no":
+		if !isIssue {
+			return sqlFalse, nil, nil
+		}
+		switch q.Value {
+		case "assignee":
+			return "NOT EXISTS (SELECT 1 FROM issue_assignees ia WHERE ia.issue_id = i.id)", nil, nil
+		case "milestone":
+			return "COALESCE(i.milestone_id, 0) = 0", nil, nil
+		}
+		return "NOT EXISTS (SELECT 1 FROM issue_labels il WHERE il.issue_id = i.id)", nil, nil
+
+	case "assignee":
+		if !isIssue {
+			return sqlFalse, nil, nil
+		}
+		// いずれかの担当者に一致するIssue
+		cond, args, err := c.userCondition(node, "ia.user_id")
+		if err != nil {
+			return "", nil, err
+		}
+		return "EXISTS (SELECT 1 FROM issue_assignees ia WHERE ia.issue_id = i.id AND " + cond + ")", args, nil
+
+	case "creator":
+		if !c.target.hasCreator {
+			return sqlFalse, nil, nil
+		}
+		return c.userCondition(node, alias+".creator_id")
+
+	case "milestone":
+		if !isIssue {
+			return sqlFalse, nil, nil
+		}
+		return "i.milestone_id IN (SELECT id FROM milestones WHERE title = ?)", []interface{}{q.Value}, nil
+
+	case "category":
+		if c.target.resultType != models.SearchResultTypeDiscussion {
+			return sqlFalse, nil, nil
+		}
+		return "d.category = ?", []interface{}{q.Value}, nil
+
+	case "repo":
+		repoCond := "repository_id IN (SELECT id FROM repositories WHERE name = ?)"
+		if c.target.parent != "" {
+			return fmt.Sprintf("c.target_id IN (SELECT id FROM %s WHERE %s)", c.target.parent, repoCond), []interface{}{q.Value}, nil
+		}
+		return alias + "." + repoCond, []interface{}{q.Value}, nil
+
+	case "created", "updated":
+		column := alias + "." + q.Key + "_at"
+		conds := &sqlConditions{}
+		if !q.From.IsZero() {
+			conds.add(column+" >= ?", q.From)
+		}
+		if !q.To.IsZero() {
+			conds.add(column+" < ?", q.To)
+		}
+		return "(" + conds.String() + ")", conds.args, nil
+	}
+
+	return "", nil, &models.SearchSyntaxError{Pos: node.Pos, Message: fmt.Sprintf("unknown qualifier %q", q.Key)}
+}
+
+// userCondition はユーザー指定（@me・ユーザーID・ユーザー名）をSQL条件に変換する
+func (c *searchCompiler) userCondition(node *models.SearchNode, column string) (string, []interface{}, error) {
+	value := node.Qualifier.Value
+	if value == "@me" {
+		if c.viewerID == 0 {
+			return "", nil, &models.SearchSyntaxError{Pos: node.Pos, Message: "@me requires an authenticated user"}
+		}
+		return column + " = ?", []interface{}{c.viewerID}, nil
+	}
+
+	if id, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return column + " = ?", []interface{}{id}, nil
+	}
+	return column + " IN (SELECT id FROM users WHERE username = ?)", []interface{}{value}, nil
+}
+
+// labelNameCondition はラベル名の指定をSQL条件に変換する（* は任意の文字列に一致）
+func labelNameCondition(value string) (string, []interface{}) {
+	if !strings.Contains(value, "*") {
+		return "l.name = ?", []interface{}{value}
+	}
+	pattern := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`, "*", "%").Replace(value)
+	return `l.name LIKE ? ESCAPE '\'`, []interface{}{pattern}
+}
+
+// ftsExpression は全文検索用のノードをFTS5のクエリ式に変換する
+// 単語は前方一致、フレーズは完全一致として引用符で囲む
+func ftsExpression(node *models.SearchNode) string {
+	switch node.Kind {
+	case models.SearchNodeTerm:
+		return quoteFTS(node.Text) + "*"
+	case models.SearchNodePhrase:
+		return quoteFTS(node.Text)
+	}
+
+	separator := " AND "
+	if node.Kind == models.SearchNodeOr {
+		separator = " OR "
+	}
+	parts := make([]string, 0, len(node.Children))
+	for _, child := range node.Children {
+		parts = append(parts, ftsExpression(child))
+	}
+	return "(" + strings.Join(parts, separator) + ")"
+}
+
+// quoteFTS はFTS5の文字列として引用符で囲む
+func quoteFTS(text string) string {
+	return `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+}
+
+// structuredSearchFilter はSearchQueryの個別フィルタ（APIパラメータ）を検索式に変換する
+func structuredSearchFilter(query models.SearchQuery) *models.SearchNode {
+	var nodes []*models.SearchNode
+	for _, label := range query.Labels {
+		nodes = append(nodes, models.NewSearchQualifierNode("label", label))
+	}
+	if query.Status != "" && query.Status != "all" {
+		nodes = append(nodes, models.NewSearchQualifierNode("status", query.Status))
+	}
+	if query.AssigneeID > 0 {
+		nodes = append(nodes, models.NewSearchQualifierNode("assignee", strconv.FormatInt(query.AssigneeID, 10)))
+	}
+	if query.CreatorID > 0 {
+		nodes = append(nodes, models.NewSearchQualifierNode("creator", strconv.FormatInt(query.CreatorID, 10)))
+	}
+	if query.Category != "" {
+		nodes = append(nodes, models.NewSearchQualifierNode("category", query.Category))
+	}
+	if query.Repository != "" {
+		nodes = append(nodes, models.NewSearchQualifierNode("repo", query.Repository))
+	}
+	if !query.UpdatedSince.IsZero() {
+		node := models.NewSearchQualifierNode("updated", "")
+		node.Qualifier.From = query.UpdatedSince
+		nodes = append(nodes, node)
+	}
+	return models.SearchAnd(nodes...)
+}
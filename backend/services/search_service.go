@@ -17,12 +17,25 @@ type SearchService interface {
 	// IndexComment は指定されたCommentをインデックスに追加または更新する
 	IndexComment(ctx context.Context, comment *models.Comment) error
 
+	// IndexDiscussion は指定されたDiscussionをインデックスに追加または更新する
+	IndexDiscussion(ctx context.Context, discussion *models.Discussion) error
+
+	// IndexMilestone は指定されたMilestoneをインデックスに追加または更新する
+	IndexMilestone(ctx context.Context, milestone *models.Milestone) error
+
+	// IndexLabel は指定されたLabelをインデックスに追加または更新する
+	IndexLabel(ctx context.Context, label *models.Label) error
+
 	// DeleteFromIndex は指定されたドキュメントをインデックスから削除する
 	DeleteFromIndex(ctx context.Context, docType string, docID int64) error
 
 	// RebuildIndex はすべてのインデックスを再構築する
 	RebuildIndex(ctx context.Context) error
 
+	// VerifyIndex はインデックス件数をデータ件数と照合し、不一致の場合はインデックスを再構築する
+	VerifyIndex(ctx context.Context) error
+
 	// ParseQuery は検索クエリ文字列を解析する
-	ParseQuery(queryString string) models.SearchQuery
+	// 構文エラーの場合は位置を含む *models.SearchSyntaxError を返す
+	ParseQuery(queryString string) (models.SearchQuery, error)
 }
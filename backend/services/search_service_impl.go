@@ -4,7 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"regexp"
+	"log"
+	"sort"
 	"strings"
 	"time"
 
@@ -13,26 +14,61 @@ import (
 	"gorm.io/gorm"
 )
 
+// ftsTables は検索対象ごとのFTS5テーブル定義
+var ftsTables = []struct {
+	name    string
+	columns string
+}{
+	{"issue_search", "doc_id UNINDEXED, title, body"},
+	{"comment_search", "doc_id UNINDEXED, target_id UNINDEXED, body"},
+	{"discussion_search", "doc_id UNINDEXED, title, body, category"},
+	{"discussion_comment_search", "doc_id UNINDEXED, target_id UNINDEXED, body"},
+	{"milestone_search", "doc_id UNINDEXED, title, description"},
+	{"label_search", "doc_id UNINDEXED, name, description"},
+}
+
+// indexTablesByDocType はDeleteFromIndexで指定するドキュメントタイプと対象テーブルの対応
+var indexTablesByDocType = map[string][]string{
+	"issue":      {"issue_search"},
+	"comment":    {"comment_search", "discussion_comment_search"},
+	"discussion": {"discussion_search"},
+	"milestone":  {"milestone_search"},
+	"label":      {"label_search"},
+}
+
 // searchServiceImpl は SearchService インターフェースの実装
 type searchServiceImpl struct {
-	db          *gorm.DB
-	sqlDB       *sql.DB
-	issueRepo   repositories.IssueRepository
-	commentRepo repositories.CommentRepository
+	db             *gorm.DB
+	sqlDB          *sql.DB
+	issueRepo      repositories.IssueRepository
+	commentRepo    repositories.CommentRepository
+	discussionRepo repositories.DiscussionRepository
+	milestoneRepo  repositories.MilestoneRepository
+	labelRepo      repositories.LabelRepository
 }
 
 // NewSearchService は SearchService の新しいインスタンスを作成する
-func NewSearchService(db *gorm.DB, issueRepo repositories.IssueRepository, commentRepo repositories.CommentRepository) (SearchService, error) {
+func NewSearchService(
+	db *gorm.DB,
+	issueRepo repositories.IssueRepository,
+	commentRepo repositories.CommentRepository,
+	discussionRepo repositories.DiscussionRepository,
+	milestoneRepo repositories.MilestoneRepository,
+	labelRepo repositories.LabelRepository,
+) (SearchService, error) {
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sql.DB: %w", err)
 	}
 
 	service := &searchServiceImpl{
-		db:          db,
-		sqlDB:       sqlDB,
-		issueRepo:   issueRepo,
-		commentRepo: commentRepo,
+		db:             db,
+		sqlDB:          sqlDB,
+		issueRepo:      issueRepo,
+		commentRepo:    commentRepo,
+		discussionRepo: discussionRepo,
+		milestoneRepo:  milestoneRepo,
+		labelRepo:      labelRepo,
 	}
 
 	// 初期化時にFTS5テーブルを作成
@@ -45,228 +81,440 @@ func NewSearchService(db *gorm.DB, issueRepo repositories.IssueRepository, comme
 
 // initFTS5Tables はFTS5検索テーブルを初期化する
 func (s *searchServiceImpl) initFTS5Tables() error {
-	// FTS5拡張モジュールが有効かチェック
-	var fts5Enabled bool
-	row := s.sqlDB.QueryRow("SELECT * FROM sqlite_master WHERE type='table' AND name='sqlite_master'")
-	if err := row.Scan(&fts5Enabled); err != nil {
-		if err != sql.ErrNoRows {
-			return fmt.Errorf("failed to check FTS5 availability: %w", err)
-		}
-	}
-
-	// FTS5テーブルの作成
-	// issue_search テーブル
-	_, err := s.sqlDB.Exec(`
-		CREATE VIRTUAL TABLE IF NOT EXISTS issue_search USING fts5(
-			doc_id UNINDEXED,
-			title,
-			body,
-			tokenize = 'unicode61 remove_diacritics 1'
-		);
-	`)
-	if err != nil {
-		return fmt.Errorf("failed to create issue_search table: %w", err)
-	}
-
-	// comment_search テーブル
-	_, err = s.sqlDB.Exec(`
-		CREATE VIRTUAL TABLE IF NOT EXISTS comment_search USING fts5(
-			doc_id UNINDEXED,
-			target_id UNINDEXED,
-			body,
-			tokenize = 'unicode61 remove_diacritics 1'
-		);
-	`)
-	if err != nil {
-		return fmt.Errorf("failed to create comment_search table: %w", err)
+	for _, table := range ftsTables {
+		_, err := s.sqlDB.Exec(fmt.Sprintf(`
+			CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(
+				%s,
+				tokenize = 'unicode61 remove_diacritics 1'
+			);
+		`, table.name, table.columns))
+		if err != nil {
+			return fmt.Errorf("failed to create %s table: %w", table.name, err)
+		}
 	}
 
 	return nil
 }
 
 // Search は指定されたクエリに基づいてコンテンツを検索する
+// 件数とファセットは種類ごとにSQLで集計し、結果は各種類からページの範囲のみを取得して指定された順序で統合する
 func (s *searchServiceImpl) Search(ctx context.Context, query models.SearchQuery) (*models.SearchResults, error) {
+	if query.Limit <= 0 {
+		query.Limit = 20 // デフォルト値
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+
 	results := &models.SearchResults{
 		Query:       query.Query,
 		CurrentPage: query.Offset/query.Limit + 1,
 		Results:     []models.SearchResult{},
+		Facets:      models.NewSearchFacets(),
 	}
 
-	if query.Limit <= 0 {
-		query.Limit = 20 // デフォルト値
+	// 検索式の解析（APIパラメータで指定されたフィルタはANDで結合する）
+	if query.Expr == nil {
+		parsed, err := models.ParseSearchQueryWithLabelScopes(query.Query, s.labelScopes(ctx))
+		if err != nil {
+			return nil, err
+		}
+		query.Expr = parsed.Expr
+		if query.Sort.Field == "" {
+			query.Sort = parsed.Sort
+		}
 	}
+	root := models.SearchAnd(query.Expr, structuredSearchFilter(query))
 
-	// SQLクエリのパラメータ
-	ftsQuery := s.formatFTSQuery(query.Query)
-	statusCondition := ""
-	if query.Status != "" && query.Status != "all" {
-		statusCondition = fmt.Sprintf("AND i.status = '%s'", query.Status)
+	// 種類ごとの取得範囲（複数の種類を統合する場合は各種類の先頭からページの末尾までを取得し、統合後にページングする）
+	types := s.targetTypes(query)
+	limit, offset := query.Offset+query.Limit, 0
+	if len(types) == 1 {
+		limit, offset = query.Limit, query.Offset
 	}
 
-	// ラベル条件の構築
-	labelCondition := ""
-	labelArgs := []interface{}{}
-	if len(query.Labels) > 0 {
-		placeholders := make([]string, len(query.Labels))
-		for i, label := range query.Labels {
-			placeholders[i] = "?"
-			labelArgs = append(labelArgs, label)
+	var page []models.SearchResult
+	for _, resultType := range types {
+		target := searchTargets[resultType]
+		compiled, err := compileSearch(target, root, query.Sort, query.ViewerID)
+		if err != nil {
+			return nil, err
+		}
+		if query.RepositoryIDs != nil {
+			clause, args := repositoryRestriction(target, query.RepositoryIDs)
+			compiled.conds.add(clause, args...)
+		}
+
+		// 件数とファセットの集計
+		count, err := s.countResults(ctx, target, compiled, &results.Facets)
+		if err != nil {
+			return nil, err
+		}
+		results.TotalCount += count
+		if count <= offset {
+			continue
 		}
-		labelCondition = fmt.Sprintf("AND (SELECT COUNT(*) FROM json_each(i.labels) WHERE json_each.value IN (%s)) = %d",
-			strings.Join(placeholders, ","), len(query.Labels))
+
+		var typeResults []models.SearchResult
+		switch resultType {
+		case models.SearchResultTypeIssue:
+			typeResults, err = s.searchIssues(ctx, compiled, limit, offset)
+		case models.SearchResultTypeComment:
+			typeResults, err = s.searchComments(ctx, target, "issues", compiled, limit, offset)
+		case models.SearchResultTypeDiscussion:
+			typeResults, err = s.searchDiscussions(ctx, compiled, limit, offset)
+		case models.SearchResultTypeDiscussionComment:
+			typeResults, err = s.searchComments(ctx, target, "discussions", compiled, limit, offset)
+		case models.SearchResultTypeMilestone:
+			typeResults, err = s.searchMilestones(ctx, compiled, limit, offset)
+		case models.SearchResultTypeLabel:
+			typeResults, err = s.searchLabels(ctx, compiled, limit, offset)
+		}
+		if err != nil {
+			return nil, err
+		}
+		page = append(page, typeResults...)
 	}
 
-	// 担当者条件
-	assigneeCondition := ""
-	if query.AssigneeID > 0 {
-		assigneeCondition = fmt.Sprintf("AND i.assignee_id = %d", query.AssigneeID)
+	// 検索結果を指定された順序でソート
+	s.sortResults(page, query.Sort)
+
+	results.TotalPages = (results.TotalCount + query.Limit - 1) / query.Limit
+
+	// ページング（単一の種類の場合はSQLでページング済み）
+	if len(types) > 1 {
+		if query.Offset >= len(page) {
+			page = nil
+		} else if end := query.Offset + query.Limit; end < len(page) {
+			page = page[query.Offset:end]
+		} else {
+			page = page[query.Offset:]
+		}
+	}
+	if len(page) > 0 {
+		results.Results = page
 	}
 
-	// 作成者条件
-	creatorCondition := ""
-	if query.CreatorID > 0 {
-		creatorCondition = fmt.Sprintf("AND i.creator_id = %d", query.CreatorID)
+	return results, nil
+}
+
+// targetTypes は検索対象とする結果種類を返す
+// 修飾子を持たない種類は検索式のコンパイル時に一致しない条件となるため、ここでは種類の指定のみを扱う
+func (s *searchServiceImpl) targetTypes(query models.SearchQuery) []models.SearchResultType {
+	if len(query.Types) == 0 {
+		return models.AllSearchResultTypes
 	}
+	return query.Types
+}
 
-	// Issue検索
-	issueQuery := fmt.Sprintf(`
-		SELECT 
-			i.id, i.title, i.body, i.status, i.labels, i.assignee_id, 
-			i.creator_id, i.created_at, i.updated_at,
-			highlight(issue_search, 1, '<mark>', '</mark>') as title_highlight,
-			highlight(issue_search, 2, '<mark>', '</mark>') as body_highlight,
-			rank
+// searchIssues はIssueを検索する
+func (s *searchServiceImpl) searchIssues(ctx context.Context, compiled *compiledSearch, limit, offset int) ([]models.SearchResult, error) {
+	hasMatch := compiled.hasMatch
+
+	rows, err := s.sqlDB.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			i.id, i.title, i.body, i.status,
+			COALESCE((SELECT group_concat(l.name) FROM issue_labels il JOIN labels l ON l.id = il.label_id WHERE il.issue_id = i.id), ''),
+			COALESCE(i.assignee_id, 0), i.creator_id, i.created_at, i.updated_at,
+			%s, %s, %s
 		FROM issue_search
 		JOIN issues i ON issue_search.doc_id = i.id
-		WHERE issue_search MATCH ?
-		%s %s %s %s
-		ORDER BY rank
-		LIMIT ? OFFSET ?
-	`, statusCondition, labelCondition, assigneeCondition, creatorCondition)
-
-	// Issue検索の実行
-	issueArgs := append([]interface{}{ftsQuery}, labelArgs...)
-	issueArgs = append(issueArgs, query.Limit, query.Offset)
-
-	issueRows, err := s.sqlDB.QueryContext(ctx, issueQuery, issueArgs...)
+		WHERE %s
+		ORDER BY %s
+		LIMIT %d OFFSET %d
+	`, ftsColumn("issue_search", "title", 1, hasMatch), ftsColumn("issue_search", "body", 2, hasMatch),
+		ftsRank("issue_search", hasMatch), compiled.conds, compiled.orderBy, limit, offset), compiled.conds.args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search issues: %w", err)
 	}
-	defer issueRows.Close()
+	defer rows.Close()
 
-	// Issue結果の処理
-	for issueRows.Next() {
+	var results []models.SearchResult
+	for rows.Next() {
 		var result models.SearchResult
-		var titleHighlight, bodyHighlight string
-		var labelsJSON string
-		var createdAt, updatedAt string
-		var rank float64
+		var titleHighlight, bodyHighlight, labels, createdAt, updatedAt string
 
-		err := issueRows.Scan(
-			&result.ID, &result.Title, &result.Body, &result.Status, &labelsJSON,
+		err := rows.Scan(
+			&result.ID, &result.Title, &result.Body, &result.Status, &labels,
 			&result.AssigneeID, &result.CreatorID, &createdAt, &updatedAt,
-			&titleHighlight, &bodyHighlight, &rank,
+			&titleHighlight, &bodyHighlight, &result.Rank,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan issue result: %w", err)
 		}
 
-		// 日時のパース
-		result.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
-		result.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
-
-		// ラベルの変換
-		result.Labels = []string{}
-		// JSON文字列からラベル配列への変換処理
-
-		// 検索結果のメタデータ設定
 		result.Type = models.SearchResultTypeIssue
-		result.Rank = rank
+		result.CreatedAt = parseSearchTime(createdAt)
+		result.UpdatedAt = parseSearchTime(updatedAt)
+		result.Labels = []string{}
+		if labels != "" {
+			result.Labels = strings.Split(labels, ",")
+		}
 		result.Highlighted = s.combineHighlights(titleHighlight, bodyHighlight)
 		result.Snippet = s.createSnippet(result.Highlighted)
 
-		results.Results = append(results.Results, result)
+		results = append(results, result)
 	}
+	return results, rows.Err()
+}
 
-	// コメント検索クエリ
-	commentQuery := fmt.Sprintf(`
-		SELECT 
+// searchComments はIssueまたはDiscussionへのコメントを検索する
+func (s *searchServiceImpl) searchComments(
+	ctx context.Context,
+	target searchTarget,
+	targetTable string,
+	compiled *compiledSearch,
+	limit, offset int) ([]models.SearchResult, error) {
+
+	hasMatch := compiled.hasMatch
+
+	rows, err := s.sqlDB.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
 			c.id, c.body, c.creator_id, c.created_at, c.updated_at, c.target_id,
-			highlight(comment_search, 2, '<mark>', '</mark>') as body_highlight,
-			rank
-		FROM comment_search
-		JOIN comments c ON comment_search.doc_id = c.id
-		WHERE comment_search MATCH ?
-		AND c.type = 'issue'
-		%s
-		ORDER BY rank
-		LIMIT ? OFFSET ?
-	`, creatorCondition)
-
-	// コメント検索の実行
-	commentArgs := []interface{}{ftsQuery, query.Limit, query.Offset}
-
-	commentRows, err := s.sqlDB.QueryContext(ctx, commentQuery, commentArgs...)
+			COALESCE(t.title, ''), %s, %s
+		FROM %s
+		JOIN comments c ON %s.doc_id = c.id
+		LEFT JOIN %s t ON t.id = c.target_id
+		WHERE %s
+		ORDER BY %s
+		LIMIT %d OFFSET %d
+	`, ftsColumn(target.ftsTable, "body", 2, hasMatch), ftsRank(target.ftsTable, hasMatch),
+		target.ftsTable, target.ftsTable, targetTable, compiled.conds, compiled.orderBy, limit, offset), compiled.conds.args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search comments: %w", err)
 	}
-	defer commentRows.Close()
+	defer rows.Close()
 
-	// コメント結果の処理
-	for commentRows.Next() {
+	var results []models.SearchResult
+	for rows.Next() {
 		var result models.SearchResult
-		var bodyHighlight string
-		var createdAt, updatedAt string
-		var rank float64
+		var targetTitle, bodyHighlight, createdAt, updatedAt string
 
-		err := commentRows.Scan(
+		err := rows.Scan(
 			&result.ID, &result.Body, &result.CreatorID, &createdAt, &updatedAt,
-			&result.TargetID, &bodyHighlight, &rank,
+			&result.TargetID, &targetTitle, &bodyHighlight, &result.Rank,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan comment result: %w", err)
 		}
 
-		// 日時のパース
-		result.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
-		result.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
-
-		// 関連するIssueのタイトルを取得
-		issue, err := s.issueRepo.GetByID(ctx, result.TargetID)
-		if err == nil && issue != nil {
-			result.Title = fmt.Sprintf("Comment on: %s", issue.Title)
-		} else {
-			result.Title = "Comment"
+		result.Type = target.resultType
+		result.CreatedAt = parseSearchTime(createdAt)
+		result.UpdatedAt = parseSearchTime(updatedAt)
+		result.Title = "Comment"
+		if targetTitle != "" {
+			result.Title = fmt.Sprintf("Comment on: %s", targetTitle)
 		}
-
-		// 検索結果のメタデータ設定
-		result.Type = models.SearchResultTypeComment
-		result.Rank = rank
 		result.Highlighted = bodyHighlight
 		result.Snippet = s.createSnippet(bodyHighlight)
 
-		results.Results = append(results.Results, result)
+		results = append(results, result)
 	}
+	return results, rows.Err()
+}
 
-	// 総結果数のカウント取得
-	var totalCount int
-	countQuery := `
-		SELECT 
-			(SELECT COUNT(*) FROM issue_search WHERE issue_search MATCH ?) +
-			(SELECT COUNT(*) FROM comment_search WHERE comment_search MATCH ?)
-	`
-	err = s.sqlDB.QueryRowContext(ctx, countQuery, ftsQuery, ftsQuery).Scan(&totalCount)
+// searchDiscussions はDiscussionを検索する
+func (s *searchServiceImpl) searchDiscussions(ctx context.Context, compiled *compiledSearch, limit, offset int) ([]models.SearchResult, error) {
+	hasMatch := compiled.hasMatch
+
+	rows, err := s.sqlDB.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			d.id, d.title, d.body, d.status, d.category,
+			COALESCE((SELECT group_concat(l.name) FROM discussion_labels dl JOIN labels l ON l.id = dl.label_id WHERE dl.discussion_id = d.id), ''),
+			d.creator_id, d.created_at, d.updated_at,
+			%s, %s, %s
+		FROM discussion_search
+		JOIN discussions d ON discussion_search.doc_id = d.id
+		WHERE %s
+		ORDER BY %s
+		LIMIT %d OFFSET %d
+	`, ftsColumn("discussion_search", "title", 1, hasMatch), ftsColumn("discussion_search", "body", 2, hasMatch),
+		ftsRank("discussion_search", hasMatch), compiled.conds, compiled.orderBy, limit, offset), compiled.conds.args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get total count: %w", err)
+		return nil, fmt.Errorf("failed to search discussions: %w", err)
 	}
+	defer rows.Close()
 
-	results.TotalCount = totalCount
-	results.TotalPages = (totalCount + query.Limit - 1) / query.Limit
+	var results []models.SearchResult
+	for rows.Next() {
+		var result models.SearchResult
+		var titleHighlight, bodyHighlight, labels, createdAt, updatedAt string
 
-	// 検索結果をランクでソート
-	s.sortResultsByRank(results.Results)
+		err := rows.Scan(
+			&result.ID, &result.Title, &result.Body, &result.Status, &result.Category, &labels,
+			&result.CreatorID, &createdAt, &updatedAt,
+			&titleHighlight, &bodyHighlight, &result.Rank,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan discussion result: %w", err)
+		}
 
-	return results, nil
+		result.Type = models.SearchResultTypeDiscussion
+		result.CreatedAt = parseSearchTime(createdAt)
+		result.UpdatedAt = parseSearchTime(updatedAt)
+		result.Labels = []string{}
+		if labels != "" {
+			result.Labels = strings.Split(labels, ",")
+		}
+		result.Highlighted = s.combineHighlights(titleHighlight, bodyHighlight)
+		result.Snippet = s.createSnippet(result.Highlighted)
+
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// searchMilestones はマイルストーンを検索する
+func (s *searchServiceImpl) searchMilestones(ctx context.Context, compiled *compiledSearch, limit, offset int) ([]models.SearchResult, error) {
+	hasMatch := compiled.hasMatch
+
+	rows, err := s.sqlDB.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			m.id, m.title, m.description, m.status, m.creator_id, m.created_at, m.updated_at,
+			%s, %s, %s
+		FROM milestone_search
+		JOIN milestones m ON milestone_search.doc_id = m.id
+		WHERE %s
+		ORDER BY %s
+		LIMIT %d OFFSET %d
+	`, ftsColumn("milestone_search", "title", 1, hasMatch), ftsColumn("milestone_search", "description", 2, hasMatch),
+		ftsRank("milestone_search", hasMatch), compiled.conds, compiled.orderBy, limit, offset), compiled.conds.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search milestones: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.SearchResult
+	for rows.Next() {
+		var result models.SearchResult
+		var titleHighlight, bodyHighlight, createdAt, updatedAt string
+
+		err := rows.Scan(
+			&result.ID, &result.Title, &result.Body, &result.Status, &result.CreatorID,
+			&createdAt, &updatedAt, &titleHighlight, &bodyHighlight, &result.Rank,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan milestone result: %w", err)
+		}
+
+		result.Type = models.SearchResultTypeMilestone
+		result.CreatedAt = parseSearchTime(createdAt)
+		result.UpdatedAt = parseSearchTime(updatedAt)
+		result.Highlighted = s.combineHighlights(titleHighlight, bodyHighlight)
+		result.Snippet = s.createSnippet(result.Highlighted)
+
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// searchLabels はラベルを検索する
+func (s *searchServiceImpl) searchLabels(ctx context.Context, compiled *compiledSearch, limit, offset int) ([]models.SearchResult, error) {
+	hasMatch := compiled.hasMatch
+
+	rows, err := s.sqlDB.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			l.id, l.name, l.description, l.created_at, l.updated_at,
+			%s, %s, %s
+		FROM label_search
+		JOIN labels l ON label_search.doc_id = l.id
+		WHERE %s
+		ORDER BY %s
+		LIMIT %d OFFSET %d
+	`, ftsColumn("label_search", "name", 1, hasMatch), ftsColumn("label_search", "description", 2, hasMatch),
+		ftsRank("label_search", hasMatch), compiled.conds, compiled.orderBy, limit, offset), compiled.conds.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search labels: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.SearchResult
+	for rows.Next() {
+		var result models.SearchResult
+		var nameHighlight, descriptionHighlight, createdAt, updatedAt string
+
+		err := rows.Scan(
+			&result.ID, &result.Title, &result.Body, &createdAt, &updatedAt,
+			&nameHighlight, &descriptionHighlight, &result.Rank,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan label result: %w", err)
+		}
+
+		result.Type = models.SearchResultTypeLabel
+		result.CreatedAt = parseSearchTime(createdAt)
+		result.UpdatedAt = parseSearchTime(updatedAt)
+		result.Highlighted = s.combineHighlights(nameHighlight, descriptionHighlight)
+		result.Snippet = s.createSnippet(result.Highlighted)
+
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// countResults は検索条件に一致する件数を返し、種類・ステータス・ラベル・カテゴリごとの件数をファセットに加える
+func (s *searchServiceImpl) countResults(ctx context.Context, target searchTarget, compiled *compiledSearch, facets *models.SearchFacets) (int, error) {
+	from := fmt.Sprintf("%s WHERE %s", target.from(), compiled.conds)
+	args := compiled.conds.args
+
+	var count int
+	if err := s.sqlDB.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+from, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count %s results: %w", target.resultType, err)
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	facets.Types[target.resultType] += count
+
+	if target.hasStatus {
+		err := s.countFacet(ctx, facets.Statuses, fmt.Sprintf(
+			"SELECT COALESCE(%s.status, ''), COUNT(*) FROM %s GROUP BY 1", target.alias, from), args)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if target.resultType == models.SearchResultTypeDiscussion {
+		err := s.countFacet(ctx, facets.Categories, fmt.Sprintf(
+			"SELECT COALESCE(%s.category, ''), COUNT(*) FROM %s GROUP BY 1", target.alias, from), args)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if target.labelTable != "" {
+		err := s.countFacet(ctx, facets.Labels, fmt.Sprintf(`
+			SELECT fl.name, COUNT(*)
+			FROM %s fx
+			JOIN labels fl ON fl.id = fx.label_id
+			WHERE fx.%s IN (SELECT %s.id FROM %s)
+			GROUP BY fl.name
+		`, target.labelTable, target.labelKey, target.alias, from), args)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return count, nil
+}
+
+// countFacet はGROUP BYで集計した値ごとの件数をファセットに加える（空の値は除く）
+func (s *searchServiceImpl) countFacet(ctx context.Context, counts map[string]int, query string, args []interface{}) error {
+	rows, err := s.sqlDB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to count facets: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var value string
+		var count int
+		if err := rows.Scan(&value, &count); err != nil {
+			return fmt.Errorf("failed to scan facet: %w", err)
+		}
+		if value != "" {
+			counts[value] += count
+		}
+	}
+	return rows.Err()
 }
 
 // IndexIssue は指定されたIssueをインデックスに追加または更新する
@@ -277,12 +525,7 @@ func (s *searchServiceImpl) IndexIssue(ctx context.Context, issue *models.Issue)
 	}
 
 	// 新しいインデックスを追加
-	_, err := s.sqlDB.ExecContext(ctx, `
-		INSERT INTO issue_search (doc_id, title, body)
-		VALUES (?, ?, ?)
-	`, issue.ID, issue.Title, issue.Body)
-
-	if err != nil {
+	if err := insertIssueIndex(ctx, s.sqlDB, issue); err != nil {
 		return fmt.Errorf("failed to index issue: %w", err)
 	}
 
@@ -290,10 +533,15 @@ func (s *searchServiceImpl) IndexIssue(ctx context.Context, issue *models.Issue)
 }
 
 // IndexComment は指定されたCommentをインデックスに追加または更新する
+// 返信コメントは親コメントのターゲット（Issue/Discussion）に応じたテーブルに登録する
 func (s *searchServiceImpl) IndexComment(ctx context.Context, comment *models.Comment) error {
-	// コメントタイプが"issue"のみをインデックス化
-	if comment.Type != "issue" {
-		return nil
+	targetType := comment.Type
+	if comment.IsReply() {
+		parent, err := s.commentRepo.GetByID(ctx, comment.ParentCommentID)
+		if err != nil {
+			return fmt.Errorf("failed to get parent comment: %w", err)
+		}
+		targetType = parent.Type
 	}
 
 	// 既存のインデックスを削除
@@ -302,34 +550,58 @@ func (s *searchServiceImpl) IndexComment(ctx context.Context, comment *models.Co
 	}
 
 	// 新しいインデックスを追加
-	_, err := s.sqlDB.ExecContext(ctx, `
-		INSERT INTO comment_search (doc_id, target_id, body)
-		VALUES (?, ?, ?)
-	`, comment.ID, comment.TargetID, comment.Body)
-
-	if err != nil {
+	if err := insertCommentIndex(ctx, s.sqlDB, comment, targetType); err != nil {
 		return fmt.Errorf("failed to index comment: %w", err)
 	}
 
 	return nil
 }
 
+// IndexDiscussion は指定されたDiscussionをインデックスに追加または更新する
+func (s *searchServiceImpl) IndexDiscussion(ctx context.Context, discussion *models.Discussion) error {
+	if err := s.DeleteFromIndex(ctx, "discussion", discussion.ID); err != nil {
+		return fmt.Errorf("failed to delete existing index: %w", err)
+	}
+	if err := insertDiscussionIndex(ctx, s.sqlDB, discussion); err != nil {
+		return fmt.Errorf("failed to index discussion: %w", err)
+	}
+	return nil
+}
+
+// IndexMilestone は指定されたMilestoneをインデックスに追加または更新する
+func (s *searchServiceImpl) IndexMilestone(ctx context.Context, milestone *models.Milestone) error {
+	if err := s.DeleteFromIndex(ctx, "milestone", milestone.ID); err != nil {
+		return fmt.Errorf("failed to delete existing index: %w", err)
+	}
+	if err := insertMilestoneIndex(ctx, s.sqlDB, milestone); err != nil {
+		return fmt.Errorf("failed to index milestone: %w", err)
+	}
+	return nil
+}
+
+// IndexLabel は指定されたLabelをインデックスに追加または更新する
+func (s *searchServiceImpl) IndexLabel(ctx context.Context, label *models.Label) error {
+	if err := s.DeleteFromIndex(ctx, "label", label.ID); err != nil {
+		return fmt.Errorf("failed to delete existing index: %w", err)
+	}
+	if err := insertLabelIndex(ctx, s.sqlDB, label); err != nil {
+		return fmt.Errorf("failed to index label: %w", err)
+	}
+	return nil
+}
+
 // DeleteFromIndex は指定されたドキュメントをインデックスから削除する
 func (s *searchServiceImpl) DeleteFromIndex(ctx context.Context, docType string, docID int64) error {
-	var query string
-
-	switch docType {
-	case "issue":
-		query = "DELETE FROM issue_search WHERE doc_id = ?"
-	case "comment":
-		query = "DELETE FROM comment_search WHERE doc_id = ?"
-	default:
+	tables, ok := indexTablesByDocType[docType]
+	if !ok {
 		return fmt.Errorf("unknown document type: %s", docType)
 	}
 
-	_, err := s.sqlDB.ExecContext(ctx, query, docID)
-	if err != nil {
-		return fmt.Errorf("failed to delete from index: %w", err)
+	for _, table := range tables {
+		_, err := s.sqlDB.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE doc_id = ?", table), docID)
+		if err != nil {
+			return fmt.Errorf("failed to delete from index: %w", err)
+		}
 	}
 
 	return nil
@@ -337,58 +609,95 @@ func (s *searchServiceImpl) DeleteFromIndex(ctx context.Context, docType string,
 
 // RebuildIndex はすべてのインデックスを再構築する
 func (s *searchServiceImpl) RebuildIndex(ctx context.Context) error {
-	// トランザクション開始
-	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	// 再構築対象のデータを取得
+	issues, err := s.issueRepo.GetAll(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return fmt.Errorf("failed to get all issues: %w", err)
 	}
-	defer tx.Rollback()
 
-	// インデックスをクリア
-	_, err = tx.ExecContext(ctx, "DELETE FROM issue_search")
+	var comments []*models.Comment
+	for _, commentType := range []string{"issue", "discussion", "reply"} {
+		typeComments, err := s.commentRepo.GetAllOfType(ctx, commentType)
+		if err != nil {
+			return fmt.Errorf("failed to get all %s comments: %w", commentType, err)
+		}
+		comments = append(comments, typeComments...)
+	}
+
+	discussions, err := listAll(func(page, limit int) ([]*models.Discussion, int, error) {
+		return s.discussionRepo.List(ctx, nil, page, limit)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to clear issue index: %w", err)
+		return fmt.Errorf("failed to get all discussions: %w", err)
 	}
 
-	_, err = tx.ExecContext(ctx, "DELETE FROM comment_search")
+	milestones, err := listAll(func(page, limit int) ([]*models.Milestone, int, error) {
+		return s.milestoneRepo.List(ctx, nil, page, limit)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to clear comment index: %w", err)
+		return fmt.Errorf("failed to get all milestones: %w", err)
 	}
 
-	// すべてのIssueを再インデックス化
-	issues, err := s.issueRepo.GetAll(ctx)
+	labels, err := listAll(func(page, limit int) ([]*models.Label, int, error) {
+		return s.labelRepo.List(ctx, nil, page, limit)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get all issues: %w", err)
+		return fmt.Errorf("failed to get all labels: %w", err)
 	}
 
-	for _, issue := range issues {
-		_, err = tx.ExecContext(ctx, `
-			INSERT INTO issue_search (doc_id, title, body)
-			VALUES (?, ?, ?)
-		`, issue.ID, issue.Title, issue.Body)
+	// トランザクション開始
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-		if err != nil {
-			return fmt.Errorf("failed to index issue %d: %w", issue.ID, err)
+	// インデックスをクリア
+	for _, table := range ftsTables {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table.name); err != nil {
+			return fmt.Errorf("failed to clear %s: %w", table.name, err)
 		}
 	}
 
-	// すべてのIssueコメントを再インデックス化
-	comments, err := s.commentRepo.GetAllOfType(ctx, "issue")
-	if err != nil {
-		return fmt.Errorf("failed to get all issue comments: %w", err)
+	for _, issue := range issues {
+		if err := insertIssueIndex(ctx, tx, issue); err != nil {
+			return fmt.Errorf("failed to index issue %d: %w", issue.ID, err)
+		}
 	}
 
+	// 返信コメントは親コメントのタイプで振り分ける
+	commentTypes := make(map[int64]string, len(comments))
 	for _, comment := range comments {
-		_, err = tx.ExecContext(ctx, `
-			INSERT INTO comment_search (doc_id, target_id, body)
-			VALUES (?, ?, ?)
-		`, comment.ID, comment.TargetID, comment.Body)
-
-		if err != nil {
+		commentTypes[comment.ID] = comment.Type
+	}
+	for _, comment := range comments {
+		targetType := comment.Type
+		if comment.IsReply() {
+			targetType = commentTypes[comment.ParentCommentID]
+		}
+		if err := insertCommentIndex(ctx, tx, comment, targetType); err != nil {
 			return fmt.Errorf("failed to index comment %d: %w", comment.ID, err)
 		}
 	}
 
+	for _, discussion := range discussions {
+		if err := insertDiscussionIndex(ctx, tx, discussion); err != nil {
+			return fmt.Errorf("failed to index discussion %d: %w", discussion.ID, err)
+		}
+	}
+
+	for _, milestone := range milestones {
+		if err := insertMilestoneIndex(ctx, tx, milestone); err != nil {
+			return fmt.Errorf("failed to index milestone %d: %w", milestone.ID, err)
+		}
+	}
+
+	for _, label := range labels {
+		if err := insertLabelIndex(ctx, tx, label); err != nil {
+			return fmt.Errorf("failed to index label %d: %w", label.ID, err)
+		}
+	}
+
 	// トランザクションをコミット
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
@@ -397,94 +706,172 @@ func (s *searchServiceImpl) RebuildIndex(ctx context.Context) error {
 	return nil
 }
 
-// ParseQuery は検索クエリ文字列を解析する
-func (s *searchServiceImpl) ParseQuery(queryString string) models.SearchQuery {
-	query := models.SearchQuery{
-		Query:  queryString,
-		Status: "all", // デフォルト値
-		Limit:  20,    // デフォルト値
+// VerifyIndex はインデックス件数をデータ件数と照合し、不一致の場合はインデックスを再構築する
+func (s *searchServiceImpl) VerifyIndex(ctx context.Context) error {
+	issueCount, err := s.issueRepo.CountIssues(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count issues: %w", err)
 	}
 
-	// ラベルフィルタの解析: label:bug label:feature
-	labelRegex := regexp.MustCompile(`label:(\S+)`)
-	labelMatches := labelRegex.FindAllStringSubmatch(queryString, -1)
-	for _, match := range labelMatches {
-		if len(match) > 1 {
-			query.Labels = append(query.Labels, match[1])
-			queryString = strings.Replace(queryString, match[0], "", 1)
-		}
+	discussionCount, err := s.discussionRepo.CountDiscussions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count discussions: %w", err)
 	}
 
-	// ステータスフィルタの解析: status:open / status:closed
-	statusRegex := regexp.MustCompile(`status:(\S+)`)
-	statusMatch := statusRegex.FindStringSubmatch(queryString)
-	if len(statusMatch) > 1 {
-		query.Status = statusMatch[1]
-		queryString = strings.Replace(queryString, statusMatch[0], "", 1)
+	var indexedIssues, indexedDiscussions int64
+	if err := s.sqlDB.QueryRowContext(ctx, "SELECT COUNT(*) FROM issue_search").Scan(&indexedIssues); err != nil {
+		return fmt.Errorf("failed to count indexed issues: %w", err)
+	}
+	if err := s.sqlDB.QueryRowContext(ctx, "SELECT COUNT(*) FROM discussion_search").Scan(&indexedDiscussions); err != nil {
+		return fmt.Errorf("failed to count indexed discussions: %w", err)
 	}
 
-	// 担当者フィルタの解析: assignee:123
-	assigneeRegex := regexp.MustCompile(`assignee:(\d+)`)
-	assigneeMatch := assigneeRegex.FindStringSubmatch(queryString)
-	if len(assigneeMatch) > 1 {
-		query.AssigneeID = s.parseID(assigneeMatch[1])
-		queryString = strings.Replace(queryString, assigneeMatch[0], "", 1)
+	if indexedIssues == issueCount && indexedDiscussions == discussionCount {
+		return nil
 	}
 
-	// 作成者フィルタの解析: creator:123
-	creatorRegex := regexp.MustCompile(`creator:(\d+)`)
-	creatorMatch := creatorRegex.FindStringSubmatch(queryString)
-	if len(creatorMatch) > 1 {
-		query.CreatorID = s.parseID(creatorMatch[1])
-		queryString = strings.Replace(queryString, creatorMatch[0], "", 1)
+	log.Printf("Search index is out of date (indexed issues: %d/%d, indexed discussions: %d/%d), rebuilding...",
+		indexedIssues, issueCount, indexedDiscussions, discussionCount)
+	return s.RebuildIndex(ctx)
+}
+
+// sqlExecer はsql.DBとsql.Txに共通する実行メソッド
+type sqlExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertIssueIndex はIssueをインデックスに登録する
+func insertIssueIndex(ctx context.Context, db sqlExecer, issue *models.Issue) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO issue_search (doc_id, title, body)
+		VALUES (?, ?, ?)
+	`, issue.ID, issue.Title, issue.Body)
+	return err
+}
+
+// insertCommentIndex はコメントをターゲットタイプに応じたインデックスに登録する
+func insertCommentIndex(ctx context.Context, db sqlExecer, comment *models.Comment, targetType string) error {
+	var table string
+	switch targetType {
+	case "issue":
+		table = "comment_search"
+	case "discussion":
+		table = "discussion_comment_search"
+	default:
+		return nil
 	}
 
-	// 残りのテキストをクエリとして設定
-	query.Query = strings.TrimSpace(queryString)
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO %s (doc_id, target_id, body)
+		VALUES (?, ?, ?)
+	`, table), comment.ID, comment.TargetID, comment.Body)
+	return err
+}
+
+// insertDiscussionIndex はDiscussionをインデックスに登録する
+func insertDiscussionIndex(ctx context.Context, db sqlExecer, discussion *models.Discussion) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO discussion_search (doc_id, title, body, category)
+		VALUES (?, ?, ?, ?)
+	`, discussion.ID, discussion.Title, discussion.Body, discussion.Category)
+	return err
+}
 
-	return query
+// insertMilestoneIndex はMilestoneをインデックスに登録する
+func insertMilestoneIndex(ctx context.Context, db sqlExecer, milestone *models.Milestone) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO milestone_search (doc_id, title, description)
+		VALUES (?, ?, ?)
+	`, milestone.ID, milestone.Title, milestone.Description)
+	return err
 }
 
-// 補助関数
+// insertLabelIndex はLabelをインデックスに登録する
+func insertLabelIndex(ctx context.Context, db sqlExecer, label *models.Label) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO label_search (doc_id, name, description)
+		VALUES (?, ?, ?)
+	`, label.ID, label.Name, label.Description)
+	return err
+}
 
-// formatFTSQuery はFTS5用の検索クエリを整形する
-func (s *searchServiceImpl) formatFTSQuery(query string) string {
-	// 基本的なクエリの整形
-	formattedQuery := strings.TrimSpace(query)
+// listAll はページング取得を繰り返してすべての要素を取得する
+func listAll[T any](fetch func(page, limit int) ([]T, int, error)) ([]T, error) {
+	const pageSize = 500
 
-	// 空のクエリの場合は全件一致を返す
-	if formattedQuery == "" {
-		return "*"
+	var all []T
+	for page := 1; ; page++ {
+		items, total, err := fetch(page, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if len(items) < pageSize || len(all) >= total {
+			return all, nil
+		}
 	}
+}
+
+// sqlConditions はパラメータ化されたWHERE条件を組み立てる
+type sqlConditions struct {
+	clauses []string
+	args    []interface{}
+}
+
+// add は条件とそのパラメータを追加する
+func (c *sqlConditions) add(clause string, args ...interface{}) {
+	c.clauses = append(c.clauses, clause)
+	c.args = append(c.args, args...)
+}
 
-	// 特殊文字のエスケープ
-	escapeChars := []string{"'", "\"", "\\"}
-	for _, char := range escapeChars {
-		formattedQuery = strings.ReplaceAll(formattedQuery, char, "\\"+char)
+// String はAND結合したWHERE句を返す（条件がない場合は常に真）
+func (c *sqlConditions) String() string {
+	if len(c.clauses) == 0 {
+		return "1 = 1"
 	}
+	return strings.Join(c.clauses, " AND ")
+}
 
-	// AND/OR演算子のサポート
-	formattedQuery = strings.ReplaceAll(formattedQuery, " AND ", " AND ")
-	formattedQuery = strings.ReplaceAll(formattedQuery, " OR ", " OR ")
+// ftsColumn はMATCHがある場合はハイライト付き、ない場合は元の値を返すSQL式を作成する
+func ftsColumn(table, column string, index int, hasMatch bool) string {
+	if hasMatch {
+		return fmt.Sprintf("highlight(%s, %d, '<mark>', '</mark>')", table, index)
+	}
+	return table + "." + column
+}
 
-	// 部分一致検索のためにワイルドカードを追加
-	words := strings.Fields(formattedQuery)
-	for i, word := range words {
-		if !strings.HasPrefix(word, "\"") && !strings.HasSuffix(word, "\"") {
-			words[i] = word + "*"
-		}
+// ftsRank はMATCHがある場合はFTS5のランク、ない場合は0を返すSQL式を作成する
+func ftsRank(table string, hasMatch bool) string {
+	if hasMatch {
+		return table + ".rank"
 	}
+	return "0"
+}
 
-	return strings.Join(words, " ")
+// parseSearchTime はSQLiteから取得した日時文字列をパースする
+func parseSearchTime(value string) time.Time {
+	t, _ := time.Parse(time.RFC3339, value)
+	return t
 }
 
-// parseID は文字列からID値を解析する
-func (s *searchServiceImpl) parseID(idStr string) int64 {
-	id := int64(0)
-	fmt.Sscanf(idStr, "%d", &id)
-	return id
+// ParseQuery は検索クエリ文字列を解析する
+// 構文エラーの場合は位置を含む *models.SearchSyntaxError を返す
+func (s *searchServiceImpl) ParseQuery(queryString string) (models.SearchQuery, error) {
+	return models.ParseSearchQueryWithLabelScopes(queryString, s.labelScopes(context.Background()))
 }
 
+// labelScopes は scope:value 形式で検索できるラベルのスコープの一覧を取得する
+// 取得に失敗した場合はスコープなしとして解析する（scope:* は常に使用できる）
+func (s *searchServiceImpl) labelScopes(ctx context.Context) []string {
+	var scopes []string
+	if err := s.db.WithContext(ctx).Model(&models.Label{}).Where("scope <> ''").Distinct().Pluck("scope", &scopes).Error; err != nil {
+		return nil
+	}
+	return scopes
+}
+
+// 補助関数
+
 // combineHighlights はタイトルと本文のハイライトを結合する
 func (s *searchServiceImpl) combineHighlights(titleHighlight, bodyHighlight string) string {
 	result := ""
@@ -512,7 +899,27 @@ func (s *searchServiceImpl) createSnippet(highlighted string) string {
 	return snippet
 }
 
-// sortResultsByRank は検索結果をランクでソートする
-func (s *searchServiceImpl) sortResultsByRank(results []models.SearchResult) {
-	// 実装省略 - より高度なランキングロジックを実装する場合に使用
+// sortResults は検索結果を指定された順序でソートする
+// 関連度順の場合、FTS5のrankは小さいほど関連度が高い。同順位の場合は更新日時の新しい順とする
+func (s *searchServiceImpl) sortResults(results []models.SearchResult, order models.SearchSort) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		switch order.Field {
+		case models.SearchSortCreated:
+			if order.Ascending {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+			return a.CreatedAt.After(b.CreatedAt)
+		case models.SearchSortUpdated:
+			if order.Ascending {
+				return a.UpdatedAt.Before(b.UpdatedAt)
+			}
+			return a.UpdatedAt.After(b.UpdatedAt)
+		}
+
+		if a.Rank != b.Rank {
+			return a.Rank < b.Rank
+		}
+		return a.UpdatedAt.After(b.UpdatedAt)
+	})
 }
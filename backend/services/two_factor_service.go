@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+)
+
+const (
+	// 認証アプリに表示する発行者名
+	totpIssuer = "TicketHub"
+	// TOTPの共有鍵の長さ（バイト、RFC 4226 の推奨値）
+	totpSecretLength = 20
+	// リカバリーコードの長さ（バイト、base32で16文字）
+	recoveryCodeLength = 10
+)
+
+var (
+	// ErrTwoFactorAlreadyEnabled は二要素認証がすでに有効な場合のエラー
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrTwoFactorNotEnabled は二要素認証が有効でない場合のエラー
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication is not enabled")
+	// ErrTwoFactorSetupRequired は登録を開始せずに有効化しようとした場合のエラー
+	ErrTwoFactorSetupRequired = errors.New("two-factor authentication setup has not been started")
+	// ErrInvalidTwoFactorCode はTOTPのコードまたはリカバリーコードが正しくない・使用済みの場合のエラー
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor authentication code")
+)
+
+// TwoFactorStatus はユーザーの二要素認証の状態
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorService はTOTPによる二要素認証の登録・解除とログインの2段階目の認証を扱うサービス
+type TwoFactorService struct {
+	twoFactorRepo repositories.TwoFactorRepository
+	userRepo      repositories.UserRepository
+	authService   *AuthService
+}
+
+// NewTwoFactorService は新しいTwoFactorServiceを作成します
+func NewTwoFactorService(twoFactorRepo repositories.TwoFactorRepository, userRepo repositories.UserRepository, authService *AuthService) *TwoFactorService {
+	return &TwoFactorService{
+		twoFactorRepo: twoFactorRepo,
+		userRepo:      userRepo,
+		authService:   authService,
+	}
+}
+
+// Status はユーザーの二要素認証の状態を取得します
+func (s *TwoFactorService) Status(ctx context.Context, userID int64) (*TwoFactorStatus, error) {
+	twoFactor, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	status := &TwoFactorStatus{}
+	if twoFactor == nil || !twoFactor.Enabled {
+		return status, nil
+	}
+
+	remaining, err := s.twoFactorRepo.CountUnusedRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	status.Enabled = true
+	status.EnabledAt = twoFactor.EnabledAt
+	status.RecoveryCodesRemaining = remaining
+	return status, nil
+}
+
+// Setup は二要素認証の登録を開始し、新しい共有鍵と認証アプリに登録するためのURIを返します（accountName は認証アプリに表示する名前）
+// 有効化するまでは共有鍵を何度でも作り直せます
+func (s *TwoFactorService) Setup(ctx context.Context, userID int64, accountName string) (string, string, error) {
+	twoFactor, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		return "", "", err
+	}
+	if twoFactor != nil && twoFactor.Enabled {
+		return "", "", ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+	if err := s.twoFactorRepo.Save(ctx, models.NewUserTwoFactor(userID, secret)); err != nil {
+		return "", "", fmt.Errorf("failed to save two-factor settings: %w", err)
+	}
+	return secret, models.TOTPURI(totpIssuer, accountName, secret), nil
+}
+
+// Enable は認証アプリのコードで登録を確認して二要素認証を有効にし、リカバリーコードを返します
+func (s *TwoFactorService) Enable(ctx context.Context, userID int64, code string) ([]string, error) {
+	twoFactor, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return nil, ErrTwoFactorSetupRequired
+	}
+	if twoFactor.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := models.MatchTOTPCode(twoFactor.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+	twoFactor.LastUsedStep = step
+	twoFactor.Enable()
+	if err := s.twoFactorRepo.Save(ctx, twoFactor); err != nil {
+		return nil, fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+
+	return s.replaceRecoveryCodes(ctx, userID)
+}
+
+// Disable はTOTPのコードまたはリカバリーコードを確認して二要素認証を無効にします
+func (s *TwoFactorService) Disable(ctx context.Context, userID int64, code string) error {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+	if err := s.twoFactorRepo.Delete(ctx, userID); err != nil {
+		return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes はTOTPのコードを確認してリカバリーコードを作り直します（以前のコードは使用できなくなります）
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID int64, code string) ([]string, error) {
+	if !models.IsTOTPCode(strings.TrimSpace(code)) {
+		return nil, ErrInvalidTwoFactorCode
+	}
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return nil, err
+	}
+	return s.replaceRecoveryCodes(ctx, userID)
+}
+
+// Reset は管理者がユーザーの二要素認証を解除します
+// 二要素認証が有効だった場合はtrueを返します
+func (s *TwoFactorService) Reset(ctx context.Context, userID int64) (bool, error) {
+	twoFactor, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if twoFactor == nil {
+		return false, nil
+	}
+	if err := s.twoFactorRepo.Delete(ctx, userID); err != nil {
+		return false, fmt.Errorf("failed to reset two-factor authentication: %w", err)
+	}
+	return twoFactor.Enabled, nil
+}
+
+// Verify はTOTPのコード（6桁の数字）またはリカバリーコードを確認し、使用済みにします
+func (s *TwoFactorService) Verify(ctx context.Context, userID int64, code string) error {
+	twoFactor, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if twoFactor == nil || !twoFactor.Enabled {
+		return ErrTwoFactorNotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if models.IsTOTPCode(code) {
+		step, ok := models.MatchTOTPCode(twoFactor.Secret, code, time.Now())
+		if !ok {
+			return ErrInvalidTwoFactorCode
+		}
+		// 使用済みのコードの再利用を防ぐ
+		used, err := s.twoFactorRepo.UseStep(ctx, userID, step)
+		if err != nil {
+			return fmt.Errorf("failed to record code use: %w", err)
+		}
+		if !used {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	used, err := s.twoFactorRepo.UseRecoveryCode(ctx, userID, models.HashRecoveryCode(code))
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	if !used {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// CompleteLogin は二要素認証の入力を待つ一時トークンとコードを確認し、アクセストークンとリフレッシュトークンを発行します
+// コードの誤りはパスワードの誤りと同じくログインの失敗として数え、ユーザーと ErrInvalidTwoFactorCode を返します
+func (s *TwoFactorService) CompleteLogin(ctx context.Context, pendingToken, code, userAgent, ipAddress string) (*models.User, string, string, error) {
+	user, err := s.authService.ValidateMFAPendingToken(ctx, pendingToken)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	// 失敗が続いている場合は試行を制限
+	accountID := models.AccountThrottleIdentifier(user.ID)
+	if err := s.authService.checkLoginThrottle(ctx, accountID, ipAddress); err != nil {
+		return nil, "", "", err
+	}
+	if err := s.Verify(ctx, user.ID, code); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			return user, "", "", s.authService.loginFailed(ctx, accountID, ipAddress, err)
+		}
+		return nil, "", "", err
+	}
+	if err := s.authService.loginSucceeded(ctx, accountID); err != nil {
+		return nil, "", "", err
+	}
+
+	// 最終ログイン日時を更新
+	user.RecordLogin()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, "", "", fmt.Errorf("failed to update last login: %w", err)
+	}
+
+	accessToken, refreshToken, err := s.authService.IssueTokens(ctx, user, userAgent, ipAddress)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return user, accessToken, refreshToken, nil
+}
+
+// replaceRecoveryCodes は新しいリカバリーコードを発行し、平文のコードを返します
+func (s *TwoFactorService) replaceRecoveryCodes(ctx context.Context, userID int64) ([]string, error) {
+	plaintexts := make([]string, 0, models.RecoveryCodeCount)
+	codes := make([]*models.RecoveryCode, 0, models.RecoveryCodeCount)
+	for i := 0; i < models.RecoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		plaintexts = append(plaintexts, code)
+		codes = append(codes, models.NewRecoveryCode(userID, code))
+	}
+
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(ctx, userID, codes); err != nil {
+		return nil, fmt.Errorf("failed to save recovery codes: %w", err)
+	}
+	return plaintexts, nil
+}
+
+// generateTOTPSecret はパディングなしのbase32でエンコードしたTOTPの共有鍵を生成します
+func generateTOTPSecret() (string, error) {
+	b := make([]byte, totpSecretLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b), nil
+}
+
+// generateRecoveryCode は "xxxxxxxx-xxxxxxxx" 形式のリカバリーコードを生成します
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))
+	return code[:8] + "-" + code[8:], nil
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// conversionTestEnv はIssueとDiscussionの変換のテスト環境
+type conversionTestEnv struct {
+	db                *gorm.DB
+	conversionService *services.ConversionService
+	issueRepo         repositories.IssueRepository
+	discussionRepo    repositories.DiscussionRepository
+	commentRepo       repositories.CommentRepository
+	labelRepo         repositories.LabelRepository
+	reactionRepo      repositories.ReactionRepository
+	revisionRepo      repositories.BodyRevisionRepository
+	redirectRepo      repositories.RedirectRepository
+	user              *models.User
+	repo              *models.Repository
+}
+
+func newConversionTestEnv(t *testing.T) *conversionTestEnv {
+	db := newMigratedTestDB(t)
+	factory := services.NewRepositoryFactory(db)
+
+	issueRepo, _ := factory.NewIssueRepository()
+	discussionRepo, _ := factory.NewDiscussionRepository()
+	commentRepo, _ := factory.NewCommentRepository()
+	labelRepo, _ := factory.NewLabelRepository()
+	reactionRepo, _ := factory.NewReactionRepository()
+	revisionRepo, _ := factory.NewBodyRevisionRepository()
+	redirectRepo, _ := factory.NewRedirectRepository()
+	conversionRepo, _ := factory.NewConversionRepository()
+	repoRepo, _ := factory.NewRepositoryRepository()
+
+	env := &conversionTestEnv{
+		db:                db,
+		conversionService: services.NewConversionService(conversionRepo, labelRepo, redirectRepo),
+		issueRepo:         issueRepo,
+		discussionRepo:    discussionRepo,
+		commentRepo:       commentRepo,
+		labelRepo:         labelRepo,
+		reactionRepo:      reactionRepo,
+		revisionRepo:      revisionRepo,
+		redirectRepo:      redirectRepo,
+		user:              createTestUser(t, db, "converter", false),
+	}
+	env.repo = models.NewRepository("conversion-repo", "", models.PublicRepo, env.user.ID)
+	require.NoError(t, repoRepo.Create(context.Background(), env.repo))
+	return env
+}
+
+// seedDiscussion はコメントと返信・リアクション・編集履歴を持つDiscussionを作成し、コメントと返信を返します
+func (env *conversionTestEnv) seedDiscussion(t *testing.T) (*models.Discussion, *models.Comment, *models.Comment) {
+	ctx := context.Background()
+	for _, label := range []*models.Label{
+		models.NewLabel("needs-info", "", "#ff0000", models.LabelTypeBoth),
+		models.NewLabel("faq", "", "#00ff00", models.LabelTypeDiscussion),
+	} {
+		label.RepositoryID = env.repo.ID
+		require.NoError(t, env.labelRepo.Create(ctx, label))
+	}
+
+	discussion := models.NewDiscussion("Login fails on Safari", "Steps to reproduce", "question", env.user.ID)
+	discussion.RepositoryID = env.repo.ID
+	discussion.Labels = []string{"needs-info", "faq"}
+	require.NoError(t, env.discussionRepo.Create(ctx, discussion))
+
+	comment := models.NewComment("Same here", env.user.ID, discussion.ID, "discussion")
+	require.NoError(t, env.commentRepo.Create(ctx, comment))
+	reply := models.NewReply("Which version?", env.user.ID, discussion.ID, comment.ID, "discussion")
+	require.NoError(t, env.commentRepo.Create(ctx, reply))
+
+	require.NoError(t, env.reactionRepo.Create(ctx, models.NewReaction(models.ReactionTargetDiscussion, discussion.ID, env.user.ID, "+1")))
+	require.NoError(t, env.revisionRepo.Create(ctx, models.NewBodyRevision(models.RevisionTargetDiscussion, discussion.ID, "Steps", env.user.ID, time.Now())))
+	return discussion, comment, reply
+}
+
+// assertContentMoved はコメントのツリー・リアクション・編集履歴が指定したリソースに属していることを確認します
+func (env *conversionTestEnv) assertContentMoved(t *testing.T, targetType string, targetID int64, comment, reply *models.Comment) {
+	ctx := context.Background()
+
+	movedComment, err := env.commentRepo.GetByID(ctx, comment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, targetType, movedComment.Type)
+	assert.Equal(t, targetID, movedComment.TargetID)
+
+	// 返信は親コメントとのつながりを保ったまま移動する
+	movedReply, err := env.commentRepo.GetByID(ctx, reply.ID)
+	require.NoError(t, err)
+	assert.True(t, movedReply.IsReply())
+	assert.Equal(t, comment.ID, movedReply.ParentCommentID)
+	assert.Equal(t, targetID, movedReply.TargetID)
+
+	comments, err := env.commentRepo.ListAllByTarget(ctx, targetID, targetType)
+	require.NoError(t, err)
+	assert.Len(t, comments, 2)
+
+	reactions, err := env.reactionRepo.ListByTarget(ctx, targetType, targetID)
+	require.NoError(t, err)
+	assert.Len(t, reactions, 1)
+	revisions, err := env.revisionRepo.ListByTarget(ctx, targetType, targetID)
+	require.NoError(t, err)
+	assert.Len(t, revisions, 1)
+}
+
+func TestConversion_RoundTrip(t *testing.T) {
+	env := newConversionTestEnv(t)
+	ctx := context.Background()
+	original, comment, reply := env.seedDiscussion(t)
+
+	// Discussion → Issue
+	toIssue, err := env.conversionService.DiscussionToIssue(ctx, original, env.user.ID)
+	require.NoError(t, err)
+	issue := toIssue.Issue
+	assert.Equal(t, original.Number, issue.Number, "番号を引き継ぐ")
+	assert.Equal(t, []string{"needs-info"}, issue.Labels)
+	assert.Equal(t, []string{"faq"}, toIssue.DroppedLabels)
+	env.assertContentMoved(t, models.RedirectTypeIssue, issue.ID, comment, reply)
+
+	_, err = env.discussionRepo.GetByID(ctx, original.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "変換前のDiscussionは削除される")
+	redirect, err := env.conversionService.Resolve(ctx, models.RedirectTypeDiscussion, original.ID)
+	require.NoError(t, err)
+	require.NotNil(t, redirect)
+	assert.Equal(t, models.RedirectTypeIssue, redirect.TargetType)
+	assert.Equal(t, issue.ID, redirect.TargetID)
+
+	// Issue → Discussion
+	stored, err := env.issueRepo.GetByID(ctx, issue.ID)
+	require.NoError(t, err)
+	toDiscussion, err := env.conversionService.IssueToDiscussion(ctx, stored, "question", env.user.ID)
+	require.NoError(t, err)
+	discussion := toDiscussion.Discussion
+	assert.Equal(t, original.Number, discussion.Number)
+	assert.NotEqual(t, original.ID, discussion.ID)
+	env.assertContentMoved(t, models.RedirectTypeDiscussion, discussion.ID, comment, reply)
+
+	_, err = env.issueRepo.GetByID(ctx, issue.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "変換前のIssueは削除される")
+
+	// 以前のリダイレクトも最新のDiscussionを指す
+	for _, source := range []struct {
+		sourceType string
+		sourceID   int64
+	}{
+		{models.RedirectTypeDiscussion, original.ID},
+		{models.RedirectTypeIssue, issue.ID},
+	} {
+		redirect, err := env.conversionService.Resolve(ctx, source.sourceType, source.sourceID)
+		require.NoError(t, err)
+		require.NotNil(t, redirect, source.sourceType)
+		assert.Equal(t, models.RedirectTypeDiscussion, redirect.TargetType)
+		assert.Equal(t, discussion.ID, redirect.TargetID)
+	}
+	redirect, err = env.conversionService.ResolveNumber(ctx, env.repo.ID, models.RedirectTypeIssue, original.Number)
+	require.NoError(t, err)
+	require.NotNil(t, redirect)
+	assert.Equal(t, discussion.ID, redirect.TargetID)
+}
+
+func TestConversion_RollsBackOnFailure(t *testing.T) {
+	env := newConversionTestEnv(t)
+	ctx := context.Background()
+	original, comment, reply := env.seedDiscussion(t)
+
+	// リダイレクトの記録に失敗させる
+	require.NoError(t, env.db.Migrator().DropTable(&models.Redirect{}))
+
+	_, err := env.conversionService.DiscussionToIssue(ctx, original, env.user.ID)
+	require.Error(t, err)
+
+	// 作成したIssueと移動したコメント・リアクション・編集履歴は取り消される
+	issue, err := env.issueRepo.GetByNumber(ctx, env.repo.ID, original.Number)
+	require.NoError(t, err)
+	assert.Nil(t, issue)
+	stored, err := env.discussionRepo.GetByID(ctx, original.ID)
+	require.NoError(t, err)
+	assert.Equal(t, original.Number, stored.Number)
+	env.assertContentMoved(t, models.RedirectTypeDiscussion, original.ID, comment, reply)
+}
+
+func TestConversion_UpdatesSearchIndex(t *testing.T) {
+	search := newSearchTestEnv(t)
+	ctx := context.Background()
+
+	factory := services.NewRepositoryFactory(search.db)
+	conversionRepo, _ := factory.NewConversionRepository()
+	redirectRepo, _ := factory.NewRedirectRepository()
+	conversionService := services.NewConversionService(
+		services.NewIndexingConversionRepository(conversionRepo, search.commentRepo, search.searchService),
+		search.labelRepo, redirectRepo)
+
+	discussion := search.createDiscussion(t, "Flaky websocket reconnect", "Reconnect loops forever", "general")
+	comment := models.NewComment("Seeing the reconnect loop too", search.user.ID, discussion.ID, "discussion")
+	require.NoError(t, search.commentRepo.Create(ctx, comment))
+
+	// Discussion → Issue
+	result, err := conversionService.DiscussionToIssue(ctx, discussion, search.user.ID)
+	require.NoError(t, err)
+	issue := result.Issue
+	assert.Empty(t, search.search(t, "websocket", models.SearchResultTypeDiscussion))
+	assert.Equal(t, []int64{issue.ID}, search.search(t, "websocket", models.SearchResultTypeIssue))
+	assert.Equal(t, []int64{comment.ID}, search.search(t, "loop", models.SearchResultTypeComment), "移動したコメントはIssueへのコメントになる")
+	assert.Empty(t, search.search(t, "loop", models.SearchResultTypeDiscussionComment))
+
+	// Issue → Discussion
+	stored, err := search.issueRepo.GetByID(ctx, issue.ID)
+	require.NoError(t, err)
+	result, err = conversionService.IssueToDiscussion(ctx, stored, "general", search.user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, search.search(t, "websocket", models.SearchResultTypeIssue))
+	assert.Equal(t, []int64{result.Discussion.ID}, search.search(t, "websocket", models.SearchResultTypeDiscussion))
+	assert.Equal(t, []int64{comment.ID}, search.search(t, "loop", models.SearchResultTypeDiscussionComment))
+	assert.Empty(t, search.search(t, "loop", models.SearchResultTypeComment))
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/api"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscussionAnswer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := newMigratedTestDB(t)
+	ctx := context.Background()
+
+	factory := services.NewRepositoryFactory(db)
+	userRepo, _ := factory.NewUserRepository()
+	repoRepo, _ := factory.NewRepositoryRepository()
+	memberRepo, _ := factory.NewRepositoryMemberRepository()
+	discussionRepo, _ := factory.NewDiscussionRepository()
+	commentRepo, _ := factory.NewCommentRepository()
+	reactionRepo, _ := factory.NewReactionRepository()
+	permissionService := services.NewRepositoryPermissionService(repoRepo, memberRepo, userRepo)
+	discussionHandler := api.NewDiscussionHandler(discussionRepo, commentRepo, nil, userRepo, nil, services.NewEventBus(),
+		services.NewReactionService(reactionRepo), nil, nil, permissionService)
+
+	author := createTestUser(t, db, "author", false)
+	answerer := createTestUser(t, db, "answerer", false)
+
+	repo := models.NewRepository("answers", "", models.PublicRepo, author.ID)
+	require.NoError(t, repoRepo.Create(ctx, repo))
+
+	createDiscussion := func(category string) *models.Discussion {
+		discussion := models.NewDiscussion("How do I ...?", "", category, author.ID)
+		discussion.RepositoryID = repo.ID
+		require.NoError(t, discussionRepo.Create(ctx, discussion))
+		return discussion
+	}
+	createComment := func(discussion *models.Discussion) *models.Comment {
+		comment := models.NewComment("Like this.", answerer.ID, discussion.ID, "discussion")
+		require.NoError(t, commentRepo.Create(ctx, comment))
+		return comment
+	}
+
+	question := createDiscussion("question")
+	answer := createComment(question)
+	reply := models.NewReply("Thanks!", author.ID, question.ID, answer.ID, "discussion")
+	require.NoError(t, commentRepo.Create(ctx, reply))
+	other := createDiscussion("question")
+	otherComment := createComment(other)
+	general := createDiscussion("general")
+	generalComment := createComment(general)
+
+	router := gin.New()
+	router.Use(testUserMiddleware)
+	router.GET("/api/v1/discussions/:id", discussionHandler.GetDiscussion)
+	router.PUT("/api/v1/discussions/:id/answer", discussionHandler.MarkAnswer)
+	router.DELETE("/api/v1/discussions/:id/answer", discussionHandler.UnmarkAnswer)
+
+	discussionPath := func(discussion *models.Discussion) string {
+		return "/api/v1/discussions/" + strconv.FormatInt(discussion.ID, 10)
+	}
+
+	t.Run("回答の選択と解除", func(t *testing.T) {
+		status, resp := performTestRequest(t, router, http.MethodPut, discussionPath(question)+"/answer", author, gin.H{"comment_id": answer.ID})
+		require.Equal(t, http.StatusOK, status, resp)
+		assert.Equal(t, "answered", resp["status"])
+		assert.Equal(t, float64(answer.ID), resp["answer_comment_id"])
+		require.IsType(t, map[string]interface{}{}, resp["answered_by"])
+		assert.Equal(t, "answerer", resp["answered_by"].(map[string]interface{})["username"])
+
+		status, resp = performTestRequest(t, router, http.MethodGet, discussionPath(question), nil, nil)
+		require.Equal(t, http.StatusOK, status, resp)
+		assert.Equal(t, float64(answer.ID), resp["answer_comment_id"])
+		assert.NotNil(t, resp["answered_by"])
+
+		status, resp = performTestRequest(t, router, http.MethodDelete, discussionPath(question)+"/answer", author, nil)
+		require.Equal(t, http.StatusOK, status, resp)
+		assert.Equal(t, "open", resp["status"])
+		assert.Nil(t, resp["answer_comment_id"])
+		assert.Nil(t, resp["answered_by"])
+
+		// 回答が選ばれていない場合は解除できない
+		status, _ = performTestRequest(t, router, http.MethodDelete, discussionPath(question)+"/answer", author, nil)
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("回答を選択できない場合", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			discussion *models.Discussion
+			user       *models.User
+			commentID  int64
+			wantStatus int
+		}{
+			{name: "質問カテゴリ以外のDiscussion", discussion: general, user: author, commentID: generalComment.ID, wantStatus: http.StatusBadRequest},
+			{name: "他のDiscussionのコメント", discussion: question, user: author, commentID: otherComment.ID, wantStatus: http.StatusBadRequest},
+			{name: "返信コメント", discussion: question, user: author, commentID: reply.ID, wantStatus: http.StatusBadRequest},
+			{name: "存在しないコメント", discussion: question, user: author, commentID: 9999, wantStatus: http.StatusNotFound},
+			{name: "作成者以外のユーザー", discussion: question, user: answerer, commentID: answer.ID, wantStatus: http.StatusForbidden},
+			{name: "未ログイン", discussion: question, user: nil, commentID: answer.ID, wantStatus: http.StatusUnauthorized},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				status, resp := performTestRequest(t, router, http.MethodPut, discussionPath(tt.discussion)+"/answer", tt.user, gin.H{"comment_id": tt.commentID})
+				assert.Equal(t, tt.wantStatus, status, resp)
+
+				// 回答は選ばれていない
+				stored, err := discussionRepo.GetByID(ctx, tt.discussion.ID)
+				require.NoError(t, err)
+				assert.Zero(t, stored.AnswerCommentID)
+			})
+		}
+	})
+
+	t.Run("回答コメントが削除されている場合", func(t *testing.T) {
+		status, resp := performTestRequest(t, router, http.MethodPut, discussionPath(other)+"/answer", author, gin.H{"comment_id": otherComment.ID})
+		require.Equal(t, http.StatusOK, status, resp)
+
+		// 回答の選択を解除せずにコメントが削除された場合も取得できる
+		require.NoError(t, db.Delete(&models.Comment{}, otherComment.ID).Error)
+
+		status, resp = performTestRequest(t, router, http.MethodGet, discussionPath(other), nil, nil)
+		require.Equal(t, http.StatusOK, status, resp)
+		assert.Equal(t, float64(otherComment.ID), resp["answer_comment_id"])
+		assert.Nil(t, resp["answered_by"])
+	})
+}
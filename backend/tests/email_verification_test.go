@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/api"
+	"github.com/shimauma0312/module-tickethub/backend/migrations"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const emailVerificationTestPassword = "correct-horse-battery"
+
+// verificationLinkPattern は確認メールの本文のリンクに一致する
+var verificationLinkPattern = regexp.MustCompile(`https?://\S+/verify-email\?token=\S+`)
+
+// emailVerificationTestEnv はメールアドレスの確認のテスト環境
+type emailVerificationTestEnv struct {
+	db          *gorm.DB
+	router      *gin.Engine
+	mailSender  *services.MemoryMailSender
+	authService *services.AuthService
+}
+
+// newEmailVerificationTestEnv はテスト環境を作成します（requireEmailVerify でシステム設定のメールアドレスの確認を切り替える）
+func newEmailVerificationTestEnv(t *testing.T, requireEmailVerify bool) *emailVerificationTestEnv {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1) // インメモリのデータベースを接続間で共有するため
+	t.Cleanup(func() { sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.AuthToken{}, &models.PasswordReset{}))
+	require.NoError(t, models.AutoMigrateTwoFactor(db))
+	require.NoError(t, models.AutoMigrateEmailVerification(db))
+	require.NoError(t, models.AutoMigrateSystemSettings(db))
+	require.NoError(t, models.AutoMigrateActivityLog(db))
+
+	factory := services.NewRepositoryFactory(db)
+	userRepo, _ := factory.NewUserRepository()
+	tokenRepo, _ := factory.NewAuthTokenRepository()
+	passwordResetRepo, _ := factory.NewPasswordResetRepository()
+	twoFactorRepo, _ := factory.NewTwoFactorRepository()
+	emailVerificationRepo, _ := factory.NewEmailVerificationRepository()
+	systemSettingsRepo, _ := factory.NewSystemSettingsRepository()
+	activityLogRepo, _ := factory.NewActivityLogRepository()
+
+	systemSettings := models.NewDefaultSystemSettings()
+	systemSettings.SiteURL = "https://tickethub.example.com/"
+	systemSettings.RequireEmailVerify = requireEmailVerify
+	require.NoError(t, systemSettingsRepo.CreateOrUpdate(context.Background(), systemSettings))
+
+	mailSender := services.NewMemoryMailSender()
+	authService := services.NewAuthService(userRepo, tokenRepo, passwordResetRepo, twoFactorRepo, nil, "test-secret")
+	emailVerificationService := services.NewEmailVerificationService(emailVerificationRepo, userRepo, systemSettingsRepo, mailSender)
+	activityLogService := services.NewActivityLogService(activityLogRepo)
+
+	router := gin.New()
+	authHandler := api.NewAuthHandler(authService, activityLogService, emailVerificationService)
+	emailVerificationHandler := api.NewEmailVerificationHandler(emailVerificationService, activityLogService)
+	authGroup := router.Group("/api/auth")
+	authGroup.POST("/register", authHandler.Register)
+	authGroup.POST("/login", authHandler.Login)
+	authGroup.POST("/verify-email", emailVerificationHandler.VerifyEmail)
+	authGroup.POST("/verify-email/resend", api.AuthMiddleware(authService, nil), emailVerificationHandler.ResendVerificationEmail)
+
+	// 書き込みのルートの代わりに、ミドルウェアを通過した場合に201を返すルートを使用する
+	v1 := router.Group("/api/v1", api.AuthMiddleware(authService, nil), api.RequireVerifiedEmailMiddleware(emailVerificationService))
+	v1.GET("/probe", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) })
+	v1.POST("/probe", func(c *gin.Context) { c.JSON(http.StatusCreated, gin.H{}) })
+
+	return &emailVerificationTestEnv{db: db, router: router, mailSender: mailSender, authService: authService}
+}
+
+// request はJSONのリクエストを送信し、ステータスコードとレスポンスとヘッダーを返します
+func (e *emailVerificationTestEnv) request(t *testing.T, method, path, token string, body interface{}) (int, map[string]interface{}, http.Header) {
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&buf).Encode(body))
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+	return w.Code, resp, w.Header()
+}
+
+// register はユーザーを登録し、レスポンスを返します
+func (e *emailVerificationTestEnv) register(t *testing.T, username string) map[string]interface{} {
+	status, resp, _ := e.request(t, http.MethodPost, "/api/auth/register", "", gin.H{
+		"username": username,
+		"email":    username + "@example.com",
+		"password": emailVerificationTestPassword,
+	})
+	require.Equal(t, http.StatusCreated, status, resp)
+	return resp
+}
+
+// accessToken はパスワードでログインし、アクセストークンを返します
+func (e *emailVerificationTestEnv) accessToken(t *testing.T, username string) string {
+	status, resp, _ := e.request(t, http.MethodPost, "/api/auth/login", "", gin.H{
+		"username_or_email": username,
+		"password":          emailVerificationTestPassword,
+	})
+	require.Equal(t, http.StatusOK, status, resp)
+	return resp["token"].(map[string]interface{})["access_token"].(string)
+}
+
+// lastToken は最後に送信した確認メールのリンクから確認トークンを取り出します
+func (e *emailVerificationTestEnv) lastToken(t *testing.T) string {
+	messages := e.mailSender.Messages()
+	require.NotEmpty(t, messages)
+	link := verificationLinkPattern.FindString(messages[len(messages)-1].Body)
+	require.NotEmpty(t, link, messages[len(messages)-1].Body)
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	return u.Query().Get("token")
+}
+
+// backdateVerifications は発行済みの確認トークンの発行日時を d だけ過去にずらします
+func (e *emailVerificationTestEnv) backdateVerifications(t *testing.T, d time.Duration) {
+	var verifications []models.EmailVerification
+	require.NoError(t, e.db.Find(&verifications).Error)
+	for _, v := range verifications {
+		require.NoError(t, e.db.Model(&models.EmailVerification{}).Where("id = ?", v.ID).Update("created_at", v.CreatedAt.Add(-d)).Error)
+	}
+}
+
+func TestEmailVerificationFlow(t *testing.T) {
+	env := newEmailVerificationTestEnv(t, true)
+
+	// 登録時に確認メールを送信する
+	resp := env.register(t, "alice")
+	assert.Equal(t, true, resp["email_verification_required"])
+	assert.Equal(t, false, resp["user"].(map[string]interface{})["email_verified"])
+	messages := env.mailSender.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "alice@example.com", messages[0].To)
+	assert.Contains(t, messages[0].Body, "https://tickethub.example.com/verify-email?token=")
+	token := env.lastToken(t)
+
+	// 未確認のユーザーは読み取りのみできる
+	accessToken := env.accessToken(t, "alice")
+	status, _, _ := env.request(t, http.MethodGet, "/api/v1/probe", accessToken, nil)
+	assert.Equal(t, http.StatusOK, status)
+	status, resp, _ = env.request(t, http.MethodPost, "/api/v1/probe", accessToken, nil)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, true, resp["email_verification_required"])
+
+	status, resp, _ = env.request(t, http.MethodPost, "/api/auth/verify-email", "", gin.H{"token": "invalid"})
+	assert.Equal(t, http.StatusBadRequest, status, resp)
+
+	status, resp, _ = env.request(t, http.MethodPost, "/api/auth/verify-email", "", gin.H{"token": token})
+	require.Equal(t, http.StatusOK, status, resp)
+
+	// 確認済みのトークンは再利用できない
+	status, _, _ = env.request(t, http.MethodPost, "/api/auth/verify-email", "", gin.H{"token": token})
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	// 確認後は同じアクセストークンで書き込みができる
+	status, _, _ = env.request(t, http.MethodPost, "/api/v1/probe", accessToken, nil)
+	assert.Equal(t, http.StatusCreated, status)
+
+	status, _, _ = env.request(t, http.MethodPost, "/api/auth/verify-email/resend", accessToken, nil)
+	assert.Equal(t, http.StatusConflict, status, "確認済みのユーザーへの再送信")
+
+	var actions []string
+	require.NoError(t, env.db.Model(&models.ActivityLog{}).Pluck("action", &actions).Error)
+	assert.Equal(t, []string{string(models.ActionUserEmailVerified)}, actions)
+}
+
+func TestEmailVerificationResendRateLimit(t *testing.T) {
+	env := newEmailVerificationTestEnv(t, true)
+	env.register(t, "alice")
+	firstToken := env.lastToken(t)
+	accessToken := env.accessToken(t, "alice")
+
+	// 登録直後は送信間隔を空ける必要がある
+	status, resp, header := env.request(t, http.MethodPost, "/api/auth/verify-email/resend", accessToken, nil)
+	require.Equal(t, http.StatusTooManyRequests, status, resp)
+	assert.NotEmpty(t, header.Get("Retry-After"))
+
+	// 1時間あたりの上限まで再送信できる
+	for i := 2; i <= 5; i++ {
+		env.backdateVerifications(t, 2*time.Minute)
+		status, resp, _ = env.request(t, http.MethodPost, "/api/auth/verify-email/resend", accessToken, nil)
+		require.Equal(t, http.StatusOK, status, "%d通目: %v", i, resp)
+	}
+	assert.Len(t, env.mailSender.Messages(), 5)
+
+	env.backdateVerifications(t, 2*time.Minute)
+	status, resp, header = env.request(t, http.MethodPost, "/api/auth/verify-email/resend", accessToken, nil)
+	require.Equal(t, http.StatusTooManyRequests, status, resp)
+	assert.Greater(t, resp["retry_after"], float64(60), "1時間あたりの上限")
+	assert.Equal(t, header.Get("Retry-After"), jsonNumber(resp["retry_after"]))
+
+	// 再送信すると以前のトークンは使用できない
+	status, _, _ = env.request(t, http.MethodPost, "/api/auth/verify-email", "", gin.H{"token": firstToken})
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _, _ = env.request(t, http.MethodPost, "/api/auth/verify-email", "", gin.H{"token": env.lastToken(t)})
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestEmailVerificationNotRequired(t *testing.T) {
+	env := newEmailVerificationTestEnv(t, false)
+
+	// 設定が無効な場合は確認メールを送信せず、書き込みも制限しない
+	resp := env.register(t, "alice")
+	assert.Equal(t, false, resp["email_verification_required"])
+	assert.Empty(t, env.mailSender.Messages())
+
+	status, _, _ := env.request(t, http.MethodPost, "/api/v1/probe", env.accessToken(t, "alice"), nil)
+	assert.Equal(t, http.StatusCreated, status)
+}
+
+func TestEmailVerificationAdminExempt(t *testing.T) {
+	env := newEmailVerificationTestEnv(t, true)
+	env.register(t, "admin")
+	require.NoError(t, env.db.Model(&models.User{}).Where("username = ?", "admin").Update("is_admin", true).Error)
+
+	status, _, _ := env.request(t, http.MethodPost, "/api/v1/probe", env.accessToken(t, "admin"), nil)
+	assert.Equal(t, http.StatusCreated, status)
+}
+
+func TestEmailVerificationEmailChanged(t *testing.T) {
+	env := newEmailVerificationTestEnv(t, true)
+	env.register(t, "alice")
+	token := env.lastToken(t)
+
+	// 送信後にメールアドレスを変更した場合は確認できない
+	require.NoError(t, env.db.Model(&models.User{}).Where("username = ?", "alice").Update("email", "alice@other.example.com").Error)
+	status, _, _ := env.request(t, http.MethodPost, "/api/auth/verify-email", "", gin.H{"token": token})
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestMigrateUserEmailVerified(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	// カラムの追加前のusersテーブル
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, db.Exec("CREATE TABLE users (id integer PRIMARY KEY, username text, email text, password text, full_name text, avatar_url text, created_at datetime, updated_at datetime, last_login datetime, is_admin numeric, is_active numeric)").Error)
+	require.NoError(t, db.Exec("INSERT INTO users (id, username, email, created_at) VALUES (1, 'alice', 'alice@example.com', ?)", createdAt).Error)
+
+	require.NoError(t, migrations.MigrateUserEmailVerified(db))
+	require.NoError(t, migrations.MigrateUserEmailVerified(db), "2回目の実行")
+
+	var user models.User
+	require.NoError(t, db.First(&user, 1).Error)
+	require.NotNil(t, user.EmailVerifiedAt, "既存のユーザーは確認済みとする")
+	assert.True(t, user.EmailVerifiedAt.Equal(createdAt))
+}
+
+// jsonNumber はJSONの数値を文字列に変換します
+func jsonNumber(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/api"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const loginThrottleTestPassword = "correct-horse-battery"
+
+// loginThrottleTestEnv はログインの試行の制限のテスト環境
+type loginThrottleTestEnv struct {
+	db               *gorm.DB
+	router           *gin.Engine
+	user             *models.User
+	twoFactorService *services.TwoFactorService
+}
+
+// newLoginThrottleTestEnv はテスト環境を作成します（settings でシステム設定のログインの試行の制限を変更する）
+func newLoginThrottleTestEnv(t *testing.T, settings func(*models.SystemSettings)) *loginThrottleTestEnv {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1) // インメモリのデータベースを接続間で共有するため
+	t.Cleanup(func() { sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.AuthToken{}, &models.PasswordReset{}))
+	require.NoError(t, models.AutoMigrateTwoFactor(db))
+	require.NoError(t, models.AutoMigrateLoginThrottle(db))
+	require.NoError(t, models.AutoMigrateSystemSettings(db))
+	require.NoError(t, models.AutoMigrateActivityLog(db))
+
+	factory := services.NewRepositoryFactory(db)
+	userRepo, _ := factory.NewUserRepository()
+	tokenRepo, _ := factory.NewAuthTokenRepository()
+	passwordResetRepo, _ := factory.NewPasswordResetRepository()
+	twoFactorRepo, _ := factory.NewTwoFactorRepository()
+	loginThrottleRepo, _ := factory.NewLoginThrottleRepository()
+	systemSettingsRepo, _ := factory.NewSystemSettingsRepository()
+	activityLogRepo, _ := factory.NewActivityLogRepository()
+
+	systemSettings := models.NewDefaultSystemSettings()
+	settings(systemSettings)
+	require.NoError(t, systemSettingsRepo.CreateOrUpdate(context.Background(), systemSettings))
+
+	loginThrottleService := services.NewLoginThrottleService(loginThrottleRepo, systemSettingsRepo)
+	authService := services.NewAuthService(userRepo, tokenRepo, passwordResetRepo, twoFactorRepo, loginThrottleService, "test-secret")
+	twoFactorService := services.NewTwoFactorService(twoFactorRepo, userRepo, authService)
+	activityLogService := services.NewActivityLogService(activityLogRepo)
+
+	user, err := authService.Register(context.Background(), "alice", "alice@example.com", loginThrottleTestPassword, "Alice")
+	require.NoError(t, err)
+
+	router := gin.New()
+	authHandler := api.NewAuthHandler(authService, activityLogService, nil)
+	twoFactorHandler := api.NewTwoFactorHandler(twoFactorService, activityLogService)
+	router.POST("/api/auth/login", authHandler.Login)
+	router.POST("/api/auth/login/2fa", twoFactorHandler.CompleteLogin)
+
+	adminHandler := api.NewAdminHandler(userRepo, systemSettingsRepo, activityLogService, nil, nil, twoFactorService, loginThrottleService)
+	router.POST("/api/admin/users/:id/unlock", adminHandler.UnlockUser)
+
+	return &loginThrottleTestEnv{db: db, router: router, user: user, twoFactorService: twoFactorService}
+}
+
+// request は ipAddress から送信したJSONのリクエストのレスポンスを返します
+func (e *loginThrottleTestEnv) request(t *testing.T, path, ipAddress string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	require.NoError(t, json.NewEncoder(&buf).Encode(body))
+	req := httptest.NewRequest(http.MethodPost, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = ipAddress + ":12345"
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+	return w
+}
+
+// login はパスワードでログインし、ステータスコードとレスポンスを返します
+func (e *loginThrottleTestEnv) login(t *testing.T, usernameOrEmail, password, ipAddress string) (int, map[string]interface{}, http.Header) {
+	w := e.request(t, "/api/auth/login", ipAddress, gin.H{
+		"username_or_email": usernameOrEmail,
+		"password":          password,
+	})
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+	return w.Code, resp, w.Header()
+}
+
+// actions はアクティビティログに記録されたアクションを返します
+func (e *loginThrottleTestEnv) actions(t *testing.T) []string {
+	var actions []string
+	require.NoError(t, e.db.Model(&models.ActivityLog{}).Order("id ASC").Pluck("action", &actions).Error)
+	return actions
+}
+
+// withoutBackoff は次の試行までの待ち時間をなくし、ロックのみを確認できるようにします
+func withoutBackoff(maxFailedAttempts int) func(*models.SystemSettings) {
+	return func(settings *models.SystemSettings) {
+		settings.LoginMaxFailedAttempts = maxFailedAttempts
+		settings.LoginBackoffBaseSeconds = 0
+	}
+}
+
+func TestLoginLockoutAndUnlock(t *testing.T) {
+	env := newLoginThrottleTestEnv(t, withoutBackoff(3))
+
+	for i := 1; i <= 3; i++ {
+		status, _, _ := env.login(t, "alice", "wrong-password", "192.0.2.1")
+		assert.Equal(t, http.StatusUnauthorized, status, "%d回目の失敗", i)
+	}
+
+	// ロック中は正しいパスワードでもログインできない
+	status, resp, header := env.login(t, "alice", loginThrottleTestPassword, "192.0.2.2")
+	require.Equal(t, http.StatusTooManyRequests, status, resp)
+	assert.Equal(t, true, resp["locked"])
+	assert.Equal(t, "900", header.Get("Retry-After"))
+
+	assert.Equal(t, []string{
+		string(models.ActionUserLoginFailed),
+		string(models.ActionUserLoginFailed),
+		string(models.ActionUserLoginFailed),
+		string(models.ActionUserLocked),
+	}, env.actions(t))
+
+	// 管理者がロックを解除する
+	w := env.request(t, "/api/admin/users/"+strconv.FormatInt(env.user.ID, 10)+"/unlock", "192.0.2.10", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"message":"User unlocked successfully","was_locked":true}`, w.Body.String())
+
+	status, resp, _ = env.login(t, "alice", loginThrottleTestPassword, "192.0.2.2")
+	require.Equal(t, http.StatusOK, status, resp)
+	assert.Equal(t, string(models.ActionUserUnlocked), env.actions(t)[4])
+
+	w = env.request(t, "/api/admin/users/999/unlock", "192.0.2.10", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestLoginSuccessResetsFailures(t *testing.T) {
+	env := newLoginThrottleTestEnv(t, withoutBackoff(3))
+
+	for i := 0; i < 2; i++ {
+		status, _, _ := env.login(t, "alice", "wrong-password", "192.0.2.1")
+		require.Equal(t, http.StatusUnauthorized, status)
+	}
+	status, _, _ := env.login(t, "alice@example.com", loginThrottleTestPassword, "192.0.2.1")
+	require.Equal(t, http.StatusOK, status, "メールアドレスでも同じアカウントとして数える")
+
+	// 成功で失敗回数が消去されるため、続けて2回失敗してもロックされない
+	for i := 0; i < 2; i++ {
+		status, _, _ := env.login(t, "alice", "wrong-password", "192.0.2.1")
+		require.Equal(t, http.StatusUnauthorized, status)
+	}
+	status, _, _ = env.login(t, "alice", loginThrottleTestPassword, "192.0.2.1")
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestLoginBackoff(t *testing.T) {
+	env := newLoginThrottleTestEnv(t, func(settings *models.SystemSettings) {})
+
+	status, _, _ := env.login(t, "alice", "wrong-password", "192.0.2.1")
+	require.Equal(t, http.StatusUnauthorized, status)
+
+	// 1回目の失敗の直後は1秒待つ必要がある
+	status, resp, header := env.login(t, "alice", loginThrottleTestPassword, "192.0.2.1")
+	require.Equal(t, http.StatusTooManyRequests, status, resp)
+	assert.Equal(t, false, resp["locked"])
+	assert.Equal(t, "1", header.Get("Retry-After"))
+
+	// 待ち時間が経過した後はログインできる
+	require.NoError(t, env.db.Model(&models.LoginThrottle{}).Where("1 = 1").
+		UpdateColumn("last_failed_at", time.Now().Add(-2*time.Second)).Error)
+	status, resp, _ = env.login(t, "alice", loginThrottleTestPassword, "192.0.2.1")
+	require.Equal(t, http.StatusOK, status, resp)
+}
+
+func TestLoginThrottleUnknownAccount(t *testing.T) {
+	env := newLoginThrottleTestEnv(t, withoutBackoff(2))
+
+	// 存在しないアカウントも同じようにロックし、応答からアカウントの有無がわからないようにする
+	for i := 0; i < 2; i++ {
+		status, _, _ := env.login(t, "mallory", "wrong-password", "192.0.2.1")
+		require.Equal(t, http.StatusUnauthorized, status)
+	}
+	status, resp, _ := env.login(t, "Mallory", "wrong-password", "192.0.2.2")
+	assert.Equal(t, http.StatusTooManyRequests, status)
+	assert.Equal(t, true, resp["locked"])
+
+	var username string
+	require.NoError(t, env.db.Model(&models.ActivityLog{}).Where("action = ?", models.ActionUserLocked).Pluck("username", &username).Error)
+	assert.Equal(t, "mallory", username)
+}
+
+func TestLoginThrottlePerIP(t *testing.T) {
+	env := newLoginThrottleTestEnv(t, func(settings *models.SystemSettings) {
+		settings.LoginBackoffBaseSeconds = 0
+		settings.LoginIPMaxFailedAttempts = 3
+	})
+
+	// 同じIPアドレスから複数のアカウントを試す
+	for _, username := range []string{"bob", "carol", "dave"} {
+		status, _, _ := env.login(t, username, "wrong-password", "192.0.2.1")
+		require.Equal(t, http.StatusUnauthorized, status)
+	}
+
+	status, resp, _ := env.login(t, "alice", loginThrottleTestPassword, "192.0.2.1")
+	assert.Equal(t, http.StatusTooManyRequests, status)
+	assert.Equal(t, false, resp["locked"], "IPアドレスの制限はアカウントのロックとして扱わない")
+
+	// 別のIPアドレスからはログインできる
+	status, _, _ = env.login(t, "alice", loginThrottleTestPassword, "192.0.2.2")
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestTwoFactorFailuresCountTowardsLockout(t *testing.T) {
+	env := newLoginThrottleTestEnv(t, withoutBackoff(3))
+	ctx := context.Background()
+
+	secret, _, err := env.twoFactorService.Setup(ctx, env.user.ID, env.user.Username)
+	require.NoError(t, err)
+	code, err := models.GenerateTOTPCode(secret, models.TOTPStep(time.Now()))
+	require.NoError(t, err)
+	_, err = env.twoFactorService.Enable(ctx, env.user.ID, code)
+	require.NoError(t, err)
+
+	status, resp, _ := env.login(t, "alice", loginThrottleTestPassword, "192.0.2.1")
+	require.Equal(t, http.StatusOK, status)
+	pendingToken := resp["mfa_pending_token"].(string)
+
+	for i := 1; i <= 3; i++ {
+		w := env.request(t, "/api/auth/login/2fa", "192.0.2.1", gin.H{"mfa_pending_token": pendingToken, "code": "not-a-recovery-code"})
+		assert.Equal(t, http.StatusUnauthorized, w.Code, "%d回目の失敗", i)
+	}
+
+	// ロック中はパスワードでのログインもできない
+	w := env.request(t, "/api/auth/login/2fa", "192.0.2.1", gin.H{"mfa_pending_token": pendingToken, "code": "000000"})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	status, _, _ = env.login(t, "alice", loginThrottleTestPassword, "192.0.2.1")
+	assert.Equal(t, http.StatusTooManyRequests, status)
+
+	assert.Equal(t, []string{
+		string(models.ActionUserLoginFailed),
+		string(models.ActionUserLoginFailed),
+		string(models.ActionUserLoginFailed),
+		string(models.ActionUserLocked),
+	}, env.actions(t))
+}
+
+func TestLoginThrottleConcurrentFailures(t *testing.T) {
+	env := newLoginThrottleTestEnv(t, withoutBackoff(5))
+	ctx := context.Background()
+	factory := services.NewRepositoryFactory(env.db)
+	throttleRepo, _ := factory.NewLoginThrottleRepository()
+	settingsRepo, _ := factory.NewSystemSettingsRepository()
+	throttleService := services.NewLoginThrottleService(throttleRepo, settingsRepo)
+
+	// 初回の失敗が同時に届いても一意制約の違反にならず、すべての失敗を数える
+	const attempts = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, attempts)
+	locks := make(chan bool, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			locked, err := throttleService.RecordFailure(ctx, "user:42", "192.0.2.1")
+			errs <- err
+			locks <- locked
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	close(locks)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	lockCount := 0
+	for locked := range locks {
+		if locked {
+			lockCount++
+		}
+	}
+	assert.Equal(t, 1, lockCount, "ロックしたことになるのは1回のみ")
+
+	account, err := throttleRepo.Get(ctx, models.LoginThrottleAccount, "user:42")
+	require.NoError(t, err)
+	require.NotNil(t, account)
+	assert.Equal(t, attempts, account.FailedCount)
+	assert.True(t, account.IsLocked(time.Now()))
+
+	ip, err := throttleRepo.Get(ctx, models.LoginThrottleIP, "192.0.2.1")
+	require.NoError(t, err)
+	require.NotNil(t, ip)
+	assert.Equal(t, attempts, ip.FailedCount)
+}
+
+func TestLoginThrottleRepositoryResetsAfterWindow(t *testing.T) {
+	env := newLoginThrottleTestEnv(t, withoutBackoff(5))
+	ctx := context.Background()
+	throttleRepo, _ := services.NewRepositoryFactory(env.db).NewLoginThrottleRepository()
+	now := time.Now()
+
+	throttle, err := throttleRepo.RecordFailure(ctx, models.LoginThrottleAccount, "user:7", now, 15*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, throttle.FailedCount)
+	throttle, err = throttleRepo.RecordFailure(ctx, models.LoginThrottleAccount, "user:7", now.Add(time.Minute), 15*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, throttle.FailedCount)
+
+	// 最後の失敗から一定時間が経過した場合は数え直す
+	throttle, err = throttleRepo.RecordFailure(ctx, models.LoginThrottleAccount, "user:7", now.Add(20*time.Minute), 15*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, throttle.FailedCount)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMentionService_SyncMentions(t *testing.T) {
+	env := newNotificationTestEnv(t)
+	ctx := context.Background()
+
+	factory := services.NewRepositoryFactory(env.db)
+	mentionRepo, _ := factory.NewMentionRepository()
+	userRepo, _ := factory.NewUserRepository()
+	mentionService := services.NewMentionService(mentionRepo, userRepo, env.notificationService)
+
+	actor := createTestUser(t, env.db, "actor", false)
+	bob := createTestUser(t, env.db, "bob", false)
+	carol := createTestUser(t, env.db, "carol", false)
+
+	activeMentions := func(user *models.User) int {
+		_, total, err := mentionRepo.ListByUser(ctx, user.ID, 1, 10)
+		require.NoError(t, err)
+		return total
+	}
+
+	steps := []struct {
+		name              string
+		body              string
+		wantNotifications map[int64]int // 累計の通知件数
+		wantActive        map[*models.User]int
+	}{
+		{
+			name:              "メンションしたユーザーに通知する（自分自身と存在しないユーザーは除く）",
+			body:              "@bob @actor @nobody 見てください",
+			wantNotifications: map[int64]int{bob.ID: 1},
+			wantActive:        map[*models.User]int{bob: 1, actor: 1, carol: 0},
+		},
+		{
+			name:              "同じ本文の再保存では通知しない",
+			body:              "@bob @actor @nobody 見てください（編集）",
+			wantNotifications: map[int64]int{bob.ID: 1},
+			wantActive:        map[*models.User]int{bob: 1, actor: 1, carol: 0},
+		},
+		{
+			name:              "本文から削除したメンションは一覧に含めない",
+			body:              "@carol に変更",
+			wantNotifications: map[int64]int{bob.ID: 1, carol.ID: 1},
+			wantActive:        map[*models.User]int{bob: 0, actor: 0, carol: 1},
+		},
+		{
+			name:              "削除して再度メンションしても再通知しない",
+			body:              "@carol @bob",
+			wantNotifications: map[int64]int{bob.ID: 1, carol.ID: 1},
+			wantActive:        map[*models.User]int{bob: 1, actor: 0, carol: 1},
+		},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			require.NoError(t, mentionService.SyncMentions(ctx, "issue", 1, actor.ID, step.body))
+			assert.Equal(t, step.wantNotifications, env.notificationCounts(t, models.NotificationTypeMention))
+			for user, want := range step.wantActive {
+				assert.Equal(t, want, activeMentions(user), user.Username)
+			}
+		})
+	}
+
+	// 削除したメンションも行は残る
+	mentions, err := mentionRepo.ListBySource(ctx, "issue", 1)
+	require.NoError(t, err)
+	assert.Len(t, mentions, 3)
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/migrations"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// labelSnapshot はラベルと付与の状態を比較するためのスナップショット
+type labelSnapshot struct {
+	Labels           []models.Label
+	IssueLabels      []models.IssueLabel
+	DiscussionLabels []models.DiscussionLabel
+}
+
+// takeLabelSnapshot はラベルと付与の状態を取得します
+func takeLabelSnapshot(t *testing.T, db *gorm.DB) labelSnapshot {
+	var snapshot labelSnapshot
+	require.NoError(t, db.Order("id ASC").Find(&snapshot.Labels).Error)
+	require.NoError(t, db.Order("issue_id ASC, label_id ASC").Find(&snapshot.IssueLabels).Error)
+	require.NoError(t, db.Order("discussion_id ASC, label_id ASC").Find(&snapshot.DiscussionLabels).Error)
+	return snapshot
+}
+
+// findLabel はリポジトリ内のラベルを名前で取得します
+func findLabel(t *testing.T, db *gorm.DB, repositoryID int64, name string) models.Label {
+	var labels []models.Label
+	require.NoError(t, db.Where("repository_id = ? AND name = ?", repositoryID, name).Find(&labels).Error)
+	require.Len(t, labels, 1, "リポジトリ %d のラベル %q", repositoryID, name)
+	return labels[0]
+}
+
+func TestMigrateLabelReferences(t *testing.T) {
+	db := newMigratedTestDB(t)
+	ctx := context.Background()
+	user := createTestUser(t, db, "alice", false)
+	now := time.Now()
+
+	var defaultRepo models.Repository
+	require.NoError(t, db.Where("name = ?", models.DefaultRepositoryName).First(&defaultRepo).Error)
+	otherRepo := models.NewRepository("other", "", models.PublicRepo, user.ID)
+	require.NoError(t, db.WithContext(ctx).Create(otherRepo).Error)
+
+	// デフォルトリポジトリには Discussion 用の bug ラベルのみ存在する
+	discussionBug := models.NewLabel("bug", "", models.DefaultLabelColor, models.LabelTypeDiscussion)
+	discussionBug.RepositoryID = defaultRepo.ID
+	require.NoError(t, db.Create(discussionBug).Error)
+
+	// ラベル名を文字列で保持していた旧スキーマ
+	require.NoError(t, db.Exec("DROP TABLE issue_labels").Error)
+	require.NoError(t, db.Exec("CREATE TABLE issue_labels (issue_id integer, label text)").Error)
+	require.NoError(t, db.Exec("ALTER TABLE discussions ADD COLUMN labels text").Error)
+
+	insertIssue := "INSERT INTO issues (id, repository_id, number, title, body, status, creator_id, created_at, updated_at) VALUES (?, ?, ?, ?, '', 'open', ?, ?, ?)"
+	require.NoError(t, db.Exec(insertIssue, 1, defaultRepo.ID, 1, "Default issue", user.ID, now, now).Error)
+	require.NoError(t, db.Exec(insertIssue, 2, otherRepo.ID, 1, "Other issue", user.ID, now, now).Error)
+	require.NoError(t, db.Exec("INSERT INTO issue_labels (issue_id, label) VALUES (1, 'bug'), (1, 'feature'), (2, 'bug')").Error)
+
+	insertDiscussion := "INSERT INTO discussions (id, repository_id, number, title, body, status, category, creator_id, labels, created_at, updated_at) VALUES (?, ?, ?, ?, '', 'open', 'general', ?, ?, ?, ?)"
+	require.NoError(t, db.Exec(insertDiscussion, 1, defaultRepo.ID, 2, "Default discussion", user.ID, `["question","bug"]`, now, now).Error)
+	require.NoError(t, db.Exec(insertDiscussion, 2, otherRepo.ID, 2, "Other discussion", user.ID, `["bug"]`, now, now).Error)
+	require.NoError(t, db.Exec(insertDiscussion, 3, otherRepo.ID, 3, "No labels", user.ID, `[]`, now, now).Error)
+
+	// 旧テーブルの退避からラベルの移行までをアプリケーションの起動時と同じ順序で実行する
+	require.NoError(t, migrations.GormMigrate(db))
+
+	// ラベルはリポジトリごとに作成され、種類が合わないラベルは both に変更される
+	bug := findLabel(t, db, defaultRepo.ID, "bug")
+	assert.Equal(t, discussionBug.ID, bug.ID, "既存のラベルを使う")
+	assert.Equal(t, models.LabelTypeBoth, bug.Type)
+	feature := findLabel(t, db, defaultRepo.ID, "feature")
+	assert.Equal(t, models.LabelTypeIssue, feature.Type)
+	question := findLabel(t, db, defaultRepo.ID, "question")
+	assert.Equal(t, models.LabelTypeDiscussion, question.Type)
+	otherBug := findLabel(t, db, otherRepo.ID, "bug")
+	assert.NotEqual(t, bug.ID, otherBug.ID, "リポジトリをまたいでラベルを共有しない")
+	assert.Equal(t, models.LabelTypeBoth, otherBug.Type)
+
+	// Issue・DiscussionはラベルIDで参照する
+	snapshot := takeLabelSnapshot(t, db)
+	assert.ElementsMatch(t, []models.IssueLabel{
+		{IssueID: 1, LabelID: bug.ID},
+		{IssueID: 1, LabelID: feature.ID},
+		{IssueID: 2, LabelID: otherBug.ID},
+	}, snapshot.IssueLabels)
+	assert.ElementsMatch(t, []models.DiscussionLabel{
+		{DiscussionID: 1, LabelID: question.ID},
+		{DiscussionID: 1, LabelID: bug.ID},
+		{DiscussionID: 2, LabelID: otherBug.ID},
+	}, snapshot.DiscussionLabels)
+
+	// 旧テーブルは削除され、旧カラムは空になる
+	assert.False(t, db.Migrator().HasTable("issue_labels_legacy"))
+	assert.False(t, db.Migrator().HasColumn("issue_labels", "label"))
+	var legacyLabels int64
+	require.NoError(t, db.Table("discussions").Where("labels IS NOT NULL").Count(&legacyLabels).Error)
+	assert.Zero(t, legacyLabels)
+
+	// 2回目の実行では何も変わらない
+	require.NoError(t, migrations.GormMigrate(db))
+	require.NoError(t, migrations.MigrateLabelReferences(db))
+	assert.Equal(t, snapshot, takeLabelSnapshot(t, db))
+}
+
+// numberedRow はIssue・Discussionのリポジトリと番号
+type numberedRow struct {
+	ID           int64
+	RepositoryID int64
+	Number       int64
+}
+
+// numberedRows は table の行のリポジトリと番号をID順に返します
+func numberedRows(t *testing.T, db *gorm.DB, table string) []numberedRow {
+	var rows []numberedRow
+	require.NoError(t, db.Table(table).Select("id, repository_id, number").Order("id ASC").Scan(&rows).Error)
+	return rows
+}
+
+func TestMigrateRepositoryScope(t *testing.T) {
+	db := newMigratedTestDB(t)
+	user := createTestUser(t, db, "alice", false)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var defaultRepo models.Repository
+	require.NoError(t, db.Where("name = ?", models.DefaultRepositoryName).First(&defaultRepo).Error)
+	otherRepo := models.NewRepository("other", "", models.PublicRepo, user.ID)
+	require.NoError(t, db.Create(otherRepo).Error)
+	require.NoError(t, db.Model(otherRepo).Update("last_number", 10).Error)
+
+	// リポジトリの導入前はリポジトリと番号が未設定のため、番号の一意制約もない
+	require.NoError(t, db.Exec("DROP INDEX idx_issues_repository_number").Error)
+	require.NoError(t, db.Exec("DROP INDEX idx_discussions_repository_number").Error)
+
+	insertIssue := "INSERT INTO issues (id, repository_id, number, title, body, status, creator_id, created_at, updated_at) VALUES (?, ?, ?, 'issue', '', 'open', ?, ?, ?)"
+	insertDiscussion := "INSERT INTO discussions (id, repository_id, number, title, body, status, category, creator_id, created_at, updated_at) VALUES (?, ?, ?, 'discussion', '', 'open', 'general', ?, ?, ?)"
+	at := func(hours int) time.Time { return base.Add(time.Duration(hours) * time.Hour) }
+	require.NoError(t, db.Exec(insertIssue, 1, 0, 0, user.ID, at(3), at(3)).Error)
+	require.NoError(t, db.Exec(insertIssue, 2, defaultRepo.ID, 5, user.ID, at(0), at(0)).Error) // 採番済み
+	require.NoError(t, db.Exec(insertIssue, 3, 0, 0, user.ID, at(1), at(1)).Error)
+	require.NoError(t, db.Exec(insertDiscussion, 1, 0, 0, user.ID, at(2), at(2)).Error)
+	require.NoError(t, db.Exec(insertDiscussion, 2, 0, 0, user.ID, at(1), at(1)).Error) // Issue 3 と同時刻
+	require.NoError(t, db.Exec(insertDiscussion, 3, otherRepo.ID, 0, user.ID, at(0), at(0)).Error)
+	require.NoError(t, db.Exec("INSERT INTO labels (id, repository_id, name, type) VALUES (100, 0, 'bug', 'issue')").Error)
+	require.NoError(t, db.Exec("INSERT INTO milestones (id, repository_id, title, status) VALUES (100, 0, 'v1', 'open')").Error)
+
+	require.NoError(t, migrations.MigrateRepositoryScope(db))
+
+	// リポジトリ未所属のデータはデフォルトリポジトリに割り当て、作成順に採番済みの番号の続きから振る
+	// 同時刻の場合はIssueを先にする
+	issues := []numberedRow{
+		{ID: 1, RepositoryID: defaultRepo.ID, Number: 9},
+		{ID: 2, RepositoryID: defaultRepo.ID, Number: 5},
+		{ID: 3, RepositoryID: defaultRepo.ID, Number: 6},
+	}
+	discussions := []numberedRow{
+		{ID: 1, RepositoryID: defaultRepo.ID, Number: 8},
+		{ID: 2, RepositoryID: defaultRepo.ID, Number: 7},
+		{ID: 3, RepositoryID: otherRepo.ID, Number: 11}, // リポジトリの最終番号の続き
+	}
+	assert.Equal(t, issues, numberedRows(t, db, "issues"))
+	assert.Equal(t, discussions, numberedRows(t, db, "discussions"))
+
+	var label models.Label
+	require.NoError(t, db.First(&label, 100).Error)
+	assert.Equal(t, defaultRepo.ID, label.RepositoryID)
+	var milestone models.Milestone
+	require.NoError(t, db.First(&milestone, 100).Error)
+	assert.Equal(t, defaultRepo.ID, milestone.RepositoryID)
+
+	var repos []models.Repository
+	require.NoError(t, db.Order("id ASC").Find(&repos).Error)
+	lastNumbers := map[int64]int64{}
+	for _, repo := range repos {
+		lastNumbers[repo.ID] = repo.LastNumber
+	}
+	assert.Equal(t, map[int64]int64{defaultRepo.ID: 9, otherRepo.ID: 11}, lastNumbers)
+
+	// 番号の一意制約が作成される
+	assert.True(t, db.Migrator().HasIndex("issues", "idx_issues_repository_number"))
+	assert.True(t, db.Migrator().HasIndex("discussions", "idx_discussions_repository_number"))
+
+	// 2回目の実行では何も変わらない
+	require.NoError(t, migrations.MigrateRepositoryScope(db))
+	assert.Equal(t, issues, numberedRows(t, db, "issues"))
+	assert.Equal(t, discussions, numberedRows(t, db, "discussions"))
+	var again []models.Repository
+	require.NoError(t, db.Order("id ASC").Find(&again).Error)
+	assert.Equal(t, repos, again)
+}
+
+func TestMigrateIssueAssignees(t *testing.T) {
+	db := newMigratedTestDB(t)
+	alice := createTestUser(t, db, "alice", false)
+	bob := createTestUser(t, db, "bob", false)
+	carol := createTestUser(t, db, "carol", false)
+	now := time.Now()
+
+	// 複数担当者の導入前は issues.assignee_id のみに担当者を保持していた
+	insertIssue := "INSERT INTO issues (id, repository_id, number, title, body, status, creator_id, assignee_id, created_at, updated_at) VALUES (?, 1, ?, 'issue', '', 'open', ?, ?, ?, ?)"
+	require.NoError(t, db.Exec(insertIssue, 1, 1, alice.ID, bob.ID, now, now).Error)
+	require.NoError(t, db.Exec(insertIssue, 2, 2, alice.ID, nil, now, now).Error)
+	require.NoError(t, db.Exec(insertIssue, 3, 3, alice.ID, 0, now, now).Error)
+	// 移行済みのIssueは担当者を上書きしない
+	require.NoError(t, db.Exec(insertIssue, 4, 4, alice.ID, bob.ID, now, now).Error)
+	require.NoError(t, db.Create(&[]models.IssueAssignee{
+		{IssueID: 4, UserID: bob.ID, Position: 0},
+		{IssueID: 4, UserID: carol.ID, Position: 1},
+	}).Error)
+
+	require.NoError(t, migrations.MigrateIssueAssignees(db))
+
+	want := []models.IssueAssignee{
+		{IssueID: 1, UserID: bob.ID, Position: 0},
+		{IssueID: 4, UserID: bob.ID, Position: 0},
+		{IssueID: 4, UserID: carol.ID, Position: 1},
+	}
+	var assignees []models.IssueAssignee
+	require.NoError(t, db.Order("issue_id ASC, position ASC").Find(&assignees).Error)
+	assert.Equal(t, want, assignees)
+
+	// 2回目の実行では何も変わらない
+	require.NoError(t, migrations.MigrateIssueAssignees(db))
+	assignees = nil
+	require.NoError(t, db.Order("issue_id ASC, position ASC").Find(&assignees).Error)
+	assert.Equal(t, want, assignees)
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// notificationTestEnv は通知のテスト環境
+type notificationTestEnv struct {
+	db                  *gorm.DB
+	eventBus            *services.EventBus
+	notificationService *services.NotificationService
+	issueRepo           repositories.IssueRepository
+	commentRepo         repositories.CommentRepository
+	repo                *models.Repository
+}
+
+func newNotificationTestEnv(t *testing.T) *notificationTestEnv {
+	db := newMigratedTestDB(t)
+	factory := services.NewRepositoryFactory(db)
+
+	notificationService, err := services.NewNotificationService(factory, "", "", "", 0, "", "", "", "")
+	require.NoError(t, err)
+	issueRepo, _ := factory.NewIssueRepository()
+	discussionRepo, _ := factory.NewDiscussionRepository()
+	commentRepo, _ := factory.NewCommentRepository()
+	repoRepo, _ := factory.NewRepositoryRepository()
+
+	env := &notificationTestEnv{
+		db:                  db,
+		eventBus:            services.NewEventBus(),
+		notificationService: notificationService,
+		issueRepo:           issueRepo,
+		commentRepo:         commentRepo,
+	}
+	services.NewNotificationDispatcher(notificationService, issueRepo, discussionRepo, commentRepo).Register(env.eventBus)
+
+	env.repo = models.NewRepository("notify-repo", "", models.PublicRepo, createTestUser(t, db, "repo-owner", false).ID)
+	require.NoError(t, repoRepo.Create(context.Background(), env.repo))
+	return env
+}
+
+// setNotificationTypes はユーザーが受け取る通知の種類を設定します
+func (env *notificationTestEnv) setNotificationTypes(t *testing.T, user *models.User, types string) {
+	settings := models.NewUserSettings(user.ID)
+	settings.EmailNotification = false
+	settings.PushNotification = false
+	settings.NotificationTypes = types
+	require.NoError(t, env.db.Create(settings).Error)
+}
+
+// notificationCounts はユーザーごとに受け取った通知の件数を返します
+func (env *notificationTestEnv) notificationCounts(t *testing.T, notificationType string) map[int64]int {
+	var notifications []models.Notification
+	require.NoError(t, env.db.Where("type = ?", notificationType).Find(&notifications).Error)
+	counts := map[int64]int{}
+	for _, notification := range notifications {
+		counts[notification.UserID]++
+	}
+	return counts
+}
+
+func (env *notificationTestEnv) createComment(t *testing.T, user *models.User, issue *models.Issue) *models.Comment {
+	comment := models.NewComment("comment", user.ID, issue.ID, "issue")
+	require.NoError(t, env.commentRepo.Create(context.Background(), comment))
+	return comment
+}
+
+func TestNotificationDispatcher_IssueCreated(t *testing.T) {
+	env := newNotificationTestEnv(t)
+	ctx := context.Background()
+
+	actor := createTestUser(t, env.db, "actor", false)
+	assignee := createTestUser(t, env.db, "assignee", false)
+	muted := createTestUser(t, env.db, "muted", false)
+	env.setNotificationTypes(t, muted, "mention,comment")
+
+	issue := models.NewIssue("New issue", "", actor.ID)
+	issue.RepositoryID = env.repo.ID
+	issue.AssigneeIDs = []int64{actor.ID, assignee.ID, muted.ID}
+	require.NoError(t, env.issueRepo.Create(ctx, issue))
+
+	env.eventBus.Publish(ctx, services.NewIssueEvent(services.EventIssueCreated, actor.ID, issue))
+
+	// 自分自身と担当の通知を無効にしているユーザーには通知しない
+	assert.Equal(t, map[int64]int{assignee.ID: 1}, env.notificationCounts(t, models.NotificationTypeAssign))
+}
+
+func TestNotificationDispatcher_IssueAssigned(t *testing.T) {
+	env := newNotificationTestEnv(t)
+	ctx := context.Background()
+
+	author := createTestUser(t, env.db, "author", false)
+	actor := createTestUser(t, env.db, "actor", false)
+	assignee := createTestUser(t, env.db, "assignee", false)
+
+	issue := models.NewIssue("Issue", "", author.ID)
+	issue.RepositoryID = env.repo.ID
+	require.NoError(t, env.issueRepo.Create(ctx, issue))
+
+	// 作成者自身が担当者に追加された場合は担当の通知のみ受け取る
+	env.eventBus.Publish(ctx, services.NewIssueAssignedEvent(actor.ID, issue, []int64{assignee.ID, author.ID, actor.ID}))
+
+	assert.Equal(t, map[int64]int{assignee.ID: 1, author.ID: 1}, env.notificationCounts(t, models.NotificationTypeAssign))
+	assert.Empty(t, env.notificationCounts(t, models.NotificationTypeUpdate))
+}
+
+func TestNotificationDispatcher_IssueStatusChanged(t *testing.T) {
+	env := newNotificationTestEnv(t)
+	ctx := context.Background()
+
+	author := createTestUser(t, env.db, "author", false)
+	assignee := createTestUser(t, env.db, "assignee", false)
+	commenter := createTestUser(t, env.db, "commenter", false)
+	actor := createTestUser(t, env.db, "actor", false)
+	muted := createTestUser(t, env.db, "muted", false)
+	env.setNotificationTypes(t, muted, "assign")
+
+	issue := models.NewIssue("Issue", "", author.ID)
+	issue.RepositoryID = env.repo.ID
+	issue.AssigneeIDs = []int64{assignee.ID, author.ID}
+	require.NoError(t, env.issueRepo.Create(ctx, issue))
+	env.createComment(t, commenter, issue)
+	env.createComment(t, commenter, issue)
+	env.createComment(t, author, issue)
+	env.createComment(t, muted, issue)
+	env.createComment(t, actor, issue)
+
+	issue.Close()
+	env.eventBus.Publish(ctx, services.NewIssueEvent(services.EventIssueStatusChanged, actor.ID, issue))
+
+	// 作成者・担当者・コメント投稿者はそれぞれ1件だけ受け取る
+	assert.Equal(t, map[int64]int{author.ID: 1, assignee.ID: 1, commenter.ID: 1}, env.notificationCounts(t, models.NotificationTypeUpdate))
+}
+
+func TestNotificationDispatcher_CommentCreated(t *testing.T) {
+	env := newNotificationTestEnv(t)
+	ctx := context.Background()
+
+	author := createTestUser(t, env.db, "author", false)
+	assignee := createTestUser(t, env.db, "assignee", false)
+	commenter := createTestUser(t, env.db, "commenter", false)
+	replier := createTestUser(t, env.db, "replier", false)
+	muted := createTestUser(t, env.db, "muted", false)
+	env.setNotificationTypes(t, muted, "mention")
+
+	issue := models.NewIssue("Issue", "", author.ID)
+	issue.RepositoryID = env.repo.ID
+	issue.AssigneeIDs = []int64{assignee.ID, muted.ID}
+	require.NoError(t, env.issueRepo.Create(ctx, issue))
+	parent := env.createComment(t, commenter, issue)
+	env.createComment(t, commenter, issue)
+	env.createComment(t, assignee, issue)
+
+	reply := models.NewReply("reply", replier.ID, issue.ID, parent.ID, "issue")
+	require.NoError(t, env.commentRepo.Create(ctx, reply))
+	env.eventBus.Publish(ctx, services.NewCommentEvent(services.EventCommentCreated, replier.ID, "issue", issue.ID, reply))
+
+	// 返信先のコメント投稿者を含め、関係者はそれぞれ1件だけ受け取る
+	assert.Equal(t, map[int64]int{author.ID: 1, assignee.ID: 1, commenter.ID: 1}, env.notificationCounts(t, models.NotificationTypeComment))
+}
+
+func TestNotificationService_CreateNotification(t *testing.T) {
+	env := newNotificationTestEnv(t)
+	ctx := context.Background()
+
+	actor := createTestUser(t, env.db, "actor", false)
+	defaults := createTestUser(t, env.db, "defaults", false)
+	all := createTestUser(t, env.db, "all", false)
+	selective := createTestUser(t, env.db, "selective", false)
+	env.setNotificationTypes(t, all, "all")
+	env.setNotificationTypes(t, selective, "mention, comment")
+
+	tests := []struct {
+		name             string
+		user             *models.User
+		notificationType string
+		want             int
+	}{
+		{name: "設定がない場合は受け取る", user: defaults, notificationType: models.NotificationTypeUpdate, want: 1},
+		{name: "allの場合は受け取る", user: all, notificationType: models.NotificationTypeAssign, want: 1},
+		{name: "設定に含まれる種類は受け取る", user: selective, notificationType: models.NotificationTypeComment, want: 1},
+		{name: "設定に含まれない種類は受け取らない", user: selective, notificationType: models.NotificationTypeUpdate, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, env.notificationService.CreateNotification(ctx, tt.user.ID, tt.notificationType, "issue", 1, actor.ID, "message"))
+			assert.Equal(t, tt.want, env.notificationCounts(t, tt.notificationType)[tt.user.ID])
+		})
+	}
+}
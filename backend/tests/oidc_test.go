@@ -0,0 +1,473 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/shimauma0312/module-tickethub/backend/api"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	oidcTestClientID    = "tickethub"
+	oidcTestRedirectURL = "http://tickethub.test/api/auth/oidc/mock/callback"
+	oidcTestKeyID       = "mock-key"
+)
+
+// mockOIDCProvider はテスト用のOpenID ConnectのIDプロバイダー
+// 認可エンドポイントは即座に認可コードを発行し、トークンエンドポイントはPKCEを検証してIDトークンを返します
+type mockOIDCProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu        sync.Mutex
+	claims    jwt.MapClaims   // 次のログインでIDトークンに含めるクレーム
+	signKey   *rsa.PrivateKey // nil以外の場合はJWKSにない鍵で署名する
+	audience  string          // 空以外の場合はIDトークンの対象者を上書きする
+	authorize map[string]mockAuthorization
+}
+
+type mockAuthorization struct {
+	nonce         string
+	codeChallenge string
+	claims        jwt.MapClaims
+}
+
+func newMockOIDCProvider(t *testing.T) *mockOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	m := &mockOIDCProvider{key: key, authorize: map[string]mockAuthorization{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 m.server.URL,
+			"authorization_endpoint": m.server.URL + "/authorize",
+			"token_endpoint":         m.server.URL + "/token",
+			"jwks_uri":               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/authorize", m.handleAuthorize)
+	mux.HandleFunc("/token", m.handleToken)
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": oidcTestKeyID,
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+			}},
+		})
+	})
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+// nextLogin は次のログインでIDトークンに含めるクレームを設定します
+func (m *mockOIDCProvider) nextLogin(claims jwt.MapClaims) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.claims = claims
+}
+
+func (m *mockOIDCProvider) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != oidcTestClientID || query.Get("code_challenge_method") != "S256" || query.Get("response_type") != "code" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	m.mu.Lock()
+	code := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(len(m.authorize) + 1)).Bytes())
+	m.authorize[code] = mockAuthorization{
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		claims:        m.claims,
+	}
+	m.mu.Unlock()
+
+	redirect, _ := url.Parse(query.Get("redirect_uri"))
+	values := redirect.Query()
+	values.Set("code", code)
+	values.Set("state", query.Get("state"))
+	redirect.RawQuery = values.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (m *mockOIDCProvider) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	m.mu.Lock()
+	authorization, ok := m.authorize[r.PostForm.Get("code")]
+	delete(m.authorize, r.PostForm.Get("code"))
+	signKey, audience := m.key, oidcTestClientID
+	if m.signKey != nil {
+		signKey = m.signKey
+	}
+	if m.audience != "" {
+		audience = m.audience
+	}
+	m.mu.Unlock()
+
+	// 認可コードとPKCEのcode_verifierの検証
+	if !ok || models.CodeChallenge(r.PostForm.Get("code_verifier")) != authorization.codeChallenge {
+		w.WriteHeader(http.StatusBadRequest)
+		writeJSON(w, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   m.server.URL,
+		"aud":   audience,
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": authorization.nonce,
+	}
+	for name, value := range authorization.claims {
+		claims[name] = value
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = oidcTestKeyID
+	idToken, err := token.SignedString(signKey)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]string{"access_token": "mock-access-token", "token_type": "Bearer", "id_token": idToken})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// oidcTestEnv はシングルサインオンのテスト環境
+type oidcTestEnv struct {
+	provider      *mockOIDCProvider
+	router        *gin.Engine
+	userRepo      repositories.UserRepository
+	twoFactorRepo repositories.TwoFactorRepository
+	authService   *services.AuthService
+	stateCookie   *http.Cookie // 最後に開始したログインの state のCookie
+}
+
+func newOIDCTestEnv(t *testing.T, adminGroups ...string) *oidcTestEnv {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1) // インメモリのデータベースを接続間で共有するため
+	t.Cleanup(func() { sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.AuthToken{}, &models.PasswordReset{}))
+	require.NoError(t, models.AutoMigrateOIDC(db))
+	require.NoError(t, models.AutoMigrateTwoFactor(db))
+
+	factory := services.NewRepositoryFactory(db)
+	userRepo, _ := factory.NewUserRepository()
+	tokenRepo, _ := factory.NewAuthTokenRepository()
+	passwordResetRepo, _ := factory.NewPasswordResetRepository()
+	identityRepo, _ := factory.NewUserIdentityRepository()
+	stateRepo, _ := factory.NewOIDCLoginStateRepository()
+	twoFactorRepo, _ := factory.NewTwoFactorRepository()
+	authService := services.NewAuthService(userRepo, tokenRepo, passwordResetRepo, twoFactorRepo, nil, "test-secret")
+
+	mock := newMockOIDCProvider(t)
+	provider := services.NewOIDCProvider(services.OIDCProviderConfig{
+		Name:        "mock",
+		IssuerURL:   mock.server.URL,
+		ClientID:    oidcTestClientID,
+		RedirectURL: oidcTestRedirectURL,
+		AdminGroups: adminGroups,
+	}, mock.server.Client())
+	oidcService := services.NewOIDCService(stateRepo, identityRepo, userRepo, authService, provider)
+
+	router := gin.New()
+	api.NewOIDCHandler(oidcService).RegisterRoutes(router.Group("/api/auth"))
+	return &oidcTestEnv{provider: mock, router: router, userRepo: userRepo, twoFactorRepo: twoFactorRepo, authService: authService}
+}
+
+// authorize はログインを開始してIDプロバイダーで認可し、コールバックのクエリを返します
+func (e *oidcTestEnv) authorize(t *testing.T, claims jwt.MapClaims) url.Values {
+	e.provider.nextLogin(claims)
+
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/auth/oidc/mock/login", nil))
+	require.Equal(t, http.StatusFound, w.Code)
+	e.stateCookie = findCookie(w.Result().Cookies(), "oidc_state")
+	require.NotNil(t, e.stateCookie, "state のCookie")
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(w.Header().Get("Location"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	return callback.Query()
+}
+
+// callback はログインを開始したブラウザとしてコールバックのエンドポイントを呼び出します
+func (e *oidcTestEnv) callback(query url.Values) *httptest.ResponseRecorder {
+	return e.callbackWithCookie(query, e.stateCookie)
+}
+
+// callbackWithCookie は指定した state のCookieを送信してコールバックのエンドポイントを呼び出します（nilの場合は送信しない）
+func (e *oidcTestEnv) callbackWithCookie(query url.Values, cookie *http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/oidc/mock/callback?"+query.Encode(), nil)
+	if cookie != nil {
+		req.AddCookie(&http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+	return w
+}
+
+// findCookie はレスポンスのCookieから指定した名前のものを返します（ない場合はnil）
+func findCookie(cookies []*http.Cookie, name string) *http.Cookie {
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
+
+// login はログインを完了し、ログインしたユーザーのIDを返します
+func (e *oidcTestEnv) login(t *testing.T, claims jwt.MapClaims) int64 {
+	w := e.callback(e.authorize(t, claims))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var body struct {
+		User struct {
+			ID int64 `json:"id"`
+		} `json:"user"`
+		Token api.TokenResponse `json:"token"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.NotEmpty(t, body.Token.AccessToken)
+	assert.NotEmpty(t, body.Token.RefreshToken)
+	return body.User.ID
+}
+
+func TestOIDCLoginProvisionsUserAndMapsAdminGroup(t *testing.T) {
+	env := newOIDCTestEnv(t, "tickethub-admins")
+	ctx := context.Background()
+
+	userID := env.login(t, jwt.MapClaims{
+		"sub":                "sub-alice",
+		"email":              "alice@example.com",
+		"email_verified":     true,
+		"name":               "Alice",
+		"preferred_username": "alice",
+		"groups":             []string{"dev", "tickethub-admins"},
+	})
+	user, err := env.userRepo.GetByID(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", user.Username)
+	assert.Equal(t, "alice@example.com", user.Email)
+	assert.Equal(t, "Alice", user.FullName)
+	assert.True(t, user.IsAdmin)
+
+	// 2回目のログインは同じユーザーに紐付き、グループから外れると管理者権限が外れる
+	again := env.login(t, jwt.MapClaims{
+		"sub":            "sub-alice",
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"groups":         []string{"dev"},
+	})
+	assert.Equal(t, userID, again)
+	user, err = env.userRepo.GetByID(ctx, userID)
+	require.NoError(t, err)
+	assert.False(t, user.IsAdmin)
+}
+
+func TestOIDCLoginLinksExistingUserByVerifiedEmail(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	ctx := context.Background()
+
+	existing := models.NewUser("carol", "carol@example.com", "hashed", "Carol")
+	existing.SetAdmin(true)
+	existing.MarkEmailVerified()
+	require.NoError(t, env.userRepo.Create(ctx, existing))
+
+	// 検証されていないメールアドレスでは既存のユーザーに紐付けない
+	w := env.callback(env.authorize(t, jwt.MapClaims{
+		"sub":            "sub-carol",
+		"email":          "carol@example.com",
+		"email_verified": false,
+	}))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	userID := env.login(t, jwt.MapClaims{
+		"sub":            "sub-carol",
+		"email":          "carol@example.com",
+		"email_verified": true,
+	})
+	assert.Equal(t, existing.ID, userID)
+
+	// 管理者にするグループが設定されていない場合は管理者権限を変更しない
+	user, err := env.userRepo.GetByID(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, user.IsAdmin)
+}
+
+func TestOIDCLoginDoesNotLinkUnverifiedLocalAccount(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	ctx := context.Background()
+
+	// 第三者が他人のメールアドレスでパスワードのアカウントを先に登録した場合
+	squatter := models.NewUser("mallory", "heidi@example.com", "hashed", "")
+	require.NoError(t, env.userRepo.Create(ctx, squatter))
+
+	claims := jwt.MapClaims{"sub": "sub-heidi", "email": "heidi@example.com", "email_verified": true}
+	w := env.callback(env.authorize(t, claims))
+	assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	assert.Nil(t, findCookie(w.Result().Cookies(), "access_token"), "アクセストークンのCookie")
+
+	// 紐付けも確認済みへの変更も行わない
+	user, err := env.userRepo.GetByID(ctx, squatter.ID)
+	require.NoError(t, err)
+	assert.False(t, user.IsEmailVerified())
+	w = env.callback(env.authorize(t, claims))
+	assert.Equal(t, http.StatusConflict, w.Code, "2回目のログインでも紐付いていない")
+}
+
+func TestOIDCLoginRequiresTwoFactor(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	ctx := context.Background()
+	claims := jwt.MapClaims{"sub": "sub-grace", "email": "grace@example.com", "email_verified": true}
+	userID := env.login(t, claims)
+
+	twoFactor := models.NewUserTwoFactor(userID, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+	twoFactor.Enable()
+	require.NoError(t, env.twoFactorRepo.Save(ctx, twoFactor))
+
+	// 二要素認証が有効なユーザーにはシングルサインオンでもトークンを発行しない
+	w := env.callback(env.authorize(t, claims))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, true, body["mfa_required"])
+	assert.NotContains(t, body, "token")
+	assert.Nil(t, findCookie(w.Result().Cookies(), "access_token"), "アクセストークンのCookie")
+
+	user, err := env.authService.ValidateMFAPendingToken(ctx, body["mfa_pending_token"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, userID, user.ID)
+}
+
+func TestOIDCLoginAvoidsUsernameCollision(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	ctx := context.Background()
+	require.NoError(t, env.userRepo.Create(ctx, models.NewUser("dave", "dave@old.example.com", "hashed", "")))
+
+	userID := env.login(t, jwt.MapClaims{
+		"sub":                "sub-dave",
+		"email":              "dave@example.com",
+		"email_verified":     true,
+		"preferred_username": "dave",
+	})
+	user, err := env.userRepo.GetByID(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "dave2", user.Username)
+}
+
+func TestOIDCLoginRejectsReplayedState(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	query := env.authorize(t, jwt.MapClaims{"sub": "sub-erin", "email": "erin@example.com", "email_verified": true})
+
+	assert.Equal(t, http.StatusOK, env.callback(query).Code)
+	assert.Equal(t, http.StatusBadRequest, env.callback(query).Code)
+
+	unknown := url.Values{"code": {"code"}, "state": {"unknown"}}
+	assert.Equal(t, http.StatusBadRequest, env.callback(unknown).Code)
+}
+
+func TestOIDCLoginRejectsCallbackFromAnotherBrowser(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	query := env.authorize(t, jwt.MapClaims{"sub": "sub-ivan", "email": "ivan@example.com", "email_verified": true})
+	assert.Equal(t, http.SameSiteLaxMode, env.stateCookie.SameSite)
+	assert.True(t, env.stateCookie.HttpOnly)
+
+	// 攻撃者が開始したログインの code と state を別のブラウザで使わせる（ログインCSRF）
+	w := env.callbackWithCookie(query, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, findCookie(w.Result().Cookies(), "access_token"))
+	otherBrowser := &http.Cookie{Name: "oidc_state", Value: "state-of-another-login"}
+	assert.Equal(t, http.StatusBadRequest, env.callbackWithCookie(query, otherBrowser).Code)
+
+	// ログインを開始したブラウザからは完了でき、state のCookieは削除される
+	w = env.callback(query)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotNil(t, findCookie(w.Result().Cookies(), "access_token"))
+	cleared := findCookie(w.Result().Cookies(), "oidc_state")
+	require.NotNil(t, cleared)
+	assert.Negative(t, cleared.MaxAge)
+}
+
+func TestOIDCLoginRejectsInvalidIDToken(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	claims := jwt.MapClaims{"sub": "sub-frank", "email": "frank@example.com", "email_verified": true}
+
+	// JWKSにない鍵での署名
+	rogueKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	env.provider.mu.Lock()
+	env.provider.signKey = rogueKey
+	env.provider.mu.Unlock()
+	w := env.callback(env.authorize(t, claims))
+	assert.Equal(t, http.StatusUnauthorized, w.Code, w.Body.String())
+
+	// 別のクライアント向けのIDトークン
+	env.provider.mu.Lock()
+	env.provider.signKey = nil
+	env.provider.audience = "another-client"
+	env.provider.mu.Unlock()
+	w = env.callback(env.authorize(t, claims))
+	assert.Equal(t, http.StatusUnauthorized, w.Code, w.Body.String())
+}
+
+func TestOIDCLoginErrors(t *testing.T) {
+	env := newOIDCTestEnv(t)
+
+	w := httptest.NewRecorder()
+	env.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/auth/oidc/unknown/login", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	assert.Equal(t, http.StatusUnauthorized, env.callback(url.Values{"error": {"access_denied"}}).Code)
+	assert.Equal(t, http.StatusBadRequest, env.callback(url.Values{"state": {"state"}}).Code)
+
+	w = httptest.NewRecorder()
+	env.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/auth/oidc/providers", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"providers":["mock"]}`, w.Body.String())
+}
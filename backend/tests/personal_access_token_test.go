@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/api"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// patTestEnv はパーソナルアクセストークンで認証するAPIのテスト環境
+type patTestEnv struct {
+	db           *gorm.DB
+	router       *gin.Engine
+	authService  *services.AuthService
+	tokenService *services.PersonalAccessTokenService
+	tokenRepo    repositories.PersonalAccessTokenRepository
+	user         *models.User
+	admin        *models.User
+}
+
+// newPATTestEnv はmain.goと同じミドルウェアの構成でルートを登録したテスト環境を作成します
+func newPATTestEnv(t *testing.T) *patTestEnv {
+	gin.SetMode(gin.TestMode)
+	db := newMigratedTestDB(t)
+	require.NoError(t, models.AutoMigrateTwoFactor(db))
+
+	factory := services.NewRepositoryFactory(db)
+	userRepo, _ := factory.NewUserRepository()
+	authTokenRepo, _ := factory.NewAuthTokenRepository()
+	passwordResetRepo, _ := factory.NewPasswordResetRepository()
+	twoFactorRepo, _ := factory.NewTwoFactorRepository()
+	tokenRepo, _ := factory.NewPersonalAccessTokenRepository()
+	repoRepo, _ := factory.NewRepositoryRepository()
+	memberRepo, _ := factory.NewRepositoryMemberRepository()
+	issueRepo, _ := factory.NewIssueRepository()
+	labelRepo, _ := factory.NewLabelRepository()
+	milestoneRepo, _ := factory.NewMilestoneRepository()
+	commentRepo, _ := factory.NewCommentRepository()
+	eventRepo, _ := factory.NewIssueEventRepository()
+	reactionRepo, _ := factory.NewReactionRepository()
+	issueLinkRepo, _ := factory.NewIssueLinkRepository()
+	conversionRepo, _ := factory.NewConversionRepository()
+	redirectRepo, _ := factory.NewRedirectRepository()
+	systemSettingsRepo, _ := factory.NewSystemSettingsRepository()
+
+	authService := services.NewAuthService(userRepo, authTokenRepo, passwordResetRepo, twoFactorRepo, nil, "test-secret")
+	tokenService := services.NewPersonalAccessTokenService(tokenRepo, userRepo)
+	permissionService := services.NewRepositoryPermissionService(repoRepo, memberRepo, userRepo)
+
+	issueHandler := api.NewIssueHandler(issueRepo, labelRepo, milestoneRepo, userRepo, commentRepo, eventRepo, nil, services.NewEventBus(),
+		services.NewReactionService(reactionRepo), nil, services.NewIssueLinkService(issueLinkRepo, issueRepo),
+		services.NewConversionService(conversionRepo, labelRepo, redirectRepo), permissionService)
+	adminHandler := api.NewAdminHandler(userRepo, systemSettingsRepo, nil, nil, nil, nil, nil)
+
+	router := gin.New()
+	authGroup := router.Group("/api/v1")
+	authGroup.Use(api.AuthMiddleware(authService, tokenService))
+	adminGroup := authGroup.Group("/")
+	adminGroup.Use(api.AdminMiddleware())
+
+	issueTokenScope := api.RequireScope(models.ScopeIssuesRead, models.ScopeIssuesWrite)
+	authGroup.GET("/issues/:id", issueTokenScope, issueHandler.GetIssue)
+	authGroup.POST("/issues", issueTokenScope, api.DefaultRepositoryScopeMiddleware(repoRepo, permissionService), issueHandler.CreateIssue)
+	api.NewPersonalAccessTokenHandler(tokenService).RegisterRoutes(authGroup.Group("/", api.SessionOnlyMiddleware()))
+	adminGroup.GET("/users", adminHandler.GetUsers)
+
+	return &patTestEnv{
+		db:           db,
+		router:       router,
+		authService:  authService,
+		tokenService: tokenService,
+		tokenRepo:    tokenRepo,
+		user:         createTestUser(t, db, "alice", false),
+		admin:        createTestUser(t, db, "root", true),
+	}
+}
+
+// createToken は user のパーソナルアクセストークンを発行し、保存したトークンと平文のトークン文字列を返します
+func (e *patTestEnv) createToken(t *testing.T, user *models.User, name string, scopes ...string) (*models.PersonalAccessToken, string) {
+	token, plaintext, err := e.tokenService.Create(context.Background(), user.ID, name, scopes, time.Time{})
+	require.NoError(t, err)
+	return token, plaintext
+}
+
+// sessionToken は user がログインした場合のJWTのアクセストークンを返します
+func (e *patTestEnv) sessionToken(t *testing.T, user *models.User) string {
+	accessToken, _, err := e.authService.IssueTokens(context.Background(), user, "test", "192.0.2.1")
+	require.NoError(t, err)
+	return accessToken
+}
+
+// request は token で認証して 192.0.2.50 からJSONリクエストを送信し、ステータスコードとレスポンスを返します
+func (e *patTestEnv) request(t *testing.T, method, path, token string, body interface{}) (int, map[string]interface{}) {
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&buf).Encode(body))
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.RemoteAddr = "192.0.2.50:40000"
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+	return w.Code, resp
+}
+
+func TestPersonalAccessToken_Scopes(t *testing.T) {
+	env := newPATTestEnv(t)
+	_, readToken := env.createToken(t, env.user, "read", models.ScopeIssuesRead)
+	_, writeToken := env.createToken(t, env.user, "write", models.ScopeIssuesWrite)
+	newIssue := gin.H{"title": "Created with a token", "body": "body"}
+
+	// issues:read のトークンではIssueを作成できない
+	status, resp := env.request(t, http.MethodPost, "/api/v1/issues", readToken, newIssue)
+	assert.Equal(t, http.StatusForbidden, status, resp)
+	assert.Equal(t, models.ScopeIssuesWrite, resp["required_scope"])
+
+	// issues:write のトークンはIssueの作成と閲覧ができる
+	status, resp = env.request(t, http.MethodPost, "/api/v1/issues", writeToken, newIssue)
+	require.Equal(t, http.StatusCreated, status, resp)
+	issuePath := "/api/v1/issues/" + strconv.FormatInt(int64(resp["id"].(float64)), 10)
+
+	status, resp = env.request(t, http.MethodGet, issuePath, readToken, nil)
+	assert.Equal(t, http.StatusOK, status, resp)
+	status, resp = env.request(t, http.MethodGet, issuePath, writeToken, nil)
+	assert.Equal(t, http.StatusOK, status, resp, "write は read を含む")
+}
+
+func TestPersonalAccessToken_SessionOnlyEndpoints(t *testing.T) {
+	env := newPATTestEnv(t)
+	_, adminToken := env.createToken(t, env.admin, "everything", models.ScopeAdmin)
+
+	// トークンの管理はパーソナルアクセストークンでは利用できない（admin スコープでも同じ）
+	status, resp := env.request(t, http.MethodGet, "/api/v1/users/me/tokens", adminToken, nil)
+	assert.Equal(t, http.StatusForbidden, status, resp)
+	status, resp = env.request(t, http.MethodPost, "/api/v1/users/me/tokens", adminToken, gin.H{"name": "escalated", "scopes": []string{models.ScopeAdmin}})
+	assert.Equal(t, http.StatusForbidden, status, resp)
+
+	// ログインしたセッションでは利用できる
+	status, resp = env.request(t, http.MethodGet, "/api/v1/users/me/tokens", env.sessionToken(t, env.admin), nil)
+	assert.Equal(t, http.StatusOK, status, resp)
+	assert.EqualValues(t, 1, resp["total"])
+}
+
+func TestPersonalAccessToken_AdminEndpoints(t *testing.T) {
+	env := newPATTestEnv(t)
+
+	tests := []struct {
+		name   string
+		user   *models.User
+		scopes []string
+		want   int
+	}{
+		{name: "一般ユーザーのadminスコープのトークン", user: env.user, scopes: []string{models.ScopeAdmin}, want: http.StatusForbidden},
+		{name: "管理者のadminスコープなしのトークン", user: env.admin, scopes: []string{models.ScopeIssuesWrite, models.ScopeUserWrite}, want: http.StatusForbidden},
+		{name: "管理者のadminスコープのトークン", user: env.admin, scopes: []string{models.ScopeAdmin}, want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, token := env.createToken(t, tt.user, tt.name, tt.scopes...)
+			status, resp := env.request(t, http.MethodGet, "/api/v1/users", token, nil)
+			assert.Equal(t, tt.want, status, resp)
+		})
+	}
+}
+
+func TestPersonalAccessToken_InvalidTokens(t *testing.T) {
+	env := newPATTestEnv(t)
+	ctx := context.Background()
+
+	// 期限切れのトークン
+	expired, expiredToken := env.createToken(t, env.user, "expired", models.ScopeIssuesRead)
+	require.NoError(t, env.db.Model(expired).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	status, resp := env.request(t, http.MethodGet, "/api/v1/issues/1", expiredToken, nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, "Token has expired", resp["error"])
+
+	// 削除したトークン
+	deleted, deletedToken := env.createToken(t, env.user, "deleted", models.ScopeIssuesRead)
+	require.NoError(t, env.tokenService.Delete(ctx, env.user.ID, deleted.ID))
+	status, resp = env.request(t, http.MethodGet, "/api/v1/issues/1", deletedToken, nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, "Invalid token", resp["error"])
+
+	// 存在しないトークン
+	status, _ = env.request(t, http.MethodGet, "/api/v1/issues/1", models.PersonalAccessTokenPrefix+"unknown", nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	// 無効化したユーザーのトークン
+	_, disabledToken := env.createToken(t, env.user, "disabled", models.ScopeIssuesRead)
+	require.NoError(t, env.db.Model(env.user).Update("is_active", false).Error)
+	status, resp = env.request(t, http.MethodGet, "/api/v1/issues/1", disabledToken, nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, "User account is disabled", resp["error"])
+}
+
+func TestPersonalAccessToken_RecordsLastUse(t *testing.T) {
+	env := newPATTestEnv(t)
+	ctx := context.Background()
+	token, plaintext := env.createToken(t, env.user, "ci", models.ScopeIssuesRead)
+	assert.Nil(t, token.LastUsedAt)
+
+	before := time.Now().Add(-time.Second)
+	status, _ := env.request(t, http.MethodGet, "/api/v1/issues/99999", plaintext, nil)
+	require.Equal(t, http.StatusNotFound, status)
+
+	used, err := env.tokenRepo.GetByID(ctx, token.ID)
+	require.NoError(t, err)
+	require.NotNil(t, used.LastUsedAt)
+	assert.True(t, used.LastUsedAt.After(before))
+	assert.Equal(t, "192.0.2.50", used.LastUsedIP)
+
+	// 一覧でも最終利用日時とIPアドレスを確認できる
+	status, resp := env.request(t, http.MethodGet, "/api/v1/users/me/tokens", env.sessionToken(t, env.user), nil)
+	require.Equal(t, http.StatusOK, status, resp)
+	listed := resp["tokens"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "192.0.2.50", listed["last_used_ip"])
+	assert.NotNil(t, listed["last_used_at"])
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/api"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// racingReactionRepository は存在確認の直後に同じリアクションが別のリクエストで付けられた状況を再現します
+type racingReactionRepository struct {
+	repositories.ReactionRepository
+	raced bool
+}
+
+func (r *racingReactionRepository) GetByUserAndTarget(ctx context.Context, userID int64, targetType string, targetID int64, emoji string) (*models.Reaction, error) {
+	if !r.raced {
+		r.raced = true
+		if err := r.ReactionRepository.Create(ctx, models.NewReaction(targetType, targetID, userID, emoji)); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+	return r.ReactionRepository.GetByUserAndTarget(ctx, userID, targetType, targetID, emoji)
+}
+
+func TestReactions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := newMigratedTestDB(t)
+	ctx := context.Background()
+
+	factory := services.NewRepositoryFactory(db)
+	userRepo, _ := factory.NewUserRepository()
+	repoRepo, _ := factory.NewRepositoryRepository()
+	memberRepo, _ := factory.NewRepositoryMemberRepository()
+	issueRepo, _ := factory.NewIssueRepository()
+	discussionRepo, _ := factory.NewDiscussionRepository()
+	commentRepo, _ := factory.NewCommentRepository()
+	reactionRepo, _ := factory.NewReactionRepository()
+	revisionRepo, _ := factory.NewBodyRevisionRepository()
+	permissionService := services.NewRepositoryPermissionService(repoRepo, memberRepo, userRepo)
+	reactionService := services.NewReactionService(reactionRepo)
+
+	alice := createTestUser(t, db, "alice", false)
+	bob := createTestUser(t, db, "bob", false)
+
+	repo := models.NewRepository("reactions", "", models.PublicRepo, alice.ID)
+	require.NoError(t, repoRepo.Create(ctx, repo))
+	issue := models.NewIssue("Issue", "", alice.ID)
+	issue.RepositoryID = repo.ID
+	require.NoError(t, issueRepo.Create(ctx, issue))
+	otherIssue := models.NewIssue("Other issue", "", alice.ID)
+	otherIssue.RepositoryID = repo.ID
+	require.NoError(t, issueRepo.Create(ctx, otherIssue))
+
+	router := gin.New()
+	router.Use(testUserMiddleware)
+	api.NewReactionHandler(issueRepo, discussionRepo, commentRepo, reactionService, permissionService).RegisterRoutes(router.Group("/api/v1"))
+	commentHandler := api.NewCommentHandler(commentRepo, issueRepo, discussionRepo, userRepo, services.NewEventBus(),
+		reactionService, services.NewRevisionService(revisionRepo), permissionService)
+	router.DELETE("/api/v1/comments/:id", commentHandler.DeleteComment)
+
+	issuePath := "/api/v1/issues/" + strconv.FormatInt(issue.ID, 10) + "/reactions"
+
+	t.Run("付けて外す", func(t *testing.T) {
+		status, resp := performTestRequest(t, router, http.MethodPost, issuePath, alice, gin.H{"emoji": "rocket"})
+		require.Equal(t, http.StatusOK, status, resp)
+		assert.Equal(t, true, resp["reacted"])
+		assert.Equal(t, []interface{}{map[string]interface{}{"emoji": "rocket", "count": float64(1), "reacted_by_me": true}}, resp["reactions"])
+
+		status, resp = performTestRequest(t, router, http.MethodPost, issuePath, alice, gin.H{"emoji": "rocket"})
+		require.Equal(t, http.StatusOK, status, resp)
+		assert.Equal(t, false, resp["reacted"])
+		assert.Equal(t, []interface{}{}, resp["reactions"])
+	})
+
+	t.Run("使用できない絵文字", func(t *testing.T) {
+		for _, emoji := range []string{"thumbsup", "🚀", "ROCKET"} {
+			status, resp := performTestRequest(t, router, http.MethodPost, issuePath, alice, gin.H{"emoji": emoji})
+			assert.Equal(t, http.StatusBadRequest, status, emoji)
+			assert.NotEmpty(t, resp["allowed_emojis"])
+
+			_, _, err := reactionService.Toggle(ctx, models.ReactionTargetIssue, issue.ID, alice.ID, emoji)
+			assert.ErrorIs(t, err, services.ErrInvalidReactionEmoji)
+		}
+		reactions, err := reactionRepo.ListByTarget(ctx, models.ReactionTargetIssue, issue.ID)
+		require.NoError(t, err)
+		assert.Empty(t, reactions)
+	})
+
+	t.Run("閲覧者ごとの集計", func(t *testing.T) {
+		for _, r := range []*models.Reaction{
+			models.NewReaction(models.ReactionTargetIssue, issue.ID, alice.ID, "+1"),
+			models.NewReaction(models.ReactionTargetIssue, issue.ID, bob.ID, "+1"),
+			models.NewReaction(models.ReactionTargetIssue, issue.ID, bob.ID, "heart"),
+			models.NewReaction(models.ReactionTargetIssue, otherIssue.ID, alice.ID, "eyes"),
+		} {
+			require.NoError(t, reactionRepo.Create(ctx, r))
+		}
+		t.Cleanup(func() {
+			require.NoError(t, reactionRepo.DeleteByTarget(ctx, models.ReactionTargetIssue, issue.ID))
+			require.NoError(t, reactionRepo.DeleteByTarget(ctx, models.ReactionTargetIssue, otherIssue.ID))
+		})
+
+		tests := []struct {
+			name     string
+			viewerID int64
+			want     map[int64][]models.ReactionSummary
+		}{
+			{name: "alice", viewerID: alice.ID, want: map[int64][]models.ReactionSummary{
+				issue.ID:      {{Emoji: "+1", Count: 2, ReactedByMe: true}, {Emoji: "heart", Count: 1}},
+				otherIssue.ID: {{Emoji: "eyes", Count: 1, ReactedByMe: true}},
+			}},
+			{name: "bob", viewerID: bob.ID, want: map[int64][]models.ReactionSummary{
+				issue.ID:      {{Emoji: "+1", Count: 2, ReactedByMe: true}, {Emoji: "heart", Count: 1, ReactedByMe: true}},
+				otherIssue.ID: {{Emoji: "eyes", Count: 1}},
+			}},
+			{name: "未ログイン", viewerID: 0, want: map[int64][]models.ReactionSummary{
+				issue.ID:      {{Emoji: "+1", Count: 2}, {Emoji: "heart", Count: 1}},
+				otherIssue.ID: {{Emoji: "eyes", Count: 1}},
+			}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				summaries, err := reactionRepo.SummarizeByTargets(ctx, models.ReactionTargetIssue, []int64{issue.ID, otherIssue.ID}, tt.viewerID)
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, summaries)
+			})
+		}
+	})
+
+	t.Run("同時に付けられた場合は付けた状態として扱う", func(t *testing.T) {
+		racing := services.NewReactionService(&racingReactionRepository{ReactionRepository: reactionRepo})
+		t.Cleanup(func() {
+			require.NoError(t, reactionRepo.DeleteByTarget(ctx, models.ReactionTargetIssue, issue.ID))
+		})
+
+		added, summaries, err := racing.Toggle(ctx, models.ReactionTargetIssue, issue.ID, bob.ID, "hooray")
+		require.NoError(t, err)
+		assert.True(t, added)
+		assert.Equal(t, []models.ReactionSummary{{Emoji: "hooray", Count: 1, ReactedByMe: true}}, summaries)
+	})
+
+	t.Run("コメントの削除でリアクションも削除される", func(t *testing.T) {
+		comment := models.NewComment("comment", bob.ID, issue.ID, "issue")
+		require.NoError(t, commentRepo.Create(ctx, comment))
+		commentPath := "/api/v1/comments/" + strconv.FormatInt(comment.ID, 10)
+		for _, user := range []*models.User{alice, bob} {
+			status, resp := performTestRequest(t, router, http.MethodPost, commentPath+"/reactions", user, gin.H{"emoji": "laugh"})
+			require.Equal(t, http.StatusOK, status, resp)
+		}
+		status, resp := performTestRequest(t, router, http.MethodPost, issuePath, bob, gin.H{"emoji": "laugh"})
+		require.Equal(t, http.StatusOK, status, resp)
+
+		status, resp = performTestRequest(t, router, http.MethodDelete, commentPath, bob, nil)
+		require.Equal(t, http.StatusOK, status, resp)
+
+		reactions, err := reactionRepo.ListByTarget(ctx, models.ReactionTargetComment, comment.ID)
+		require.NoError(t, err)
+		assert.Empty(t, reactions)
+
+		// 他の対象のリアクションは残る
+		reactions, err = reactionRepo.ListByTarget(ctx, models.ReactionTargetIssue, issue.ID)
+		require.NoError(t, err)
+		assert.Len(t, reactions, 1)
+	})
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/api"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// permissionTestEnv はリポジトリの権限のテスト環境
+type permissionTestEnv struct {
+	db                *gorm.DB
+	factory           *services.RepositoryFactory
+	permissionService *services.RepositoryPermissionService
+	repoRepo          repositories.RepositoryRepository
+
+	// ユーザー
+	owner, sysAdmin, reader, triager, writer, maintainer, stranger *models.User
+	// リポジトリ
+	public, internal, private *models.Repository
+}
+
+func newPermissionTestEnv(t *testing.T) *permissionTestEnv {
+	db := newMigratedTestDB(t)
+	ctx := context.Background()
+
+	factory := services.NewRepositoryFactory(db)
+	userRepo, _ := factory.NewUserRepository()
+	repoRepo, _ := factory.NewRepositoryRepository()
+	memberRepo, _ := factory.NewRepositoryMemberRepository()
+
+	env := &permissionTestEnv{
+		db:                db,
+		factory:           factory,
+		permissionService: services.NewRepositoryPermissionService(repoRepo, memberRepo, userRepo),
+		repoRepo:          repoRepo,
+		owner:             createTestUser(t, db, "owner", false),
+		sysAdmin:          createTestUser(t, db, "sysadmin", true),
+		reader:            createTestUser(t, db, "reader", false),
+		triager:           createTestUser(t, db, "triager", false),
+		writer:            createTestUser(t, db, "writer", false),
+		maintainer:        createTestUser(t, db, "maintainer", false),
+		stranger:          createTestUser(t, db, "stranger", false),
+	}
+
+	env.public = models.NewRepository("public-repo", "", models.PublicRepo, env.owner.ID)
+	env.internal = models.NewRepository("internal-repo", "", models.InternalRepo, env.owner.ID)
+	env.private = models.NewRepository("private-repo", "", models.PrivateRepo, env.owner.ID)
+	for _, repo := range []*models.Repository{env.public, env.internal, env.private} {
+		require.NoError(t, repoRepo.Create(ctx, repo))
+	}
+
+	for user, role := range map[*models.User]models.RepositoryRole{
+		env.reader:     models.RepositoryRoleRead,
+		env.triager:    models.RepositoryRoleTriage,
+		env.writer:     models.RepositoryRoleWrite,
+		env.maintainer: models.RepositoryRoleMaintain,
+	} {
+		require.NoError(t, memberRepo.Save(ctx, models.NewRepositoryMember(env.private.ID, user.ID, role)))
+	}
+	// 公開範囲による閲覧権限より強いロールのみ反映される
+	require.NoError(t, memberRepo.Save(ctx, models.NewRepositoryMember(env.internal.ID, env.writer.ID, models.RepositoryRoleWrite)))
+	return env
+}
+
+func TestRepositoryPermission_RoleFor(t *testing.T) {
+	env := newPermissionTestEnv(t)
+
+	tests := []struct {
+		name   string
+		repo   *models.Repository
+		userID int64
+		want   models.RepositoryRole
+	}{
+		{name: "公開リポジトリの未ログイン", repo: env.public, userID: 0, want: models.RepositoryRoleRead},
+		{name: "公開リポジトリの非メンバー", repo: env.public, userID: env.stranger.ID, want: models.RepositoryRoleRead},
+		{name: "組織内公開リポジトリの未ログイン", repo: env.internal, userID: 0, want: ""},
+		{name: "組織内公開リポジトリのログインユーザー", repo: env.internal, userID: env.stranger.ID, want: models.RepositoryRoleRead},
+		{name: "組織内公開リポジトリのメンバー", repo: env.internal, userID: env.writer.ID, want: models.RepositoryRoleWrite},
+		{name: "非公開リポジトリの未ログイン", repo: env.private, userID: 0, want: ""},
+		{name: "非公開リポジトリの非メンバー", repo: env.private, userID: env.stranger.ID, want: ""},
+		{name: "非公開リポジトリのread", repo: env.private, userID: env.reader.ID, want: models.RepositoryRoleRead},
+		{name: "非公開リポジトリのtriage", repo: env.private, userID: env.triager.ID, want: models.RepositoryRoleTriage},
+		{name: "非公開リポジトリのwrite", repo: env.private, userID: env.writer.ID, want: models.RepositoryRoleWrite},
+		{name: "非公開リポジトリのmaintain", repo: env.private, userID: env.maintainer.ID, want: models.RepositoryRoleMaintain},
+		{name: "非公開リポジトリのオーナー", repo: env.private, userID: env.owner.ID, want: models.RepositoryRoleAdmin},
+		{name: "非公開リポジトリのシステム管理者", repo: env.private, userID: env.sysAdmin.ID, want: models.RepositoryRoleAdmin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, err := env.permissionService.RoleFor(context.Background(), tt.repo, tt.userID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, role)
+		})
+	}
+}
+
+func TestRepositoryPermission_Authorize(t *testing.T) {
+	env := newPermissionTestEnv(t)
+
+	tests := []struct {
+		name         string
+		repositoryID int64
+		userID       int64
+		required     models.RepositoryRole
+		wantErr      error
+	}{
+		{name: "公開リポジトリの閲覧", repositoryID: env.public.ID, userID: 0, required: models.RepositoryRoleRead},
+		{name: "公開リポジトリの非メンバーのtriage", repositoryID: env.public.ID, userID: env.stranger.ID, required: models.RepositoryRoleTriage, wantErr: services.ErrRepositoryPermissionDenied},
+		{name: "組織内公開リポジトリの未ログインは存在を隠す", repositoryID: env.internal.ID, userID: 0, required: models.RepositoryRoleRead, wantErr: services.ErrRepositoryNotFound},
+		{name: "非公開リポジトリの非メンバーは存在を隠す", repositoryID: env.private.ID, userID: env.stranger.ID, required: models.RepositoryRoleRead, wantErr: services.ErrRepositoryNotFound},
+		{name: "非公開リポジトリの非メンバーの書き込みも存在を隠す", repositoryID: env.private.ID, userID: env.stranger.ID, required: models.RepositoryRoleWrite, wantErr: services.ErrRepositoryNotFound},
+		{name: "readのtriage", repositoryID: env.private.ID, userID: env.reader.ID, required: models.RepositoryRoleTriage, wantErr: services.ErrRepositoryPermissionDenied},
+		{name: "triageのtriage", repositoryID: env.private.ID, userID: env.triager.ID, required: models.RepositoryRoleTriage},
+		{name: "triageのwrite", repositoryID: env.private.ID, userID: env.triager.ID, required: models.RepositoryRoleWrite, wantErr: services.ErrRepositoryPermissionDenied},
+		{name: "writeのwrite", repositoryID: env.private.ID, userID: env.writer.ID, required: models.RepositoryRoleWrite},
+		{name: "writeのmaintain", repositoryID: env.private.ID, userID: env.writer.ID, required: models.RepositoryRoleMaintain, wantErr: services.ErrRepositoryPermissionDenied},
+		{name: "maintainのmaintain", repositoryID: env.private.ID, userID: env.maintainer.ID, required: models.RepositoryRoleMaintain},
+		{name: "maintainのadmin", repositoryID: env.private.ID, userID: env.maintainer.ID, required: models.RepositoryRoleAdmin, wantErr: services.ErrRepositoryPermissionDenied},
+		{name: "オーナーのadmin", repositoryID: env.private.ID, userID: env.owner.ID, required: models.RepositoryRoleAdmin},
+		{name: "システム管理者のadmin", repositoryID: env.private.ID, userID: env.sysAdmin.ID, required: models.RepositoryRoleAdmin},
+		{name: "存在しないリポジトリ", repositoryID: 9999, userID: env.sysAdmin.ID, required: models.RepositoryRoleRead, wantErr: services.ErrRepositoryNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := env.permissionService.Authorize(context.Background(), tt.repositoryID, tt.userID, tt.required)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, repo)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.repositoryID, repo.ID)
+		})
+	}
+}
+
+func TestRepositoryPermission_VisibleRepositoryIDs(t *testing.T) {
+	env := newPermissionTestEnv(t)
+
+	tests := []struct {
+		name        string
+		userID      int64
+		wantVisible []int64
+		wantHidden  []int64
+	}{
+		{name: "未ログイン", userID: 0, wantVisible: []int64{env.public.ID}, wantHidden: []int64{env.internal.ID, env.private.ID}},
+		{name: "非メンバー", userID: env.stranger.ID, wantVisible: []int64{env.public.ID, env.internal.ID}, wantHidden: []int64{env.private.ID}},
+		{name: "メンバー", userID: env.reader.ID, wantVisible: []int64{env.public.ID, env.internal.ID, env.private.ID}},
+		{name: "オーナー", userID: env.owner.ID, wantVisible: []int64{env.public.ID, env.internal.ID, env.private.ID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, err := env.permissionService.VisibleRepositoryIDs(context.Background(), tt.userID)
+			require.NoError(t, err)
+			require.NotNil(t, ids, "空の場合も絞り込みに使うため nil を返さない")
+			for _, id := range tt.wantVisible {
+				assert.Contains(t, ids, id)
+			}
+			for _, id := range tt.wantHidden {
+				assert.NotContains(t, ids, id)
+			}
+		})
+	}
+
+	// システム管理者は絞り込まない
+	ids, err := env.permissionService.VisibleRepositoryIDs(context.Background(), env.sysAdmin.ID)
+	require.NoError(t, err)
+	assert.Nil(t, ids)
+}
+
+func TestRepositoryPermissionHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	env := newPermissionTestEnv(t)
+	ctx := context.Background()
+
+	issueRepo, _ := env.factory.NewIssueRepository()
+	reactionRepo, _ := env.factory.NewReactionRepository()
+	issueLinkRepo, _ := env.factory.NewIssueLinkRepository()
+	issueHandler := api.NewIssueHandler(issueRepo, nil, nil, nil, nil, nil, nil, services.NewEventBus(),
+		services.NewReactionService(reactionRepo), nil, services.NewIssueLinkService(issueLinkRepo, issueRepo), nil, env.permissionService)
+
+	privateIssue := models.NewIssue("Private issue", "", env.owner.ID)
+	privateIssue.RepositoryID = env.private.ID
+	require.NoError(t, issueRepo.Create(ctx, privateIssue))
+	publicIssue := models.NewIssue("Public issue", "", env.owner.ID)
+	publicIssue.RepositoryID = env.public.ID
+	require.NoError(t, issueRepo.Create(ctx, publicIssue))
+
+	router := gin.New()
+	router.Use(testUserMiddleware)
+	router.GET("/api/v1/issues", issueHandler.ListIssues)
+	router.GET("/api/v1/repos/:name/issues", api.RepositoryScopeMiddleware(env.repoRepo, env.permissionService), issueHandler.ListIssues)
+	router.PATCH("/api/v1/issues/:id/status", issueHandler.UpdateIssueStatus)
+
+	request := func(method, path string, user *models.User, body interface{}) (int, map[string]interface{}) {
+		return performTestRequest(t, router, method, path, user, body)
+	}
+	statusPath := "/api/v1/issues/" + strconv.FormatInt(privateIssue.ID, 10) + "/status"
+
+	// 非メンバーには非公開リポジトリの存在を隠す
+	status, _ := request(http.MethodGet, "/api/v1/repos/private-repo/issues", env.stranger, nil)
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = request(http.MethodGet, "/api/v1/repos/private-repo/issues", nil, nil)
+	assert.Equal(t, http.StatusNotFound, status, "未ログイン")
+	status, _ = request(http.MethodPatch, statusPath, env.stranger, gin.H{"status": "closed"})
+	assert.Equal(t, http.StatusNotFound, status)
+
+	// リポジトリを指定しない一覧は閲覧できるリポジトリに限定する
+	status, resp := request(http.MethodGet, "/api/v1/issues", env.stranger, nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, float64(1), resp["total"])
+	status, resp = request(http.MethodGet, "/api/v1/issues", env.reader, nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, float64(2), resp["total"])
+
+	// readのメンバーは閲覧できるが、他人のIssueのステータスは変更できない
+	status, resp = request(http.MethodGet, "/api/v1/repos/private-repo/issues", env.reader, nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, float64(1), resp["total"])
+	status, _ = request(http.MethodPatch, statusPath, env.reader, gin.H{"status": "closed"})
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, resp = request(http.MethodPatch, statusPath, env.triager, gin.H{"status": "closed"})
+	require.Equal(t, http.StatusOK, status, resp)
+	assert.Equal(t, "closed", resp["status"])
+}
+
+func TestRepositoryPermissionHandlers_MissingAndHiddenIDs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	env := newPermissionTestEnv(t)
+	ctx := context.Background()
+
+	issueRepo, _ := env.factory.NewIssueRepository()
+	discussionRepo, _ := env.factory.NewDiscussionRepository()
+	commentRepo, _ := env.factory.NewCommentRepository()
+	labelRepo, _ := env.factory.NewLabelRepository()
+	milestoneRepo, _ := env.factory.NewMilestoneRepository()
+	reactionRepo, _ := env.factory.NewReactionRepository()
+	revisionRepo, _ := env.factory.NewBodyRevisionRepository()
+	redirectRepo, _ := env.factory.NewRedirectRepository()
+	issueLinkRepo, _ := env.factory.NewIssueLinkRepository()
+	eventRepo, _ := env.factory.NewIssueEventRepository()
+	userRepo, _ := env.factory.NewUserRepository()
+
+	eventBus := services.NewEventBus()
+	reactionService := services.NewReactionService(reactionRepo)
+	revisionService := services.NewRevisionService(revisionRepo)
+	linkService := services.NewIssueLinkService(issueLinkRepo, issueRepo)
+	conversionRepo, _ := env.factory.NewConversionRepository()
+	conversionService := services.NewConversionService(conversionRepo, labelRepo, redirectRepo)
+
+	issueHandler := api.NewIssueHandler(issueRepo, labelRepo, milestoneRepo, userRepo, commentRepo, eventRepo, nil, eventBus,
+		reactionService, revisionService, linkService, conversionService, env.permissionService)
+	discussionHandler := api.NewDiscussionHandler(discussionRepo, commentRepo, labelRepo, userRepo, nil, eventBus,
+		reactionService, revisionService, conversionService, env.permissionService)
+	commentHandler := api.NewCommentHandler(commentRepo, issueRepo, discussionRepo, userRepo, eventBus, reactionService, revisionService, env.permissionService)
+	milestoneHandler := api.NewMilestoneHandler(milestoneRepo, issueRepo, eventRepo, env.permissionService)
+	labelHandler := api.NewLabelHandler(labelRepo, env.permissionService)
+	reactionHandler := api.NewReactionHandler(issueRepo, discussionRepo, commentRepo, reactionService, env.permissionService)
+
+	// 非公開リポジトリの各リソース
+	issue := models.NewIssue("Private issue", "", env.owner.ID)
+	issue.RepositoryID = env.private.ID
+	require.NoError(t, issueRepo.Create(ctx, issue))
+	discussion := models.NewDiscussion("Private discussion", "", "general", env.owner.ID)
+	discussion.RepositoryID = env.private.ID
+	require.NoError(t, discussionRepo.Create(ctx, discussion))
+	comment := models.NewComment("Private comment", env.owner.ID, issue.ID, "issue")
+	require.NoError(t, commentRepo.Create(ctx, comment))
+	milestone := models.NewMilestone("Private milestone", "", time.Time{}, env.owner.ID)
+	milestone.RepositoryID = env.private.ID
+	require.NoError(t, milestoneRepo.Create(ctx, milestone))
+	label := models.NewLabel("private-label", "", "#ff0000", models.LabelTypeBoth)
+	label.RepositoryID = env.private.ID
+	require.NoError(t, labelRepo.Create(ctx, label))
+
+	router := gin.New()
+	router.Use(testUserMiddleware)
+	router.GET("/api/v1/issues/:id", issueHandler.GetIssue)
+	router.GET("/api/v1/issues/:id/timeline", issueHandler.GetIssueTimeline)
+	router.PUT("/api/v1/issues/:id", issueHandler.UpdateIssue)
+	router.PATCH("/api/v1/issues/:id/status", issueHandler.UpdateIssueStatus)
+	router.GET("/api/v1/discussions/:id", discussionHandler.GetDiscussion)
+	router.PATCH("/api/v1/discussions/:id/status", discussionHandler.UpdateDiscussionStatus)
+	router.GET("/api/v1/comments/:id", commentHandler.GetComment)
+	router.GET("/api/v1/milestones/:id", milestoneHandler.GetMilestone)
+	router.GET("/api/v1/milestones/:id/burndown", milestoneHandler.GetBurndown)
+	router.GET("/api/v1/labels/:id", labelHandler.GetLabel)
+	reactionHandler.RegisterRoutes(router.Group("/api/v1"))
+
+	const missingID = 99999
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		hiddenID int64
+		body     interface{}
+	}{
+		{name: "Issueの取得", method: http.MethodGet, path: "/api/v1/issues/%d", hiddenID: issue.ID},
+		{name: "Issueのタイムライン", method: http.MethodGet, path: "/api/v1/issues/%d/timeline", hiddenID: issue.ID},
+		{name: "Issueの更新", method: http.MethodPut, path: "/api/v1/issues/%d", hiddenID: issue.ID, body: gin.H{"title": "x"}},
+		{name: "Issueのステータス変更", method: http.MethodPatch, path: "/api/v1/issues/%d/status", hiddenID: issue.ID, body: gin.H{"status": "closed"}},
+		{name: "Discussionの取得", method: http.MethodGet, path: "/api/v1/discussions/%d", hiddenID: discussion.ID},
+		{name: "Discussionのステータス変更", method: http.MethodPatch, path: "/api/v1/discussions/%d/status", hiddenID: discussion.ID, body: gin.H{"status": "closed"}},
+		{name: "コメントの取得", method: http.MethodGet, path: "/api/v1/comments/%d", hiddenID: comment.ID},
+		{name: "マイルストーンの取得", method: http.MethodGet, path: "/api/v1/milestones/%d", hiddenID: milestone.ID},
+		{name: "バーンダウン", method: http.MethodGet, path: "/api/v1/milestones/%d/burndown", hiddenID: milestone.ID},
+		{name: "ラベルの取得", method: http.MethodGet, path: "/api/v1/labels/%d", hiddenID: label.ID},
+		{name: "Issueへのリアクション", method: http.MethodPost, path: "/api/v1/issues/%d/reactions", hiddenID: issue.ID, body: gin.H{"emoji": "+1"}},
+		{name: "Discussionへのリアクション", method: http.MethodPost, path: "/api/v1/discussions/%d/reactions", hiddenID: discussion.ID, body: gin.H{"emoji": "+1"}},
+		{name: "コメントへのリアクション", method: http.MethodPost, path: "/api/v1/comments/%d/reactions", hiddenID: comment.ID, body: gin.H{"emoji": "+1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 存在しないIDと閲覧できないIDを区別できない
+			hiddenStatus, hiddenResp := performTestRequest(t, router, tt.method, fmt.Sprintf(tt.path, tt.hiddenID), env.stranger, tt.body)
+			missingStatus, missingResp := performTestRequest(t, router, tt.method, fmt.Sprintf(tt.path, missingID), env.stranger, tt.body)
+			assert.Equal(t, http.StatusNotFound, hiddenStatus, hiddenResp)
+			assert.Equal(t, hiddenStatus, missingStatus)
+			assert.Equal(t, hiddenResp, missingResp)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/api"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSavedSearchRouter は保存済み検索のルートを登録したルーターを作成します
+func newSavedSearchRouter(t *testing.T, env *searchTestEnv) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	factory := services.NewRepositoryFactory(env.db)
+	savedSearchRepo, _ := factory.NewSavedSearchRepository()
+	userRepo, _ := factory.NewUserRepository()
+	repoRepo, _ := factory.NewRepositoryRepository()
+	memberRepo, _ := factory.NewRepositoryMemberRepository()
+	permissionService := services.NewRepositoryPermissionService(repoRepo, memberRepo, userRepo)
+
+	handler := api.NewSavedSearchHandler(savedSearchRepo, services.NewSavedSearchService(savedSearchRepo, env.searchService, permissionService))
+	router := gin.New()
+	router.Use(testUserMiddleware)
+	handler.RegisterRoutes(router.Group("/api/v1"))
+	return router
+}
+
+func TestSavedSearchVisibility(t *testing.T) {
+	env := newSearchTestEnv(t)
+	router := newSavedSearchRouter(t, env)
+	owner := env.user
+	other := createTestUser(t, env.db, "other", false)
+
+	create := func(name string, isShared bool) string {
+		status, resp := performTestRequest(t, router, http.MethodPost, "/api/v1/saved-searches", owner,
+			gin.H{"name": name, "query": "is:open", "is_shared": isShared})
+		require.Equal(t, http.StatusCreated, status, resp)
+		return "/api/v1/saved-searches/" + strconv.FormatInt(int64(resp["id"].(float64)), 10)
+	}
+	privatePath := create("Private", false)
+	sharedPath := create("Shared", true)
+
+	// 一覧には自分の保存済み検索と共有された保存済み検索のみ含まれる
+	status, resp := performTestRequest(t, router, http.MethodGet, "/api/v1/saved-searches", owner, nil)
+	require.Equal(t, http.StatusOK, status, resp)
+	assert.Equal(t, float64(2), resp["total"])
+	status, resp = performTestRequest(t, router, http.MethodGet, "/api/v1/saved-searches", other, nil)
+	require.Equal(t, http.StatusOK, status, resp)
+	require.Equal(t, float64(1), resp["total"])
+	assert.Equal(t, "Shared", resp["saved_searches"].([]interface{})[0].(map[string]interface{})["name"])
+
+	update := gin.H{"name": "Renamed", "query": "is:closed", "is_shared": true}
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		user       *models.User
+		body       interface{}
+		wantStatus int
+	}{
+		{name: "他人の非公開の保存済み検索は取得できない", method: http.MethodGet, path: privatePath, user: other, wantStatus: http.StatusNotFound},
+		{name: "他人の非公開の保存済み検索は実行できない", method: http.MethodGet, path: privatePath + "/results", user: other, wantStatus: http.StatusNotFound},
+		{name: "他人の非公開の保存済み検索は更新できない", method: http.MethodPut, path: privatePath, user: other, body: update, wantStatus: http.StatusNotFound},
+		{name: "他人の非公開の保存済み検索は削除できない", method: http.MethodDelete, path: privatePath, user: other, wantStatus: http.StatusNotFound},
+		{name: "共有された保存済み検索は取得できる", method: http.MethodGet, path: sharedPath, user: other, wantStatus: http.StatusOK},
+		{name: "共有された保存済み検索は実行できる", method: http.MethodGet, path: sharedPath + "/results", user: other, wantStatus: http.StatusOK},
+		{name: "共有された保存済み検索は作成者以外は更新できない", method: http.MethodPut, path: sharedPath, user: other, body: update, wantStatus: http.StatusForbidden},
+		{name: "共有された保存済み検索は作成者以外は削除できない", method: http.MethodDelete, path: sharedPath, user: other, wantStatus: http.StatusForbidden},
+		{name: "作成者は更新できる", method: http.MethodPut, path: sharedPath, user: owner, body: update, wantStatus: http.StatusOK},
+		{name: "作成者は削除できる", method: http.MethodDelete, path: privatePath, user: owner, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := performTestRequest(t, router, tt.method, tt.path, tt.user, tt.body)
+			assert.Equal(t, tt.wantStatus, status, resp)
+		})
+	}
+
+	// 更新・削除の結果
+	status, resp = performTestRequest(t, router, http.MethodGet, sharedPath, other, nil)
+	require.Equal(t, http.StatusOK, status, resp)
+	assert.Equal(t, "Renamed", resp["name"])
+	status, _ = performTestRequest(t, router, http.MethodGet, privatePath, owner, nil)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestSavedSearchNewCount(t *testing.T) {
+	env := newSearchTestEnv(t)
+	router := newSavedSearchRouter(t, env)
+	owner := env.user
+	other := createTestUser(t, env.db, "other", false)
+
+	env.createIssue(t, "Deploy fails", "")
+	env.createIssue(t, "Deploy is slow", "")
+	env.createIssue(t, "Unrelated", "")
+
+	status, resp := performTestRequest(t, router, http.MethodPost, "/api/v1/saved-searches", owner,
+		gin.H{"name": "Deploys", "query": "deploy", "is_shared": true})
+	require.Equal(t, http.StatusCreated, status, resp)
+	path := "/api/v1/saved-searches/" + strconv.FormatInt(int64(resp["id"].(float64)), 10)
+
+	newCount := func(user *models.User) float64 {
+		status, resp := performTestRequest(t, router, http.MethodGet, path, user, nil)
+		require.Equal(t, http.StatusOK, status, resp)
+		return resp["new_count"].(float64)
+	}
+
+	// 未閲覧の場合は全件数
+	assert.Equal(t, float64(2), newCount(owner))
+
+	// 閲覧すると新着件数はリセットされる
+	status, resp = performTestRequest(t, router, http.MethodGet, path+"/results", owner, nil)
+	require.Equal(t, http.StatusOK, status, resp)
+	assert.Equal(t, float64(2), resp["total_count"])
+	assert.Equal(t, float64(0), newCount(owner))
+
+	// 閲覧後に作成・更新されたもののみ数える
+	issue := env.createIssue(t, "Deploy docs are outdated", "")
+	assert.Equal(t, float64(1), newCount(owner))
+	issue.Body = "Still outdated"
+	require.NoError(t, env.issueRepo.Update(context.Background(), issue))
+	assert.Equal(t, float64(1), newCount(owner))
+
+	// 最終閲覧日時はユーザーごとに管理される
+	assert.Equal(t, float64(3), newCount(other))
+	status, _ = performTestRequest(t, router, http.MethodGet, path+"/results", other, nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, float64(0), newCount(other))
+	assert.Equal(t, float64(1), newCount(owner))
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchIndexing_Issue(t *testing.T) {
+	env := newSearchTestEnv(t)
+	ctx := context.Background()
+
+	// 作成
+	issue := env.createIssue(t, "Flaky scheduler", "The cron scheduler skips jobs")
+	assert.Equal(t, []int64{issue.ID}, env.search(t, "scheduler", models.SearchResultTypeIssue))
+
+	// 更新
+	issue.Title = "Flaky dispatcher"
+	issue.Body = "The dispatcher skips jobs"
+	require.NoError(t, env.issueRepo.Update(ctx, issue))
+	assert.Empty(t, env.search(t, "scheduler", models.SearchResultTypeIssue))
+	assert.Equal(t, []int64{issue.ID}, env.search(t, "dispatcher", models.SearchResultTypeIssue))
+
+	// 削除
+	require.NoError(t, env.issueRepo.Delete(ctx, issue.ID))
+	assert.Empty(t, env.search(t, "dispatcher", models.SearchResultTypeIssue))
+}
+
+func TestSearchIndexing_Comment(t *testing.T) {
+	env := newSearchTestEnv(t)
+	ctx := context.Background()
+
+	issue := env.createIssue(t, "Login fails", "")
+	discussion := env.createDiscussion(t, "Ideas", "", "idea")
+
+	// 作成
+	comment := models.NewComment("Reproduced on firefox", env.user.ID, issue.ID, "issue")
+	require.NoError(t, env.commentRepo.Create(ctx, comment))
+	discussionComment := models.NewComment("Support firefox profiles", env.user.ID, discussion.ID, "discussion")
+	require.NoError(t, env.commentRepo.Create(ctx, discussionComment))
+	assert.Equal(t, []int64{comment.ID}, env.search(t, "firefox", models.SearchResultTypeComment))
+	assert.Equal(t, []int64{discussionComment.ID}, env.search(t, "firefox", models.SearchResultTypeDiscussionComment))
+
+	// 返信は親コメントのターゲットの種類で登録される
+	reply := models.NewReply("Also on safari", env.user.ID, issue.ID, comment.ID, "issue")
+	require.NoError(t, env.commentRepo.Create(ctx, reply))
+	assert.Equal(t, []int64{reply.ID}, env.search(t, "safari", models.SearchResultTypeComment))
+	assert.Empty(t, env.search(t, "safari", models.SearchResultTypeDiscussionComment))
+
+	// 更新
+	comment.Body = "Reproduced on chrome"
+	require.NoError(t, env.commentRepo.Update(ctx, comment))
+	assert.Empty(t, env.search(t, "firefox", models.SearchResultTypeComment))
+	assert.Equal(t, []int64{comment.ID}, env.search(t, "chrome", models.SearchResultTypeComment))
+
+	// 削除
+	require.NoError(t, env.commentRepo.Delete(ctx, comment.ID))
+	assert.Empty(t, env.search(t, "chrome", models.SearchResultTypeComment))
+	assert.Equal(t, []int64{discussionComment.ID}, env.search(t, "firefox", models.SearchResultTypeDiscussionComment))
+}
+
+func TestSearchVerifyIndex(t *testing.T) {
+	env := newSearchTestEnv(t)
+	ctx := context.Background()
+
+	issue := env.createIssue(t, "Memory leak", "The worker leaks memory")
+	discussion := env.createDiscussion(t, "Memory usage", "How much memory is normal?", "question")
+
+	// 件数が一致する場合はそのまま
+	require.NoError(t, env.searchService.VerifyIndex(ctx))
+	assert.Equal(t, []int64{issue.ID}, env.search(t, "memory", models.SearchResultTypeIssue))
+
+	// インデックスの件数がデータと一致しない場合は再構築する
+	require.NoError(t, env.db.Exec("DELETE FROM issue_search").Error)
+	require.NoError(t, env.db.Exec("DELETE FROM discussion_search").Error)
+	assert.Empty(t, env.search(t, "memory"))
+
+	require.NoError(t, env.searchService.VerifyIndex(ctx))
+	assert.Equal(t, []int64{issue.ID}, env.search(t, "memory", models.SearchResultTypeIssue))
+	assert.Equal(t, []int64{discussion.ID}, env.search(t, "memory", models.SearchResultTypeDiscussion))
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/repositories"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// searchTestEnv は全文検索のテスト環境（リポジトリは書き込み時にインデックスを更新する）
+type searchTestEnv struct {
+	db             *gorm.DB
+	searchService  services.SearchService
+	issueRepo      repositories.IssueRepository
+	commentRepo    repositories.CommentRepository
+	discussionRepo repositories.DiscussionRepository
+	milestoneRepo  repositories.MilestoneRepository
+	labelRepo      repositories.LabelRepository
+	user           *models.User
+	repo           *models.Repository
+}
+
+func newSearchTestEnv(t *testing.T) *searchTestEnv {
+	db := newMigratedTestDB(t)
+	factory := services.NewRepositoryFactory(db)
+
+	searchService, err := factory.NewSearchService()
+	if err != nil && strings.Contains(err.Error(), "no such module: fts5") {
+		t.Skip("SQLite is built without FTS5 (run with -tags sqlite_fts5)")
+	}
+	require.NoError(t, err)
+	issueRepo, _ := factory.NewIssueRepository()
+	commentRepo, _ := factory.NewCommentRepository()
+	discussionRepo, _ := factory.NewDiscussionRepository()
+	milestoneRepo, _ := factory.NewMilestoneRepository()
+	labelRepo, _ := factory.NewLabelRepository()
+	repoRepo, _ := factory.NewRepositoryRepository()
+
+	env := &searchTestEnv{
+		db:             db,
+		searchService:  searchService,
+		issueRepo:      services.NewIndexingIssueRepository(issueRepo, searchService),
+		commentRepo:    services.NewIndexingCommentRepository(commentRepo, searchService),
+		discussionRepo: services.NewIndexingDiscussionRepository(discussionRepo, searchService),
+		milestoneRepo:  services.NewIndexingMilestoneRepository(milestoneRepo, searchService),
+		labelRepo:      services.NewIndexingLabelRepository(labelRepo, searchService),
+		user:           createTestUser(t, db, "searcher", false),
+	}
+	env.repo = models.NewRepository("search-repo", "", models.PublicRepo, env.user.ID)
+	require.NoError(t, repoRepo.Create(context.Background(), env.repo))
+	return env
+}
+
+func (env *searchTestEnv) createLabel(t *testing.T, name, description, labelType string) *models.Label {
+	label := models.NewLabel(name, description, "#ff0000", labelType)
+	label.RepositoryID = env.repo.ID
+	require.NoError(t, env.labelRepo.Create(context.Background(), label))
+	return label
+}
+
+func (env *searchTestEnv) createIssue(t *testing.T, title, body string, labels ...string) *models.Issue {
+	issue := models.NewIssue(title, body, env.user.ID)
+	issue.RepositoryID = env.repo.ID
+	issue.Labels = labels
+	require.NoError(t, env.issueRepo.Create(context.Background(), issue))
+	return issue
+}
+
+func (env *searchTestEnv) createDiscussion(t *testing.T, title, body, category string, labels ...string) *models.Discussion {
+	discussion := models.NewDiscussion(title, body, category, env.user.ID)
+	discussion.RepositoryID = env.repo.ID
+	discussion.Labels = labels
+	require.NoError(t, env.discussionRepo.Create(context.Background(), discussion))
+	return discussion
+}
+
+func (env *searchTestEnv) createMilestone(t *testing.T, title, description string) *models.Milestone {
+	milestone := models.NewMilestone(title, description, time.Time{}, env.user.ID)
+	milestone.RepositoryID = env.repo.ID
+	require.NoError(t, env.milestoneRepo.Create(context.Background(), milestone))
+	return milestone
+}
+
+// search は指定した種類を検索し、結果のIDを返します
+func (env *searchTestEnv) search(t *testing.T, query string, types ...models.SearchResultType) []int64 {
+	results, err := env.searchService.Search(context.Background(), models.SearchQuery{Query: query, Types: types, Limit: 100})
+	require.NoError(t, err)
+	ids := make([]int64, 0, len(results.Results))
+	for _, result := range results.Results {
+		ids = append(ids, result.ID)
+	}
+	return ids
+}
+
+func TestSearchDiscussions(t *testing.T) {
+	env := newSearchTestEnv(t)
+	env.createLabel(t, "help-wanted", "", models.LabelTypeDiscussion)
+
+	question := env.createDiscussion(t, "Deploying with kubernetes", "How do I deploy?", "question", "help-wanted")
+	idea := env.createDiscussion(t, "Kubernetes operator", "An operator would be nice", "idea")
+	env.createDiscussion(t, "Release notes", "Nothing to see here", "announcement")
+	env.createIssue(t, "Kubernetes manifests are broken", "")
+
+	assert.ElementsMatch(t, []int64{question.ID, idea.ID}, env.search(t, "kubernetes", models.SearchResultTypeDiscussion))
+	assert.Equal(t, []int64{question.ID}, env.search(t, "kubernetes category:question", models.SearchResultTypeDiscussion))
+	assert.Equal(t, []int64{question.ID}, env.search(t, "kubernetes label:help-wanted", models.SearchResultTypeDiscussion))
+
+	results, err := env.searchService.Search(context.Background(), models.SearchQuery{Query: "kubernetes", Types: []models.SearchResultType{models.SearchResultTypeDiscussion}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, results.TotalCount)
+	assert.Equal(t, map[string]int{"question": 1, "idea": 1}, results.Facets.Categories)
+	assert.Equal(t, map[string]int{"help-wanted": 1}, results.Facets.Labels)
+	assert.Equal(t, map[models.SearchResultType]int{models.SearchResultTypeDiscussion: 2}, results.Facets.Types)
+}
+
+func TestSearchMilestonesAndLabels(t *testing.T) {
+	env := newSearchTestEnv(t)
+
+	release := env.createMilestone(t, "Release 2.0", "Rewrite of the payment flow")
+	env.createMilestone(t, "Release 1.0", "Initial launch")
+	payments := env.createLabel(t, "payments", "Anything about the payment flow", models.LabelTypeBoth)
+	env.createLabel(t, "docs", "Documentation", models.LabelTypeBoth)
+
+	assert.Equal(t, []int64{release.ID}, env.search(t, "payment", models.SearchResultTypeMilestone))
+	assert.Equal(t, []int64{payments.ID}, env.search(t, "payment", models.SearchResultTypeLabel))
+	assert.Len(t, env.search(t, "release", models.SearchResultTypeMilestone), 2)
+
+	// マイルストーンはステータスで絞り込める
+	closed, err := env.milestoneRepo.GetByID(context.Background(), release.ID)
+	require.NoError(t, err)
+	closed.Close()
+	require.NoError(t, env.milestoneRepo.Update(context.Background(), closed))
+	assert.Equal(t, []int64{release.ID}, env.search(t, "release is:closed", models.SearchResultTypeMilestone))
+
+	results, err := env.searchService.Search(context.Background(), models.SearchQuery{Query: "payment"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, results.TotalCount)
+	assert.Equal(t, map[models.SearchResultType]int{models.SearchResultTypeMilestone: 1, models.SearchResultTypeLabel: 1}, results.Facets.Types)
+	assert.Equal(t, map[string]int{"closed": 1}, results.Facets.Statuses)
+}
+
+func TestSearchPagingAndFacets(t *testing.T) {
+	env := newSearchTestEnv(t)
+	env.createLabel(t, "bug", "", models.LabelTypeBoth)
+
+	var want []int64
+	for i := 0; i < 5; i++ {
+		labels := []string{}
+		if i%2 == 0 {
+			labels = append(labels, "bug")
+		}
+		want = append(want, env.createIssue(t, "Widget issue", "The widget is broken", labels...).ID)
+	}
+	for i := 0; i < 3; i++ {
+		want = append(want, env.createDiscussion(t, "Widget discussion", "Let's talk about the widget", "general", "bug").ID)
+	}
+	env.createIssue(t, "Unrelated", "Nothing to see here")
+
+	// ページをまたいでも件数とファセットは全体から集計され、結果は重複しない
+	var got []int64
+	for offset := 0; offset < 9; offset += 3 {
+		results, err := env.searchService.Search(context.Background(), models.SearchQuery{Query: "widget", Limit: 3, Offset: offset})
+		require.NoError(t, err)
+		assert.Equal(t, 8, results.TotalCount)
+		assert.Equal(t, 3, results.TotalPages)
+		assert.Equal(t, offset/3+1, results.CurrentPage)
+		assert.Equal(t, map[models.SearchResultType]int{models.SearchResultTypeIssue: 5, models.SearchResultTypeDiscussion: 3}, results.Facets.Types)
+		assert.Equal(t, map[string]int{"open": 8}, results.Facets.Statuses)
+		assert.Equal(t, map[string]int{"bug": 6}, results.Facets.Labels)
+		assert.Equal(t, map[string]int{"general": 3}, results.Facets.Categories)
+		for _, result := range results.Results {
+			got = append(got, result.ID)
+		}
+	}
+	assert.Len(t, got, 8)
+	assert.ElementsMatch(t, want, got)
+
+	// 単一の種類の場合もSQLでページングする
+	results, err := env.searchService.Search(context.Background(), models.SearchQuery{
+		Query: "widget", Types: []models.SearchResultType{models.SearchResultTypeIssue}, Limit: 2, Offset: 4,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5, results.TotalCount)
+	assert.Len(t, results.Results, 1)
+
+	// 範囲外のページ
+	results, err = env.searchService.Search(context.Background(), models.SearchQuery{Query: "widget", Limit: 3, Offset: 30})
+	require.NoError(t, err)
+	assert.Equal(t, 8, results.TotalCount)
+	assert.Empty(t, results.Results)
+	assert.NotNil(t, results.Results)
+}
+
+func TestSearchRepositoryRestriction(t *testing.T) {
+	env := newSearchTestEnv(t)
+	ctx := context.Background()
+
+	other := models.NewRepository("other-repo", "", models.PrivateRepo, env.user.ID)
+	require.NoError(t, env.db.Create(other).Error)
+
+	issue := env.createIssue(t, "Cache invalidation", "")
+	discussion := env.createDiscussion(t, "Cache strategy", "", "general")
+	comment := models.NewComment("Cache headers are wrong", env.user.ID, issue.ID, "issue")
+	require.NoError(t, env.commentRepo.Create(ctx, comment))
+	hidden := models.NewIssue("Cache secrets", "", env.user.ID)
+	hidden.RepositoryID = other.ID
+	require.NoError(t, env.issueRepo.Create(ctx, hidden))
+
+	tests := []struct {
+		name          string
+		repositoryIDs []int64
+		want          []int64
+	}{
+		{name: "制限なし", repositoryIDs: nil, want: []int64{issue.ID, discussion.ID, comment.ID, hidden.ID}},
+		{name: "閲覧できるリポジトリのみ", repositoryIDs: []int64{env.repo.ID}, want: []int64{issue.ID, discussion.ID, comment.ID}},
+		{name: "閲覧できるリポジトリがない", repositoryIDs: []int64{}, want: []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := env.searchService.Search(ctx, models.SearchQuery{Query: "cache", RepositoryIDs: tt.repositoryIDs})
+			require.NoError(t, err)
+			ids := []int64{}
+			for _, result := range results.Results {
+				ids = append(ids, result.ID)
+			}
+			assert.ElementsMatch(t, tt.want, ids)
+			assert.Equal(t, len(tt.want), results.TotalCount)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/migrations"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newMigratedTestDB はアプリケーションと同じマイグレーションを適用したインメモリのデータベースを作成します
+func newMigratedTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1) // インメモリのデータベースを接続間で共有するため
+	t.Cleanup(func() { sqlDB.Close() })
+
+	// users・comments・通知関連のテーブルはGormMigrateの対象外のため先に作成する
+	require.NoError(t, db.AutoMigrate(
+		&models.User{}, &models.AuthToken{}, &models.PasswordReset{}, &models.Comment{},
+		&models.Notification{}, &models.UserSettings{},
+	))
+	require.NoError(t, migrations.GormMigrate(db))
+	return db
+}
+
+// createTestUser はユーザーを作成します
+func createTestUser(t *testing.T, db *gorm.DB, username string, isAdmin bool) *models.User {
+	user := models.NewUser(username, username+"@example.com", "hashed", "")
+	user.SetAdmin(isAdmin)
+	require.NoError(t, db.Create(user).Error)
+	return user
+}
+
+// testUserMiddleware はX-Test-User-IDヘッダーのユーザーをログインユーザーとして設定します（ヘッダーがない場合は未ログイン）
+func testUserMiddleware(c *gin.Context) {
+	if id, err := strconv.ParseInt(c.GetHeader("X-Test-User-ID"), 10, 64); err == nil {
+		c.Set("user_id", id)
+	}
+	c.Next()
+}
+
+// performTestRequest は指定したユーザーとしてJSONリクエストを送信し、ステータスコードとレスポンスを返します（userがnilの場合は未ログイン）
+func performTestRequest(t *testing.T, router http.Handler, method, path string, user *models.User, body interface{}) (int, map[string]interface{}) {
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&buf).Encode(body))
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if user != nil {
+		req.Header.Set("X-Test-User-ID", strconv.FormatInt(user.ID, 10))
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+	return w.Code, resp
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shimauma0312/module-tickethub/backend/api"
+	"github.com/shimauma0312/module-tickethub/backend/models"
+	"github.com/shimauma0312/module-tickethub/backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const twoFactorTestPassword = "correct-horse-battery"
+
+// twoFactorTestEnv は二要素認証のテスト環境
+type twoFactorTestEnv struct {
+	db     *gorm.DB
+	router *gin.Engine
+	user   *models.User
+}
+
+func newTwoFactorTestEnv(t *testing.T) *twoFactorTestEnv {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1) // インメモリのデータベースを接続間で共有するため
+	t.Cleanup(func() { sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.AuthToken{}, &models.PasswordReset{}))
+	require.NoError(t, models.AutoMigrateTwoFactor(db))
+	require.NoError(t, models.AutoMigrateActivityLog(db))
+
+	factory := services.NewRepositoryFactory(db)
+	userRepo, _ := factory.NewUserRepository()
+	tokenRepo, _ := factory.NewAuthTokenRepository()
+	passwordResetRepo, _ := factory.NewPasswordResetRepository()
+	twoFactorRepo, _ := factory.NewTwoFactorRepository()
+	activityLogRepo, _ := factory.NewActivityLogRepository()
+	authService := services.NewAuthService(userRepo, tokenRepo, passwordResetRepo, twoFactorRepo, nil, "test-secret")
+	twoFactorService := services.NewTwoFactorService(twoFactorRepo, userRepo, authService)
+	activityLogService := services.NewActivityLogService(activityLogRepo)
+
+	user, err := authService.Register(context.Background(), "alice", "alice@example.com", twoFactorTestPassword, "Alice")
+	require.NoError(t, err)
+
+	router := gin.New()
+	authHandler := api.NewAuthHandler(authService, activityLogService, nil)
+	twoFactorHandler := api.NewTwoFactorHandler(twoFactorService, activityLogService)
+	authGroup := router.Group("/api/auth")
+	authGroup.POST("/login", authHandler.Login)
+	authGroup.POST("/login/2fa", twoFactorHandler.CompleteLogin)
+	twoFactorHandler.RegisterRoutes(authGroup.Group("/", api.AuthMiddleware(authService, nil)))
+
+	adminHandler := api.NewAdminHandler(userRepo, nil, activityLogService, nil, nil, twoFactorService, nil)
+	router.PUT("/api/admin/users/:id", adminHandler.UpdateUser)
+
+	return &twoFactorTestEnv{db: db, router: router, user: user}
+}
+
+// request はJSONのリクエストを送信し、ステータスコードとレスポンスを返します
+func (e *twoFactorTestEnv) request(t *testing.T, method, path, token string, body interface{}) (int, map[string]interface{}) {
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&buf).Encode(body))
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+	return w.Code, resp
+}
+
+// login はパスワードでログインし、レスポンスを返します
+func (e *twoFactorTestEnv) login(t *testing.T) map[string]interface{} {
+	code, resp := e.request(t, http.MethodPost, "/api/auth/login", "", gin.H{
+		"username_or_email": "alice",
+		"password":          twoFactorTestPassword,
+	})
+	require.Equal(t, http.StatusOK, code, resp)
+	return resp
+}
+
+// accessToken はパスワードでログインし、アクセストークンを返します（二要素認証が無効な場合）
+func (e *twoFactorTestEnv) accessToken(t *testing.T) string {
+	resp := e.login(t)
+	require.NotContains(t, resp, "mfa_required")
+	return resp["token"].(map[string]interface{})["access_token"].(string)
+}
+
+// enable は二要素認証を有効にし、共有鍵・有効化に使用したステップ・リカバリーコードを返します
+func (e *twoFactorTestEnv) enable(t *testing.T, token string) (string, int64, []string) {
+	status, resp := e.request(t, http.MethodPost, "/api/auth/2fa/setup", token, nil)
+	require.Equal(t, http.StatusOK, status, resp)
+	secret := resp["secret"].(string)
+	assert.Contains(t, resp["otpauth_uri"], "otpauth://totp/TicketHub:alice?")
+
+	step := models.TOTPStep(time.Now())
+	status, resp = e.request(t, http.MethodPost, "/api/auth/2fa/enable", token, gin.H{"code": totpCode(t, secret, step)})
+	require.Equal(t, http.StatusOK, status, resp)
+
+	var recoveryCodes []string
+	for _, code := range resp["recovery_codes"].([]interface{}) {
+		recoveryCodes = append(recoveryCodes, code.(string))
+	}
+	require.Len(t, recoveryCodes, models.RecoveryCodeCount)
+	return secret, step, recoveryCodes
+}
+
+// actions はアクティビティログに記録されたアクションを返します
+func (e *twoFactorTestEnv) actions(t *testing.T) []string {
+	var actions []string
+	require.NoError(t, e.db.Model(&models.ActivityLog{}).Order("id ASC").Pluck("action", &actions).Error)
+	return actions
+}
+
+func totpCode(t *testing.T, secret string, step int64) string {
+	code, err := models.GenerateTOTPCode(secret, step)
+	require.NoError(t, err)
+	return code
+}
+
+func TestTwoFactorLogin(t *testing.T) {
+	env := newTwoFactorTestEnv(t)
+	token := env.accessToken(t)
+
+	// 有効化前は誤ったコードを受け付けない
+	status, _ := env.request(t, http.MethodPost, "/api/auth/2fa/enable", token, gin.H{"code": "000000"})
+	assert.Equal(t, http.StatusConflict, status, "登録の開始前")
+	status, resp := env.request(t, http.MethodPost, "/api/auth/2fa/setup", token, nil)
+	require.Equal(t, http.StatusOK, status)
+	wrong := totpCode(t, resp["secret"].(string), models.TOTPStep(time.Now())-5)
+	status, _ = env.request(t, http.MethodPost, "/api/auth/2fa/enable", token, gin.H{"code": wrong})
+	assert.Equal(t, http.StatusBadRequest, status, "誤ったコード")
+
+	secret, step, recoveryCodes := env.enable(t, token)
+	assert.Equal(t, []string{string(models.ActionUserTwoFactorEnabled)}, env.actions(t))
+
+	// パスワードのみではトークンを発行しない
+	resp = env.login(t)
+	assert.Equal(t, true, resp["mfa_required"])
+	assert.NotContains(t, resp, "token")
+	pendingToken := resp["mfa_pending_token"].(string)
+
+	// 一時トークンは通常のAPIの認証に使用できない
+	status, _ = env.request(t, http.MethodGet, "/api/auth/2fa", pendingToken, nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	// 有効化で使用したコードは再利用できない
+	status, _ = env.request(t, http.MethodPost, "/api/auth/login/2fa", "", gin.H{"mfa_pending_token": pendingToken, "code": totpCode(t, secret, step)})
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, resp = env.request(t, http.MethodPost, "/api/auth/login/2fa", "", gin.H{"mfa_pending_token": pendingToken, "code": totpCode(t, secret, step+1)})
+	require.Equal(t, http.StatusOK, status, resp)
+	assert.NotEmpty(t, resp["token"].(map[string]interface{})["access_token"])
+
+	// リカバリーコードは一度だけ使用できる
+	pendingToken = env.login(t)["mfa_pending_token"].(string)
+	status, _ = env.request(t, http.MethodPost, "/api/auth/login/2fa", "", gin.H{"mfa_pending_token": pendingToken, "code": recoveryCodes[0]})
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = env.request(t, http.MethodPost, "/api/auth/login/2fa", "", gin.H{"mfa_pending_token": pendingToken, "code": recoveryCodes[0]})
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, resp = env.request(t, http.MethodPost, "/api/auth/login/2fa", "", gin.H{"mfa_pending_token": "invalid", "code": recoveryCodes[1]})
+	assert.Equal(t, http.StatusUnauthorized, status, resp)
+
+	status, resp = env.request(t, http.MethodGet, "/api/auth/2fa", token, nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, true, resp["enabled"])
+	assert.Equal(t, float64(models.RecoveryCodeCount-1), resp["recovery_codes_remaining"])
+}
+
+func TestTwoFactorDisableAndRegenerate(t *testing.T) {
+	env := newTwoFactorTestEnv(t)
+	token := env.accessToken(t)
+	secret, step, recoveryCodes := env.enable(t, token)
+
+	status, _ := env.request(t, http.MethodPost, "/api/auth/2fa/setup", token, nil)
+	assert.Equal(t, http.StatusConflict, status, "有効化後の再登録")
+
+	// リカバリーコードの再発行で以前のコードは使用できなくなる
+	status, _ = env.request(t, http.MethodPost, "/api/auth/2fa/recovery-codes", token, gin.H{"code": recoveryCodes[0]})
+	assert.Equal(t, http.StatusBadRequest, status, "リカバリーコードでは再発行できない")
+	status, resp := env.request(t, http.MethodPost, "/api/auth/2fa/recovery-codes", token, gin.H{"code": totpCode(t, secret, step+1)})
+	require.Equal(t, http.StatusOK, status, resp)
+	assert.Len(t, resp["recovery_codes"], models.RecoveryCodeCount)
+
+	status, _ = env.request(t, http.MethodPost, "/api/auth/2fa/disable", token, gin.H{"code": recoveryCodes[1]})
+	assert.Equal(t, http.StatusBadRequest, status, "再発行前のリカバリーコード")
+	newCode := resp["recovery_codes"].([]interface{})[0].(string)
+	status, _ = env.request(t, http.MethodPost, "/api/auth/2fa/disable", token, gin.H{"code": newCode})
+	require.Equal(t, http.StatusOK, status)
+
+	// 無効化後はパスワードのみでログインできる
+	env.accessToken(t)
+	assert.Equal(t, []string{string(models.ActionUserTwoFactorEnabled), string(models.ActionUserTwoFactorDisabled)}, env.actions(t))
+}
+
+func TestAdminResetTwoFactor(t *testing.T) {
+	env := newTwoFactorTestEnv(t)
+	env.enable(t, env.accessToken(t))
+	require.Equal(t, true, env.login(t)["mfa_required"])
+
+	status, resp := env.request(t, http.MethodPut, "/api/admin/users/"+strconv.FormatInt(env.user.ID, 10), "", gin.H{"reset_two_factor": true})
+	require.Equal(t, http.StatusOK, status, resp)
+
+	env.accessToken(t)
+	assert.Equal(t, []string{
+		string(models.ActionUserTwoFactorEnabled),
+		string(models.ActionUserUpdated),
+		string(models.ActionUserTwoFactorDisabled),
+	}, env.actions(t))
+
+	var details string
+	require.NoError(t, env.db.Model(&models.ActivityLog{}).Where("action = ?", models.ActionUserTwoFactorDisabled).Pluck("details", &details).Error)
+	assert.JSONEq(t, `{"reset_by_admin":true}`, details)
+}